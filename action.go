@@ -0,0 +1,199 @@
+package forgeui
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"reflect"
+
+	"github.com/a-h/templ"
+
+	"github.com/xraph/forgeui/bridge"
+	"github.com/xraph/forgeui/htmx"
+	"github.com/xraph/forgeui/router"
+)
+
+// ActionField is the form field (or query parameter) that selects which
+// action handles a submission, e.g. <button name="_action" value="delete">.
+const ActionField = "_action"
+
+// Action handles a form submitted to a page. Create one with FormAction and
+// attach it with PageBuilder.Action.
+type Action struct {
+	run      func(ctx *router.PageContext) error
+	fragment func(ctx *router.PageContext) templ.Component
+	redirect string
+}
+
+// FormAction creates an Action that decodes the submitted form into T, using
+// the same rules as form-encoded bridge calls (json tag names as keys),
+// checks its validate tags and calls fn.
+//
+// When decoding or validation fails, or fn returns router.FieldErrors, the
+// page is rendered again with the errors available through
+// PageContext.FieldErrors. Other errors go to the error handler.
+//
+// Example:
+//
+//	type SignupForm struct {
+//	    Email string `json:"email" validate:"required,email"`
+//	    Name  string `json:"name" validate:"required"`
+//	}
+//
+//	app.Page("/signup").
+//	    Handler(SignupPage).
+//	    Action("signup", forgeui.FormAction(func(ctx *router.PageContext, form SignupForm) error {
+//	        if emailTaken(form.Email) {
+//	            return router.FieldErrors{"email": "Email is already registered"}
+//	        }
+//	        return createUser(ctx.Context(), form)
+//	    }).RedirectTo("/welcome")).
+//	    Register()
+func FormAction[T any](fn func(ctx *router.PageContext, form T) error) *Action {
+	if reflect.TypeFor[T]().Kind() != reflect.Struct {
+		panic("forgeui: FormAction form type must be a struct")
+	}
+
+	return &Action{
+		run: func(ctx *router.PageContext) error {
+			var form T
+
+			errs := bridge.DecodeForm(ctx.Request.Form, &form)
+			if errs == nil {
+				errs = bridge.ValidateFormContext(ctx.Context(), &form)
+			}
+
+			if len(errs) > 0 {
+				return router.FieldErrors(errs)
+			}
+
+			return fn(ctx, form)
+		},
+	}
+}
+
+// Fragment sets the component HTMX submissions receive instead of a redirect
+// or the whole page, typically the form itself. It renders without layouts,
+// with field errors set when the submission failed.
+func (a *Action) Fragment(fragment func(ctx *router.PageContext) templ.Component) *Action {
+	a.fragment = fragment
+	return a
+}
+
+// RedirectTo sets where a successful submission redirects to.
+// Defaults to the page itself.
+func (a *Action) RedirectTo(url string) *Action {
+	a.redirect = url
+	return a
+}
+
+// Action adds a named form action to the page. Actions handle POST requests
+// to the page's path; with several actions, the submission selects one with
+// the _action field (see ActionField).
+//
+// Successful submissions follow Post/Redirect/Get with a 303 redirect. Failed
+// ones render the page again, with status 422 and the field errors in the
+// PageContext. HTMX requests get the action's fragment instead.
+func (pb *PageBuilder) Action(name string, action *Action) *PageBuilder {
+	if pb.actions == nil {
+		pb.actions = make(map[string]*Action)
+	}
+
+	pb.actions[name] = action
+
+	return pb
+}
+
+// actionHandler returns the handler for POST requests to a page with actions.
+func (pb *PageBuilder) actionHandler() router.PageHandler {
+	actions := pb.actions
+	page := pb.handler
+
+	return func(ctx *router.PageContext) (templ.Component, error) {
+		if err := ctx.Request.ParseMultipartForm(32 << 20); err != nil && !errors.Is(err, http.ErrNotMultipart) {
+			return router.DefaultErrorPage(http.StatusBadRequest)(ctx)
+		}
+
+		action, ok := selectAction(actions, ctx.Request.Form.Get(ActionField))
+		if !ok {
+			return router.DefaultErrorPage(http.StatusBadRequest)(ctx)
+		}
+
+		isHTMX := htmx.IsHTMX(ctx.Request)
+
+		err := action.run(ctx)
+
+		var fieldErrs router.FieldErrors
+		if errors.As(err, &fieldErrs) {
+			ctx.SetFieldErrors(fieldErrs)
+
+			if isHTMX {
+				// htmx doesn't swap 4xx responses by default
+				ctx.SkipLayout()
+
+				if action.fragment != nil {
+					return action.fragment(ctx), nil
+				}
+
+				return page(ctx)
+			}
+
+			ctx.ResponseWriter.WriteHeader(http.StatusUnprocessableEntity)
+
+			return page(ctx)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if isHTMX && action.fragment != nil {
+			ctx.SkipLayout()
+			return action.fragment(ctx), nil
+		}
+
+		target := action.redirect
+		if target == "" {
+			target = selfURL(ctx.Request)
+		}
+
+		if isHTMX {
+			htmx.SetHTMXLocation(ctx.ResponseWriter, target)
+			return nil, nil
+		}
+
+		http.Redirect(ctx.ResponseWriter, ctx.Request, target, http.StatusSeeOther)
+
+		return nil, nil
+	}
+}
+
+// selectAction returns the action named name, or the only action when no
+// name was submitted.
+func selectAction(actions map[string]*Action, name string) (*Action, bool) {
+	if name == "" && len(actions) == 1 {
+		for _, action := range actions {
+			return action, true
+		}
+	}
+
+	action, ok := actions[name]
+
+	return action, ok
+}
+
+// selfURL returns the URL of the page a form was posted to, as the client
+// sees it: RequestURI keeps any prefix http.StripPrefix removed from the path.
+func selfURL(req *http.Request) string {
+	u, err := url.ParseRequestURI(req.RequestURI)
+	if err != nil {
+		return req.URL.Path
+	}
+
+	query := u.Query()
+	query.Del(ActionField)
+
+	u.RawQuery = query.Encode()
+
+	return u.RequestURI()
+}
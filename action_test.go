@@ -0,0 +1,237 @@
+package forgeui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+
+	"github.com/xraph/forgeui/router"
+)
+
+type signupForm struct {
+	Email string `json:"email" validate:"required,email"`
+	Name  string `json:"name" validate:"required"`
+	Age   int    `json:"age"`
+}
+
+// newActionApp returns an app with a /signup page whose signup action
+// records the submitted form.
+func newActionApp(t *testing.T, fragment bool) (*App, *signupForm) {
+	t.Helper()
+
+	app := New()
+	app.Router().RegisterLayout("main", func(ctx *router.PageContext, content templ.Component) templ.Component {
+		return templ.ComponentFunc(func(c context.Context, w io.Writer) error {
+			_, _ = io.WriteString(w, "<main>")
+			_ = content.Render(c, w)
+			_, err := io.WriteString(w, "</main>")
+
+			return err
+		})
+	})
+
+	var submitted signupForm
+
+	action := FormAction(func(ctx *router.PageContext, form signupForm) error {
+		if form.Email == "taken@example.com" {
+			return router.FieldErrors{"email": "already registered"}
+		}
+
+		if form.Name == "boom" {
+			return errors.New("database down")
+		}
+
+		submitted = form
+
+		return nil
+	})
+
+	if fragment {
+		action.Fragment(func(ctx *router.PageContext) templ.Component {
+			return templ.Raw(fmt.Sprintf("<form>errors=%d</form>", len(ctx.FieldErrors())))
+		})
+	}
+
+	app.Page("/signup").
+		Handler(func(ctx *router.PageContext) (templ.Component, error) {
+			return templ.Raw(fmt.Sprintf("page email-error=%q", ctx.FieldError("email"))), nil
+		}).
+		Layout("main").
+		Action("signup", action).
+		Register()
+
+	return app, &submitted
+}
+
+func postForm(app *App, target string, values url.Values, htmx bool) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(values.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	if htmx {
+		req.Header.Set("HX-Request", "true")
+	}
+
+	w := httptest.NewRecorder()
+	app.Router().ServeHTTP(w, req)
+
+	return w
+}
+
+func TestFormAction_Success(t *testing.T) {
+	app, submitted := newActionApp(t, false)
+
+	w := postForm(app, "/signup?_action=signup", url.Values{
+		"email": {"jane@example.com"},
+		"name":  {"Jane"},
+		"age":   {"30"},
+	}, false)
+
+	if w.Code != http.StatusSeeOther {
+		t.Fatalf("Expected status 303, got %d", w.Code)
+	}
+
+	if loc := w.Header().Get("Location"); loc != "/signup" {
+		t.Errorf("Expected redirect to /signup, got %q", loc)
+	}
+
+	if submitted.Email != "jane@example.com" || submitted.Name != "Jane" || submitted.Age != 30 {
+		t.Errorf("Unexpected form: %+v", *submitted)
+	}
+}
+
+func TestFormAction_ValidationFailure(t *testing.T) {
+	tests := []struct {
+		name      string
+		values    url.Values
+		wantError string
+	}{
+		{"missing email", url.Values{"name": {"Jane"}}, "is required"},
+		{"invalid email", url.Values{"email": {"nope"}, "name": {"Jane"}}, "valid email"},
+		{"returned field errors", url.Values{"email": {"taken@example.com"}, "name": {"Jane"}}, "already registered"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app, _ := newActionApp(t, false)
+
+			w := postForm(app, "/signup", tt.values, false)
+
+			if w.Code != http.StatusUnprocessableEntity {
+				t.Errorf("Expected status 422, got %d", w.Code)
+			}
+
+			body := w.Body.String()
+			if !strings.HasPrefix(body, "<main>page") {
+				t.Errorf("Expected page re-rendered in its layout, got %q", body)
+			}
+
+			if !strings.Contains(body, tt.wantError) {
+				t.Errorf("Expected field error containing %q, got %q", tt.wantError, body)
+			}
+		})
+	}
+}
+
+func TestFormAction_DecodeError(t *testing.T) {
+	app, _ := newActionApp(t, false)
+
+	w := postForm(app, "/signup", url.Values{"email": {"a@b.co"}, "name": {"Jane"}, "age": {"old"}}, false)
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status 422, got %d", w.Code)
+	}
+}
+
+func TestFormAction_HandlerError(t *testing.T) {
+	app, _ := newActionApp(t, false)
+
+	w := postForm(app, "/signup", url.Values{"email": {"a@b.co"}, "name": {"boom"}}, false)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status 500, got %d", w.Code)
+	}
+}
+
+func TestFormAction_HTMX(t *testing.T) {
+	app, _ := newActionApp(t, true)
+
+	// Failure: the fragment with errors, no layout, swappable status
+	w := postForm(app, "/signup", url.Values{"name": {"Jane"}}, true)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", w.Code)
+	}
+
+	if body := w.Body.String(); body != "<form>errors=1</form>" {
+		t.Errorf("Expected form fragment, got %q", body)
+	}
+
+	// Success: the fresh fragment instead of a redirect
+	w = postForm(app, "/signup", url.Values{"email": {"a@b.co"}, "name": {"Jane"}}, true)
+
+	if w.Header().Get("Location") != "" {
+		t.Error("HTMX submissions should not redirect")
+	}
+
+	if body := w.Body.String(); body != "<form>errors=0</form>" {
+		t.Errorf("Expected form fragment, got %q", body)
+	}
+}
+
+func TestFormAction_HTMXWithoutFragment(t *testing.T) {
+	app, _ := newActionApp(t, false)
+
+	w := postForm(app, "/signup", url.Values{"email": {"a@b.co"}, "name": {"Jane"}}, true)
+
+	if loc := w.Header().Get("HX-Location"); loc != "/signup" {
+		t.Errorf("Expected HX-Location /signup, got %q", loc)
+	}
+}
+
+func TestFormAction_SelectsAction(t *testing.T) {
+	app := New()
+
+	var called string
+
+	named := func(name string) *Action {
+		return FormAction(func(ctx *router.PageContext, form struct{}) error {
+			called = name
+			return nil
+		})
+	}
+
+	app.Page("/items").
+		Handler(func(ctx *router.PageContext) (templ.Component, error) {
+			return templ.Raw("items"), nil
+		}).
+		Action("create", named("create")).
+		Action("delete", named("delete")).
+		Register()
+
+	postForm(app, "/items", url.Values{ActionField: {"delete"}}, false)
+
+	if called != "delete" {
+		t.Errorf("Expected delete action, got %q", called)
+	}
+
+	w := postForm(app, "/items", url.Values{}, false)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 without an action name, got %d", w.Code)
+	}
+
+	// The page itself still answers GET
+	w = httptest.NewRecorder()
+	app.Router().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/items", nil))
+
+	if w.Body.String() != "items" {
+		t.Errorf("Expected page, got %q", w.Body.String())
+	}
+}
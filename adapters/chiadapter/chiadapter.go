@@ -0,0 +1,61 @@
+// Package chiadapter serves ForgeUI apps from a chi router.
+//
+//	r := chi.NewRouter()
+//	r.Use(middleware.Logger)
+//	r.Get("/api/orders", listOrders)
+//
+//	app := forgeui.New(forgeui.WithBasePath("/ui"), forgeui.WithBridge())
+//	chiadapter.Mount(r, app)  // pages, assets and bridge under /ui
+//	chiadapter.Routes(r, app) // optional: match pages with chi's router
+package chiadapter
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/xraph/forgeui"
+	"github.com/xraph/forgeui/router"
+)
+
+// Mount serves app on r under the app's base path: pages, static assets,
+// bridge endpoints and client scripts. An app without a base path takes
+// every path r doesn't route otherwise.
+func Mount(r chi.Router, app *forgeui.App) {
+	h := app.Handler()
+
+	base := app.Paths().Base()
+	if base == "" {
+		r.Handle("/*", h)
+		return
+	}
+
+	r.Handle(base, h)
+	r.Handle(base+"/*", h)
+}
+
+// Routes registers the app's pages on r with chi patterns, so chi matches
+// them and passes its URL parameters on. Pages run behind the app's
+// middleware (see App.Wrap). Static assets and bridge endpoints still need
+// Mount.
+func Routes(r chi.Router, app *forgeui.App) {
+	rt := app.Router()
+
+	for _, route := range rt.Routes() {
+		h := app.Wrap(rt.RouteHandler(route))
+
+		handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			h.ServeHTTP(w, router.WithParams(req, route.ParamsFrom(func(name string, catchAll bool) string {
+				if catchAll {
+					name = "*"
+				}
+
+				return chi.URLParam(req, name)
+			})))
+		})
+
+		for _, pattern := range route.Patterns(router.ChiSyntax) {
+			r.Method(route.Method, pattern, handler)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package chiadapter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/xraph/forgeui/internal/adaptertest"
+	"github.com/xraph/forgeui/router"
+)
+
+func TestMount(t *testing.T) {
+	app := adaptertest.NewApp()
+
+	r := chi.NewRouter()
+	Mount(r, app)
+
+	adaptertest.Run(t, app, r)
+}
+
+func TestRoutes(t *testing.T) {
+	app := adaptertest.NewApp()
+
+	r := chi.NewRouter()
+	Mount(r, app)
+	Routes(r, app)
+
+	adaptertest.Run(t, app, r)
+}
+
+func TestFromHTTP(t *testing.T) {
+	app := adaptertest.NewApp()
+
+	// chi's own middleware signature is net/http's
+	var seen string
+
+	app.Use(router.FromHTTP(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			seen = chi.RouteContext(req.Context()).RoutePattern()
+			next.ServeHTTP(w, req)
+		})
+	}))
+
+	r := chi.NewRouter()
+	Mount(r, app)
+	Routes(r, app)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, adaptertest.BasePath+"/users/7", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	if seen != adaptertest.BasePath+"/users/{id}" {
+		t.Errorf("Expected the middleware to see chi's route context, got %q", seen)
+	}
+}
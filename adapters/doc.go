@@ -0,0 +1,13 @@
+// Package adapters serves ForgeUI apps from chi, echo and gin routers.
+//
+// Each adapter package offers Mount, which serves a whole app (pages,
+// static assets, bridge endpoints and scripts) under its base path, and
+// Routes, which registers the app's pages as native routes so the other
+// router matches them and hands over its path parameters; gin's Mount
+// always does. Pages keep the app's middleware, layouts, loaders and error
+// pages either way.
+//
+// The adapters live in their own module so the forgeui module doesn't
+// depend on any of these routers. Standard net/http middleware such as
+// chi's works as page middleware through router.FromHTTP.
+package adapters
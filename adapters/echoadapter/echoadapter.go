@@ -0,0 +1,78 @@
+// Package echoadapter serves ForgeUI apps from an echo server.
+//
+//	e := echo.New()
+//	e.GET("/api/orders", listOrders)
+//
+//	app := forgeui.New(forgeui.WithBasePath("/ui"), forgeui.WithBridge())
+//	echoadapter.Mount(e, app)  // pages, assets and bridge under /ui
+//	echoadapter.Routes(e, app) // optional: match pages with echo's router
+package echoadapter
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/xraph/forgeui"
+	"github.com/xraph/forgeui/router"
+)
+
+// Registrar adds routes; *echo.Echo implements it. Paths are absolute, so
+// pass a group only if its prefix is empty.
+type Registrar interface {
+	Add(method, path string, handler echo.HandlerFunc, middleware ...echo.MiddlewareFunc) *echo.Route
+}
+
+// methods are the methods Mount serves.
+var methods = []string{
+	http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut,
+	http.MethodPatch, http.MethodDelete, http.MethodOptions,
+}
+
+// Mount serves app on e under the app's base path: pages, static assets,
+// bridge endpoints and client scripts. An app without a base path takes
+// every path e doesn't route otherwise.
+func Mount(e Registrar, app *forgeui.App) {
+	h := echo.WrapHandler(app.Handler())
+
+	paths := []string{"/*"}
+	if base := app.Paths().Base(); base != "" {
+		paths = []string{base, base + "/*"}
+	}
+
+	for _, method := range methods {
+		for _, path := range paths {
+			e.Add(method, path, h)
+		}
+	}
+}
+
+// Routes registers the app's pages on e with echo patterns, so echo
+// matches them and passes its path parameters on. Pages run behind the
+// app's middleware (see App.Wrap). Static assets and bridge endpoints
+// still need Mount.
+func Routes(e Registrar, app *forgeui.App) {
+	rt := app.Router()
+
+	for _, route := range rt.Routes() {
+		h := app.Wrap(rt.RouteHandler(route))
+
+		handler := func(c echo.Context) error {
+			params := route.ParamsFrom(func(name string, catchAll bool) string {
+				if catchAll {
+					name = "*"
+				}
+
+				return c.Param(name)
+			})
+
+			h.ServeHTTP(c.Response(), router.WithParams(c.Request(), params))
+
+			return nil
+		}
+
+		for _, pattern := range route.Patterns(router.EchoSyntax) {
+			e.Add(route.Method, pattern, handler)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package echoadapter
+
+import (
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/xraph/forgeui/internal/adaptertest"
+)
+
+func TestMount(t *testing.T) {
+	app := adaptertest.NewApp()
+
+	e := echo.New()
+	Mount(e, app)
+
+	adaptertest.Run(t, app, e)
+}
+
+func TestRoutes(t *testing.T) {
+	app := adaptertest.NewApp()
+
+	e := echo.New()
+	Mount(e, app)
+	Routes(e, app)
+
+	adaptertest.Run(t, app, e)
+}
@@ -0,0 +1,84 @@
+// Package ginadapter serves ForgeUI apps from a gin engine.
+//
+//	g := gin.New()
+//	g.GET("/api/orders", listOrders)
+//
+//	app := forgeui.New(forgeui.WithBasePath("/ui"), forgeui.WithBridge())
+//	ginadapter.Mount(g, app) // pages, assets and bridge under /ui
+package ginadapter
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/xraph/forgeui"
+	"github.com/xraph/forgeui/router"
+)
+
+// Mount serves app on g: its pages as native gin routes (see Routes), and
+// its static assets, bridge client scripts, bridge endpoints (the WebSocket
+// too, when enabled), probes and metrics at the paths App.Paths resolves.
+// gin doesn't allow a catch-all next to other routes, so unlike the chi and
+// echo adapters Mount registers each endpoint, and apps mounted with
+// App.Mount need their own call.
+func Mount(g gin.IRoutes, app *forgeui.App) {
+	h := gin.WrapH(app.Handler())
+	paths := app.Paths()
+
+	// The static handler serves the bridge client scripts too
+	g.Any(paths.Static()+"/*filepath", h)
+
+	if app.HasBridge() {
+		g.Any(paths.BridgeCall(), h)
+		g.Any(paths.BridgeStream()+":function", h)
+
+		if app.Bridge().GetConfig().EnableWebSocket {
+			g.GET(paths.BridgeWebSocket(), h)
+		}
+	}
+
+	if app.IsDev() {
+		g.GET(paths.HotReload(), h)
+	}
+
+	for _, path := range []string{paths.Health(), paths.Ready(), paths.Metrics()} {
+		if path != "" {
+			g.GET(path, h)
+		}
+	}
+
+	Routes(g, app)
+}
+
+// Routes registers the app's pages on g with gin patterns, so gin matches
+// them and passes its path parameters on. Pages run behind the app's
+// middleware (see App.Wrap).
+//
+// gin doesn't allow a parameter next to a catch-all at the same position,
+// so neither may the app's routes.
+func Routes(g gin.IRoutes, app *forgeui.App) {
+	rt := app.Router()
+
+	for _, route := range rt.Routes() {
+		h := app.Wrap(rt.RouteHandler(route))
+
+		handler := func(c *gin.Context) {
+			params := route.ParamsFrom(func(name string, catchAll bool) string {
+				v := c.Param(name)
+				if catchAll {
+					// gin keeps the catch-all's leading slash
+					v = strings.TrimPrefix(v, "/")
+				}
+
+				return v
+			})
+
+			h.ServeHTTP(c.Writer, router.WithParams(c.Request, params))
+		}
+
+		for _, pattern := range route.Patterns(router.GinSyntax) {
+			g.Handle(route.Method, pattern, handler)
+		}
+	}
+}
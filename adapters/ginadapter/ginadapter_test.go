@@ -0,0 +1,20 @@
+package ginadapter
+
+import (
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/xraph/forgeui/internal/adaptertest"
+)
+
+func TestMount(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	app := adaptertest.NewApp()
+
+	g := gin.New()
+	Mount(g, app)
+
+	adaptertest.Run(t, app, g)
+}
@@ -3,6 +3,8 @@ package forgeui
 import (
 	"context"
 	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -11,9 +13,16 @@ import (
 	"github.com/a-h/templ"
 
 	"github.com/xraph/forgeui/assets"
+	"github.com/xraph/forgeui/auth"
 	"github.com/xraph/forgeui/bridge"
+	"github.com/xraph/forgeui/csrf"
+	"github.com/xraph/forgeui/i18n"
+	"github.com/xraph/forgeui/logging"
+	"github.com/xraph/forgeui/metrics"
 	"github.com/xraph/forgeui/router"
+	"github.com/xraph/forgeui/session"
 	"github.com/xraph/forgeui/theme"
+	"github.com/xraph/forgeui/tracing"
 )
 
 // App is the main ForgeUI application with enhanced features
@@ -24,8 +33,12 @@ type App struct {
 	bridge     *bridge.Bridge
 	lightTheme *theme.Theme
 	darkTheme  *theme.Theme
-	staticPath string
 	cssBuilt   bool // true when CSS was compiled via Tailwind CLI
+	lifecycle  lifecycle
+
+	parent      *App   // the app this one is mounted on (see Mount)
+	mountPrefix string // the prefix it is mounted at
+	mounts      []*App // the apps mounted on this one
 }
 
 // New creates a new ForgeUI application with enhanced initialization
@@ -35,39 +48,83 @@ func New(opts ...AppOption) *App {
 		opt(config)
 	}
 
-	// Build full static path with base path if provided
-	staticPath := config.StaticPath
-	if config.BasePath != "" {
-		staticPath = config.BasePath + config.StaticPath
+	if config.EnableCSRF && config.SessionStore == nil {
+		panic("forgeui: WithCSRF requires WithSessions")
 	}
 
 	// Initialize asset manager
 	assetManager := assets.NewManager(assets.Config{
 		PublicDir:  config.AssetPublicDir,
 		OutputDir:  config.AssetOutputDir,
-		StaticPath: staticPath,
+		StaticPath: config.BasePath + config.StaticPath,
 		IsDev:      config.Debug,
 		Manifest:   config.AssetManifest,
 		FileSystem: config.AssetFileSystem,
+		Logger:     config.Logger,
 	})
 
 	// Initialize router (pass basePath so page routes are prefixed correctly)
-	r := router.New(router.WithBasePath(config.BasePath))
+	routerOpts := []router.RouterOption{router.WithBasePath(config.BasePath)}
+	if config.BufferedRendering {
+		routerOpts = append(routerOpts, router.WithBuffering())
+	}
+
+	if config.Logger != nil {
+		routerOpts = append(routerOpts, router.WithLogger(config.Logger))
+	}
+
+	if config.LoginPath != "" {
+		routerOpts = append(routerOpts, router.WithLoginPath(config.LoginPath))
+	}
+
+	if config.Tracer != nil {
+		routerOpts = append(routerOpts, router.WithTracer(config.Tracer))
+	}
+
+	if config.MetricsPath != "" && config.Metrics == nil {
+		config.Metrics = metrics.NewRegistry()
+	}
+
+	if config.Metrics != nil {
+		routerOpts = append(routerOpts, router.WithMetrics(config.Metrics))
+	}
+
+	r := router.New(routerOpts...)
 	if config.DefaultLayout != "" {
 		r.SetDefaultLayout(config.DefaultLayout)
 	}
 
+	if config.SessionStore != nil {
+		r.Use(router.Sessions(config.SessionStore))
+	}
+
 	// Initialize bridge if enabled
 	var b *bridge.Bridge
 
 	if config.EnableBridge {
+		var bridgeOpts []bridge.ConfigOption
 		if config.BridgeConfig != nil {
-			b = bridge.New(func(c *bridge.Config) {
+			bridgeOpts = append(bridgeOpts, func(c *bridge.Config) {
 				*c = *config.BridgeConfig
 			})
-		} else {
-			b = bridge.New()
 		}
+
+		// The app's logger, tracer and metrics unless the bridge has its own
+		bridgeOpts = append(bridgeOpts, func(c *bridge.Config) {
+			if c.Logger == nil {
+				c.Logger = config.Logger
+			}
+
+			if c.Tracer == nil {
+				c.Tracer = config.Tracer
+			}
+
+			if c.Metrics == nil {
+				c.Metrics = config.Metrics
+			}
+		})
+
+		b = bridge.New(bridgeOpts...)
 	}
 
 	app := &App{
@@ -77,12 +134,14 @@ func New(opts ...AppOption) *App {
 		bridge:     b,
 		lightTheme: config.LightTheme,
 		darkTheme:  config.DarkTheme,
-		staticPath: staticPath,
 	}
 
 	// Set app reference in router for PageContext
 	r.SetApp(app)
 
+	// Point the hot reload script at the app's endpoint
+	assetManager.SetReloadPath(app.Paths().HotReload())
+
 	return app
 }
 
@@ -106,6 +165,22 @@ func (a *App) Bridge() *bridge.Bridge {
 	return a.bridge
 }
 
+// Logger returns the application's logger (see WithLogger), or
+// slog.Default() when none is configured
+func (a *App) Logger() *slog.Logger {
+	if a.config.Logger != nil {
+		return a.config.Logger
+	}
+
+	return slog.Default()
+}
+
+// Metrics returns the metrics registry (see WithMetrics), or nil when
+// metrics are disabled
+func (a *App) Metrics() *metrics.Registry {
+	return a.config.Metrics
+}
+
 // HasBridge returns true if bridge system is enabled
 func (a *App) HasBridge() bool {
 	return a.bridge != nil
@@ -113,55 +188,57 @@ func (a *App) HasBridge() bool {
 
 // BridgeCallPath returns the full bridge call endpoint path
 func (a *App) BridgeCallPath() string {
-	if a.config.BasePath != "" {
-		return a.config.BasePath + "/bridge/call"
-	}
-	return "/api/bridge/call"
+	return a.Paths().BridgeCall()
 }
 
 // BridgeStreamPath returns the full bridge stream endpoint path
 func (a *App) BridgeStreamPath() string {
-	if a.config.BasePath != "" {
-		return a.config.BasePath + "/bridge/stream/"
-	}
-	return "/api/bridge/stream/"
+	return a.Paths().BridgeStream()
+}
+
+// BridgeWebSocketPath returns the full bridge WebSocket endpoint path
+func (a *App) BridgeWebSocketPath() string {
+	return a.Paths().BridgeWebSocket()
 }
 
 // BridgeScriptPath returns the full path to the bridge JavaScript file
 func (a *App) BridgeScriptPath() string {
-	return a.staticPath + "/js/forge-bridge.js"
+	return a.Paths().BridgeScript()
 }
 
 // AlpineBridgeScriptPath returns the full path to the Alpine bridge JavaScript file
 func (a *App) AlpineBridgeScriptPath() string {
-	return a.staticPath + "/js/alpine-bridge.js"
+	return a.Paths().AlpineBridgeScript()
 }
 
 // HotReloadPath returns the full hot reload SSE endpoint path
 func (a *App) HotReloadPath() string {
-	if a.config.BasePath != "" {
-		return a.config.BasePath + "/_forgeui/reload"
-	}
-	return "/_forgeui/reload"
+	return a.Paths().HotReload()
 }
 
 // BridgeScripts returns properly configured bridge script tags as a templ.Component.
-// This respects the BasePath configuration.
+// This respects the BasePath configuration. The bridge client gets the
+// request's CSRF token (see WithCSRF) when rendered; the optional csrfToken
+// argument predates WithCSRF and overrides it. Traced pages also hand the
+// client their traceparent, so its calls join the page trace.
 func (a *App) BridgeScripts(includeAlpine bool, csrfToken ...string) templ.Component {
 	if !a.HasBridge() {
 		return templ.NopComponent
 	}
 
-	token := ""
-	if len(csrfToken) > 0 {
-		token = csrfToken[0]
-	}
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		token := csrf.Token(ctx)
+		if len(csrfToken) > 0 && csrfToken[0] != "" {
+			token = csrfToken[0]
+		}
 
-	return bridge.BridgeScriptsExternal(bridge.ScriptConfig{
-		Endpoint:      a.BridgeCallPath(),
-		CSRFToken:     token,
-		IncludeAlpine: includeAlpine,
-		StaticPath:    a.staticPath,
+		return bridge.BridgeScriptsExternal(bridge.ScriptConfig{
+			Endpoint:      a.BridgeCallPath(),
+			CSRFToken:     token,
+			IncludeAlpine: includeAlpine,
+			StaticPath:    a.Paths().Static(),
+			TraceParent:   tracing.TraceParent(ctx),
+		}).Render(ctx, w)
 	})
 }
 
@@ -170,7 +247,7 @@ func (a *App) bridgeScriptHandler() http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Extract the script name from the URL path
 		// e.g., "/api/identity/ui/static/js/forge-bridge.js" -> "forge-bridge.js"
-		path := strings.TrimPrefix(r.URL.Path, a.staticPath+"/js/")
+		path := strings.TrimPrefix(r.URL.Path, a.Paths().Static()+"/js/")
 
 		var content string
 		var contentType string
@@ -196,6 +273,11 @@ func (a *App) bridgeScriptHandler() http.Handler {
 	})
 }
 
+// I18n returns the message bundle set with WithI18n, or nil.
+func (a *App) I18n() *i18n.Bundle {
+	return a.config.I18n
+}
+
 // Theme returns the light theme
 func (a *App) Theme() *theme.Theme {
 	return a.lightTheme
@@ -223,7 +305,7 @@ func (a *App) Initialize(ctx context.Context) error {
 	if a.lightTheme != nil && a.darkTheme != nil {
 		if err := a.buildThemeCSS(ctx); err != nil {
 			// Non-fatal: fall back to CDN mode
-			fmt.Printf("[ForgeUI] CSS build skipped: %v (using CDN fallback)\n", err)
+			a.Logger().WarnContext(ctx, "CSS build skipped, using CDN fallback", logging.KeyError, err.Error())
 		}
 	}
 
@@ -233,6 +315,21 @@ func (a *App) Initialize(ctx context.Context) error {
 		_ = a.Assets.FingerprintAll()
 	}
 
+	// Resolve and initialize plugins in dependency order
+	if a.HasPlugins() {
+		if err := a.config.Plugins.Initialize(ctx); err != nil {
+			return fmt.Errorf("plugin initialization failed: %w", err)
+		}
+	}
+
+	for _, sub := range a.mounts {
+		if err := sub.Initialize(ctx); err != nil {
+			return fmt.Errorf("mounted app %s: %w", sub.Paths().Base(), err)
+		}
+	}
+
+	a.lifecycle.ready.Store(true)
+
 	return nil
 }
 
@@ -279,6 +376,7 @@ func (a *App) buildThemeCSS(ctx context.Context) error {
 		OutputDir: outputDir,
 		IsDev:     a.IsDev(),
 		Minify:    !a.IsDev(),
+		Logger:    a.config.Logger,
 	}
 
 	if err := tp.Process(ctx, cfg); err != nil {
@@ -292,13 +390,13 @@ func (a *App) buildThemeCSS(ctx context.Context) error {
 
 // StaticPath returns the full URL path prefix for static assets.
 func (a *App) StaticPath() string {
-	return a.staticPath
+	return a.Paths().Static()
 }
 
 // CSSPath returns the URL path to the compiled CSS stylesheet.
 // This respects the BasePath and StaticPath configuration.
 func (a *App) CSSPath() string {
-	return a.staticPath + "/css/app.css"
+	return a.Paths().Static() + "/css/app.css"
 }
 
 // FontPreloadLinks returns a templ.Component that renders <link rel="preload">
@@ -343,55 +441,165 @@ func (a *App) ThemeStylesheet() templ.Component {
 }
 
 // Handler returns an http.Handler that serves the entire application
-// This includes static assets, bridge endpoints, and routed pages
+// This includes static assets, bridge endpoints, and routed pages.
+// When plugins are configured, the handler is wrapped in their middleware,
+// and with WithSessions, WithAuthenticator, WithCSRF and WithI18n in the
+// session, authentication, CSRF and locale detection middleware.
+// WithLogger and WithTracer store the logger and tracer in every request's
+// context, WithMetrics mounts the metrics endpoint and WithHealthChecks
+// the liveness and readiness probes. Every endpoint lives at the path Paths
+// resolves for it; apps added with Mount share the handler's ServeMux.
 func (a *App) Handler() http.Handler {
-	mux := http.NewServeMux()
+	m := &mountMux{mux: http.NewServeMux(), chains: make(map[string]http.Handler)}
+
+	a.register(m.mux, true)
+	m.root = a.Wrap(m.mux)
+
+	a.registerMounts(m)
 
-	// Build bridge path with base path if provided
-	bridgeCallPath := "/api/bridge/call"
-	bridgeStreamPath := "/api/bridge/stream/"
-	hotReloadPath := "/_forgeui/reload"
+	return m
+}
+
+// registerMounts registers the endpoints of the apps mounted on a, and on
+// them, behind their own middleware.
+func (a *App) registerMounts(m *mountMux) {
+	for _, sub := range a.mounts {
+		chain := sub.Wrap(m.mux)
 
-	if a.config.BasePath != "" {
-		bridgeCallPath = a.config.BasePath + "/bridge/call"
-		bridgeStreamPath = a.config.BasePath + "/bridge/stream/"
-		hotReloadPath = a.config.BasePath + "/_forgeui/reload"
+		for _, pattern := range sub.register(m.mux, false) {
+			m.chains[pattern] = chain
+		}
+
+		sub.registerMounts(m)
+	}
+}
+
+// register adds the app's endpoints to mux at the paths resolved by Paths
+// and returns their patterns. The root app's router serves every request
+// no other endpoint matches; a mounted app's router serves its base path.
+func (a *App) register(mux *http.ServeMux, root bool) []string {
+	paths := a.Paths()
+
+	var patterns []string
+
+	handle := func(pattern string, handler http.Handler) {
+		mux.Handle(pattern, handler)
+		patterns = append(patterns, pattern)
 	}
 
 	// Serve embedded bridge client scripts if bridge is enabled
 	if a.HasBridge() {
-		mux.Handle(a.staticPath+"/js/forge-bridge.js", a.bridgeScriptHandler())
-		mux.Handle(a.staticPath+"/js/alpine-bridge.js", a.bridgeScriptHandler())
+		handle(paths.BridgeScript(), a.bridgeScriptHandler())
+		handle(paths.AlpineBridgeScript(), a.bridgeScriptHandler())
 	}
 
 	// Serve static assets
-	mux.Handle(a.staticPath+"/", a.Assets.Handler())
+	handle(paths.Static()+"/", a.Assets.Handler())
 
 	// Serve bridge endpoints if enabled
 	if a.HasBridge() {
-		mux.Handle(bridgeCallPath, a.bridge.Handler())
-		mux.Handle(bridgeStreamPath, a.bridge.StreamHandler())
+		handle(paths.BridgeCall(), a.bridge.Handler())
+		handle(paths.BridgeStream(), a.bridge.StreamHandler())
+
+		if a.bridge.GetConfig().EnableWebSocket {
+			handle(paths.BridgeWebSocket(), a.bridge.WebSocketHandler())
+		}
+	}
+
+	// Serve liveness and readiness probes, which mounted apps leave to the
+	// root app
+	if path := paths.Health(); path != "" {
+		handle(path, a.healthHandler())
+	}
+
+	if path := paths.Ready(); path != "" {
+		handle(path, a.readyHandler())
+	}
+
+	// Serve metrics in the Prometheus text format
+	if path := paths.Metrics(); path != "" {
+		handle(path, a.config.Metrics.Handler())
 	}
 
 	// Serve SSE endpoint for hot reload in dev mode
 	if a.IsDev() {
 		if handler := a.Assets.SSEHandler(); handler != nil {
-			mux.Handle(hotReloadPath, handler.(http.Handler))
+			handle(paths.HotReload(), handler.(http.Handler))
 		}
 	}
 
-	// Serve all other requests through router.
-	// When BasePath is set, mux routes (static, bridge, SSE) already include the
-	// basePath prefix and match before this catch-all. Page routes, however, are
-	// registered as relative paths (e.g., "/", "/health"). Strip the basePath
-	// so the router sees relative paths that match its compiled patterns.
-	if a.config.BasePath != "" {
-		mux.Handle("/", http.StripPrefix(a.config.BasePath, a.router))
+	// Serve all other requests through the router. Its routes are
+	// registered under the base path already, so it sees full paths.
+	if root {
+		handle("/", a.router)
 	} else {
-		mux.Handle("/", a.router)
+		handle(paths.Base(), a.router)
+		handle(paths.Base()+"/", a.router)
 	}
 
-	return mux
+	return patterns
+}
+
+// Wrap wraps next in the app's middleware, as Handler does: plugins, the
+// bridge for bridge.Publish, locale detection, CSRF protection,
+// authentication, sessions, and the logger and tracer. Use it for app
+// pages served by another router (see Router.RouteHandler).
+func (a *App) Wrap(next http.Handler) http.Handler {
+	handler := next
+
+	// Wrap everything in the middleware plugin chain
+	if a.HasPlugins() {
+		handler = a.config.Plugins.WrapHandler(handler)
+	}
+
+	// Carry the bridge so pages and plugins publish with bridge.Publish
+	if a.HasBridge() {
+		next := handler
+		handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(bridge.ContextWithBridge(r.Context(), a.bridge)))
+		})
+	}
+
+	// Detect the locale for plugins and every endpoint, reading the URL
+	// prefix after the app's base path
+	if a.config.I18n != nil {
+		opts := append([]i18n.Option{i18n.WithBasePath(a.Paths().Base())}, a.config.I18nOptions...)
+		handler = i18n.Middleware(a.config.I18n, opts...)(handler)
+	}
+
+	// CSRF validation runs before plugins and every endpoint. Bridge
+	// streams run functions on GET, whatever their Accept header says. The
+	// hot reload stream changes nothing, and EventSource can't send a token.
+	if a.config.EnableCSRF {
+		opts := a.config.CSRFOptions
+		if a.HasBridge() {
+			opts = append([]csrf.Option{csrf.WithProtectedPaths(a.Paths().BridgeStream())}, opts...)
+		}
+
+		if a.IsDev() {
+			opts = append([]csrf.Option{csrf.WithExemptPaths(a.Paths().HotReload())}, opts...)
+		}
+
+		handler = csrf.Protect(opts...)(handler)
+	}
+
+	// Resolve the user once the session is loaded, for pages, the bridge
+	// and plugins alike
+	if a.config.Authenticator != nil {
+		handler = auth.Middleware(a.config.Authenticator)(handler)
+	}
+
+	// Load the session so plugin middleware can use it too
+	if a.config.SessionStore != nil {
+		handler = session.Middleware(a.config.SessionStore)(handler)
+	}
+
+	// The logger and tracer go outermost so every layer logs and traces
+	// through them
+	handler = tracing.Middleware(a.config.Tracer)(handler)
+	handler = logging.Middleware(a.config.Logger)(handler)
+
+	return handler
 }
 
 // Use adds global middleware (convenience method)
@@ -1,6 +1,7 @@
 package forgeui
 
 import (
+	"github.com/xraph/forgeui/auth"
 	"github.com/xraph/forgeui/router"
 )
 
@@ -16,6 +17,13 @@ type PageBuilder struct {
 	meta       *router.RouteMeta
 	name       string
 	noLayout   bool
+	buffered   bool
+	params     router.ParamEnumerator
+	json       bool
+	partials   bool
+	fragments  []pageFragment
+	policies   []auth.Policy
+	actions    map[string]*Action
 }
 
 // Handler sets the page handler function
@@ -76,6 +84,55 @@ func (pb *PageBuilder) Name(name string) *PageBuilder {
 	return pb
 }
 
+// Buffered renders this page into a buffer before sending it, so render
+// errors produce an error page and responses get an ETag and Content-Length
+func (pb *PageBuilder) Buffered() *PageBuilder {
+	pb.buffered = true
+	return pb
+}
+
+// StaticParams sets the parameter sets this page is rendered with during
+// static export (see App.Export)
+func (pb *PageBuilder) StaticParams(fn router.ParamEnumerator) *PageBuilder {
+	pb.params = fn
+	return pb
+}
+
+// Authorize applies an auth.Policy to this page and its form actions;
+// several policies must all allow a request (see router.Authorize)
+func (pb *PageBuilder) Authorize(policy auth.Policy) *PageBuilder {
+	pb.policies = append(pb.policies, policy)
+	return pb
+}
+
+// RequireRoles allows only users with at least one of roles
+// (see auth.Roles)
+func (pb *PageBuilder) RequireRoles(roles ...string) *PageBuilder {
+	return pb.Authorize(auth.Roles(roles...))
+}
+
+// JSON answers requests preferring application/json with the loader data
+// instead of the page (see router.Route.WithJSON)
+func (pb *PageBuilder) JSON() *PageBuilder {
+	pb.json = true
+	return pb
+}
+
+// HTMXPartials renders this page without its layouts for HTMX requests
+// (see router.Route.WithHTMXPartials)
+func (pb *PageBuilder) HTMXPartials() *PageBuilder {
+	pb.partials = true
+	return pb
+}
+
+// Fragment registers a named sub-component of this page, served for
+// ?_fragment=name or an HTMX request targeting one of targets
+// (see router.Route.WithFragment)
+func (pb *PageBuilder) Fragment(name string, handler router.PageHandler, targets ...string) *PageBuilder {
+	pb.fragments = append(pb.fragments, pageFragment{name: name, handler: handler, targets: targets})
+	return pb
+}
+
 // Method sets the HTTP method for this page
 func (pb *PageBuilder) Method(method string) *PageBuilder {
 	pb.method = method
@@ -106,6 +163,37 @@ func (pb *PageBuilder) Register() *router.Route {
 		route = pb.app.router.Get(pb.pattern, pb.handler)
 	}
 
+	pb.configure(route)
+
+	// Form actions handle POST requests to the same path
+	if len(pb.actions) > 0 {
+		pb.configure(pb.app.router.Post(pb.pattern, pb.actionHandler()))
+	}
+
+	// Apply content negotiation if set
+	if pb.json {
+		route.WithJSON()
+	}
+
+	if pb.partials {
+		route.WithHTMXPartials()
+	}
+
+	for _, f := range pb.fragments {
+		route.WithFragment(f.name, f.handler, f.targets...)
+	}
+
+	// Apply name if set
+	if pb.name != "" {
+		pb.app.router.Name(pb.name, route)
+	}
+
+	return route
+}
+
+// configure applies the builder's loader, layout, metadata, middleware and
+// rendering options to a route
+func (pb *PageBuilder) configure(route *router.Route) {
 	// Apply loader if set
 	if pb.loader != nil {
 		route.WithLoader(pb.loader)
@@ -123,17 +211,25 @@ func (pb *PageBuilder) Register() *router.Route {
 		route.WithMeta(pb.meta)
 	}
 
+	// Apply authorization before the page's own middleware
+	if len(pb.policies) > 0 {
+		route.Authorize(auth.All(pb.policies...))
+	}
+
 	// Apply middleware if set
 	if len(pb.middleware) > 0 {
 		route.WithMiddleware(pb.middleware...)
 	}
 
-	// Apply name if set
-	if pb.name != "" {
-		pb.app.router.Name(pb.name, route)
+	// Apply buffered rendering if set
+	if pb.buffered {
+		route.WithBuffering()
 	}
 
-	return route
+	// Apply static export params if set
+	if pb.params != nil {
+		route.StaticParams(pb.params)
+	}
 }
 
 // GET is a convenience method to set method to GET
@@ -165,3 +261,10 @@ func (pb *PageBuilder) DELETE() *PageBuilder {
 	pb.method = "DELETE"
 	return pb
 }
+
+// pageFragment is a fragment registered with Fragment
+type pageFragment struct {
+	name    string
+	handler router.PageHandler
+	targets []string
+}
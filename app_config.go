@@ -2,9 +2,17 @@ package forgeui
 
 import (
 	"io/fs"
+	"log/slog"
+	"time"
 
+	"github.com/xraph/forgeui/auth"
 	"github.com/xraph/forgeui/bridge"
+	"github.com/xraph/forgeui/csrf"
+	"github.com/xraph/forgeui/i18n"
+	"github.com/xraph/forgeui/metrics"
+	"github.com/xraph/forgeui/session"
 	"github.com/xraph/forgeui/theme"
+	"github.com/xraph/forgeui/tracing"
 )
 
 // AppConfig holds enhanced ForgeUI application configuration
@@ -58,6 +66,62 @@ type AppConfig struct {
 	// Default: "/static"
 	StaticPath string
 
+	// BufferedRendering renders every page into a buffer before sending it
+	// (see router.WithBuffering)
+	BufferedRendering bool
+
+	// Plugins is the plugin registry driven by the App (optional).
+	// Typically a *plugin.Registry.
+	Plugins PluginRegistry
+
+	// SessionStore enables sessions for pages and bridge calls (optional)
+	SessionStore session.Store
+
+	// EnableCSRF enables CSRF protection for every endpoint (requires SessionStore)
+	EnableCSRF bool
+
+	// CSRFOptions configure CSRF protection
+	CSRFOptions []csrf.Option
+
+	// Authenticator resolves the user of pages and bridge calls (optional)
+	Authenticator auth.Authenticator
+
+	// LoginPath is the page anonymous requests denied by a policy are
+	// redirected to, relative to BasePath (see router.WithLoginPath)
+	LoginPath string
+
+	// I18n enables locale detection and message translation (optional)
+	I18n *i18n.Bundle
+
+	// I18nOptions configure locale detection
+	I18nOptions []i18n.Option
+
+	// Logger is used by the router, bridge and asset pipeline, and stored
+	// in every request's context (see logging.FromContext).
+	// Default: slog.Default()
+	Logger *slog.Logger
+
+	// Tracer traces page requests and bridge calls (see package tracing)
+	Tracer tracing.Tracer
+
+	// MetricsPath serves Metrics in the Prometheus text format when set
+	MetricsPath string
+
+	// Metrics collects router and bridge metrics. Default: a new registry
+	// when MetricsPath is set
+	Metrics *metrics.Registry
+
+	// HealthPath serves the liveness probe when set
+	HealthPath string
+
+	// ReadyPath serves the readiness probe when set. It fails until
+	// Initialize has finished and once Shutdown starts.
+	ReadyPath string
+
+	// ShutdownTimeout bounds how long Run waits for in-flight requests,
+	// streams and stop hooks when shutting down. Default: 30s
+	ShutdownTimeout time.Duration
+
 	// Component defaults (from legacy Config)
 	DefaultSize    Size
 	DefaultVariant Variant
@@ -82,6 +146,8 @@ func DefaultAppConfig() *AppConfig {
 		DefaultSize:    SizeMD,
 		DefaultVariant: VariantDefault,
 		DefaultRadius:  RadiusMD,
+
+		ShutdownTimeout: 30 * time.Second,
 	}
 }
 
@@ -148,6 +214,154 @@ func WithBridge(opts ...bridge.ConfigOption) AppOption {
 	}
 }
 
+// WithPlugins attaches a plugin registry to the App.
+// The App initializes the registry in Initialize, wraps Handler in its
+// middleware plugins and shuts it down in Shutdown.
+//
+// Example:
+//
+//	app := forgeui.New(forgeui.WithPlugins(
+//	    plugin.NewRegistry().Use(toast.New(), sortable.New()),
+//	))
+func WithPlugins(registry PluginRegistry) AppOption {
+	return func(c *AppConfig) { c.Plugins = registry }
+}
+
+// WithBuffering renders every page into a buffer before sending it.
+// Render errors then produce an error page instead of a truncated response,
+// and pages get a strong ETag, Content-Length and 304 Not Modified support.
+func WithBuffering() AppOption {
+	return func(c *AppConfig) { c.BufferedRendering = true }
+}
+
+// WithSessions enables sessions backed by store. Handler loads the session
+// once per request for pages, bridge calls and static files alike, so
+// PageContext.Session() and bridge.Context.Session() return the same object,
+// and pages take the pending flash messages before rendering.
+//
+// Example:
+//
+//	store, err := session.NewCookieStore(secret, session.WithSecure(true))
+//	app := forgeui.New(forgeui.WithSessions(store))
+func WithSessions(store session.Store) AppOption {
+	return func(c *AppConfig) { c.SessionStore = store }
+}
+
+// WithAuthenticator resolves the user of every request with a, after the
+// session is loaded, so PageContext.User() and bridge.Context.User() return
+// the same user. Pages, groups and bridge functions restrict access with
+// auth policies (see PageBuilder.Authorize and bridge.Authorize).
+//
+// Example:
+//
+//	app := forgeui.New(
+//	    forgeui.WithSessions(store),
+//	    forgeui.WithAuthenticator(auth.AuthenticatorFunc(userFromSession)),
+//	    forgeui.WithLoginPath("/login"),
+//	)
+func WithAuthenticator(a auth.Authenticator) AppOption {
+	return func(c *AppConfig) { c.Authenticator = a }
+}
+
+// WithLoginPath sets the login page anonymous requests are redirected to
+// when a policy denies them. HTMX and JSON requests get 401 instead.
+func WithLoginPath(path string) AppOption {
+	return func(c *AppConfig) { c.LoginPath = path }
+}
+
+// WithCSRF protects every page, form action and bridge endpoint against
+// cross-site request forgery with session-bound tokens (see csrf.Protect).
+// It requires WithSessions. Templates add the token with csrf.Field() and
+// csrf.HXHeaders(ctx); BridgeScripts passes it to the bridge client. The
+// hot reload stream of dev mode is exempt.
+//
+// Example:
+//
+//	app := forgeui.New(
+//	    forgeui.WithSessions(store),
+//	    forgeui.WithCSRF(csrf.WithTrustedOrigins("https://admin.example.com")),
+//	)
+func WithCSRF(opts ...csrf.Option) AppOption {
+	return func(c *AppConfig) {
+		c.EnableCSRF = true
+		c.CSRFOptions = append(c.CSRFOptions, opts...)
+	}
+}
+
+// WithI18n detects each request's locale from the URL prefix, locale
+// cookie or Accept-Language header (see i18n.Middleware), so pages, bridge
+// functions and templates translate messages with i18n.T(ctx, key, args).
+// Page titles and descriptions that are message keys are translated too.
+//
+// Example:
+//
+//	bundle := i18n.NewBundle("en")
+//	if err := bundle.LoadFS(localesFS, "locales"); err != nil {
+//	    log.Fatal(err)
+//	}
+//	app := forgeui.New(forgeui.WithI18n(bundle))
+func WithI18n(bundle *i18n.Bundle, opts ...i18n.Option) AppOption {
+	return func(c *AppConfig) {
+		c.I18n = bundle
+		c.I18nOptions = append(c.I18nOptions, opts...)
+	}
+}
+
+// WithLogger sets the structured logger used by every subsystem. Records
+// written while serving a request carry its request ID, route and bridge
+// function as attributes (see package logging).
+//
+// Example:
+//
+//	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+//	app := forgeui.New(forgeui.WithLogger(logger))
+//	app.Use(router.RequestID(), router.Logger())
+func WithLogger(l *slog.Logger) AppOption {
+	return func(c *AppConfig) { c.Logger = l }
+}
+
+// WithTracer traces requests: route matching, loaders, layouts, rendering
+// and bridge calls. Incoming traceparent headers are continued, and pages
+// pass theirs on to the bridge client (see BridgeScripts).
+//
+// Example:
+//
+//	exporter := tracing.NewInMemoryExporter()
+//	app := forgeui.New(forgeui.WithTracer(tracing.NewTracer(exporter)))
+func WithTracer(t tracing.Tracer) AppOption {
+	return func(c *AppConfig) { c.Tracer = t }
+}
+
+// WithMetrics serves Prometheus text format metrics on path: page requests
+// by route and status, loader and render latency, bridge calls by function
+// and error code, rate-limit rejections and open WebSocket/SSE connections.
+// App.Metrics returns the registry for custom metrics.
+//
+// Example:
+//
+//	app := forgeui.New(forgeui.WithMetrics("/metrics"), forgeui.WithBridge())
+func WithMetrics(path string) AppOption {
+	return func(c *AppConfig) { c.MetricsPath = path }
+}
+
+// WithHealthChecks serves the liveness and readiness probes on the given
+// paths; an empty path disables its probe. Apps added with Mount don't
+// serve probes, since the root app's already cover the process.
+//
+//	app := forgeui.New(forgeui.WithHealthChecks("/healthz", "/readyz"))
+func WithHealthChecks(healthPath, readyPath string) AppOption {
+	return func(c *AppConfig) {
+		c.HealthPath = healthPath
+		c.ReadyPath = readyPath
+	}
+}
+
+// WithShutdownTimeout bounds how long Run drains requests, bridge streams
+// and WebSocket connections and runs stop hooks after SIGTERM.
+func WithShutdownTimeout(d time.Duration) AppOption {
+	return func(c *AppConfig) { c.ShutdownTimeout = d }
+}
+
 // WithThemes sets the light and dark themes
 func WithThemes(light, dark *theme.Theme) AppOption {
 	return func(c *AppConfig) {
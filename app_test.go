@@ -1,11 +1,31 @@
 package forgeui
 
 import (
+	"bytes"
 	"context"
+	"io"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
+
+	"github.com/a-h/templ"
+	"nhooyr.io/websocket" //nolint:staticcheck // Library moved to github.com/coder/websocket - migration pending
+
+	"github.com/xraph/forgeui/auth"
+	"github.com/xraph/forgeui/bridge"
+	"github.com/xraph/forgeui/bridge/brokertest"
+	"github.com/xraph/forgeui/csrf"
+	"github.com/xraph/forgeui/i18n"
+	"github.com/xraph/forgeui/logging"
+	"github.com/xraph/forgeui/metrics"
+	"github.com/xraph/forgeui/router"
+	"github.com/xraph/forgeui/session"
+	"github.com/xraph/forgeui/tracing"
 )
 
 func TestApp_New(t *testing.T) {
@@ -243,3 +263,598 @@ func TestApp_BasePath(t *testing.T) {
 		})
 	}
 }
+
+func TestApp_WithSessions(t *testing.T) {
+	store := session.NewMemoryStore(time.Minute)
+	app := New(WithSessions(store), WithBridge(bridge.WithCSRF(false)))
+
+	app.Get("/login", func(ctx *router.PageContext) (templ.Component, error) {
+		ctx.Session().Set("user", "jane")
+		return templ.Raw("ok"), nil
+	})
+
+	_ = app.Bridge().Register("whoami", func(ctx bridge.Context, _ struct{}) (string, error) {
+		return ctx.Session().(*session.Session).GetString("user"), nil
+	})
+
+	handler := app.Handler()
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/login", nil))
+
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 {
+		t.Fatalf("Expected session cookie, got %d cookies", len(cookies))
+	}
+
+	req := httptest.NewRequest(http.MethodPost, app.BridgeCallPath(),
+		strings.NewReader(`{"jsonrpc":"2.0","id":"1","method":"whoami","params":{}}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.AddCookie(cookies[0])
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if !strings.Contains(w.Body.String(), `"result":"jane"`) {
+		t.Errorf("Expected the bridge call to see the page's session, got %s", w.Body.String())
+	}
+}
+
+func TestApp_WithAuthenticator(t *testing.T) {
+	store := session.NewMemoryStore(time.Minute)
+
+	app := New(
+		WithSessions(store),
+		WithAuthenticator(auth.AuthenticatorFunc(func(r *http.Request) (auth.User, error) {
+			id := session.FromContext(r.Context()).GetString("user")
+			if id == "" {
+				return nil, nil
+			}
+
+			return &bridge.SimpleUser{UserID: id, UserRoles: []string{"member"}}, nil
+		})),
+		WithLoginPath("/login"),
+		WithBridge(bridge.WithCSRF(false)),
+	)
+
+	app.Get("/login", func(ctx *router.PageContext) (templ.Component, error) {
+		ctx.Session().Set("user", "jane")
+		return templ.Raw("ok"), nil
+	})
+
+	app.Page("/account").
+		Handler(func(ctx *router.PageContext) (templ.Component, error) {
+			return templ.Raw("account of " + ctx.User().ID()), nil
+		}).
+		Authorize(auth.Authenticated()).
+		Register()
+
+	app.Page("/admin").
+		Handler(func(ctx *router.PageContext) (templ.Component, error) {
+			return templ.Raw("admin"), nil
+		}).
+		RequireRoles("admin").
+		Register()
+
+	_ = app.Bridge().Register("whoami", func(ctx bridge.Context, _ struct{}) (string, error) {
+		return ctx.User().ID(), nil
+	}, bridge.Authorize(auth.Authenticated()))
+
+	handler := app.Handler()
+
+	// Anonymous requests are sent to the login page
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/account", nil))
+
+	if w.Code != http.StatusSeeOther || w.Header().Get("Location") != "/login?next=%2Faccount" {
+		t.Errorf("Expected a redirect to the login page, got %d %q", w.Code, w.Header().Get("Location"))
+	}
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/login", nil))
+
+	cookie := w.Result().Cookies()[0]
+
+	tests := []struct {
+		path       string
+		wantStatus int
+		want       string
+	}{
+		{"/account", http.StatusOK, "account of jane"},
+		{"/admin", http.StatusForbidden, ""},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		req.AddCookie(cookie)
+
+		w = httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		if w.Code != tt.wantStatus {
+			t.Errorf("Expected status %d for %s, got %d", tt.wantStatus, tt.path, w.Code)
+		}
+
+		if tt.want != "" && w.Body.String() != tt.want {
+			t.Errorf("Expected %q for %s, got %q", tt.want, tt.path, w.Body.String())
+		}
+	}
+
+	// Bridge calls see the same user
+	req := httptest.NewRequest(http.MethodPost, app.BridgeCallPath(),
+		strings.NewReader(`{"jsonrpc":"2.0","id":"1","method":"whoami","params":{}}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.AddCookie(cookie)
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if !strings.Contains(w.Body.String(), `"result":"jane"`) {
+		t.Errorf("Expected the bridge call to see the page's user, got %s", w.Body.String())
+	}
+}
+
+func TestApp_WithCSRF(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected WithCSRF without WithSessions to panic")
+		}
+	}()
+
+	New(WithCSRF())
+}
+
+func TestApp_WithCSRFProtectsPagesAndBridge(t *testing.T) {
+	app := New(WithSessions(session.NewMemoryStore(time.Minute)), WithCSRF(), WithBridge())
+
+	app.Get("/", func(ctx *router.PageContext) (templ.Component, error) {
+		return templ.Raw(csrf.Token(ctx.Context())), nil
+	})
+
+	app.Post("/items", func(ctx *router.PageContext) (templ.Component, error) {
+		return templ.Raw("created"), nil
+	})
+
+	_ = app.Bridge().Register("ping", func(ctx bridge.Context, _ struct{}) (string, error) {
+		return "pong", nil
+	})
+
+	handler := app.Handler()
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	cookie := w.Result().Cookies()[0]
+	token := w.Body.String()
+
+	post := func(path, token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.AddCookie(cookie)
+
+		if token != "" {
+			req.Header.Set("X-CSRF-Token", token)
+		}
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		return w
+	}
+
+	if w := post("/items", "", ""); w.Code != http.StatusForbidden {
+		t.Errorf("Expected page POST without token to be rejected, got %d", w.Code)
+	}
+
+	if w := post("/items", token, ""); w.Body.String() != "created" {
+		t.Errorf("Expected page POST with token to pass, got %d %q", w.Code, w.Body.String())
+	}
+
+	call := `{"jsonrpc":"2.0","id":"1","method":"ping","params":{}}`
+
+	if w := post(app.BridgeCallPath(), "", call); w.Code != http.StatusForbidden {
+		t.Errorf("Expected bridge call without token to be rejected, got %d", w.Code)
+	}
+
+	// The session token replaces the bridge's double-submit cookie
+	if w := post(app.BridgeCallPath(), token, call); !strings.Contains(w.Body.String(), `"result":"pong"`) {
+		t.Errorf("Expected bridge call with token to pass, got %s", w.Body.String())
+	}
+}
+
+func TestApp_WithCSRFProtectsBridgeStreams(t *testing.T) {
+	app := New(WithSessions(session.NewMemoryStore(time.Minute)), WithCSRF(), WithBridge())
+
+	app.Get("/", func(ctx *router.PageContext) (templ.Component, error) {
+		return templ.Raw(csrf.Token(ctx.Context())), nil
+	})
+
+	var calls atomic.Int32
+
+	_ = app.Bridge().Register("delete", func(ctx bridge.Context, _ struct{}) (string, error) {
+		calls.Add(1)
+		return "deleted", nil
+	})
+
+	handler := app.Handler()
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	cookie := w.Result().Cookies()[0]
+	token := w.Body.String()
+
+	stream := func(query string) *httptest.ResponseRecorder {
+		// A top-level navigation from another site: no Accept header for
+		// event streams, but the session cookie rides along
+		req := httptest.NewRequest(http.MethodGet, app.Paths().BridgeStream()+"delete?params={}"+query, nil)
+		req.AddCookie(cookie)
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		return w
+	}
+
+	if w := stream(""); w.Code != http.StatusForbidden || calls.Load() != 0 {
+		t.Errorf("Expected a stream without token to be rejected, got %d after %d calls", w.Code, calls.Load())
+	}
+
+	if w := stream("&_csrf=" + url.QueryEscape(token)); !strings.Contains(w.Body.String(), "deleted") {
+		t.Errorf("Expected a stream with token to pass, got %d %q", w.Code, w.Body.String())
+	}
+}
+
+func TestApp_BridgeScriptsUsesRequestToken(t *testing.T) {
+	app := New(WithBridge())
+
+	s := session.New()
+	ctx := session.NewContext(context.Background(), s)
+
+	var buf strings.Builder
+	if err := app.BridgeScripts(false).Render(ctx, &buf); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(buf.String(), "setCSRF(") {
+		t.Errorf("Expected the request's CSRF token in the bridge scripts, got %s", buf.String())
+	}
+}
+
+func TestApp_WithCSRFExemptsHotReload(t *testing.T) {
+	app := New(WithDev(true), WithBasePath("/app"), WithSessions(session.NewMemoryStore(time.Minute)), WithCSRF())
+
+	req := httptest.NewRequest(http.MethodGet, app.Paths().HotReload(), nil)
+	req.Header.Set("Accept", "text/event-stream")
+
+	w := httptest.NewRecorder()
+	app.Handler().ServeHTTP(w, req)
+
+	if w.Code == http.StatusForbidden {
+		t.Error("Expected the hot reload stream to skip CSRF validation")
+	}
+}
+
+func TestApp_WithI18n(t *testing.T) {
+	bundle := i18n.NewBundle("en")
+	_ = bundle.AddMessages("en", map[string]any{"home": map[string]any{"title": "Home"}})
+	_ = bundle.AddMessages("de", map[string]any{"home": map[string]any{"title": "Startseite"}})
+
+	app := New(WithI18n(bundle))
+
+	if app.I18n() != bundle {
+		t.Error("Expected I18n() to return the bundle")
+	}
+
+	page := func(ctx *router.PageContext) (templ.Component, error) {
+		return templ.Raw(ctx.Locale() + ":" + ctx.Meta.Title), nil
+	}
+
+	app.Get("/", page).Title("home.title")
+	app.Router().Group("", router.LocalePrefix("en", "de")).Get("/home", page).Title("home.title")
+
+	tests := []struct {
+		name   string
+		path   string
+		accept string
+		want   string
+	}{
+		{"default locale", "/", "", "en:Home"},
+		{"Accept-Language", "/", "de-DE,de;q=0.9", "de:Startseite"},
+		{"URL prefix", "/de/home", "en", "de:Startseite"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept-Language", tt.accept)
+			}
+
+			w := httptest.NewRecorder()
+			app.Handler().ServeHTTP(w, req)
+
+			if w.Body.String() != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, w.Body.String())
+			}
+
+			if lang := w.Header().Get("Content-Language"); !strings.HasPrefix(tt.want, lang+":") {
+				t.Errorf("Unexpected Content-Language %q", lang)
+			}
+		})
+	}
+}
+
+func TestApp_WithI18nBasePath(t *testing.T) {
+	bundle := i18n.NewBundle("en")
+	_ = bundle.AddMessages("de", map[string]any{"home": map[string]any{"title": "Startseite"}})
+
+	app := New(WithBasePath("/app"), WithI18n(bundle))
+	app.Router().Group("", router.LocalePrefix("en", "de")).Get("/about", func(ctx *router.PageContext) (templ.Component, error) {
+		return templ.Raw(ctx.Locale() + ":" + i18n.T(ctx.Context(), "home.title")), nil
+	})
+
+	w := httptest.NewRecorder()
+	app.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/app/de/about", nil))
+
+	if w.Body.String() != "de:Startseite" {
+		t.Errorf("Expected the locale of the URL prefix after the base path, got %q", w.Body.String())
+	}
+
+	if lang := w.Header().Get("Content-Language"); lang != "de" {
+		t.Errorf("Expected Content-Language de, got %q", lang)
+	}
+}
+
+func TestApp_WithLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	app := New(WithLogger(logger), WithBridge(bridge.WithCSRF(false)))
+
+	if app.Logger() != logger {
+		t.Error("Expected Logger() to return the configured logger")
+	}
+
+	if app.Bridge().GetConfig().Logger != logger {
+		t.Error("Expected the bridge to use the app's logger")
+	}
+
+	app.Use(router.Logger())
+	app.Get("/", func(ctx *router.PageContext) (templ.Component, error) {
+		logging.FromContext(ctx.Context()).Info("rendering home")
+		return templ.Raw("home"), nil
+	})
+
+	app.Handler().ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	for _, msg := range []string{`"msg":"rendering home"`, `"msg":"request"`} {
+		if !strings.Contains(buf.String(), msg) {
+			t.Errorf("Expected a %s record in %q", msg, buf.String())
+		}
+	}
+}
+
+func TestApp_WithTracer(t *testing.T) {
+	exporter := tracing.NewInMemoryExporter()
+	tracer := tracing.NewTracer(exporter)
+
+	app := New(WithTracer(tracer), WithBridge(bridge.WithCSRF(false)))
+
+	if app.Bridge().GetConfig().Tracer != tracer {
+		t.Error("Expected the bridge to use the app's tracer")
+	}
+
+	app.Get("/", func(ctx *router.PageContext) (templ.Component, error) {
+		return app.BridgeScripts(false), nil
+	})
+
+	w := httptest.NewRecorder()
+	app.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	requests := exporter.Named("router.request")
+	if len(requests) != 1 {
+		t.Fatalf("Expected 1 router.request span, got %d", len(requests))
+	}
+
+	// The client joins the page trace
+	want := "window.bridge.setTraceParent(\"00-" + requests[0].TraceID.String()
+	if !strings.Contains(w.Body.String(), want) {
+		t.Errorf("Expected %s in %q", want, w.Body.String())
+	}
+}
+
+func TestApp_WithMetrics(t *testing.T) {
+	app := New(WithMetrics("/metrics"), WithBridge(bridge.WithCSRF(false)))
+
+	if app.Metrics() == nil {
+		t.Fatal("Expected a metrics registry")
+	}
+
+	if app.Bridge().GetConfig().Metrics != app.Metrics() {
+		t.Error("Expected the bridge to use the app's registry")
+	}
+
+	app.Get("/", func(ctx *router.PageContext) (templ.Component, error) {
+		return templ.Raw("home"), nil
+	})
+
+	handler := app.Handler()
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if w.Header().Get("Content-Type") != metrics.ContentType {
+		t.Errorf("Expected Content-Type %s, got %s", metrics.ContentType, w.Header().Get("Content-Type"))
+	}
+
+	want := `forgeui_http_requests_total{method="GET",route="/",status="200"} 1`
+	if !strings.Contains(w.Body.String(), want) {
+		t.Errorf("Expected %s in %q", want, w.Body.String())
+	}
+}
+
+func TestApp_PageNegotiation(t *testing.T) {
+	app := New()
+	app.Router().RegisterLayout("main", func(ctx *router.PageContext, content templ.Component) templ.Component {
+		return templ.ComponentFunc(func(c context.Context, w io.Writer) error {
+			_, _ = io.WriteString(w, "<main>")
+			_ = content.Render(c, w)
+			_, err := io.WriteString(w, "</main>")
+
+			return err
+		})
+	})
+
+	app.Page("/stats").
+		Handler(func(ctx *router.PageContext) (templ.Component, error) {
+			return templ.Raw("page"), nil
+		}).
+		Loader(func(ctx context.Context, params router.Params) (any, error) {
+			return map[string]int{"visits": 3}, nil
+		}).
+		Layout("main").
+		JSON().
+		HTMXPartials().
+		Fragment("chart", func(ctx *router.PageContext) (templ.Component, error) {
+			return templ.Raw("chart"), nil
+		}, "chart").
+		Register()
+
+	tests := []struct {
+		name    string
+		target  string
+		headers map[string]string
+		want    string
+	}{
+		{"page", "/stats", nil, "<main>page</main>"},
+		{"json", "/stats", map[string]string{"Accept": "application/json"}, `{"data":{"visits":3},"meta":{"route":"/stats"}}` + "\n"},
+		{"htmx", "/stats", map[string]string{"HX-Request": "true"}, "page"},
+		{"fragment", "/stats?_fragment=chart", nil, "chart"},
+		{"hx-target", "/stats", map[string]string{"HX-Request": "true", "HX-Target": "chart"}, "chart"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+
+			w := httptest.NewRecorder()
+			app.Handler().ServeHTTP(w, req)
+
+			if w.Body.String() != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestApp_BridgeWebSocketPublish(t *testing.T) {
+	broker := brokertest.New()
+
+	app := New(WithBridge(bridge.WithWebSocket(true), bridge.WithBroker(broker)))
+	_ = app.Bridge().AuthorizeTopic("orders.{id}", nil)
+
+	app.Post("/orders/{id}/ship", func(ctx *router.PageContext) (templ.Component, error) {
+		if err := bridge.Publish(ctx.Context(), "orders."+ctx.Param("id"), map[string]string{"status": "shipped"}); err != nil {
+			return nil, err
+		}
+
+		return templ.Raw("shipped"), nil
+	})
+
+	srv := httptest.NewServer(app.Handler())
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	conn, _, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(srv.URL, "http")+app.BridgeWebSocketPath(), nil)
+	if err != nil {
+		t.Fatalf("Expected the WebSocket endpoint to be served, got %v", err)
+	}
+	defer func() { _ = conn.CloseNow() }()
+
+	_ = conn.Write(ctx, websocket.MessageText, []byte(`{"jsonrpc":"2.0","id":"1","method":"$subscribe","params":{"topic":"orders.42"}}`))
+
+	if _, data, err := conn.Read(ctx); err != nil || strings.Contains(string(data), "error") {
+		t.Fatalf("Expected subscription, got %s, %v", data, err)
+	}
+
+	resp, err := http.Post(srv.URL+"/orders/42/ship", "text/plain", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+
+	_, data, err := conn.Read(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(data), `"event":{"status":"shipped"}`) {
+		t.Errorf("Expected the published event, got %s", data)
+	}
+
+	events := broker.Events("orders.42")
+	if len(events) != 1 || string(events[0]) != `{"status":"shipped"}` {
+		t.Errorf("Expected the broker to record the event, got %s", events)
+	}
+}
+
+func TestApp_WithCSRFBridgeWebSocket(t *testing.T) {
+	app := New(WithSessions(session.NewMemoryStore(time.Minute)), WithCSRF(), WithBridge(bridge.WithWebSocket(true)))
+	_ = app.Bridge().AuthorizeTopic("orders.{id}", nil)
+
+	app.Get("/", func(ctx *router.PageContext) (templ.Component, error) {
+		return templ.Raw(csrf.Token(ctx.Context())), nil
+	})
+
+	srv := httptest.NewServer(app.Handler())
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	opts := &websocket.DialOptions{HTTPHeader: http.Header{"Cookie": {resp.Cookies()[0].String()}}}
+	endpoint := "ws" + strings.TrimPrefix(srv.URL, "http") + app.BridgeWebSocketPath()
+
+	if _, resp, err := websocket.Dial(ctx, endpoint, opts); err == nil || resp == nil || resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("Expected an upgrade without token to be rejected, got %v", err)
+	}
+
+	// The bridge client sends the token in the query, as for streams
+	conn, _, err := websocket.Dial(ctx, endpoint+"?_csrf="+url.QueryEscape(string(token)), opts)
+	if err != nil {
+		t.Fatalf("Expected an upgrade with token to pass, got %v", err)
+	}
+	defer func() { _ = conn.CloseNow() }()
+
+	_ = conn.Write(ctx, websocket.MessageText, []byte(`{"jsonrpc":"2.0","id":"1","method":"$subscribe","params":{"topic":"orders.42"}}`))
+
+	if _, data, err := conn.Read(ctx); err != nil || strings.Contains(string(data), "error") {
+		t.Errorf("Expected subscription, got %s, %v", data, err)
+	}
+}
+
+func TestApp_BridgeWebSocketDisabled(t *testing.T) {
+	app := New(WithBridge())
+
+	w := httptest.NewRecorder()
+	app.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, app.BridgeWebSocketPath(), nil))
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected no WebSocket endpoint by default, got %d", w.Code)
+	}
+}
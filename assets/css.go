@@ -11,7 +11,6 @@ import (
 
 // StyleSheet creates a <link> element for a CSS file
 func (m *Manager) StyleSheet(path string, opts ...StyleOption) templ.Component {
-	fmt.Println("StyleSheet", path)
 	cfg := &styleConfig{}
 	for _, opt := range opts {
 		opt(cfg)
@@ -2,15 +2,23 @@ package assets
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"os"
 	"sync"
 	"time"
 
 	"github.com/fsnotify/fsnotify"
+
+	"github.com/xraph/forgeui/logging"
 )
 
+// DefaultReloadPath is the URL path of the hot reload stream of an app
+// served at the root.
+const DefaultReloadPath = "/_forgeui/reload"
+
 // DevServer provides development features like hot reload and file watching.
 // It watches for file changes and notifies connected browsers via Server-Sent Events (SSE).
 type DevServer struct {
@@ -19,8 +27,10 @@ type DevServer struct {
 	sseClients []chan string
 	mu         sync.RWMutex
 	verbose    bool
+	logger     *slog.Logger
 	building   bool
 	buildMu    sync.Mutex
+	reloadPath string
 }
 
 // NewDevServer creates a new development server
@@ -30,10 +40,19 @@ func NewDevServer(pipeline *Pipeline) (*DevServer, error) {
 		return nil, fmt.Errorf("failed to create watcher: %w", err)
 	}
 
+	var logger *slog.Logger
+	if pipeline != nil {
+		logger = pipeline.config.Logger
+	}
+
+	watcher.SetLogger(logger)
+
 	return &DevServer{
 		pipeline:   pipeline,
 		watcher:    watcher,
 		sseClients: make([]chan string, 0),
+		logger:     logger,
+		reloadPath: DefaultReloadPath,
 	}, nil
 }
 
@@ -59,13 +78,12 @@ func (ds *DevServer) Start(ctx context.Context) error {
 		return fmt.Errorf("failed to setup watchers: %w", err)
 	}
 
-	fmt.Println("[DevServer] Hot reload enabled - watching Go files for changes")
-	fmt.Println("[DevServer] Edit any .go file to trigger reload")
+	ds.log().InfoContext(ctx, "hot reload enabled, edit any .go file to trigger reload")
 
 	// Start watcher in background
 	go func() {
 		if err := ds.watcher.Start(ctx); err != nil {
-			fmt.Printf("[DevServer] Watcher error: %v\n", err)
+			ds.log().ErrorContext(ctx, "file watcher stopped", logging.KeyError, err.Error())
 		}
 	}()
 
@@ -80,14 +98,10 @@ func (ds *DevServer) watchProjectDirs() error {
 	// Watch current directory for .go files only (non-recursive)
 	// This watches the example app source files
 	cwd, _ := os.Getwd()
-	if ds.verbose {
-		fmt.Printf("[DevServer] Watching current directory for Go files: %s\n", cwd)
-	}
+	ds.log().Log(context.Background(), progressLevel(ds.verbose), "watching current directory for Go files", "path", cwd)
 
 	if err := ds.watcher.AddPath("."); err != nil {
-		if ds.verbose {
-			fmt.Printf("[DevServer] Warning: Could not watch current directory: %v\n", err)
-		}
+		ds.log().Warn("could not watch current directory", "path", cwd, logging.KeyError, err.Error())
 	}
 
 	// Note: We deliberately DON'T watch static/ directory because:
@@ -117,22 +131,17 @@ func (ds *DevServer) onFileChange(ctx context.Context, event fsnotify.Event) err
 		ds.buildMu.Unlock()
 	}()
 
-	if ds.verbose {
-		fmt.Printf("[DevServer] Rebuilding due to: %s\n", event.Name)
-	}
+	level := progressLevel(ds.verbose)
+	ds.log().Log(ctx, level, "rebuilding assets", "path", event.Name)
 
 	// Rebuild pipeline
 	if err := ds.pipeline.Build(ctx); err != nil {
-		if ds.verbose {
-			fmt.Printf("[DevServer] Build failed: %v\n", err)
-		}
+		ds.log().ErrorContext(ctx, "asset build failed", "path", event.Name, logging.KeyError, err.Error())
 
 		return err
 	}
 
-	if ds.verbose {
-		fmt.Println("[DevServer] Build successful, reloading browsers...")
-	}
+	ds.log().Log(ctx, level, "asset build succeeded, reloading browsers")
 
 	// Notify all SSE clients
 	ds.notifyClients("reload")
@@ -185,17 +194,13 @@ func (ds *DevServer) SSEHandler() http.HandlerFunc {
 		_, _ = fmt.Fprintf(w, "data: connected\n\n")
 		w.(http.Flusher).Flush()
 
-		if ds.verbose {
-			fmt.Println("[DevServer] SSE client connected")
-		}
+		ds.log().Log(r.Context(), progressLevel(ds.verbose), "hot reload client connected")
 
 		// Listen for messages or client disconnect
 		for {
 			select {
 			case <-r.Context().Done():
-				if ds.verbose {
-					fmt.Println("[DevServer] SSE client disconnected")
-				}
+				ds.log().Log(context.Background(), progressLevel(ds.verbose), "hot reload client disconnected")
 
 				return
 
@@ -207,11 +212,23 @@ func (ds *DevServer) SSEHandler() http.HandlerFunc {
 	}
 }
 
+// SetReloadPath sets the URL path the hot reload script connects to
+func (ds *DevServer) SetReloadPath(path string) {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	ds.reloadPath = path
+}
+
 // HotReloadScript returns the client-side JavaScript for hot reload
 func (ds *DevServer) HotReloadScript() string {
+	ds.mu.RLock()
+	path, _ := json.Marshal(ds.reloadPath)
+	ds.mu.RUnlock()
+
 	return `<script>
 (function() {
-  const es = new EventSource('/_forgeui/reload');
+  const es = new EventSource(` + string(path) + `);
   
   es.onmessage = function(event) {
     if (event.data === 'reload') {
@@ -238,6 +255,19 @@ func (ds *DevServer) SetVerbose(verbose bool) {
 	}
 }
 
+// SetLogger sets the logger for the dev server and its watcher
+func (ds *DevServer) SetLogger(l *slog.Logger) {
+	ds.logger = l
+	if ds.watcher != nil {
+		ds.watcher.SetLogger(l)
+	}
+}
+
+// log returns the dev server's logger
+func (ds *DevServer) log() *slog.Logger {
+	return loggerOrDefault(ds.logger)
+}
+
 // Close stops the dev server and releases resources
 func (ds *DevServer) Close() error {
 	// Close all SSE clients
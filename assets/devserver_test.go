@@ -196,6 +196,23 @@ func TestDevServer_HotReloadScript(t *testing.T) {
 	}
 }
 
+func TestManager_SetReloadPath(t *testing.T) {
+	m := NewManager(Config{IsDev: true})
+
+	ds, err := NewDevServer(NewPipeline(PipelineConfig{IsDev: true}, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = ds.Close() }()
+
+	m.devServer = ds
+	m.SetReloadPath("/admin/_forgeui/reload")
+
+	if script := m.HotReloadScript(); !strings.Contains(script, `new EventSource("/admin/_forgeui/reload")`) {
+		t.Errorf("Expected the script to connect to the reload path, got %s", script)
+	}
+}
+
 func TestDevServer_Close(t *testing.T) {
 	pipeline := NewPipeline(PipelineConfig{IsDev: true}, nil)
 
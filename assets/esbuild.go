@@ -3,6 +3,7 @@ package assets
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -34,8 +35,12 @@ type ESBuildProcessor struct {
 	// External are packages to exclude from bundling
 	External []string
 
-	// Verbose enables detailed logging
+	// Verbose logs progress at Info instead of Debug
 	Verbose bool
+
+	// Logger receives the processor's logs.
+	// Default: ProcessorConfig.Logger, then slog.Default()
+	Logger *slog.Logger
 }
 
 // NewESBuildProcessor creates a new ESBuild processor with sensible defaults
@@ -60,21 +65,19 @@ func (ep *ESBuildProcessor) FileTypes() []string {
 
 // Process executes the ESBuild bundling
 func (ep *ESBuildProcessor) Process(ctx context.Context, cfg ProcessorConfig) error {
+	logger := ep.logger(cfg)
+	level := progressLevel(ep.Verbose)
+
 	// Check if esbuild is available
 	if !ep.isESBuildAvailable() {
-		if ep.Verbose {
-			fmt.Println("[ESBuild] ESBuild not found, skipping JavaScript bundling")
-			fmt.Println("[ESBuild] Install with: npm install -D esbuild")
-		}
+		logger.Log(ctx, level, "esbuild not found, skipping JavaScript bundling; install with: npm install -D esbuild")
 
 		return nil // Don't fail, just skip
 	}
 
 	// Validate entry points
 	if len(ep.EntryPoints) == 0 {
-		if ep.Verbose {
-			fmt.Println("[ESBuild] No entry points specified, skipping")
-		}
+		logger.Log(ctx, level, "no esbuild entry points specified, skipping")
 
 		return nil
 	}
@@ -139,17 +142,13 @@ func (ep *ESBuildProcessor) Process(ctx context.Context, cfg ProcessorConfig) er
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 
-	if ep.Verbose {
-		fmt.Printf("[ESBuild] Running: npx %s\n", strings.Join(args, " "))
-	}
+	logger.Log(ctx, level, "running esbuild", "command", "npx "+strings.Join(args, " "))
 
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("esbuild failed: %w", err)
 	}
 
-	if ep.Verbose {
-		fmt.Printf("[ESBuild] Generated bundle: %s\n", outfile)
-	}
+	logger.Log(ctx, level, "generated bundle", "path", outfile)
 
 	return nil
 }
@@ -212,3 +211,18 @@ func (ep *ESBuildProcessor) WithVerbose(verbose bool) *ESBuildProcessor {
 	ep.Verbose = verbose
 	return ep
 }
+
+// WithLogger sets the processor's logger
+func (ep *ESBuildProcessor) WithLogger(l *slog.Logger) *ESBuildProcessor {
+	ep.Logger = l
+	return ep
+}
+
+// logger returns the processor's logger, falling back to cfg.Logger.
+func (ep *ESBuildProcessor) logger(cfg ProcessorConfig) *slog.Logger {
+	if ep.Logger != nil {
+		return ep.Logger
+	}
+
+	return loggerOrDefault(cfg.Logger)
+}
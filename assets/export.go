@@ -0,0 +1,92 @@
+package assets
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Export copies every asset into dir, the directory served at the static
+// path, for hosting without a Go server. In production mode each file is
+// written under both its plain and fingerprinted name, and the mappings are
+// written to dir/manifest.json and returned.
+func (m *Manager) Export(dir string) (Manifest, error) {
+	manifest := make(Manifest)
+
+	err := fs.WalkDir(m.fileSystem, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			return nil
+		}
+
+		path = filepath.ToSlash(path)
+
+		if err := m.copyAsset(path, filepath.Join(dir, filepath.FromSlash(path))); err != nil {
+			return err
+		}
+
+		if m.isDev {
+			return nil
+		}
+
+		fp := m.fingerprint(path)
+		manifest.Set(path, fp)
+
+		m.mu.Lock()
+		m.fingerprints[path] = fp
+		m.mu.Unlock()
+
+		if fp == path {
+			return nil
+		}
+
+		return m.copyAsset(path, filepath.Join(dir, filepath.FromSlash(fp)))
+	})
+	if err != nil {
+		return nil, fmt.Errorf("assets: export: %w", err)
+	}
+
+	if m.isDev {
+		return manifest, nil
+	}
+
+	if err := manifest.Save(filepath.Join(dir, "manifest.json")); err != nil {
+		return nil, fmt.Errorf("assets: export: %w", err)
+	}
+
+	return manifest, nil
+}
+
+// copyAsset copies path from the asset filesystem to dst.
+func (m *Manager) copyAsset(path, dst string) error {
+	if !isValidPath(path) {
+		return fmt.Errorf("invalid asset path %q", path)
+	}
+
+	src, err := m.fileSystem.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = src.Close() }()
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, src); err != nil {
+		_ = out.Close()
+		return err
+	}
+
+	return out.Close()
+}
@@ -0,0 +1,77 @@
+package assets
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func TestManager_Export(t *testing.T) {
+	fsys := fstest.MapFS{
+		"css/app.css": {Data: []byte("body{}")},
+		"logo.svg":    {Data: []byte("<svg/>")},
+	}
+
+	tests := []struct {
+		name      string
+		isDev     bool
+		wantFiles int
+	}{
+		{name: "production", isDev: false, wantFiles: 5},
+		{name: "development", isDev: true, wantFiles: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewManager(Config{FileSystem: fsys, IsDev: tt.isDev})
+			dir := t.TempDir()
+
+			manifest, err := m.Export(dir)
+			if err != nil {
+				t.Fatalf("Export() error = %v", err)
+			}
+
+			files := 0
+
+			_ = filepath.WalkDir(dir, func(_ string, d os.DirEntry, _ error) error {
+				if !d.IsDir() {
+					files++
+				}
+
+				return nil
+			})
+
+			if files != tt.wantFiles {
+				t.Errorf("Expected %d files, got %d", tt.wantFiles, files)
+			}
+
+			if tt.isDev {
+				if len(manifest) != 0 {
+					t.Errorf("Expected no manifest in development, got %v", manifest)
+				}
+
+				return
+			}
+
+			fp, ok := manifest.Get("css/app.css")
+			if !ok || fp == "css/app.css" {
+				t.Fatalf("Expected a fingerprinted entry, got %q", fp)
+			}
+
+			data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(fp)))
+			if err != nil || string(data) != "body{}" {
+				t.Errorf("Expected fingerprinted copy, got %q (%v)", data, err)
+			}
+
+			saved, err := LoadManifest(filepath.Join(dir, "manifest.json"))
+			if err != nil || saved["logo.svg"] != manifest["logo.svg"] {
+				t.Errorf("Expected manifest.json to match, got %v (%v)", saved, err)
+			}
+
+			if got := m.URL("css/app.css"); got != "/static/"+fp {
+				t.Errorf("Expected URL to use the exported fingerprint, got %s", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package assets
+
+import (
+	"log/slog"
+)
+
+// Logger returns the manager's logger (see Config.Logger).
+func (m *Manager) Logger() *slog.Logger {
+	return loggerOrDefault(m.logger)
+}
+
+// loggerOrDefault returns l, or slog.Default() when l is nil.
+func loggerOrDefault(l *slog.Logger) *slog.Logger {
+	if l != nil {
+		return l
+	}
+
+	return slog.Default()
+}
+
+// progressLevel is the level of progress messages: Info for verbose
+// components, Debug otherwise.
+func progressLevel(verbose bool) slog.Level {
+	if verbose {
+		return slog.LevelInfo
+	}
+
+	return slog.LevelDebug
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"io/fs"
+	"log/slog"
 	"maps"
 	"os"
 	"path/filepath"
@@ -24,6 +25,8 @@ type Manager struct {
 	pipeline     *Pipeline
 	devServer    *DevServer
 	fileSystem   fs.FS // Filesystem abstraction for serving files
+	logger       *slog.Logger
+	reloadPath   string
 }
 
 // Config defines configuration options for asset management
@@ -46,6 +49,13 @@ type Config struct {
 	// FileSystem is an optional custom filesystem (e.g., embed.FS)
 	// If nil, os.DirFS(PublicDir) will be used
 	FileSystem fs.FS
+
+	// Logger receives build and dev server logs. Default: slog.Default()
+	Logger *slog.Logger
+
+	// ReloadPath is the URL path of the hot reload stream.
+	// Default: DefaultReloadPath
+	ReloadPath string
 }
 
 // NewManager creates a new asset manager with the given configuration
@@ -58,20 +68,8 @@ func NewManager(cfg Config) *Manager {
 		cfg.OutputDir = "dist"
 	}
 
-	// Default static path to "/static" if not provided
-	staticPath := cfg.StaticPath
-	if staticPath == "" {
-		staticPath = "/static"
-	}
-
-	// Ensure leading slash
-	if staticPath[0] != '/' {
-		staticPath = "/" + staticPath
-	}
-
-	// Ensure trailing slash for consistent URL building
-	if staticPath[len(staticPath)-1] != '/' {
-		staticPath += "/"
+	if cfg.ReloadPath == "" {
+		cfg.ReloadPath = DefaultReloadPath
 	}
 
 	// Use custom filesystem if provided, otherwise default to os.DirFS
@@ -83,11 +81,13 @@ func NewManager(cfg Config) *Manager {
 	m := &Manager{
 		publicDir:    cfg.PublicDir,
 		outputDir:    cfg.OutputDir,
-		staticPath:   staticPath,
+		staticPath:   normalizeStaticPath(cfg.StaticPath),
 		fingerprints: make(map[string]string),
 		isDev:        cfg.IsDev,
 		manifest:     make(map[string]string),
 		fileSystem:   fileSystem,
+		logger:       cfg.Logger,
+		reloadPath:   cfg.ReloadPath,
 	}
 
 	// Load manifest if exists
@@ -98,6 +98,45 @@ func NewManager(cfg Config) *Manager {
 	return m
 }
 
+// normalizeStaticPath defaults an empty static path to "/static" and gives
+// it leading and trailing slashes.
+func normalizeStaticPath(staticPath string) string {
+	// Default static path to "/static" if not provided
+	if staticPath == "" {
+		staticPath = "/static"
+	}
+
+	// Ensure leading slash
+	if staticPath[0] != '/' {
+		staticPath = "/" + staticPath
+	}
+
+	// Ensure trailing slash for consistent URL building
+	if staticPath[len(staticPath)-1] != '/' {
+		staticPath += "/"
+	}
+
+	return staticPath
+}
+
+// SetStaticPath changes the URL path prefix assets are served and linked
+// under. Call it before serving requests.
+func (m *Manager) SetStaticPath(staticPath string) {
+	m.staticPath = normalizeStaticPath(staticPath)
+}
+
+// SetReloadPath changes the URL path of the hot reload stream, which the
+// hot reload script connects to. Call it before serving requests.
+func (m *Manager) SetReloadPath(path string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.reloadPath = path
+	if m.devServer != nil {
+		m.devServer.SetReloadPath(path)
+	}
+}
+
 // URL returns the URL for an asset, with fingerprint in production
 func (m *Manager) URL(path string) string {
 	if m.isDev {
@@ -200,6 +239,7 @@ func (m *Manager) Pipeline() *Pipeline {
 			InputDir:  m.publicDir,
 			OutputDir: m.outputDir,
 			IsDev:     m.isDev,
+			Logger:    m.logger,
 		}, m)
 	}
 
@@ -261,6 +301,7 @@ func (m *Manager) StartDevServer(ctx context.Context) error {
 
 	// Set dev server (with lock)
 	m.mu.Lock()
+	devServer.SetReloadPath(m.reloadPath)
 	m.devServer = devServer
 	m.mu.Unlock()
 
@@ -268,7 +309,8 @@ func (m *Manager) StartDevServer(ctx context.Context) error {
 }
 
 // SSEHandler returns the Server-Sent Events handler for hot reload.
-// Mount this at /_forgeui/reload in your HTTP server.
+// Mount this at the reload path (see Config.ReloadPath) in your HTTP
+// server; App.Handler serves it at App.Paths().HotReload().
 // Returns nil if dev server is not running.
 func (m *Manager) SSEHandler() any {
 	m.mu.RLock()
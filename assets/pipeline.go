@@ -3,6 +3,7 @@ package assets
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"sync"
@@ -43,6 +44,9 @@ type ProcessorConfig struct {
 
 	// CustomConfig allows processors to receive custom configuration
 	CustomConfig map[string]any
+
+	// Logger receives the processor's logs. Default: slog.Default()
+	Logger *slog.Logger
 }
 
 // Pipeline orchestrates multiple asset processors in sequence.
@@ -77,8 +81,12 @@ type PipelineConfig struct {
 	// CleanOutput removes the output directory before building
 	CleanOutput bool
 
-	// Verbose enables detailed logging
+	// Verbose logs progress at Info instead of Debug
 	Verbose bool
+
+	// Logger receives the pipeline's and processors' logs.
+	// Default: the manager's logger
+	Logger *slog.Logger
 }
 
 // NewPipeline creates a new asset pipeline with the given configuration
@@ -92,6 +100,10 @@ func NewPipeline(cfg PipelineConfig, manager *Manager) *Pipeline {
 		cfg.OutputDir = "dist"
 	}
 
+	if cfg.Logger == nil && manager != nil {
+		cfg.Logger = manager.logger
+	}
+
 	// In production, enable minification by default
 	if !cfg.IsDev && !cfg.Minify {
 		cfg.Minify = true
@@ -141,21 +153,21 @@ func (p *Pipeline) Build(ctx context.Context) error {
 		Minify:     p.config.Minify,
 		SourceMaps: p.config.SourceMaps,
 		Watch:      p.config.Watch,
+		Logger:     p.config.Logger,
 	}
 
+	logger := loggerOrDefault(p.config.Logger)
+	level := progressLevel(p.config.Verbose)
+
 	// Execute each processor
 	for _, processor := range p.processors {
-		if p.config.Verbose {
-			fmt.Printf("[Pipeline] Running processor: %s\n", processor.Name())
-		}
+		logger.Log(ctx, level, "running asset processor", "processor", processor.Name())
 
 		if err := processor.Process(ctx, procConfig); err != nil {
 			return fmt.Errorf("processor %s failed: %w", processor.Name(), err)
 		}
 
-		if p.config.Verbose {
-			fmt.Printf("[Pipeline] Processor %s completed successfully\n", processor.Name())
-		}
+		logger.Log(ctx, level, "asset processor completed", "processor", processor.Name())
 	}
 
 	// Generate manifest for production builds
@@ -196,9 +208,8 @@ func (p *Pipeline) generateManifest() error {
 		return fmt.Errorf("failed to save manifest: %w", err)
 	}
 
-	if p.config.Verbose {
-		fmt.Printf("[Pipeline] Generated manifest: %s\n", manifestPath)
-	}
+	loggerOrDefault(p.config.Logger).Log(context.Background(), progressLevel(p.config.Verbose),
+		"generated asset manifest", "path", manifestPath)
 
 	return nil
 }
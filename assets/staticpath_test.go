@@ -78,3 +78,16 @@ func TestManager_StaticPath_Production(t *testing.T) {
 		t.Errorf("URL() = %q, want %q", url, expected)
 	}
 }
+
+func TestManager_SetStaticPath(t *testing.T) {
+	m := NewManager(Config{
+		StaticPath: "/static",
+		IsDev:      true,
+	})
+
+	m.SetStaticPath("/admin/static")
+
+	if url := m.URL("app.css"); url != "/admin/static/app.css" {
+		t.Errorf("URL() = %q, want %q", url, "/admin/static/app.css")
+	}
+}
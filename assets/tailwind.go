@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -48,9 +49,13 @@ type TailwindProcessor struct {
 	// UseCDN falls back to CDN if Tailwind CLI is not available
 	UseCDN bool
 
-	// Verbose enables detailed logging
+	// Verbose logs progress at Info instead of Debug
 	Verbose bool
 
+	// Logger receives the processor's logs.
+	// Default: ProcessorConfig.Logger, then slog.Default()
+	Logger *slog.Logger
+
 	// LightTheme is the light theme for generating v4 input CSS.
 	// When set together with DarkTheme, the processor auto-generates a complete
 	// Tailwind v4 input.css from theme tokens using theme.GenerateInputCSS().
@@ -105,6 +110,9 @@ func (tp *TailwindProcessor) processV4(ctx context.Context, cfg ProcessorConfig)
 			return fmt.Errorf("failed to create v4 input CSS: %w", err)
 		}
 
+		tp.logger(cfg).Log(ctx, progressLevel(tp.Verbose), "generated tailwind input CSS",
+			"path", inputCSS, "themed", tp.LightTheme != nil && tp.DarkTheme != nil)
+
 		defer func() { _ = os.Remove(inputCSS) }()
 	}
 
@@ -137,7 +145,7 @@ func (tp *TailwindProcessor) processV4(ctx context.Context, cfg ProcessorConfig)
 	cliCmd := tp.findTailwindV4CLI()
 	if cliCmd == "" {
 		if tp.UseCDN {
-			return tp.generateCDNFallback(outputCSS)
+			return tp.cdnFallback(ctx, cfg, outputCSS)
 		}
 
 		return errors.New("tailwind v4 CLI not found (@tailwindcss/cli) and CDN fallback disabled. Install with: npm install -D @tailwindcss/cli")
@@ -164,18 +172,16 @@ func (tp *TailwindProcessor) processV4(ctx context.Context, cfg ProcessorConfig)
 	// can resolve the `tailwindcss` package (required by `@import "tailwindcss"`).
 	cmd.Dir = filepath.Dir(inputCSS)
 
-	if tp.Verbose {
-		fmt.Printf("[Tailwind v4] Running: npx %s\n", strings.Join(args, " "))
-		fmt.Printf("[Tailwind v4] Working directory: %s\n", cmd.Dir)
-	}
+	logger := tp.logger(cfg)
+	level := progressLevel(tp.Verbose)
+
+	logger.Log(ctx, level, "running tailwind", "version", 4, "command", "npx "+strings.Join(args, " "), "dir", cmd.Dir)
 
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("tailwind v4 build failed: %w", err)
 	}
 
-	if tp.Verbose {
-		fmt.Printf("[Tailwind v4] Generated CSS: %s\n", outputCSS)
-	}
+	logger.Log(ctx, level, "generated CSS", "path", outputCSS)
 
 	return nil
 }
@@ -193,6 +199,8 @@ func (tp *TailwindProcessor) processV3(ctx context.Context, cfg ProcessorConfig)
 			return fmt.Errorf("failed to generate tailwind config: %w", err)
 		}
 
+		tp.logger(cfg).Log(ctx, progressLevel(tp.Verbose), "generated tailwind config", "path", configPath)
+
 		defer func() { _ = os.Remove(configPath) }()
 	}
 
@@ -221,7 +229,7 @@ func (tp *TailwindProcessor) processV3(ctx context.Context, cfg ProcessorConfig)
 	// Check if Tailwind CLI is available
 	if !tp.isTailwindV3Available() {
 		if tp.UseCDN {
-			return tp.generateCDNFallback(outputCSS)
+			return tp.cdnFallback(ctx, cfg, outputCSS)
 		}
 
 		return errors.New("tailwind CLI not found and CDN fallback disabled")
@@ -245,17 +253,16 @@ func (tp *TailwindProcessor) processV3(ctx context.Context, cfg ProcessorConfig)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 
-	if tp.Verbose {
-		fmt.Printf("[Tailwind v3] Running: npx %s\n", strings.Join(args, " "))
-	}
+	logger := tp.logger(cfg)
+	level := progressLevel(tp.Verbose)
+
+	logger.Log(ctx, level, "running tailwind", "version", 3, "command", "npx "+strings.Join(args, " "))
 
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("tailwind v3 build failed: %w", err)
 	}
 
-	if tp.Verbose {
-		fmt.Printf("[Tailwind v3] Generated CSS: %s\n", outputCSS)
-	}
+	logger.Log(ctx, level, "generated CSS", "path", outputCSS)
 
 	return nil
 }
@@ -283,14 +290,6 @@ func (tp *TailwindProcessor) createInputCSSv4(outputDir string) (string, error)
 		return "", err
 	}
 
-	if tp.Verbose {
-		if tp.LightTheme != nil && tp.DarkTheme != nil {
-			fmt.Printf("[Tailwind v4] Generated theme-aware input CSS: %s\n", inputPath)
-		} else {
-			fmt.Printf("[Tailwind v4] Generated minimal input CSS: %s\n", inputPath)
-		}
-	}
-
 	return inputPath, nil
 }
 
@@ -355,10 +354,6 @@ func (tp *TailwindProcessor) generateConfig(outputDir string) (string, error) {
 		return "", err
 	}
 
-	if tp.Verbose {
-		fmt.Printf("[Tailwind v3] Generated config: %s\n", configPath)
-	}
-
 	return configPath, nil
 }
 
@@ -433,12 +428,31 @@ func (tp *TailwindProcessor) generateCDNFallback(outputPath string) error {
 		return fmt.Errorf("failed to write CDN fallback: %w", err)
 	}
 
-	fmt.Println("[Tailwind] WARNING: Tailwind CLI not found. Generated CDN fallback.")
-	fmt.Println("[Tailwind] For production, install: npm install -D @tailwindcss/cli")
+	return nil
+}
+
+// cdnFallback writes the CDN fallback CSS and warns that the Tailwind CLI
+// is missing.
+func (tp *TailwindProcessor) cdnFallback(ctx context.Context, cfg ProcessorConfig, outputPath string) error {
+	if err := tp.generateCDNFallback(outputPath); err != nil {
+		return err
+	}
+
+	tp.logger(cfg).WarnContext(ctx, "tailwind CLI not found, generated CDN fallback; for production, install: npm install -D @tailwindcss/cli",
+		"path", outputPath)
 
 	return nil
 }
 
+// logger returns the processor's logger, falling back to cfg.Logger.
+func (tp *TailwindProcessor) logger(cfg ProcessorConfig) *slog.Logger {
+	if tp.Logger != nil {
+		return tp.Logger
+	}
+
+	return loggerOrDefault(cfg.Logger)
+}
+
 // --- Option methods ---
 
 // WithVersion sets the Tailwind CSS major version (TailwindV3 or TailwindV4).
@@ -486,6 +500,12 @@ func (tp *TailwindProcessor) WithVerbose(verbose bool) *TailwindProcessor {
 	return tp
 }
 
+// WithLogger sets the processor's logger
+func (tp *TailwindProcessor) WithLogger(l *slog.Logger) *TailwindProcessor {
+	tp.Logger = l
+	return tp
+}
+
 // WithCDNFallback enables or disables CDN fallback when CLI is not available.
 func (tp *TailwindProcessor) WithCDNFallback(useCDN bool) *TailwindProcessor {
 	tp.UseCDN = useCDN
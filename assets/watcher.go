@@ -3,6 +3,7 @@ package assets
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"slices"
@@ -11,6 +12,8 @@ import (
 	"time"
 
 	"github.com/fsnotify/fsnotify"
+
+	"github.com/xraph/forgeui/logging"
 )
 
 // Watcher watches files for changes and triggers callbacks.
@@ -22,6 +25,7 @@ type Watcher struct {
 	mu        sync.RWMutex
 	patterns  []string
 	verbose   bool
+	logger    *slog.Logger
 }
 
 // WatchCallback is called when files change
@@ -51,9 +55,7 @@ func (w *Watcher) AddPath(path string) error {
 		return fmt.Errorf("failed to watch %s: %w", path, err)
 	}
 
-	if w.verbose {
-		fmt.Printf("[Watcher] Watching: %s\n", path)
-	}
+	loggerOrDefault(w.logger).Log(context.Background(), progressLevel(w.verbose), "watching path", "path", path)
 
 	return nil
 }
@@ -90,6 +92,14 @@ func (w *Watcher) SetVerbose(verbose bool) {
 	w.verbose = verbose
 }
 
+// SetLogger sets the logger for watch events and errors
+func (w *Watcher) SetLogger(l *slog.Logger) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.logger = l
+}
+
 // Start begins watching for file changes
 func (w *Watcher) Start(ctx context.Context) error {
 	// Debounce timer
@@ -137,9 +147,11 @@ func (w *Watcher) Start(ctx context.Context) error {
 				return nil
 			}
 
-			if w.verbose {
-				fmt.Printf("[Watcher] Error: %v\n", err)
-			}
+			w.mu.RLock()
+			logger := loggerOrDefault(w.logger)
+			w.mu.RUnlock()
+
+			logger.WarnContext(ctx, "file watcher error", logging.KeyError, err.Error())
 		}
 	}
 }
@@ -202,17 +214,15 @@ func (w *Watcher) processEvent(event fsnotify.Event) {
 	callbacks := make([]WatchCallback, len(w.callbacks))
 	copy(callbacks, w.callbacks)
 	verbose := w.verbose
+	logger := loggerOrDefault(w.logger)
 	w.mu.RUnlock()
 
-	if verbose {
-		fmt.Printf("[Watcher] File changed: %s\n", event.Name)
-	}
+	ctx := context.Background()
+	logger.Log(ctx, progressLevel(verbose), "file changed", "path", event.Name)
 
 	for _, callback := range callbacks {
 		if err := callback(event); err != nil {
-			if verbose {
-				fmt.Printf("[Watcher] Callback error: %v\n", err)
-			}
+			logger.Log(ctx, progressLevel(verbose), "watch callback failed", "path", event.Name, logging.KeyError, err.Error())
 		}
 	}
 }
@@ -0,0 +1,134 @@
+// Package auth provides authentication and authorization shared by ForgeUI
+// pages and bridge functions.
+//
+// An Authenticator resolves the User of a request, for example from the
+// session or a bearer token. Middleware resolves it once per request and
+// stores it in the request context, where router.PageContext.User() and
+// bridge.Context.User() find the same user.
+//
+// A Policy decides whether a user may access a request. Policies are plain
+// functions, composed with All, Any and Not.
+//
+// # Basic Usage
+//
+//	authn := auth.AuthenticatorFunc(func(r *http.Request) (auth.User, error) {
+//	    id, ok := session.FromContext(r.Context()).Get("user_id")
+//	    if !ok {
+//	        return nil, nil
+//	    }
+//	    return users.Find(r.Context(), id.(string))
+//	})
+//
+//	app := forgeui.New(
+//	    forgeui.WithSessions(store),
+//	    forgeui.WithAuthenticator(authn),
+//	    forgeui.WithLoginPath("/login"),
+//	)
+//
+//	app.Page("/admin").Handler(adminPage).RequireRoles("admin").Register()
+//	app.Group("/orders").Authorize(auth.Any(auth.Roles("support"), ownsOrder))
+package auth
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/xraph/forgeui/logging"
+)
+
+// User represents an authenticated user
+type User interface {
+	// ID returns the user's unique identifier
+	ID() string
+
+	// Email returns the user's email address
+	Email() string
+
+	// Name returns the user's display name
+	Name() string
+
+	// Roles returns the user's roles/permissions
+	Roles() []string
+
+	// HasRole checks if the user has a specific role
+	HasRole(role string) bool
+
+	// Data returns additional user data
+	Data() map[string]any
+}
+
+// Authenticator resolves the user of a request. It returns a nil User
+// and no error for anonymous requests.
+type Authenticator interface {
+	Authenticate(r *http.Request) (User, error)
+}
+
+// AuthenticatorFunc adapts a function to an Authenticator.
+type AuthenticatorFunc func(r *http.Request) (User, error)
+
+// Authenticate calls f(r).
+func (f AuthenticatorFunc) Authenticate(r *http.Request) (User, error) {
+	return f(r)
+}
+
+// Middleware returns HTTP middleware that resolves the user once per
+// request and stores it in the request context (see FromContext).
+//
+// When an outer middleware already resolved the user, the request passes
+// through untouched. If the authenticator fails, the failure is logged and
+// the request continues anonymously, so policies decide what it may see.
+func Middleware(a Authenticator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, Load(r, a))
+		})
+	}
+}
+
+// Load resolves the request's user with a and returns the request carrying
+// it in its context. A request whose user was already resolved is returned
+// unchanged.
+//
+// Middleware for other handler types (such as router.Authenticate) use
+// Load; applications normally use Middleware.
+func Load(r *http.Request, a Authenticator) *http.Request {
+	if Resolved(r.Context()) {
+		return r
+	}
+
+	user, err := a.Authenticate(r)
+	if err != nil {
+		logging.FromContext(r.Context()).Warn("authentication failed", logging.KeyError, err.Error())
+
+		user = nil
+	}
+
+	return r.WithContext(NewContext(r.Context(), user))
+}
+
+type contextKey struct{}
+
+// resolved is the user stored in a context, nil for anonymous requests.
+type resolved struct {
+	user User
+}
+
+// NewContext returns a copy of ctx carrying the user. A nil user marks the
+// request as resolved and anonymous.
+func NewContext(ctx context.Context, user User) context.Context {
+	return context.WithValue(ctx, contextKey{}, resolved{user: user})
+}
+
+// FromContext returns the user stored in ctx by Middleware, or nil for
+// anonymous requests and requests without authentication middleware.
+func FromContext(ctx context.Context) User {
+	v, _ := ctx.Value(contextKey{}).(resolved)
+	return v.user
+}
+
+// Resolved reports whether the user of ctx was resolved, even if the
+// request is anonymous.
+func Resolved(ctx context.Context) bool {
+	_, ok := ctx.Value(contextKey{}).(resolved)
+	return ok
+}
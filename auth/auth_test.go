@@ -0,0 +1,103 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+// testUser is a User with an ID and roles.
+type testUser struct {
+	id    string
+	roles []string
+}
+
+func (u *testUser) ID() string               { return u.id }
+func (u *testUser) Email() string            { return u.id + "@example.com" }
+func (u *testUser) Name() string             { return u.id }
+func (u *testUser) Roles() []string          { return u.roles }
+func (u *testUser) HasRole(role string) bool { return slices.Contains(u.roles, role) }
+func (u *testUser) Data() map[string]any     { return nil }
+
+// headerAuthenticator resolves the user named by the X-User header and
+// counts its calls.
+func headerAuthenticator(calls *int) Authenticator {
+	return AuthenticatorFunc(func(r *http.Request) (User, error) {
+		*calls++
+
+		switch id := r.Header.Get("X-User"); id {
+		case "":
+			return nil, nil
+		case "broken":
+			return nil, errors.New("token expired")
+		default:
+			return &testUser{id: id}, nil
+		}
+	})
+}
+
+func TestMiddleware(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{"user", "ada", "ada"},
+		{"anonymous", "", ""},
+		{"failure", "broken", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+
+			var got string
+
+			handler := Middleware(headerAuthenticator(&calls))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if !Resolved(r.Context()) {
+					t.Error("Expected the user to be resolved")
+				}
+
+				if user := FromContext(r.Context()); user != nil {
+					got = user.ID()
+				}
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("X-User", tt.header)
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			if got != tt.want {
+				t.Errorf("Expected user %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestMiddleware_ResolvesOnce(t *testing.T) {
+	calls := 0
+	authn := headerAuthenticator(&calls)
+
+	// Anonymous requests are resolved too, so nested middleware doesn't
+	// ask the authenticator again
+	handler := Middleware(authn)(Middleware(authn)(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if calls != 1 {
+		t.Errorf("Expected one call, got %d", calls)
+	}
+}
+
+func TestFromContext_WithoutMiddleware(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	if FromContext(req.Context()) != nil {
+		t.Error("Expected no user")
+	}
+
+	if Resolved(req.Context()) {
+		t.Error("Expected the user not to be resolved")
+	}
+}
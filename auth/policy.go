@@ -0,0 +1,111 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+)
+
+var (
+	// ErrUnauthenticated is returned by Check when an anonymous request is denied
+	ErrUnauthenticated = errors.New("auth: authentication required")
+
+	// ErrForbidden is returned by Check when a user is denied
+	ErrForbidden = errors.New("auth: forbidden")
+)
+
+// Policy decides whether user may access r. user is nil for anonymous
+// requests. Route parameters of page requests are available through Param.
+//
+// A policy over a resource loads it itself:
+//
+//	ownsOrder := func(user auth.User, r *http.Request) bool {
+//	    order, err := orders.Find(r.Context(), auth.Param(r, "id"))
+//	    return err == nil && user != nil && order.OwnerID == user.ID()
+//	}
+type Policy func(user User, r *http.Request) bool
+
+// Check applies policy to user and r. It returns ErrUnauthenticated when
+// an anonymous request is denied and ErrForbidden when a user is denied.
+func Check(user User, r *http.Request, policy Policy) error {
+	if policy(user, r) {
+		return nil
+	}
+
+	if user == nil {
+		return ErrUnauthenticated
+	}
+
+	return ErrForbidden
+}
+
+// Authenticated allows any signed-in user.
+func Authenticated() Policy {
+	return func(user User, _ *http.Request) bool {
+		return user != nil
+	}
+}
+
+// Roles allows users with at least one of roles.
+func Roles(roles ...string) Policy {
+	return func(user User, _ *http.Request) bool {
+		return user != nil && slices.ContainsFunc(roles, user.HasRole)
+	}
+}
+
+// Self allows the user whose ID is the value of the route parameter param,
+// as in /users/{id}/settings.
+func Self(param string) Policy {
+	return func(user User, r *http.Request) bool {
+		return user != nil && user.ID() == Param(r, param)
+	}
+}
+
+// All allows requests every policy allows.
+func All(policies ...Policy) Policy {
+	return func(user User, r *http.Request) bool {
+		for _, p := range policies {
+			if !p(user, r) {
+				return false
+			}
+		}
+
+		return true
+	}
+}
+
+// Any allows requests at least one policy allows.
+func Any(policies ...Policy) Policy {
+	return func(user User, r *http.Request) bool {
+		for _, p := range policies {
+			if p(user, r) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// Not allows requests policy denies, for example Not(Authenticated()) for
+// pages only guests see.
+func Not(policy Policy) Policy {
+	return func(user User, r *http.Request) bool {
+		return !policy(user, r)
+	}
+}
+
+type paramsKey struct{}
+
+// WithParams returns a copy of r carrying route parameters for Param.
+// The router adds them before applying a policy.
+func WithParams(r *http.Request, params map[string]string) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), paramsKey{}, params))
+}
+
+// Param returns the route parameter name of r, or "" if it has none.
+func Param(r *http.Request, name string) string {
+	params, _ := r.Context().Value(paramsKey{}).(map[string]string)
+	return params[name]
+}
@@ -0,0 +1,60 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPolicies(t *testing.T) {
+	admin := &testUser{id: "1", roles: []string{"admin"}}
+	member := &testUser{id: "2", roles: []string{"member"}}
+
+	req := WithParams(httptest.NewRequest(http.MethodGet, "/users/2", nil), map[string]string{"id": "2"})
+
+	tests := []struct {
+		name   string
+		policy Policy
+		user   User
+		want   error
+	}{
+		{"authenticated user", Authenticated(), member, nil},
+		{"authenticated anonymous", Authenticated(), nil, ErrUnauthenticated},
+		{"roles match", Roles("admin", "editor"), admin, nil},
+		{"roles mismatch", Roles("admin"), member, ErrForbidden},
+		{"roles anonymous", Roles("admin"), nil, ErrUnauthenticated},
+		{"self", Self("id"), member, nil},
+		{"not self", Self("id"), admin, ErrForbidden},
+		{"missing param", Self("slug"), member, ErrForbidden},
+		{"all", All(Authenticated(), Roles("admin")), admin, nil},
+		{"all denies", All(Authenticated(), Roles("admin")), member, ErrForbidden},
+		{"any", Any(Roles("admin"), Self("id")), member, nil},
+		{"any denies", Any(Roles("admin"), Self("id")), nil, ErrUnauthenticated},
+		{"not", Not(Authenticated()), nil, nil},
+		{"not denies", Not(Authenticated()), member, ErrForbidden},
+		{"empty all", All(), nil, nil},
+		{"empty any", Any(), member, ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Check(tt.user, req, tt.policy); err != tt.want {
+				t.Errorf("Expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestParam(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	if Param(req, "id") != "" {
+		t.Error("Expected no parameter without WithParams")
+	}
+
+	req = WithParams(req, map[string]string{"id": "7"})
+
+	if Param(req, "id") != "7" {
+		t.Errorf("Expected 7, got %q", Param(req, "id"))
+	}
+}
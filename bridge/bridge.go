@@ -2,8 +2,13 @@ package bridge
 
 import (
 	"fmt"
+	"log/slog"
 	"sync"
 	"time"
+
+	"github.com/xraph/forgeui/metrics"
+	"github.com/xraph/forgeui/ratelimit"
+	"github.com/xraph/forgeui/tracing"
 )
 
 // Bridge manages function registration and execution
@@ -12,6 +17,13 @@ type Bridge struct {
 	functions map[string]*Function
 	config    *Config
 	hooks     *HookManager
+	metrics   *bridgeMetrics
+	conns     connTracker
+	streams   streamRegistry
+	topics    []*topicRule
+
+	wsOnce sync.Once
+	ws     *WSHandler
 }
 
 // Config holds bridge configuration
@@ -42,6 +54,38 @@ type Config struct {
 
 	// CSRFCookieName is the cookie name for CSRF token
 	CSRFCookieName string
+
+	// Logger logs calls for requests that don't carry a logger
+	// (see WithLogger)
+	Logger *slog.Logger
+
+	// Tracer traces calls for requests that don't carry a tracer
+	// (see WithTracer)
+	Tracer tracing.Tracer
+
+	// Metrics aggregates call and connection metrics (see WithMetrics)
+	Metrics *metrics.Registry
+
+	// StreamBuffer is the number of events kept per stream for resuming
+	// (see WithStreamBuffer)
+	StreamBuffer int
+
+	// StreamResumeWindow is how long streams wait for their client to
+	// resume them (see WithStreamResumeWindow)
+	StreamResumeWindow time.Duration
+
+	// StreamHeartbeat is the interval of heartbeat comments on idle
+	// streams (see WithStreamHeartbeat)
+	StreamHeartbeat time.Duration
+
+	// EnableWebSocket enables the WebSocket endpoint (see WithWebSocket)
+	EnableWebSocket bool
+
+	// Broker carries published events and presence (see WithBroker)
+	Broker Broker
+
+	// Limiter limits calls of functions with a rate limit (see WithLimiter)
+	Limiter ratelimit.Limiter
 }
 
 // DefaultConfig returns the default bridge configuration
@@ -56,6 +100,10 @@ func DefaultConfig() *Config {
 		EnableCache:      true,
 		CSRFTokenHeader:  "X-CSRF-Token",
 		CSRFCookieName:   "csrf_token",
+
+		StreamBuffer:       256,
+		StreamResumeWindow: 10 * time.Second,
+		StreamHeartbeat:    15 * time.Second,
 	}
 }
 
@@ -118,11 +166,26 @@ func New(opts ...ConfigOption) *Bridge {
 		opt(config)
 	}
 
-	return &Bridge{
+	if config.Broker == nil {
+		config.Broker = NewMemoryBroker()
+	}
+
+	if config.Limiter == nil {
+		config.Limiter = defaultLimiter(config)
+	}
+
+	b := &Bridge{
 		functions: make(map[string]*Function),
 		config:    config,
 		hooks:     NewHookManager(),
+		metrics:   newBridgeMetrics(config.Metrics),
+	}
+
+	if b.metrics != nil {
+		b.hooks.Register(AfterCall, b.metrics.afterCall)
 	}
+
+	return b
 }
 
 // Register registers a new function
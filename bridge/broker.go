@@ -0,0 +1,143 @@
+package bridge
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Broker carries published events between the bridge instances serving an
+// app and tracks the members present in each topic. MemoryBroker serves a
+// single instance; a broker over Redis, NATS or Postgres fans events out
+// across several (see WithBroker).
+type Broker interface {
+	// Publish delivers data to the subscribers of topic on every instance
+	Publish(ctx context.Context, topic string, data []byte) error
+
+	// Subscribe calls handler with the data published to topic until
+	// unsubscribe is called. handler must not block.
+	Subscribe(topic string, handler func(data []byte)) (unsubscribe func(), err error)
+
+	// Join records that m is present in topic
+	Join(ctx context.Context, topic string, m Member) error
+
+	// Leave removes the member with m's connection ID from topic
+	Leave(ctx context.Context, topic string, m Member) error
+
+	// Members returns the members present in topic, sorted by connection
+	Members(ctx context.Context, topic string) ([]Member, error)
+}
+
+// Member is a WebSocket connection present in a topic with presence (see
+// WithPresence). A user connected from two tabs is two members.
+type Member struct {
+	// ConnID identifies the connection
+	ConnID string `json:"connId"`
+
+	// UserID is the ID of the connection's user, empty when anonymous
+	UserID string `json:"userId,omitempty"`
+
+	// Name is the user's display name
+	Name string `json:"name,omitempty"`
+}
+
+// MemoryBroker is an in-process Broker
+type MemoryBroker struct {
+	mu      sync.RWMutex
+	subs    map[string]map[int]func([]byte)
+	members map[string]map[string]Member
+	nextID  int
+}
+
+// NewMemoryBroker creates a new in-process broker
+func NewMemoryBroker() *MemoryBroker {
+	return &MemoryBroker{
+		subs:    make(map[string]map[int]func([]byte)),
+		members: make(map[string]map[string]Member),
+	}
+}
+
+// Publish calls the handlers subscribed to topic
+func (b *MemoryBroker) Publish(_ context.Context, topic string, data []byte) error {
+	b.mu.RLock()
+	handlers := make([]func([]byte), 0, len(b.subs[topic]))
+	for _, h := range b.subs[topic] {
+		handlers = append(handlers, h)
+	}
+	b.mu.RUnlock()
+
+	for _, h := range handlers {
+		h(data)
+	}
+
+	return nil
+}
+
+// Subscribe registers handler for topic
+func (b *MemoryBroker) Subscribe(topic string, handler func([]byte)) (func(), error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.subs[topic] == nil {
+		b.subs[topic] = make(map[int]func([]byte))
+	}
+
+	b.nextID++
+	id := b.nextID
+	b.subs[topic][id] = handler
+
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		delete(b.subs[topic], id)
+
+		if len(b.subs[topic]) == 0 {
+			delete(b.subs, topic)
+		}
+	}, nil
+}
+
+// Join records that m is present in topic
+func (b *MemoryBroker) Join(_ context.Context, topic string, m Member) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.members[topic] == nil {
+		b.members[topic] = make(map[string]Member)
+	}
+
+	b.members[topic][m.ConnID] = m
+
+	return nil
+}
+
+// Leave removes m from topic
+func (b *MemoryBroker) Leave(_ context.Context, topic string, m Member) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.members[topic], m.ConnID)
+
+	if len(b.members[topic]) == 0 {
+		delete(b.members, topic)
+	}
+
+	return nil
+}
+
+// Members returns the members present in topic
+func (b *MemoryBroker) Members(_ context.Context, topic string) ([]Member, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	members := make([]Member, 0, len(b.members[topic]))
+	for _, m := range b.members[topic] {
+		members = append(members, m)
+	}
+
+	slices.SortFunc(members, func(a, b Member) int { return strings.Compare(a.ConnID, b.ConnID) })
+
+	return members, nil
+}
@@ -0,0 +1,144 @@
+// Package brokertest provides a bridge.Broker for tests: it delivers like
+// bridge.MemoryBroker, records what is published and can be made to fail.
+//
+//	broker := brokertest.New()
+//	app := forgeui.New(forgeui.WithBridge(bridge.WithBroker(broker)))
+//	// ... serve a request publishing to "orders.42"
+//	if events := broker.Events("orders.42"); len(events) != 1 { ... }
+package brokertest
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/xraph/forgeui/bridge"
+)
+
+// Message is a message published through the broker
+type Message struct {
+	Topic string
+	Data  []byte
+}
+
+// Broker is a bridge.Broker recording published messages
+type Broker struct {
+	*bridge.MemoryBroker
+
+	mu        sync.Mutex
+	published []Message
+	err       error
+}
+
+// New creates a new recording broker
+func New() *Broker {
+	return &Broker{MemoryBroker: bridge.NewMemoryBroker()}
+}
+
+// FailWith makes every later call fail with err, or succeed again when err
+// is nil.
+func (b *Broker) FailWith(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.err = err
+}
+
+func (b *Broker) failure() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.err
+}
+
+// Publish records the message and delivers it to the subscribers of topic
+func (b *Broker) Publish(ctx context.Context, topic string, data []byte) error {
+	if err := b.failure(); err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	b.published = append(b.published, Message{Topic: topic, Data: data})
+	b.mu.Unlock()
+
+	return b.MemoryBroker.Publish(ctx, topic, data)
+}
+
+// Subscribe registers handler for topic
+func (b *Broker) Subscribe(topic string, handler func([]byte)) (func(), error) {
+	if err := b.failure(); err != nil {
+		return nil, err
+	}
+
+	return b.MemoryBroker.Subscribe(topic, handler)
+}
+
+// Join records that m is present in topic
+func (b *Broker) Join(ctx context.Context, topic string, m bridge.Member) error {
+	if err := b.failure(); err != nil {
+		return err
+	}
+
+	return b.MemoryBroker.Join(ctx, topic, m)
+}
+
+// Leave removes m from topic
+func (b *Broker) Leave(ctx context.Context, topic string, m bridge.Member) error {
+	if err := b.failure(); err != nil {
+		return err
+	}
+
+	return b.MemoryBroker.Leave(ctx, topic, m)
+}
+
+// Members returns the members present in topic
+func (b *Broker) Members(ctx context.Context, topic string) ([]bridge.Member, error) {
+	if err := b.failure(); err != nil {
+		return nil, err
+	}
+
+	return b.MemoryBroker.Members(ctx, topic)
+}
+
+// Published returns the messages published so far, in order
+func (b *Broker) Published() []Message {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return append([]Message(nil), b.published...)
+}
+
+// Events returns the raw JSON of the events published to topic with
+// bridge.Publish, in order. Presence notifications are left out.
+func (b *Broker) Events(topic string) []json.RawMessage {
+	var events []json.RawMessage
+
+	for _, m := range b.Published() {
+		if m.Topic != topic {
+			continue
+		}
+
+		var n struct {
+			Method string `json:"method"`
+			Params struct {
+				Event json.RawMessage `json:"event"`
+			} `json:"params"`
+		}
+
+		if json.Unmarshal(m.Data, &n) == nil && n.Method == bridge.MethodEvent {
+			events = append(events, n.Params.Event)
+		}
+	}
+
+	return events
+}
+
+// Reset forgets the published messages
+func (b *Broker) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.published = nil
+}
+
+var _ bridge.Broker = (*Broker)(nil)
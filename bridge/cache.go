@@ -25,8 +25,9 @@ type Cache interface {
 
 // MemoryCache is an in-memory cache implementation
 type MemoryCache struct {
-	mu    sync.RWMutex
-	items map[string]*cacheItem
+	mu      sync.RWMutex
+	items   map[string]*cacheItem
+	metrics *cacheMetrics
 }
 
 // cacheItem holds a cached value with expiration
@@ -53,14 +54,12 @@ func (c *MemoryCache) Get(key string) (any, bool) {
 	defer c.mu.RUnlock()
 
 	item, exists := c.items[key]
-	if !exists {
+	if !exists || time.Now().After(item.expiresAt) {
+		c.metrics.observe(false)
 		return nil, false
 	}
 
-	// Check if expired
-	if time.Now().After(item.expiresAt) {
-		return nil, false
-	}
+	c.metrics.observe(true)
 
 	return item.value, true
 }
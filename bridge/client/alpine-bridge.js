@@ -38,7 +38,7 @@ function AlpineBridgePlugin(Alpine) {
      * @param {object} params - Function parameters
      * @param {function} onData - Data callback
      * @param {function} onError - Error callback
-     * @returns {function} - Cleanup function
+     * @returns {BridgeStream|function} - The stream, or a cleanup function with callbacks
      */
     stream(method, params, onData, onError) {
       return bridge.stream(method, params, onData, onError);
@@ -12,10 +12,22 @@ class ForgeBridge {
       maxRetries: config.maxRetries || 3,
       retryDelay: config.retryDelay || 1000,
       csrf: config.csrf || null,
+      traceparent: config.traceparent || ForgeBridge.getTraceParentFromMeta(),
       ...config
     };
 
+    // The stream endpoint is a sibling of the call endpoint
+    if (!this.config.streamEndpoint) {
+      this.config.streamEndpoint = this.config.endpoint.replace(/\/call\/?$/, '') + '/stream/';
+    }
+
+    // So is the WebSocket endpoint
+    if (!this.config.wsEndpoint) {
+      this.config.wsEndpoint = this.config.endpoint.replace(/\/call\/?$/, '') + '/ws';
+    }
+
     this.requestId = 0;
+    this._socket = null;
   }
 
   /**
@@ -65,48 +77,73 @@ class ForgeBridge {
   }
 
   /**
-   * Stream results from a function using SSE
+   * Get the functions as methods: client().users.get(params) calls
+   * "users.get". `forgeui bridge gen-ts` generates its BridgeClient type.
+   * @returns {object} - Proxy calling functions by property path
+   */
+  client() {
+    const bridge = this;
+    const path = (name) => new Proxy(function () {}, {
+      get(_, prop) {
+        // Not thenable, so a namespace can be awaited or returned from async code
+        if (typeof prop !== 'string' || prop === 'then') {
+          return undefined;
+        }
+        return path(name ? `${name}.${prop}` : prop);
+      },
+      apply(_, __, args) {
+        return bridge.call(name, args[0] || {});
+      }
+    });
+
+    return path('');
+  }
+
+  /**
+   * Stream results from a function using SSE. Without callbacks it returns
+   * a BridgeStream, an async iterator over the chunks:
+   *
+   *   for await (const line of bridge.stream('logs.tail', { file })) { ... }
+   *
+   * With callbacks, onData gets each chunk and onError any error.
    * @param {string} method - Function name
    * @param {object} params - Function parameters
    * @param {function} onData - Callback for each data chunk
    * @param {function} onError - Callback for errors
-   * @returns {function} - Cleanup function
+   * @returns {BridgeStream|function} - The stream, or a cleanup function with callbacks
    */
   stream(method, params = {}, onData, onError) {
-    const url = new URL(`${this.config.endpoint}/stream`, window.location.origin);
-    url.searchParams.set('method', method);
+    const url = new URL(this.config.streamEndpoint + encodeURIComponent(method), window.location.origin);
     url.searchParams.set('params', JSON.stringify(params));
 
-    const eventSource = new EventSource(url.toString());
+    // EventSource can't send headers, so the CSRF token goes in the query
+    if (this.config.csrf) {
+      url.searchParams.set('_csrf', this.config.csrf);
+    }
 
-    eventSource.onmessage = (event) => {
-      try {
-        const chunk = JSON.parse(event.data);
-        
-        if (chunk.error) {
-          if (onError) onError(new BridgeError(chunk.error));
-          eventSource.close();
-          return;
-        }
+    // Likewise for the trace context, so the stream joins the page trace
+    if (this.config.traceparent) {
+      url.searchParams.set('traceparent', this.config.traceparent);
+    }
 
-        if (onData) onData(chunk.data);
+    const stream = new BridgeStream(url.toString());
+
+    if (!onData && !onError) {
+      return stream;
+    }
 
-        if (chunk.done) {
-          eventSource.close();
+    (async () => {
+      try {
+        for await (const chunk of stream) {
+          if (onData) onData(chunk);
         }
       } catch (err) {
         if (onError) onError(err);
-        eventSource.close();
       }
-    };
-
-    eventSource.onerror = (err) => {
-      if (onError) onError(err);
-      eventSource.close();
-    };
+    })();
 
     // Return cleanup function
-    return () => eventSource.close();
+    return () => stream.close();
   }
 
   /**
@@ -123,6 +160,11 @@ class ForgeBridge {
       headers['X-CSRF-Token'] = this.config.csrf;
     }
 
+    // Join the trace of the page that made the call
+    if (this.config.traceparent) {
+      headers['traceparent'] = this.config.traceparent;
+    }
+
     let lastError;
     for (let attempt = 0; attempt < this.config.maxRetries; attempt++) {
       try {
@@ -162,6 +204,53 @@ class ForgeBridge {
     throw lastError;
   }
 
+  /**
+   * Subscribe to a topic over the bridge WebSocket. onEvent gets each event
+   * published to it; for topics with presence, options.onPresence gets
+   * { joined } or { left } as members come and go. Subscriptions are
+   * restored when the connection drops and comes back.
+   *
+   *   const unsubscribe = bridge.subscribe('chat.42', (msg) => { ... });
+   * @param {string} topic - Topic name
+   * @param {function} onEvent - Callback for each event
+   * @param {object} options - { onPresence, onError }
+   * @returns {function} - Unsubscribe function
+   */
+  subscribe(topic, onEvent, options = {}) {
+    return this._ws().subscribe(topic, { onEvent, ...options });
+  }
+
+  /**
+   * Get the members present in a topic with presence
+   * @param {string} topic - Topic name
+   * @returns {Promise<Array<{connId: string, userId?: string, name?: string}>>} - Members
+   */
+  async presence(topic) {
+    const result = await this._ws().request('$presence', { topic });
+    return result.members || [];
+  }
+
+  // _ws returns the WebSocket connection, opening it on first use
+  _ws() {
+    if (!this._socket) {
+      const url = new URL(this.config.wsEndpoint, window.location.origin);
+      url.protocol = url.protocol === 'https:' ? 'wss:' : 'ws:';
+
+      // Browsers can't send headers with the upgrade either
+      if (this.config.csrf) {
+        url.searchParams.set('_csrf', this.config.csrf);
+      }
+
+      if (this.config.traceparent) {
+        url.searchParams.set('traceparent', this.config.traceparent);
+      }
+
+      this._socket = new BridgeSocket(url.toString(), this.config.retryDelay);
+    }
+
+    return this._socket;
+  }
+
   /**
    * Set CSRF token
    * @param {string} token - CSRF token
@@ -170,6 +259,27 @@ class ForgeBridge {
     this.config.csrf = token;
   }
 
+  /**
+   * Set the W3C traceparent sent with every call
+   * @param {string} traceparent - traceparent header value
+   */
+  setTraceParent(traceparent) {
+    this.config.traceparent = traceparent;
+  }
+
+  /**
+   * Get the traceparent from a <meta name="traceparent"> tag
+   * @returns {string|null} - traceparent header value
+   */
+  static getTraceParentFromMeta() {
+    if (typeof document === 'undefined') {
+      return null;
+    }
+
+    const meta = document.querySelector('meta[name="traceparent"]');
+    return meta ? meta.getAttribute('content') : null;
+  }
+
   /**
    * Get CSRF token from cookie
    * @returns {string|null} - CSRF token
@@ -186,6 +296,255 @@ class ForgeBridge {
   }
 }
 
+/**
+ * Async iterator over the chunks of a bridge stream. The browser
+ * reconnects dropped streams with the ID of the last event received, and
+ * the server resumes them from there.
+ */
+class BridgeStream {
+  constructor(url) {
+    this._queue = [];
+    this._pending = null;
+    this._done = false;
+    this._error = null;
+
+    this._source = new EventSource(url);
+
+    this._source.onmessage = (event) => {
+      let chunk;
+      try {
+        chunk = JSON.parse(event.data);
+      } catch (err) {
+        this._fail(err);
+        return;
+      }
+
+      if (chunk.error) {
+        this._fail(new BridgeError(chunk.error));
+        return;
+      }
+
+      // Functions that don't stream send their result with the final event
+      if (chunk.data !== undefined) {
+        this._push(chunk.data);
+      }
+
+      if (chunk.done) {
+        this.close();
+      }
+    };
+
+    this._source.onerror = () => {
+      // While connecting the browser is retrying; closed means it gave up
+      if (this._source.readyState === EventSource.CLOSED) {
+        this._fail(new Error('Stream connection failed'));
+      }
+    };
+  }
+
+  /**
+   * Stop the stream
+   */
+  close() {
+    this._source.close();
+    this._done = true;
+    this._settle();
+  }
+
+  next() {
+    if (this._queue.length > 0) {
+      return Promise.resolve({ value: this._queue.shift(), done: false });
+    }
+    if (this._error) {
+      return Promise.reject(this._error);
+    }
+    if (this._done) {
+      return Promise.resolve({ value: undefined, done: true });
+    }
+
+    return new Promise((resolve, reject) => {
+      this._pending = { resolve, reject };
+    });
+  }
+
+  return() {
+    this.close();
+    return Promise.resolve({ value: undefined, done: true });
+  }
+
+  [Symbol.asyncIterator]() {
+    return this;
+  }
+
+  _push(value) {
+    this._queue.push(value);
+    this._settle();
+  }
+
+  _fail(err) {
+    this._source.close();
+    this._error = err;
+    this._settle();
+  }
+
+  // _settle answers a pending next() call, if the stream has something for it
+  _settle() {
+    if (!this._pending) {
+      return;
+    }
+
+    const { resolve, reject } = this._pending;
+
+    if (this._queue.length > 0) {
+      this._pending = null;
+      resolve({ value: this._queue.shift(), done: false });
+    } else if (this._error) {
+      this._pending = null;
+      reject(this._error);
+    } else if (this._done) {
+      this._pending = null;
+      resolve({ value: undefined, done: true });
+    }
+  }
+}
+
+/**
+ * WebSocket connection carrying topic subscriptions. It reconnects with a
+ * growing delay and subscribes again to every topic.
+ */
+class BridgeSocket {
+  constructor(url, retryDelay) {
+    this._url = url;
+    this._retryDelay = retryDelay;
+    this._attempt = 0;
+    this._requestId = 0;
+    this._pending = new Map();
+    this._topics = new Map();
+    this._connect();
+  }
+
+  /**
+   * Subscribe to a topic
+   * @param {string} topic - Topic name
+   * @param {object} handlers - { onEvent, onPresence, onError }
+   * @returns {function} - Unsubscribe function
+   */
+  subscribe(topic, handlers) {
+    if (!this._topics.has(topic)) {
+      this._topics.set(topic, new Set());
+    }
+
+    const subscribers = this._topics.get(topic);
+    subscribers.add(handlers);
+
+    if (subscribers.size === 1) {
+      this._subscribe(topic);
+    }
+
+    return () => {
+      subscribers.delete(handlers);
+
+      if (subscribers.size === 0 && this._topics.get(topic) === subscribers) {
+        this._topics.delete(topic);
+        this.request('$unsubscribe', { topic }).catch(() => {});
+      }
+    };
+  }
+
+  /**
+   * Send a request and wait for its response
+   * @param {string} method - Method name
+   * @param {object} params - Parameters
+   * @returns {Promise<any>} - Result
+   */
+  request(method, params) {
+    const id = String(++this._requestId);
+
+    return new Promise((resolve, reject) => {
+      this._pending.set(id, { resolve, reject });
+      this._send({ jsonrpc: '2.0', id, method, params });
+    });
+  }
+
+  _subscribe(topic) {
+    this.request('$subscribe', { topic }).catch((err) => {
+      for (const handlers of this._topics.get(topic) || []) {
+        if (handlers.onError) handlers.onError(err);
+      }
+    });
+  }
+
+  _connect() {
+    this._socket = new WebSocket(this._url);
+    this._queue = [];
+
+    this._socket.onopen = () => {
+      this._attempt = 0;
+      for (const message of this._queue) {
+        this._socket.send(message);
+      }
+      this._queue = [];
+    };
+
+    this._socket.onmessage = (event) => {
+      let message;
+      try {
+        message = JSON.parse(event.data);
+      } catch {
+        return;
+      }
+
+      // Responses to requests
+      if (message.id !== undefined && this._pending.has(message.id)) {
+        const { resolve, reject } = this._pending.get(message.id);
+        this._pending.delete(message.id);
+
+        if (message.error) {
+          reject(new BridgeError(message.error));
+        } else {
+          resolve(message.result);
+        }
+        return;
+      }
+
+      // Notifications for subscribed topics
+      const params = message.params || {};
+      for (const handlers of this._topics.get(params.topic) || []) {
+        if (message.method === '$event' && handlers.onEvent) {
+          handlers.onEvent(params.event);
+        } else if (message.method === '$presence' && handlers.onPresence) {
+          handlers.onPresence({ joined: params.joined, left: params.left });
+        }
+      }
+    };
+
+    this._socket.onclose = () => {
+      for (const { reject } of this._pending.values()) {
+        reject(new Error('WebSocket connection closed'));
+      }
+      this._pending.clear();
+
+      const delay = Math.min(this._retryDelay * 2 ** this._attempt++, 30000);
+      setTimeout(() => {
+        this._connect();
+        for (const topic of this._topics.keys()) {
+          this._subscribe(topic);
+        }
+      }, delay);
+    };
+  }
+
+  _send(message) {
+    const data = JSON.stringify(message);
+
+    if (this._socket.readyState === WebSocket.OPEN) {
+      this._socket.send(data);
+    } else {
+      this._queue.push(data);
+    }
+  }
+}
+
 /**
  * Bridge error class
  */
@@ -200,12 +559,13 @@ class BridgeError extends Error {
 
 // Export for module systems
 if (typeof module !== 'undefined' && module.exports) {
-  module.exports = { ForgeBridge, BridgeError };
+  module.exports = { ForgeBridge, BridgeStream, BridgeError };
 }
 
 // Global export
 if (typeof window !== 'undefined') {
   window.ForgeBridge = ForgeBridge;
+  window.BridgeStream = BridgeStream;
   window.BridgeError = BridgeError;
 }
 
@@ -4,6 +4,9 @@ import (
 	"context"
 	"net/http"
 	"slices"
+
+	"github.com/xraph/forgeui/auth"
+	"github.com/xraph/forgeui/session"
 )
 
 // Context provides access to request-scoped data
@@ -45,26 +48,9 @@ type Session interface {
 	Clear()
 }
 
-// User represents an authenticated user
-type User interface {
-	// ID returns the user's unique identifier
-	ID() string
-
-	// Email returns the user's email address
-	Email() string
-
-	// Name returns the user's display name
-	Name() string
-
-	// Roles returns the user's roles/permissions
-	Roles() []string
-
-	// HasRole checks if the user has a specific role
-	HasRole(role string) bool
-
-	// Data returns additional user data
-	Data() map[string]any
-}
+// User represents an authenticated user. It is the auth.User resolved by
+// the app's authenticator, which pages see too.
+type User = auth.User
 
 // bridgeContext is the default implementation of Context
 type bridgeContext struct {
@@ -103,14 +89,28 @@ func (c *bridgeContext) Value(key any) any {
 	return c.ctx.Value(key)
 }
 
-// Session returns session data
+// Session returns session data: the session set with WithSession, or the
+// *session.Session loaded by session middleware, which pages share.
 func (c *bridgeContext) Session() Session {
-	return c.session
+	if c.session != nil {
+		return c.session
+	}
+
+	if s := session.FromContext(c.ctx); s != nil {
+		return s
+	}
+
+	return nil
 }
 
-// User returns the authenticated user
+// User returns the authenticated user: the user set with WithUser, or the
+// one resolved by auth middleware, which pages share.
 func (c *bridgeContext) User() User {
-	return c.user
+	if c.user != nil {
+		return c.user
+	}
+
+	return auth.FromContext(c.ctx)
 }
 
 // SetValue stores a value in the context
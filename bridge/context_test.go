@@ -4,6 +4,9 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
+
+	"github.com/xraph/forgeui/session"
 )
 
 func TestNewContext(t *testing.T) {
@@ -50,6 +53,28 @@ func TestWithSession(t *testing.T) {
 	}
 }
 
+func TestContext_SessionFromMiddleware(t *testing.T) {
+	var (
+		fromRequest *session.Session
+		fromBridge  Session
+	)
+
+	handler := SessionMiddleware(session.NewMemoryStore(time.Minute))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fromRequest = session.FromContext(r.Context())
+		fromBridge = NewContext(r).Session()
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/test", nil))
+
+	if fromRequest == nil || fromBridge != Session(fromRequest) {
+		t.Error("Expected Session() to return the session loaded by the middleware")
+	}
+
+	if NewContext(httptest.NewRequest(http.MethodGet, "/test", nil)).Session() != nil {
+		t.Error("Expected nil session without session middleware")
+	}
+}
+
 func TestWithUser(t *testing.T) {
 	req := httptest.NewRequest(http.MethodGet, "/test", nil)
 	ctx := NewContext(req)
@@ -0,0 +1,291 @@
+package bridge
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// docsPage is the data of the API docs page.
+type docsPage struct {
+	Info      OpenAPIInfo
+	SpecURL   string
+	CallPath  string
+	Functions []docsFunction
+	Types     []docsType
+}
+
+type docsFunction struct {
+	Name        string
+	Description string
+	Endpoint    string
+	Methods     []string
+	Secured     bool
+	Roles       []string
+	RateLimit   int
+	Params      []docsField
+	Result      string
+	Example     string
+}
+
+type docsType struct {
+	Name   string
+	Fields []docsField
+}
+
+type docsField struct {
+	Name        string
+	Type        string
+	Required    bool
+	Constraints string
+}
+
+// docs builds the docs page from the document OpenAPI builds for opts.
+func (b *Bridge) docs(specURL string, opts []OpenAPIOption) docsPage {
+	cfg := &openAPIConfig{basePath: "/api/bridge"}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	doc := b.OpenAPI(opts...)
+	schemas := doc.Components.Schemas
+
+	page := docsPage{
+		Info:     doc.Info,
+		SpecURL:  specURL,
+		CallPath: cfg.basePath + "/call",
+	}
+
+	names := b.ListFunctions()
+	sort.Strings(names)
+
+	for _, name := range names {
+		fn, err := b.GetFunction(name)
+		if err != nil {
+			continue
+		}
+
+		if fn.SignatureType == SigStream {
+			page.Functions = append(page.Functions, streamDocs(fn, doc, cfg.basePath))
+			continue
+		}
+
+		req := schemas[componentName(name)+".Request"]
+		resp := schemas[componentName(name)+".Response"]
+
+		f := docsFunction{
+			Name:        name,
+			Description: fn.Description,
+			Endpoint:    cfg.basePath + "/fn/" + name,
+			Methods:     fn.httpMethods(),
+			Secured:     req.Security != nil,
+			Roles:       fn.RequireRoles,
+			RateLimit:   fn.RateLimit,
+			Result:      "none",
+		}
+
+		example := map[string]any{"jsonrpc": "2.0", "id": 1, "method": name}
+
+		if params := req.Properties["params"]; params != nil {
+			f.Params = docsFields(params)
+			example["params"] = exampleValue(params, schemas, 0)
+		}
+
+		if result := resp.Properties["result"]; result.Ref != "" || result.Type != "" {
+			f.Result = schemaTypeName(result)
+		}
+
+		data, _ := json.MarshalIndent(example, "", "  ")
+		f.Example = string(data)
+
+		page.Functions = append(page.Functions, f)
+	}
+
+	for name, s := range schemas {
+		if s.Type != "object" || strings.HasSuffix(name, ".Request") || strings.HasSuffix(name, ".Response") || name == "RPCError" {
+			continue
+		}
+
+		page.Types = append(page.Types, docsType{Name: name, Fields: docsFields(s)})
+	}
+
+	sort.Slice(page.Types, func(i, j int) bool { return page.Types[i].Name < page.Types[j].Name })
+
+	return page
+}
+
+// streamDocs documents the stream function fn from its path in doc.
+func streamDocs(fn *Function, doc *OpenAPIDocument, basePath string) docsFunction {
+	endpoint := basePath + "/stream/" + fn.Name
+	op := doc.Paths[endpoint]["get"]
+
+	f := docsFunction{
+		Name:        fn.Name,
+		Description: fn.Description,
+		Endpoint:    endpoint,
+		Methods:     []string{"GET"},
+		Secured:     op.Security != nil,
+		Roles:       fn.RequireRoles,
+		RateLimit:   fn.RateLimit,
+		Example:     "GET " + endpoint,
+	}
+
+	events := op.Responses["200"].Content["text/event-stream"].Schema
+	f.Result = "stream of " + schemaTypeName(events.OneOf[0].Properties["data"])
+
+	for _, p := range op.Parameters {
+		if p.Name != "params" {
+			continue
+		}
+
+		params := p.Content["application/json"].Schema
+		f.Params = docsFields(params)
+
+		data, _ := json.Marshal(exampleValue(params, doc.Components.Schemas, 0))
+		f.Example += "?params=" + string(data)
+	}
+
+	return f
+}
+
+// docsFields returns the properties of an object schema, sorted by name.
+func docsFields(s *JSONSchema) []docsField {
+	fields := make([]docsField, 0, len(s.Properties))
+
+	for name, prop := range s.Properties {
+		fields = append(fields, docsField{
+			Name:        name,
+			Type:        schemaTypeName(prop),
+			Required:    slices.Contains(s.Required, name),
+			Constraints: constraints(prop),
+		})
+	}
+
+	sort.Slice(fields, func(i, j int) bool { return fields[i].Name < fields[j].Name })
+
+	return fields
+}
+
+// schemaTypeName describes the type of values of s.
+func schemaTypeName(s *JSONSchema) string {
+	switch {
+	case s.Ref != "":
+		return strings.TrimPrefix(s.Ref, "#/components/schemas/")
+	case s.Type == "array":
+		return schemaTypeName(s.Items) + "[]"
+	case s.Type == "object" && s.AdditionalProperties != nil:
+		return "map of " + schemaTypeName(s.AdditionalProperties)
+	case s.Type == "":
+		return "any"
+	default:
+		return s.Type
+	}
+}
+
+// constraints describes the format and bounds of s.
+func constraints(s *JSONSchema) string {
+	var parts []string
+
+	add := func(format string, args ...any) {
+		parts = append(parts, fmt.Sprintf(format, args...))
+	}
+
+	if s.Format != "" && s.Format != "int32" && s.Format != "int64" && s.Format != "float" && s.Format != "double" {
+		add("%s", s.Format)
+	}
+
+	if len(s.Enum) > 0 {
+		values := make([]string, len(s.Enum))
+		for i, v := range s.Enum {
+			values[i] = fmt.Sprint(v)
+		}
+
+		add("one of %s", strings.Join(values, ", "))
+	}
+
+	bounds := []struct {
+		label string
+		n     *float64
+	}{
+		{">= %g", s.Minimum},
+		{"<= %g", s.Maximum},
+		{"> %g", s.ExclusiveMinimum},
+		{"< %g", s.ExclusiveMaximum},
+	}
+
+	for _, b := range bounds {
+		if b.n != nil {
+			add(b.label, *b.n)
+		}
+	}
+
+	sizes := []struct {
+		label string
+		n     *int
+	}{
+		{"min length %d", s.MinLength},
+		{"max length %d", s.MaxLength},
+		{"min items %d", s.MinItems},
+		{"max items %d", s.MaxItems},
+	}
+
+	for _, b := range sizes {
+		if b.n != nil {
+			add(b.label, *b.n)
+		}
+	}
+
+	return strings.Join(parts, "; ")
+}
+
+// exampleValue returns a placeholder value matching s, following
+// references a few levels deep.
+func exampleValue(s *JSONSchema, schemas map[string]*JSONSchema, depth int) any {
+	if s.Ref != "" {
+		ref := schemas[strings.TrimPrefix(s.Ref, "#/components/schemas/")]
+		if ref == nil || depth > 2 {
+			return map[string]any{}
+		}
+
+		return exampleValue(ref, schemas, depth+1)
+	}
+
+	if len(s.Enum) > 0 {
+		return s.Enum[0]
+	}
+
+	switch s.Type {
+	case "string":
+		switch s.Format {
+		case "date-time":
+			return "2006-01-02T15:04:05Z"
+		case "email":
+			return "user@example.com"
+		case "uuid":
+			return "00000000-0000-0000-0000-000000000000"
+		}
+
+		return ""
+	case "integer", "number":
+		if s.Minimum != nil {
+			return *s.Minimum
+		}
+
+		return 0
+	case "boolean":
+		return false
+	case "array":
+		return []any{}
+	case "object":
+		obj := make(map[string]any, len(s.Properties))
+		for name, prop := range s.Properties {
+			obj[name] = exampleValue(prop, schemas, depth+1)
+		}
+
+		return obj
+	default:
+		return nil
+	}
+}
@@ -8,6 +8,9 @@ import (
 	"reflect"
 	"runtime/debug"
 	"time"
+
+	"github.com/xraph/forgeui/ratelimit"
+	"github.com/xraph/forgeui/tracing"
 )
 
 // ExecuteResult holds the result of a function execution
@@ -17,9 +20,12 @@ type ExecuteResult struct {
 }
 
 // execute runs a registered function with the given parameters
-func (b *Bridge) execute(ctx Context, funcName string, params json.RawMessage) ExecuteResult {
+func (b *Bridge) execute(ctx Context, funcName string, params json.RawMessage) (res ExecuteResult) {
 	startTime := time.Now()
 
+	ctx, span := startSpan(ctx, "bridge.execute", tracing.String(tracing.KeyFunction, funcName))
+	defer func() { endSpan(span, res.Error) }()
+
 	// Get the function
 	fn, err := b.GetFunction(funcName)
 	if err != nil {
@@ -28,60 +34,30 @@ func (b *Bridge) execute(ctx Context, funcName string, params json.RawMessage) E
 		}
 	}
 
+	if fn.SignatureType == SigStream {
+		return ExecuteResult{Error: errStreamOnly(fn)}
+	}
+
 	// Trigger before hook
 	b.hooks.Trigger(BeforeCall, ctx, HookData{
 		FunctionName: funcName,
 		Params:       params,
 	})
 
-	var paramValue reflect.Value
-
-	if fn.HasInput {
-		// Parse parameters
-		var parseErr error
-		paramValue, parseErr = parseParams(params, fn.InputType)
-		if parseErr != nil {
-			var bridgeErr *Error
-			if errors.As(parseErr, &bridgeErr) {
-				return ExecuteResult{Error: bridgeErr}
-			}
-
-			return ExecuteResult{
-				Error: NewError(ErrCodeInvalidParams, "Invalid parameters", parseErr.Error()),
-			}
-		}
-
-		// Validate parameters
-		if fn.LaxValidation {
-			if validateErr := validateParamsLax(paramValue, fn.InputType); validateErr != nil {
-				var bridgeErr *Error
-				if errors.As(validateErr, &bridgeErr) {
-					return ExecuteResult{Error: bridgeErr}
-				}
-
-				return ExecuteResult{
-					Error: NewError(ErrCodeInvalidParams, "Parameter validation failed", validateErr.Error()),
-				}
-			}
-		} else {
-			if validateErr := validateParams(paramValue, fn.InputType); validateErr != nil {
-				var bridgeErr *Error
-				if errors.As(validateErr, &bridgeErr) {
-					return ExecuteResult{Error: bridgeErr}
-				}
+	// Execute with timeout, unless the params are invalid. Invalid calls
+	// still reach the hooks and the access log.
+	paramValue, paramErr := decodeParams(fn, params)
 
-				return ExecuteResult{
-					Error: NewError(ErrCodeInvalidParams, "Parameter validation failed", validateErr.Error()),
-				}
-			}
-		}
+	var result ExecuteResult
+	if paramErr != nil {
+		result = ExecuteResult{Error: paramErr}
+	} else {
+		result = b.executeWithTimeout(ctx, fn, paramValue)
 	}
 
-	// Execute with timeout
-	result := b.executeWithTimeout(ctx, fn, paramValue)
-
 	// Calculate duration
-	duration := time.Since(startTime).Microseconds()
+	elapsed := time.Since(startTime)
+	duration := elapsed.Microseconds()
 
 	// Trigger after hook
 	hookData := HookData{
@@ -90,7 +66,10 @@ func (b *Bridge) execute(ctx Context, funcName string, params json.RawMessage) E
 		Result:       result.Result,
 	}
 
-	if fn.HasInput {
+	switch {
+	case paramErr != nil:
+		hookData.Params = params
+	case fn.HasInput:
 		hookData.Params = paramValue.Interface()
 	}
 
@@ -103,49 +82,45 @@ func (b *Bridge) execute(ctx Context, funcName string, params json.RawMessage) E
 
 	b.hooks.Trigger(AfterCall, ctx, hookData)
 
+	b.logCall(ctx, fn.Name, elapsed, result.Error)
+
 	return result
 }
 
 // executeDirect runs a registered function with a pre-parsed parameter value.
 // This avoids the JSON round-trip used by execute() and is used by the HTMX handler.
-func (b *Bridge) executeDirect(ctx Context, fn *Function, paramValue reflect.Value) ExecuteResult {
+func (b *Bridge) executeDirect(ctx Context, fn *Function, paramValue reflect.Value) (res ExecuteResult) {
 	startTime := time.Now()
 
+	ctx, span := startSpan(ctx, "bridge.execute", tracing.String(tracing.KeyFunction, fn.Name))
+	defer func() { endSpan(span, res.Error) }()
+
+	if fn.SignatureType == SigStream {
+		return ExecuteResult{Error: errStreamOnly(fn)}
+	}
+
 	// Trigger before hook
 	b.hooks.Trigger(BeforeCall, ctx, HookData{
 		FunctionName: fn.Name,
 	})
 
-	// Validate parameters if needed
-	if fn.HasInput && !fn.LaxValidation {
-		if validateErr := validateParams(paramValue, fn.InputType); validateErr != nil {
-			var bridgeErr *Error
-			if errors.As(validateErr, &bridgeErr) {
-				return ExecuteResult{Error: bridgeErr}
-			}
-
-			return ExecuteResult{
-				Error: NewError(ErrCodeInvalidParams, "Parameter validation failed", validateErr.Error()),
-			}
-		}
-	} else if fn.HasInput && fn.LaxValidation {
-		if validateErr := validateParamsLax(paramValue, fn.InputType); validateErr != nil {
-			var bridgeErr *Error
-			if errors.As(validateErr, &bridgeErr) {
-				return ExecuteResult{Error: bridgeErr}
-			}
-
-			return ExecuteResult{
-				Error: NewError(ErrCodeInvalidParams, "Parameter validation failed", validateErr.Error()),
-			}
-		}
+	// Validate parameters if needed, then execute with timeout. Invalid
+	// calls still reach the hooks and the access log.
+	var validateErr *Error
+	if fn.HasInput {
+		validateErr = validateInput(fn, paramValue)
 	}
 
-	// Execute with timeout
-	result := b.executeWithTimeout(ctx, fn, paramValue)
+	var result ExecuteResult
+	if validateErr != nil {
+		result = ExecuteResult{Error: validateErr}
+	} else {
+		result = b.executeWithTimeout(ctx, fn, paramValue)
+	}
 
 	// Calculate duration
-	duration := time.Since(startTime).Microseconds()
+	elapsed := time.Since(startTime)
+	duration := elapsed.Microseconds()
 
 	// Trigger after hook
 	hookData := HookData{
@@ -167,9 +142,55 @@ func (b *Bridge) executeDirect(ctx Context, fn *Function, paramValue reflect.Val
 
 	b.hooks.Trigger(AfterCall, ctx, hookData)
 
+	b.logCall(ctx, fn.Name, elapsed, result.Error)
+
 	return result
 }
 
+// decodeParams parses and validates the JSON parameters of fn. It returns
+// the zero Value for functions without input.
+func decodeParams(fn *Function, params json.RawMessage) (reflect.Value, *Error) {
+	if !fn.HasInput {
+		return reflect.Value{}, nil
+	}
+
+	paramValue, parseErr := parseParams(params, fn.InputType)
+	if parseErr != nil {
+		var bridgeErr *Error
+		if errors.As(parseErr, &bridgeErr) {
+			return reflect.Value{}, bridgeErr
+		}
+
+		return reflect.Value{}, NewError(ErrCodeInvalidParams, "Invalid parameters", parseErr.Error())
+	}
+
+	if validateErr := validateInput(fn, paramValue); validateErr != nil {
+		return reflect.Value{}, validateErr
+	}
+
+	return paramValue, nil
+}
+
+// validateInput validates the parameters of fn, laxly for functions
+// registered WithLaxValidation.
+func validateInput(fn *Function, paramValue reflect.Value) *Error {
+	validate := validateParams
+	if fn.LaxValidation {
+		validate = validateParamsLax
+	}
+
+	if validateErr := validate(paramValue, fn.InputType); validateErr != nil {
+		var bridgeErr *Error
+		if errors.As(validateErr, &bridgeErr) {
+			return bridgeErr
+		}
+
+		return NewError(ErrCodeInvalidParams, "Parameter validation failed", validateErr.Error())
+	}
+
+	return nil
+}
+
 // executeWithTimeout executes a function with timeout and panic recovery
 func (b *Bridge) executeWithTimeout(ctx Context, fn *Function, paramValue reflect.Value) ExecuteResult {
 	// Create context with timeout
@@ -295,7 +316,41 @@ func (b *Bridge) Call(ctx Context, funcName string, params json.RawMessage) (any
 	return result.Result, nil
 }
 
-// CallBatch executes multiple functions in parallel
+// checkCall checks that the caller of ctx may call fn, and applies fn's
+// rate limit. It is run for every call from a client, single or batched,
+// and returns the state of the caller's quota.
+func (b *Bridge) checkCall(ctx Context, security *Security, fn *Function) (*ratelimit.Result, *Error) {
+	if err := security.CheckAuth(ctx, fn); err != nil {
+		var bridgeErr *Error
+		if errors.As(err, &bridgeErr) {
+			return nil, bridgeErr
+		}
+
+		return nil, ErrUnauthorized
+	}
+
+	res, rlErr := security.RateLimit(ctx, fn)
+	if rlErr != nil {
+		b.metrics.rateLimit(fn.Name)
+	}
+
+	return res, rlErr
+}
+
+// call checks a call from a client and executes it. Unknown functions are
+// left to execute to report.
+func (b *Bridge) call(ctx Context, security *Security, funcName string, params json.RawMessage) ExecuteResult {
+	if fn, err := b.GetFunction(funcName); err == nil {
+		if _, checkErr := b.checkCall(ctx, security, fn); checkErr != nil {
+			return ExecuteResult{Error: checkErr}
+		}
+	}
+
+	return b.execute(ctx, funcName, params)
+}
+
+// CallBatch executes multiple functions in parallel, checking each call
+// like a single request
 func (b *Bridge) CallBatch(ctx Context, requests []Request) []Response {
 	// Limit batch size
 	if len(requests) > b.config.MaxBatchSize {
@@ -305,6 +360,10 @@ func (b *Bridge) CallBatch(ctx Context, requests []Request) []Response {
 		}}
 	}
 
+	ctx, span := startSpan(ctx, "bridge.batch", tracing.Int(tracing.KeyBatchSize, len(requests)))
+	defer span.End()
+
+	security := NewSecurity(b.config)
 	responses := make([]Response, len(requests))
 
 	// Execute all requests in parallel
@@ -312,7 +371,7 @@ func (b *Bridge) CallBatch(ctx Context, requests []Request) []Response {
 
 	for i, req := range requests {
 		go func(index int, request Request) {
-			result := b.execute(ctx, request.Method, request.Params)
+			result := b.call(ctx, security, request.Method, request.Params)
 
 			responses[index] = Response{
 				JSONRPC: "2.0",
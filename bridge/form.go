@@ -0,0 +1,120 @@
+package bridge
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"github.com/xraph/forgeui/i18n"
+)
+
+// DecodeForm decodes form values into the struct dst points to, with the
+// same rules as form-encoded bridge calls: fields are keyed by their json tag
+// name (or field name) and strings are converted to the field type.
+//
+// It returns a message for each field whose value could not be converted,
+// keyed by form key, or nil if every value was decoded.
+func DecodeForm(values url.Values, dst any) map[string]string {
+	elem, err := structElem(dst)
+	if err != nil {
+		return map[string]string{"": err.Error()}
+	}
+
+	if !elem.CanSet() {
+		return map[string]string{"": fmt.Sprintf("form target must be a pointer to a struct, got %T", dst)}
+	}
+
+	var errs map[string]string
+
+	for _, fe := range decodeFields(values, elem) {
+		if errs == nil {
+			errs = make(map[string]string)
+		}
+
+		errs[fe.key] = fe.err.Error()
+	}
+
+	return errs
+}
+
+// ValidateForm checks the validate tags of the struct v (or v points to),
+// such as validate:"required,email", and returns a message for each failing
+// field keyed by form key, or nil if the struct is valid. Unlike bridge
+// calls, fields without a validate tag are optional.
+func ValidateForm(v any) map[string]string {
+	return validateForm(v, func(_, _ string, err error) string { return err.Error() })
+}
+
+// ValidateFormContext is ValidateForm with messages in the request's locale
+// (see the i18n package). A failing rule uses the message
+// "validation.<rule>", such as "validation.required", with the {field}
+// argument; rules without a message keep the English text.
+func ValidateFormContext(ctx context.Context, v any) map[string]string {
+	return validateForm(v, func(key, rule string, err error) string {
+		if l := i18n.FromContext(ctx); l != nil && l.Has("validation."+rule) {
+			return l.T("validation."+rule, i18n.Args{"field": key})
+		}
+
+		return err.Error()
+	})
+}
+
+// validateForm validates v, turning each failure into a message with msg.
+func validateForm(v any, msg func(key, rule string, err error) string) map[string]string {
+	elem, err := structElem(v)
+	if err != nil {
+		return map[string]string{"": err.Error()}
+	}
+
+	var errs map[string]string
+
+	structType := elem.Type()
+
+	for i := range structType.NumField() {
+		field := structType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		key, ok := formKey(field)
+		if !ok {
+			continue
+		}
+
+		for rule := range strings.SplitSeq(field.Tag.Get("validate"), ",") {
+			rule = strings.TrimSpace(rule)
+			if rule == "" {
+				continue
+			}
+
+			if err := validateWithTag(key, elem.Field(i), rule); err != nil {
+				if errs == nil {
+					errs = make(map[string]string)
+				}
+
+				errs[key] = msg(key, rule, err)
+
+				break
+			}
+		}
+	}
+
+	return errs
+}
+
+// structElem returns the struct value v holds or points to.
+func structElem(v any) (reflect.Value, error) {
+	elem := reflect.ValueOf(v)
+	for elem.Kind() == reflect.Ptr && !elem.IsNil() {
+		elem = elem.Elem()
+	}
+
+	if elem.Kind() != reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("form target must be a struct, got %T", v)
+	}
+
+	return elem, nil
+}
+
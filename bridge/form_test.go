@@ -0,0 +1,89 @@
+package bridge
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/xraph/forgeui/i18n"
+)
+
+type formTestParams struct {
+	Email  string `json:"email" validate:"required,email"`
+	Name   string `json:"name" validate:"required"`
+	Age    int    `json:"age"`
+	Secret string `json:"-"`
+}
+
+func TestDecodeForm(t *testing.T) {
+	var form formTestParams
+
+	errs := DecodeForm(url.Values{
+		"email":  {"jane@example.com"},
+		"name":   {"Jane"},
+		"age":    {"30"},
+		"Secret": {"x"},
+	}, &form)
+	if errs != nil {
+		t.Fatalf("DecodeForm() errors = %v", errs)
+	}
+
+	if form.Email != "jane@example.com" || form.Name != "Jane" || form.Age != 30 {
+		t.Errorf("Unexpected form: %+v", form)
+	}
+
+	if form.Secret != "" {
+		t.Error("Fields tagged json:\"-\" should be skipped")
+	}
+}
+
+func TestDecodeForm_Errors(t *testing.T) {
+	var form formTestParams
+
+	errs := DecodeForm(url.Values{"age": {"old"}}, &form)
+	if errs["age"] == "" {
+		t.Errorf("Expected error for age, got %v", errs)
+	}
+
+	if errs := DecodeForm(url.Values{}, form); errs == nil {
+		t.Error("Expected error for a non-pointer target")
+	}
+}
+
+func TestValidateForm(t *testing.T) {
+	errs := ValidateForm(&formTestParams{Email: "nope"})
+
+	if errs["email"] == "" {
+		t.Error("Expected error for invalid email")
+	}
+
+	if errs["name"] == "" {
+		t.Error("Expected error for missing name")
+	}
+
+	if _, ok := errs["age"]; ok {
+		t.Error("Fields without validate tags should be optional")
+	}
+
+	if errs := ValidateForm(formTestParams{Email: "a@b.co", Name: "Jane"}); errs != nil {
+		t.Errorf("Expected valid form, got %v", errs)
+	}
+}
+
+func TestValidateFormContext(t *testing.T) {
+	b := i18n.NewBundle("en")
+	_ = b.AddMessages("de", map[string]any{
+		"validation": map[string]any{"required": "{field} ist erforderlich"},
+	})
+
+	ctx := i18n.NewContext(context.Background(), b.Localizer("de"))
+	errs := ValidateFormContext(ctx, &formTestParams{Email: "nope"})
+
+	if errs["name"] != "name ist erforderlich" {
+		t.Errorf("Expected a translated message, got %q", errs["name"])
+	}
+
+	if errs["email"] != ValidateForm(&formTestParams{Email: "nope"})["email"] {
+		t.Errorf("Expected rules without a message to keep the English text, got %q", errs["email"])
+	}
+}
@@ -2,10 +2,14 @@ package bridge
 
 import (
 	"fmt"
+	"net/http"
 	"reflect"
 	"time"
 
 	"github.com/a-h/templ"
+
+	"github.com/xraph/forgeui/auth"
+	"github.com/xraph/forgeui/ratelimit"
 )
 
 // SignatureType describes the function signature shape
@@ -20,6 +24,8 @@ const (
 	SigInputOnly
 	// SigVoid: func(Context) error - no input, no output
 	SigVoid
+	// SigStream: func(Context[, Input], Stream[Chunk]) error - streams chunks
+	SigStream
 )
 
 // Function represents a registered bridge function
@@ -36,6 +42,10 @@ type Function struct {
 	// OutputType is the type of the output value (nil if no output)
 	OutputType reflect.Type
 
+	// ChunkType is the type of the chunks a stream function sends (nil
+	// for other functions)
+	ChunkType reflect.Type
+
 	// Description is the function's documentation
 	Description string
 
@@ -45,12 +55,22 @@ type Function struct {
 	// RequireRoles specifies required user roles
 	RequireRoles []string
 
+	// Policy authorizes calls on top of RequireAuth and RequireRoles
+	Policy auth.Policy
+
 	// Timeout is the maximum execution time
 	Timeout time.Duration
 
 	// RateLimit is the max requests per minute (0 = no limit)
 	RateLimit int
 
+	// Limiter limits calls in place of the bridge's limiter
+	Limiter ratelimit.Limiter
+
+	// RateLimitKey returns the key calls are limited by, the user or the
+	// client IP when nil
+	RateLimitKey ratelimit.KeyFunc
+
 	// Cacheable indicates if results can be cached
 	Cacheable bool
 
@@ -108,6 +128,14 @@ func RequireRoles(roles ...string) FunctionOption {
 	}
 }
 
+// Authorize applies an auth.Policy to calls, the same policies pages use.
+// Anonymous callers it denies get ErrUnauthorized, users ErrForbidden.
+func Authorize(policy auth.Policy) FunctionOption {
+	return func(f *Function) {
+		f.Policy = policy
+	}
+}
+
 // WithFunctionTimeout sets a custom timeout for a function
 func WithFunctionTimeout(d time.Duration) FunctionOption {
 	return func(f *Function) {
@@ -122,6 +150,26 @@ func WithRateLimit(rpm int) FunctionOption {
 	}
 }
 
+// WithFunctionLimiter limits calls of a function with l instead of the
+// bridge's limiter (see WithLimiter)
+func WithFunctionLimiter(l ratelimit.Limiter) FunctionOption {
+	return func(f *Function) {
+		f.Limiter = l
+	}
+}
+
+// WithRateLimitKey sets what calls of a function are limited by, such as
+// an API key or tenant:
+//
+//	bridge.WithRateLimitKey(ratelimit.ByHeader("X-Tenant-ID"))
+//
+// Keys are scoped to the function, so functions don't share quotas.
+func WithRateLimitKey(key ratelimit.KeyFunc) FunctionOption {
+	return func(f *Function) {
+		f.RateLimitKey = key
+	}
+}
+
 // WithFunctionCache enables result caching for a function
 func WithFunctionCache(ttl time.Duration) FunctionOption {
 	return func(f *Function) {
@@ -192,6 +240,27 @@ func WithRenderer[T any](renderer func(T) templ.Component) FunctionOption {
 	}
 }
 
+// httpMethods returns the HTTP methods the HTMX handler accepts for the
+// function. If AllowedMethods is explicitly set, use that. Otherwise
+// auto-detect: functions without input default to GET+POST, functions with
+// input default to POST only.
+func (f *Function) httpMethods() []string {
+	if len(f.AllowedMethods) > 0 {
+		return f.AllowedMethods
+	}
+
+	if f.HasInput {
+		return []string{http.MethodPost}
+	}
+
+	return []string{http.MethodGet, http.MethodPost}
+}
+
+// rateLimited reports whether calls of the function are rate limited.
+func (f *Function) rateLimited() bool {
+	return f.RateLimit > 0 || f.Limiter != nil
+}
+
 // validateFunction validates a function signature and returns its shape.
 //
 // Supported signatures:
@@ -199,6 +268,8 @@ func WithRenderer[T any](renderer func(T) templ.Component) FunctionOption {
 //   - func(Context) (Output, error)         → SigOutput
 //   - func(Context, Input) error            → SigInputOnly
 //   - func(Context) error                   → SigVoid
+//   - func(Context, Input, Stream[T]) error → SigStream
+//   - func(Context, Stream[T]) error        → SigStream
 func validateFunction(fn any) (SignatureType, error) {
 	fnType := reflect.TypeOf(fn)
 
@@ -209,6 +280,10 @@ func validateFunction(fn any) (SignatureType, error) {
 	numIn := fnType.NumIn()
 	numOut := fnType.NumOut()
 
+	if numIn > 1 && isStreamType(fnType.In(numIn-1)) {
+		return validateStreamFunction(fnType)
+	}
+
 	if numIn < 1 || numIn > 2 {
 		return 0, fmt.Errorf("handler must have 1 or 2 parameters (Context[, Input]), got %d", numIn)
 	}
@@ -257,6 +332,24 @@ func validateFunction(fn any) (SignatureType, error) {
 	return 0, fmt.Errorf("unsupported signature: %d inputs, %d outputs", numIn, numOut)
 }
 
+// validateStreamFunction validates the signature of a stream function,
+// whose last parameter is a Stream.
+func validateStreamFunction(fnType reflect.Type) (SignatureType, error) {
+	if fnType.NumIn() > 3 {
+		return 0, fmt.Errorf("stream handler must have 2 or 3 parameters (Context[, Input], Stream), got %d", fnType.NumIn())
+	}
+
+	if !fnType.In(0).Implements(reflect.TypeFor[Context]()) {
+		return 0, fmt.Errorf("first parameter must implement bridge.Context, got %s", fnType.In(0))
+	}
+
+	if fnType.NumOut() != 1 || !fnType.Out(0).Implements(reflect.TypeFor[error]()) {
+		return 0, fmt.Errorf("stream handler must return only error")
+	}
+
+	return SigStream, nil
+}
+
 // analyzeFunction extracts type information from a function
 func analyzeFunction(fn any) (*Function, error) {
 	sigType, err := validateFunction(fn)
@@ -286,6 +379,14 @@ func analyzeFunction(fn any) (*Function, error) {
 		f.HasInput = true
 	case SigVoid:
 		// no input, no output
+	case SigStream:
+		numIn := fnType.NumIn()
+		if numIn == 3 {
+			f.InputType = fnType.In(1)
+			f.HasInput = true
+		}
+
+		f.ChunkType = fnType.In(numIn - 1).In(0)
 	}
 
 	// Detect if output type implements templ.Component
@@ -301,6 +402,7 @@ type TypeInfo struct {
 	Name       string      `json:"name"`
 	InputType  string      `json:"inputType"`
 	OutputType string      `json:"outputType"`
+	ChunkType  string      `json:"chunkType,omitempty"`
 	Fields     []FieldInfo `json:"fields,omitempty"`
 }
 
@@ -332,6 +434,10 @@ func (f *Function) GetTypeInfo() TypeInfo {
 		info.OutputType = f.OutputType.String()
 	}
 
+	if f.ChunkType != nil {
+		info.ChunkType = f.ChunkType.String()
+	}
+
 	return info
 }
 
@@ -78,6 +78,7 @@ func (h *HTMXHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Create bridge context
+	r = h.bridge.extractTrace(r)
 	ctx := NewContext(r)
 
 	// Check authentication
@@ -87,9 +88,13 @@ func (h *HTMXHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Check rate limit
-	rateLimitKey := getRateLimitKey(ctx)
-	if rlErr := h.security.CheckRateLimit(rateLimitKey, fn); rlErr != nil {
+	res, rlErr := h.security.RateLimit(ctx, fn)
+	setRateLimitHeaders(w, res)
+
+	if rlErr != nil {
+		h.bridge.metrics.rateLimit(fn.Name)
 		http.Error(w, "Rate limit exceeded", http.StatusTooManyRequests)
+
 		return
 	}
 
@@ -126,20 +131,8 @@ func (h *HTMXHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 }
 
 // isMethodAllowed checks if the HTTP method is allowed for the function.
-// If AllowedMethods is explicitly set, use that. Otherwise auto-detect:
-// functions without input default to GET+POST, functions with input default to POST only.
 func (h *HTMXHandler) isMethodAllowed(fn *Function, method string) bool {
-	allowed := fn.AllowedMethods
-	if len(allowed) == 0 {
-		// Auto-detect based on signature
-		if fn.HasInput {
-			allowed = []string{http.MethodPost}
-		} else {
-			allowed = []string{http.MethodGet, http.MethodPost}
-		}
-	}
-
-	for _, m := range allowed {
+	for _, m := range fn.httpMethods() {
 		if strings.EqualFold(m, method) {
 			return true
 		}
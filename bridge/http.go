@@ -63,6 +63,7 @@ func (h *HTTPHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	defer func() { _ = r.Body.Close() }()
 
 	// Create bridge context
+	r = h.bridge.extractTrace(r)
 	ctx := NewContext(r)
 
 	// Try to parse as single request first
@@ -100,28 +101,12 @@ func (h *HTTPHandler) handleSingleRequest(w http.ResponseWriter, ctx Context, re
 		return
 	}
 
-	// Check authentication
-	if err := h.security.CheckAuth(ctx, fn); err != nil {
-		var bridgeErr *Error
-		if errors.As(err, &bridgeErr) {
-			h.writeError(w, req.ID, bridgeErr)
-		} else {
-			h.writeError(w, req.ID, ErrUnauthorized)
-		}
-
-		return
-	}
-
-	// Check rate limit
-	rateLimitKey := getRateLimitKey(ctx)
-	if err := h.security.CheckRateLimit(rateLimitKey, fn); err != nil {
-		var bridgeErr *Error
-		if errors.As(err, &bridgeErr) {
-			h.writeError(w, req.ID, bridgeErr)
-		} else {
-			h.writeError(w, req.ID, ErrRateLimit)
-		}
+	// Check authentication and rate limit
+	res, checkErr := h.bridge.checkCall(ctx, h.security, fn)
+	setRateLimitHeaders(w, res)
 
+	if checkErr != nil {
+		h.writeError(w, req.ID, checkErr)
 		return
 	}
 
@@ -6,6 +6,8 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+
+	"github.com/xraph/forgeui/auth"
 )
 
 func TestHTTPHandler_SingleRequest(t *testing.T) {
@@ -108,6 +110,50 @@ func TestHTTPHandler_BatchRequest(t *testing.T) {
 	}
 }
 
+func TestHTTPHandler_BatchRequest_Auth(t *testing.T) {
+	b := New(WithCSRF(false))
+
+	secret := func(ctx Context) (string, error) { return "secret", nil }
+
+	_ = b.Register("public", secret)
+	_ = b.Register("private", secret, RequireAuth())
+	_ = b.Register("admin", secret, RequireAuth(), RequireRoles("admin"))
+	_ = b.Register("policy", secret, Authorize(auth.Authenticated()))
+
+	batchReq := BatchRequest{
+		{JSONRPC: "2.0", ID: "1", Method: "public"},
+		{JSONRPC: "2.0", ID: "2", Method: "private"},
+		{JSONRPC: "2.0", ID: "3", Method: "admin"},
+		{JSONRPC: "2.0", ID: "4", Method: "policy"},
+	}
+
+	body, _ := json.Marshal(batchReq)
+
+	w := httptest.NewRecorder()
+	NewHTTPHandler(b).ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/bridge", bytes.NewReader(body)))
+
+	var responses BatchResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &responses); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+
+	want := []int{0, ErrCodeUnauthorized, ErrCodeUnauthorized, ErrCodeUnauthorized}
+	for i, resp := range responses {
+		code := 0
+		if resp.Error != nil {
+			code = resp.Error.Code
+		}
+
+		if code != want[i] {
+			t.Errorf("responses[%d] code = %d, want %d", i, code, want[i])
+		}
+
+		if code != 0 && resp.Result != nil {
+			t.Errorf("responses[%d] result = %v, want none", i, resp.Result)
+		}
+	}
+}
+
 func TestHTTPHandler_MethodNotFound(t *testing.T) {
 	b := New(WithCSRF(false))
 	handler := NewHTTPHandler(b)
@@ -21,13 +21,15 @@ func (i *Integration) RegisterHTTPRoutes(mux *http.ServeMux) {
 	mux.Handle("/api/bridge/call", i.bridge.Handler())
 
 	// WebSocket endpoint
-	mux.Handle("/api/bridge/ws", NewWSHandler(i.bridge))
+	mux.Handle("/api/bridge/ws", i.bridge.WebSocketHandler())
 
 	// SSE streaming endpoint
 	mux.Handle("/api/bridge/stream/", i.bridge.StreamHandler())
 
-	// Introspection endpoint
+	// Introspection endpoints: function list, OpenAPI document and docs page
 	mux.Handle("/api/bridge/functions", i.bridge.IntrospectionHandler())
+	mux.Handle("/api/bridge"+OpenAPIPath, i.bridge.IntrospectionHandler())
+	mux.Handle("/api/bridge"+DocsPath, i.bridge.IntrospectionHandler())
 
 	// HTMX function endpoints (GET/POST returning HTML)
 	mux.Handle("/api/bridge/fn/", i.bridge.HTMXHandler())
@@ -40,9 +42,10 @@ func BridgeMiddleware(bridge *Bridge) func(http.Handler) http.Handler {
 			// Add bridge context
 			bridgeCtx := NewContext(r)
 
-			// Store in request context
+			// Store in request context, with the bridge for Publish
 			ctx := r.Context()
 			ctx = WithBridgeContext(ctx, bridgeCtx)
+			ctx = ContextWithBridge(ctx, bridge)
 			r = r.WithContext(ctx)
 
 			next.ServeHTTP(w, r)
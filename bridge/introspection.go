@@ -3,6 +3,9 @@ package bridge
 import (
 	"encoding/json"
 	"net/http"
+	"strings"
+
+	"github.com/xraph/forgeui/logging"
 )
 
 // FunctionInfo contains information about a registered function
@@ -31,23 +34,48 @@ func signatureTypeName(st SignatureType) string {
 		return "input-only"
 	case SigVoid:
 		return "void"
+	case SigStream:
+		return "stream"
 	default:
 		return "unknown"
 	}
 }
 
+// Paths the introspection handler serves the OpenAPI document and the docs
+// page at, relative to the bridge endpoints
+const (
+	OpenAPIPath = "/openapi.json"
+	DocsPath    = "/docs"
+)
+
 // IntrospectionHandler handles introspection requests
 type IntrospectionHandler struct {
-	bridge *Bridge
+	bridge  *Bridge
+	openAPI []OpenAPIOption
 }
 
-// NewIntrospectionHandler creates a new introspection handler
-func NewIntrospectionHandler(bridge *Bridge) *IntrospectionHandler {
-	return &IntrospectionHandler{bridge: bridge}
+// NewIntrospectionHandler creates a new introspection handler. opts
+// configure its OpenAPI document.
+func NewIntrospectionHandler(bridge *Bridge, opts ...OpenAPIOption) *IntrospectionHandler {
+	return &IntrospectionHandler{bridge: bridge, openAPI: opts}
 }
 
-// ServeHTTP handles introspection HTTP requests
+// ServeHTTP handles introspection HTTP requests. Paths ending in
+// OpenAPIPath get the OpenAPI document (see Bridge.OpenAPI) and paths
+// ending in DocsPath an HTML page documenting the functions; both describe
+// the bridge endpoints as served next to them, such as
+// /api/bridge/openapi.json for /api/bridge/call. Other paths get the
+// function list.
 func (h *IntrospectionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case strings.HasSuffix(r.URL.Path, OpenAPIPath):
+		h.serveOpenAPI(w, r)
+		return
+	case strings.HasSuffix(r.URL.Path, DocsPath):
+		h.serveDocs(w, r)
+		return
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 
 	// Get all registered functions
@@ -89,9 +117,38 @@ func (h *IntrospectionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 	}
 }
 
-// IntrospectionHandler returns an HTTP handler for function introspection
-func (b *Bridge) IntrospectionHandler() http.Handler {
-	return NewIntrospectionHandler(b)
+// options returns the OpenAPI options for a request to path ending in
+// suffix: the bridge endpoints are next to it, unless the handler's
+// options say otherwise.
+func (h *IntrospectionHandler) options(path, suffix string) []OpenAPIOption {
+	base := WithOpenAPIBasePath(strings.TrimSuffix(path, suffix))
+	return append([]OpenAPIOption{base}, h.openAPI...)
+}
+
+func (h *IntrospectionHandler) serveOpenAPI(w http.ResponseWriter, r *http.Request) {
+	doc := h.bridge.OpenAPI(h.options(r.URL.Path, OpenAPIPath)...)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	_ = json.NewEncoder(w).Encode(doc)
+}
+
+func (h *IntrospectionHandler) serveDocs(w http.ResponseWriter, r *http.Request) {
+	// The document is a sibling of the page
+	page := h.bridge.docs(strings.TrimPrefix(OpenAPIPath, "/"), h.options(r.URL.Path, DocsPath))
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	if err := docsPageView(page).Render(r.Context(), w); err != nil {
+		h.bridge.logger(r.Context()).ErrorContext(r.Context(), "failed to render bridge docs", logging.KeyError, err)
+	}
+}
+
+// IntrospectionHandler returns an HTTP handler for function introspection,
+// the OpenAPI document and the docs page. opts configure the document.
+func (b *Bridge) IntrospectionHandler(opts ...OpenAPIOption) http.Handler {
+	return NewIntrospectionHandler(b, opts...)
 }
 
 // GetFunctionInfo returns information about a specific function
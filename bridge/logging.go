@@ -0,0 +1,57 @@
+package bridge
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/xraph/forgeui/logging"
+)
+
+// WithLogger sets the logger for requests that don't carry one (see
+// logging.Middleware). Default: slog.Default()
+func WithLogger(l *slog.Logger) ConfigOption {
+	return func(c *Config) {
+		c.Logger = l
+	}
+}
+
+// logger returns the logger of the request behind ctx, falling back to the
+// configured logger.
+func (b *Bridge) logger(ctx context.Context) *slog.Logger {
+	if ctx != nil && logging.HasLogger(ctx) {
+		return logging.FromContext(ctx)
+	}
+
+	if b.config.Logger != nil {
+		return b.config.Logger
+	}
+
+	return slog.Default()
+}
+
+// logCall records a function call: at Debug when it succeeded, Warn when
+// it failed with a client error and Error when it failed internally.
+func (b *Bridge) logCall(ctx Context, name string, duration time.Duration, callErr *Error) {
+	level := slog.LevelDebug
+	attrs := []slog.Attr{
+		slog.String(logging.KeyFunction, name),
+		slog.Duration(logging.KeyDuration, duration),
+	}
+
+	if callErr != nil {
+		level = slog.LevelWarn
+		if callErr.Code == ErrCodeInternal {
+			level = slog.LevelError
+		}
+
+		attrs = append(attrs, slog.String(logging.KeyError, callErr.Message), slog.Int("code", callErr.Code))
+	}
+
+	reqCtx := ctx.Context()
+	if reqCtx == nil {
+		reqCtx = context.Background()
+	}
+
+	b.logger(reqCtx).LogAttrs(reqCtx, level, "bridge call", attrs...)
+}
@@ -0,0 +1,98 @@
+package bridge
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/xraph/forgeui/logging"
+)
+
+func TestBridge_LogsCalls(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	b := New(WithLogger(logger))
+
+	_ = b.Register("ok", func(ctx Context) (string, error) {
+		return "done", nil
+	})
+	_ = b.Register("fail", func(ctx Context) (string, error) {
+		return "", errors.New("boom")
+	})
+	_ = b.Register("typed", func(ctx Context, params struct {
+		N int `json:"n"`
+	}) (int, error) {
+		return params.N, nil
+	})
+
+	tests := []struct {
+		function  string
+		params    json.RawMessage
+		wantLevel string
+	}{
+		{"ok", nil, "DEBUG"},
+		{"fail", nil, "ERROR"},
+		{"typed", json.RawMessage(`{"n":"x"}`), "WARN"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.function, func(t *testing.T) {
+			buf.Reset()
+
+			b.execute(NewContext(httptest.NewRequest(http.MethodPost, "/", nil)), tt.function, tt.params)
+
+			var record map[string]any
+			if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+				t.Fatalf("Expected one JSON log record, got %q", buf.String())
+			}
+
+			if record["level"] != tt.wantLevel {
+				t.Errorf("Expected level %s, got %v", tt.wantLevel, record["level"])
+			}
+
+			if record[logging.KeyFunction] != tt.function {
+				t.Errorf("Expected function %s, got %v", tt.function, record[logging.KeyFunction])
+			}
+
+			if _, ok := record[logging.KeyDuration]; !ok {
+				t.Error("Expected a duration attribute")
+			}
+		})
+	}
+}
+
+func TestLoggerMiddleware(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	handler := Chain(
+		RequestIDMiddleware(),
+		LoggerMiddleware(logging.WithLogger(logger), logging.WithClientErrorLevel(slog.LevelInfo)),
+	)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if logging.RequestID(r.Context()) == "" {
+			t.Error("Expected a request ID in the context")
+		}
+
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/api/bridge/call", nil))
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("Expected one JSON log record, got %q", buf.String())
+	}
+
+	if record["level"] != "INFO" || record[logging.KeyStatus] != float64(http.StatusBadRequest) {
+		t.Errorf("Expected an INFO record with status 400, got %v", record)
+	}
+
+	if record[logging.KeyRequestID] == nil {
+		t.Error("Expected the request ID on the access log")
+	}
+}
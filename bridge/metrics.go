@@ -0,0 +1,125 @@
+package bridge
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/xraph/forgeui/metrics"
+)
+
+// WithMetrics aggregates the bridge's call hooks and connection counts in
+// reg:
+//
+//	forgeui_bridge_calls_total{function,code}
+//	forgeui_bridge_call_duration_seconds{function}
+//	forgeui_bridge_rate_limited_total{function}
+//	forgeui_bridge_connections{transport}
+//
+// code is "ok" for successful calls and the JSON-RPC error code otherwise;
+// transport is "websocket" or "sse". See MemoryCache.SetMetrics for cache
+// hits.
+func WithMetrics(reg *metrics.Registry) ConfigOption {
+	return func(c *Config) {
+		c.Metrics = reg
+	}
+}
+
+// Connection transports, as labelled in forgeui_bridge_connections.
+const (
+	transportWebSocket = "websocket"
+	transportSSE       = "sse"
+)
+
+type bridgeMetrics struct {
+	calls       *metrics.Counter
+	durations   *metrics.Histogram
+	rateLimited *metrics.Counter
+	connections *metrics.Gauge
+}
+
+func newBridgeMetrics(reg *metrics.Registry) *bridgeMetrics {
+	if reg == nil {
+		return nil
+	}
+
+	return &bridgeMetrics{
+		calls: reg.Counter("forgeui_bridge_calls_total",
+			"Bridge function calls, by function and result code.", "function", "code"),
+		durations: reg.Histogram("forgeui_bridge_call_duration_seconds",
+			"Bridge function execution time.", nil, "function"),
+		rateLimited: reg.Counter("forgeui_bridge_rate_limited_total",
+			"Bridge calls rejected by the rate limiter.", "function"),
+		connections: reg.Gauge("forgeui_bridge_connections",
+			"Open bridge WebSocket and SSE connections.", "transport"),
+	}
+}
+
+// afterCall is the AfterCall hook aggregating calls.
+func (m *bridgeMetrics) afterCall(_ Context, data HookData) {
+	code := "ok"
+
+	if data.Error != nil {
+		code = strconv.Itoa(ErrCodeInternal)
+
+		var bridgeErr *Error
+		if errors.As(data.Error, &bridgeErr) {
+			code = strconv.Itoa(bridgeErr.Code)
+		}
+	}
+
+	m.calls.Inc(data.FunctionName, code)
+	m.durations.Observe((time.Duration(data.Duration) * time.Microsecond).Seconds(), data.FunctionName)
+}
+
+// rateLimit counts a call rejected by the rate limiter.
+func (m *bridgeMetrics) rateLimit(function string) {
+	if m != nil {
+		m.rateLimited.Inc(function)
+	}
+}
+
+// connect counts an open connection and returns a func that counts it
+// closed.
+func (m *bridgeMetrics) connect(transport string) func() {
+	if m == nil {
+		return func() {}
+	}
+
+	m.connections.Inc(transport)
+
+	return func() { m.connections.Dec(transport) }
+}
+
+// cacheMetrics counts the hits and misses of a MemoryCache.
+type cacheMetrics struct {
+	name   string
+	hits   *metrics.Counter
+	misses *metrics.Counter
+}
+
+// SetMetrics counts the cache's hits and misses in reg, labelled with name:
+//
+//	forgeui_bridge_cache_hits_total{cache}
+//	forgeui_bridge_cache_misses_total{cache}
+func (c *MemoryCache) SetMetrics(reg *metrics.Registry, name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.metrics = &cacheMetrics{
+		name:   name,
+		hits:   reg.Counter("forgeui_bridge_cache_hits_total", "Bridge cache lookups that found a value.", "cache"),
+		misses: reg.Counter("forgeui_bridge_cache_misses_total", "Bridge cache lookups that found nothing.", "cache"),
+	}
+}
+
+// observe counts a lookup.
+func (m *cacheMetrics) observe(hit bool) {
+	switch {
+	case m == nil:
+	case hit:
+		m.hits.Inc(m.name)
+	default:
+		m.misses.Inc(m.name)
+	}
+}
@@ -0,0 +1,102 @@
+package bridge
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xraph/forgeui/metrics"
+)
+
+func TestBridge_Metrics(t *testing.T) {
+	reg := metrics.NewRegistry()
+	b := New(WithCSRF(false), WithMetrics(reg), WithLogger(slog.New(slog.DiscardHandler)))
+
+	_ = b.Register("ok", func(ctx Context) (string, error) {
+		return "done", nil
+	})
+	_ = b.Register("denied", func(ctx Context) (string, error) {
+		return "", ErrForbidden
+	})
+	_ = b.Register("fail", func(ctx Context) (string, error) {
+		return "", errors.New("boom")
+	})
+	_ = b.Register("typed", func(ctx Context, params struct {
+		N int `json:"n"`
+	}) (int, error) {
+		return params.N, nil
+	})
+	_ = b.Register("limited", func(ctx Context) (string, error) {
+		return "done", nil
+	}, WithRateLimit(1))
+
+	ctx := NewContext(httptest.NewRequest(http.MethodPost, "/", nil))
+	b.execute(ctx, "ok", nil)
+	b.execute(ctx, "ok", nil)
+	b.execute(ctx, "denied", nil)
+	b.execute(ctx, "fail", nil)
+	b.execute(ctx, "typed", json.RawMessage(`{"n":"x"}`))
+
+	// The default limit allows a burst of 2 * DefaultRateLimit calls
+	handler := NewHTTPHandler(b)
+	for range 2*b.config.DefaultRateLimit + 1 {
+		req := httptest.NewRequest(http.MethodPost, "/api/bridge/call", strings.NewReader(`{"jsonrpc":"2.0","id":"1","method":"limited"}`))
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	// Hooks run in goroutines, give them time to complete
+	time.Sleep(50 * time.Millisecond)
+
+	calls := reg.Counter("forgeui_bridge_calls_total", "", "function", "code")
+
+	tests := []struct {
+		function string
+		code     string
+		want     float64
+	}{
+		{"ok", "ok", 2},
+		{"denied", "-32005", 1},
+		{"fail", "-32603", 1},
+		{"typed", "-32602", 1},
+	}
+
+	for _, tt := range tests {
+		if got := calls.Value(tt.function, tt.code); got != tt.want {
+			t.Errorf("Expected %v %s calls with code %s, got %v", tt.want, tt.function, tt.code, got)
+		}
+	}
+
+	durations := reg.Histogram("forgeui_bridge_call_duration_seconds", "", nil, "function")
+	if durations.Count("ok") != 2 {
+		t.Errorf("Expected 2 ok durations, got %d", durations.Count("ok"))
+	}
+
+	limited := reg.Counter("forgeui_bridge_rate_limited_total", "", "function")
+	if limited.Value("limited") != 1 {
+		t.Errorf("Expected 1 rate-limited call, got %v", limited.Value("limited"))
+	}
+}
+
+func TestMemoryCache_Metrics(t *testing.T) {
+	reg := metrics.NewRegistry()
+
+	cache := NewMemoryCache()
+	cache.SetMetrics(reg, "results")
+	cache.Set("a", 1, time.Minute)
+
+	cache.Get("a")
+	cache.Get("a")
+	cache.Get("b")
+
+	hits := reg.Counter("forgeui_bridge_cache_hits_total", "", "cache")
+	misses := reg.Counter("forgeui_bridge_cache_misses_total", "", "cache")
+
+	if hits.Value("results") != 2 || misses.Value("results") != 1 {
+		t.Errorf("Expected 2 hits and 1 miss, got %v and %v", hits.Value("results"), misses.Value("results"))
+	}
+}
@@ -2,10 +2,16 @@ package bridge
 
 import (
 	"encoding/json"
-	"log"
+	"fmt"
+	"log/slog"
 	"net/http"
+	"runtime/debug"
 	"strconv"
 	"time"
+
+	"github.com/xraph/forgeui/auth"
+	"github.com/xraph/forgeui/logging"
+	"github.com/xraph/forgeui/session"
 )
 
 // Middleware wraps an http.Handler with additional functionality
@@ -22,23 +28,45 @@ func Chain(middlewares ...Middleware) Middleware {
 	}
 }
 
-// LoggerMiddleware logs HTTP requests
-func LoggerMiddleware() Middleware {
+// SessionMiddleware loads the session from store once per request, making it
+// available through Context.Session() (see session.Middleware).
+func SessionMiddleware(store session.Store) Middleware {
+	return session.Middleware(store)
+}
+
+// AuthMiddleware resolves the user with a once per request, making it
+// available through Context.User() (see auth.Middleware).
+func AuthMiddleware(a auth.Authenticator) Middleware {
+	return auth.Middleware(a)
+}
+
+// LoggerMiddleware writes an access log record for each request with the
+// method, path, status, duration and client IP. By default records go to
+// the request's logger at Info, 4xx responses at Warn and 5xx responses at
+// Error (see logging.AccessOption).
+func LoggerMiddleware(opts ...logging.AccessOption) Middleware {
+	o := logging.NewAccessOptions(opts...)
+
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			start := time.Now()
 
 			// Wrap response writer to capture status code
-			wrapped := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+			rec, wrapped := logging.NewStatusRecorder(w)
 
 			next.ServeHTTP(wrapped, r)
 
-			log.Printf(
-				"%s %s %d %s",
-				r.Method,
-				r.URL.Path,
-				wrapped.statusCode,
-				time.Since(start),
+			status := rec.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+
+			o.LoggerFor(r.Context()).LogAttrs(r.Context(), o.LevelFor(status), "request",
+				slog.String(logging.KeyMethod, r.Method),
+				slog.String(logging.KeyPath, r.URL.Path),
+				slog.Int(logging.KeyStatus, status),
+				slog.Duration(logging.KeyDuration, time.Since(start)),
+				slog.String(logging.KeyClientIP, GetClientIP(r)),
 			)
 		})
 	}
@@ -50,7 +78,12 @@ func RecoveryMiddleware() Middleware {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			defer func() {
 				if err := recover(); err != nil {
-					log.Printf("Panic recovered: %v", err)
+					logging.FromContext(r.Context()).Error("panic recovered",
+						logging.KeyMethod, r.Method,
+						logging.KeyPath, r.URL.Path,
+						"panic", fmt.Sprint(err),
+						"stack", string(debug.Stack()),
+					)
 
 					w.Header().Set("Content-Type", "application/json")
 					w.WriteHeader(http.StatusInternalServerError)
@@ -105,35 +138,24 @@ func CORSMiddleware(allowedOrigins []string) Middleware {
 	}
 }
 
-// RequestIDMiddleware adds a unique request ID
+// RequestIDMiddleware adds a unique request ID, reusing the X-Request-ID
+// request header when present. The ID is echoed in the response header and
+// added to the request's logger (see logging.RequestID).
 func RequestIDMiddleware() Middleware {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			requestID := r.Header.Get("X-Request-ID")
+			requestID := r.Header.Get(logging.RequestIDHeader)
 			if requestID == "" {
 				requestID = generateRequestID()
 			}
 
-			w.Header().Set("X-Request-ID", requestID)
+			w.Header().Set(logging.RequestIDHeader, requestID)
 
-			next.ServeHTTP(w, r)
+			next.ServeHTTP(w, r.WithContext(logging.WithRequestID(r.Context(), requestID)))
 		})
 	}
 }
 
-// responseWriter wraps http.ResponseWriter to capture status code
-type responseWriter struct {
-	http.ResponseWriter
-
-	statusCode int
-}
-
-// WriteHeader captures the status code
-func (rw *responseWriter) WriteHeader(code int) {
-	rw.statusCode = code
-	rw.ResponseWriter.WriteHeader(code)
-}
-
 // generateRequestID generates a unique request ID
 func generateRequestID() string {
 	return strconv.FormatInt(time.Now().UnixNano(), 10)
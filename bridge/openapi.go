@@ -0,0 +1,771 @@
+package bridge
+
+import (
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// OpenAPIDocument is an OpenAPI 3.1 document, as built by Bridge.OpenAPI.
+type OpenAPIDocument struct {
+	OpenAPI    string                     `json:"openapi"`
+	Info       OpenAPIInfo                `json:"info"`
+	Servers    []OpenAPIServer            `json:"servers,omitempty"`
+	Tags       []OpenAPITag               `json:"tags,omitempty"`
+	Paths      map[string]OpenAPIPathItem `json:"paths"`
+	Components OpenAPIComponents          `json:"components"`
+}
+
+// OpenAPIInfo describes the API.
+type OpenAPIInfo struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// OpenAPIServer is a server the API is served from.
+type OpenAPIServer struct {
+	URL string `json:"url"`
+}
+
+// OpenAPITag groups operations.
+type OpenAPITag struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// OpenAPIPathItem maps lowercase HTTP methods to operations.
+type OpenAPIPathItem map[string]*OpenAPIOperation
+
+// OpenAPIOperation is an API operation on a path.
+type OpenAPIOperation struct {
+	OperationID string                       `json:"operationId,omitempty"`
+	Summary     string                       `json:"summary,omitempty"`
+	Description string                       `json:"description,omitempty"`
+	Tags        []string                     `json:"tags,omitempty"`
+	Parameters  []OpenAPIParameter           `json:"parameters,omitempty"`
+	RequestBody *OpenAPIRequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]OpenAPIResponse   `json:"responses"`
+	Security    []OpenAPISecurityRequirement `json:"security,omitempty"`
+}
+
+// OpenAPIParameter is a query parameter of an operation.
+type OpenAPIParameter struct {
+	Name        string                      `json:"name"`
+	In          string                      `json:"in"`
+	Description string                      `json:"description,omitempty"`
+	Required    bool                        `json:"required,omitempty"`
+	Schema      *JSONSchema                 `json:"schema,omitempty"`
+	Content     map[string]OpenAPIMediaType `json:"content,omitempty"`
+}
+
+// OpenAPIRequestBody is the request body of an operation, by media type.
+type OpenAPIRequestBody struct {
+	Required bool                        `json:"required,omitempty"`
+	Content  map[string]OpenAPIMediaType `json:"content"`
+}
+
+// OpenAPIResponse is a response of an operation, by media type.
+type OpenAPIResponse struct {
+	Description string                      `json:"description"`
+	Content     map[string]OpenAPIMediaType `json:"content,omitempty"`
+}
+
+// OpenAPIMediaType is the schema of a body in one media type.
+type OpenAPIMediaType struct {
+	Schema *JSONSchema `json:"schema"`
+}
+
+// OpenAPIComponents holds the schemas and security schemes operations refer to.
+type OpenAPIComponents struct {
+	Schemas         map[string]*JSONSchema           `json:"schemas,omitempty"`
+	SecuritySchemes map[string]OpenAPISecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+// OpenAPISecurityScheme describes how callers authenticate.
+type OpenAPISecurityScheme struct {
+	Type         string `json:"type"`
+	Description  string `json:"description,omitempty"`
+	Name         string `json:"name,omitempty"`
+	In           string `json:"in,omitempty"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+}
+
+// OpenAPISecurityRequirement maps security scheme names to the roles an
+// operation requires (OpenAPI 3.1 allows role names for schemes other than
+// OAuth2).
+type OpenAPISecurityRequirement map[string][]string
+
+// OpenAPIDiscriminator selects the schema of a oneOf by a property.
+type OpenAPIDiscriminator struct {
+	PropertyName string            `json:"propertyName"`
+	Mapping      map[string]string `json:"mapping,omitempty"`
+}
+
+// JSONSchema is a JSON Schema (2020-12), as used by OpenAPI 3.1.
+type JSONSchema struct {
+	Ref                  string                 `json:"$ref,omitempty"`
+	Type                 string                 `json:"type,omitempty"`
+	Format               string                 `json:"format,omitempty"`
+	ContentEncoding      string                 `json:"contentEncoding,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	Const                any                    `json:"const,omitempty"`
+	Enum                 []any                  `json:"enum,omitempty"`
+	Properties           map[string]*JSONSchema `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	AdditionalProperties *JSONSchema            `json:"additionalProperties,omitempty"`
+	Items                *JSONSchema            `json:"items,omitempty"`
+	OneOf                []*JSONSchema          `json:"oneOf,omitempty"`
+	Discriminator        *OpenAPIDiscriminator  `json:"discriminator,omitempty"`
+	Minimum              *float64               `json:"minimum,omitempty"`
+	Maximum              *float64               `json:"maximum,omitempty"`
+	ExclusiveMinimum     *float64               `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum     *float64               `json:"exclusiveMaximum,omitempty"`
+	MinLength            *int                   `json:"minLength,omitempty"`
+	MaxLength            *int                   `json:"maxLength,omitempty"`
+	MinItems             *int                   `json:"minItems,omitempty"`
+	MaxItems             *int                   `json:"maxItems,omitempty"`
+
+	// Security is the security requirement of the function a JSON-RPC
+	// request schema calls, as the single call operation can't state it
+	Security []OpenAPISecurityRequirement `json:"x-forgeui-security,omitempty"`
+}
+
+// OpenAPIOption configures the document built by OpenAPI.
+type OpenAPIOption func(*openAPIConfig)
+
+type openAPIConfig struct {
+	info           OpenAPIInfo
+	servers        []OpenAPIServer
+	basePath       string
+	securityName   string
+	securityScheme OpenAPISecurityScheme
+}
+
+// WithOpenAPIInfo sets the title, version and description of the document.
+// Default: "Bridge API" version "1.0.0".
+func WithOpenAPIInfo(title, version, description string) OpenAPIOption {
+	return func(c *openAPIConfig) {
+		c.info = OpenAPIInfo{Title: title, Version: version, Description: description}
+	}
+}
+
+// WithOpenAPIServer adds a server URL to the document.
+func WithOpenAPIServer(url string) OpenAPIOption {
+	return func(c *openAPIConfig) {
+		c.servers = append(c.servers, OpenAPIServer{URL: url})
+	}
+}
+
+// WithOpenAPIBasePath sets the path the bridge endpoints are served under:
+// the call endpoint is basePath/call and the HTMX handler basePath/fn/.
+// Default: "/api/bridge".
+func WithOpenAPIBasePath(basePath string) OpenAPIOption {
+	return func(c *openAPIConfig) {
+		c.basePath = strings.TrimSuffix(basePath, "/")
+	}
+}
+
+// WithOpenAPISecurityScheme sets the security scheme functions requiring
+// authentication refer to. Default: the "session" cookie of the session
+// package.
+func WithOpenAPISecurityScheme(name string, scheme OpenAPISecurityScheme) OpenAPIOption {
+	return func(c *openAPIConfig) {
+		c.securityName = name
+		c.securityScheme = scheme
+	}
+}
+
+// OpenAPI builds an OpenAPI 3.1 document describing the registered
+// functions:
+//
+//   - the JSON-RPC call endpoint, whose request body is one of a request
+//     schema per function, told apart by its method
+//   - the HTMX handler endpoint of every function, with the methods it
+//     accepts (see WithHTTPMethod)
+//   - a schema per parameter and result struct. validate tags become
+//     constraints: required, email, url, uuid, min, max, len, gt, gte, lt,
+//     lte and oneof.
+//
+// Descriptions come from WithDescription. Functions requiring
+// authentication (RequireAuth, RequireRoles or Authorize) get a security
+// requirement listing their roles; Authorize policies can't be described
+// beyond that.
+func (b *Bridge) OpenAPI(opts ...OpenAPIOption) *OpenAPIDocument {
+	cfg := &openAPIConfig{
+		info:         OpenAPIInfo{Title: "Bridge API", Version: "1.0.0"},
+		basePath:     "/api/bridge",
+		securityName: "session",
+		securityScheme: OpenAPISecurityScheme{
+			Type:        "apiKey",
+			In:          "cookie",
+			Name:        "forgeui_session",
+			Description: "Session cookie",
+		},
+	}
+
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	names := b.ListFunctions()
+	sort.Strings(names)
+
+	g := newSchemaGenerator()
+
+	doc := &OpenAPIDocument{
+		OpenAPI: "3.1.0",
+		Info:    cfg.info,
+		Servers: cfg.servers,
+		Tags: []OpenAPITag{
+			{Name: "jsonrpc", Description: "JSON-RPC 2.0 calls"},
+			{Name: "htmx", Description: "Functions as HTTP endpoints returning HTML or JSON"},
+			{Name: "stream", Description: "Stream functions as server-sent events"},
+		},
+		Paths: make(map[string]OpenAPIPathItem),
+		Components: OpenAPIComponents{
+			Schemas: g.schemas,
+		},
+	}
+
+	requests := &JSONSchema{Discriminator: &OpenAPIDiscriminator{PropertyName: "method", Mapping: map[string]string{}}}
+	responses := &JSONSchema{}
+	secured, streamSecured := false, false
+
+	for _, name := range names {
+		fn, err := b.GetFunction(name)
+		if err != nil {
+			continue
+		}
+
+		var params, result *JSONSchema
+		if fn.HasInput {
+			params = g.params(fn)
+		}
+
+		if fn.HasOutput {
+			result = g.schemaOf(fn.OutputType)
+		}
+
+		security := cfg.security(fn)
+
+		if fn.SignatureType == SigStream {
+			streamSecured = streamSecured || security != nil
+			doc.Paths[cfg.basePath+"/stream/"+name] = streamPathItem(fn, params, g.schemaOf(fn.ChunkType), security)
+
+			continue
+		}
+
+		secured = secured || security != nil
+
+		reqName, respName := componentName(name)+".Request", componentName(name)+".Response"
+
+		g.schemas[reqName] = rpcRequestSchema(fn, params, security)
+		g.schemas[respName] = rpcResponseSchema(result)
+
+		requests.OneOf = append(requests.OneOf, schemaRef(reqName))
+		requests.Discriminator.Mapping[name] = "#/components/schemas/" + reqName
+		responses.OneOf = append(responses.OneOf, schemaRef(respName))
+
+		doc.Paths[cfg.basePath+"/fn/"+name] = htmxPathItem(fn, params, result, security)
+	}
+
+	g.schemas["RPCError"] = &JSONSchema{
+		Type: "object",
+		Properties: map[string]*JSONSchema{
+			"code":    {Type: "integer"},
+			"message": {Type: "string"},
+			"data":    {},
+		},
+		Required: []string{"code", "message"},
+	}
+
+	call := &OpenAPIOperation{
+		OperationID: "jsonrpc.call",
+		Summary:     "Call a bridge function",
+		Description: "JSON-RPC 2.0 call. Batches are arrays of requests and get arrays of responses.",
+		Tags:        []string{"jsonrpc"},
+		RequestBody: &OpenAPIRequestBody{
+			Required: true,
+			Content:  map[string]OpenAPIMediaType{"application/json": {Schema: requests}},
+		},
+		Responses: map[string]OpenAPIResponse{
+			"200": {
+				Description: "The function's result, or a JSON-RPC error",
+				Content:     map[string]OpenAPIMediaType{"application/json": {Schema: responses}},
+			},
+		},
+	}
+
+	if secured || streamSecured {
+		doc.Components.SecuritySchemes = map[string]OpenAPISecurityScheme{cfg.securityName: cfg.securityScheme}
+	}
+
+	if secured {
+
+		// Some functions can be called anonymously, others not (see the
+		// x-forgeui-security of their request schemas)
+		call.Security = []OpenAPISecurityRequirement{{}, {cfg.securityName: {}}}
+	}
+
+	doc.Paths[cfg.basePath+"/call"] = OpenAPIPathItem{"post": call}
+
+	return doc
+}
+
+// security returns the security requirement of fn, or nil if it can be
+// called anonymously.
+func (c *openAPIConfig) security(fn *Function) []OpenAPISecurityRequirement {
+	if !fn.RequireAuth && len(fn.RequireRoles) == 0 && fn.Policy == nil {
+		return nil
+	}
+
+	roles := fn.RequireRoles
+	if roles == nil {
+		roles = []string{}
+	}
+
+	return []OpenAPISecurityRequirement{{c.securityName: roles}}
+}
+
+func rpcRequestSchema(fn *Function, params *JSONSchema, security []OpenAPISecurityRequirement) *JSONSchema {
+	s := &JSONSchema{
+		Type:        "object",
+		Description: fn.Description,
+		Properties: map[string]*JSONSchema{
+			"jsonrpc": {Const: "2.0"},
+			"id":      {Description: "Request ID, a string or number; omitted for notifications"},
+			"method":  {Const: fn.Name},
+		},
+		Required: []string{"jsonrpc", "method"},
+		Security: security,
+	}
+
+	if params != nil {
+		s.Properties["params"] = params
+		s.Required = append(s.Required, "params")
+	}
+
+	return s
+}
+
+func rpcResponseSchema(result *JSONSchema) *JSONSchema {
+	if result == nil {
+		result = &JSONSchema{Description: "No result"}
+	}
+
+	return &JSONSchema{
+		Type: "object",
+		Properties: map[string]*JSONSchema{
+			"jsonrpc": {Const: "2.0"},
+			"id":      {},
+			"result":  result,
+			"error":   schemaRef("RPCError"),
+		},
+		Required: []string{"jsonrpc"},
+	}
+}
+
+// streamPathItem describes the stream endpoint of the stream function fn.
+func streamPathItem(fn *Function, params, chunk *JSONSchema, security []OpenAPISecurityRequirement) OpenAPIPathItem {
+	op := &OpenAPIOperation{
+		OperationID: fn.Name,
+		Summary:     fn.Description,
+		Tags:        []string{"stream"},
+		Parameters: []OpenAPIParameter{{
+			Name:        "Last-Event-ID",
+			In:          "header",
+			Description: "ID of the last event received, to resume the stream after it",
+			Schema:      &JSONSchema{Type: "string"},
+		}},
+		Responses: map[string]OpenAPIResponse{
+			"200": {
+				Description: "An event per chunk, then a final event with done set and any error",
+				Content: map[string]OpenAPIMediaType{"text/event-stream": {Schema: &JSONSchema{
+					Description: "The data of the events",
+					OneOf: []*JSONSchema{
+						{
+							Type:       "object",
+							Properties: map[string]*JSONSchema{"data": chunk, "done": {Const: false}},
+							Required:   []string{"data", "done"},
+						},
+						{
+							Type:       "object",
+							Properties: map[string]*JSONSchema{"error": schemaRef("RPCError"), "done": {Const: true}},
+							Required:   []string{"done"},
+						},
+					},
+				}}},
+			},
+		},
+		Security: security,
+	}
+
+	if params != nil {
+		op.Parameters = append(op.Parameters, OpenAPIParameter{
+			Name:     "params",
+			In:       "query",
+			Required: true,
+			Content:  map[string]OpenAPIMediaType{"application/json": {Schema: params}},
+		})
+	}
+
+	return OpenAPIPathItem{"get": op}
+}
+
+// htmxPathItem describes the HTMX handler endpoint of fn.
+func htmxPathItem(fn *Function, params, result *JSONSchema, security []OpenAPISecurityRequirement) OpenAPIPathItem {
+	methods := fn.httpMethods()
+	item := make(OpenAPIPathItem, len(methods))
+
+	for _, method := range methods {
+		method = strings.ToLower(method)
+
+		op := &OpenAPIOperation{
+			OperationID: fn.Name,
+			Summary:     fn.Description,
+			Tags:        []string{"htmx"},
+			Responses:   htmxResponses(fn, result),
+			Security:    security,
+		}
+
+		if len(methods) > 1 {
+			op.OperationID += "." + method
+		}
+
+		if params != nil {
+			if method == "get" || method == "head" || method == "delete" {
+				op.Parameters = queryParameters(fn.InputType, params)
+			} else {
+				op.RequestBody = &OpenAPIRequestBody{
+					Required: true,
+					Content: map[string]OpenAPIMediaType{
+						"application/json":                  {Schema: params},
+						"application/x-www-form-urlencoded": {Schema: params},
+					},
+				}
+			}
+		}
+
+		item[method] = op
+	}
+
+	return item
+}
+
+func htmxResponses(fn *Function, result *JSONSchema) map[string]OpenAPIResponse {
+	ok := OpenAPIResponse{Description: "OK"}
+
+	switch {
+	case fn.ReturnsHTML || fn.Renderer != nil:
+		ok.Content = map[string]OpenAPIMediaType{"text/html": {Schema: &JSONSchema{Type: "string"}}}
+	case result != nil:
+		ok.Content = map[string]OpenAPIMediaType{
+			"application/json": {Schema: result},
+			"text/html":        {Schema: &JSONSchema{Type: "string", Description: "The result as JSON in a <pre> element, for HTMX requests"}},
+		}
+	}
+
+	responses := map[string]OpenAPIResponse{
+		"200":     ok,
+		"default": {Description: "Error message", Content: map[string]OpenAPIMediaType{"text/plain": {Schema: &JSONSchema{Type: "string"}}}},
+	}
+
+	if fn.HasInput {
+		responses["400"] = OpenAPIResponse{Description: "Invalid parameters"}
+	}
+
+	if fn.RequireAuth || len(fn.RequireRoles) > 0 || fn.Policy != nil {
+		responses["401"] = OpenAPIResponse{Description: "Not authorized"}
+	}
+
+	if fn.rateLimited() {
+		responses["429"] = OpenAPIResponse{Description: "Rate limit exceeded"}
+	}
+
+	return responses
+}
+
+// queryParameters returns the fields of the params struct as query
+// parameters, or a single "params" parameter for other types.
+func queryParameters(t reflect.Type, params *JSONSchema) []OpenAPIParameter {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	fields := jsonFields(t)
+	out := make([]OpenAPIParameter, 0, len(fields))
+
+	for _, f := range fields {
+		out = append(out, OpenAPIParameter{
+			Name:     f.name,
+			In:       "query",
+			Required: slices.Contains(params.Required, f.name),
+			Schema:   params.Properties[f.name],
+		})
+	}
+
+	return out
+}
+
+func schemaRef(name string) *JSONSchema {
+	return &JSONSchema{Ref: "#/components/schemas/" + name}
+}
+
+var componentNameInvalid = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+
+// componentName returns a valid component name for a function name.
+func componentName(name string) string {
+	return componentNameInvalid.ReplaceAllString(name, "_")
+}
+
+// schemaGenerator maps Go types to JSON Schemas, collecting the schemas of
+// named structs as components.
+type schemaGenerator struct {
+	typeNamer
+
+	schemas map[string]*JSONSchema
+}
+
+func newSchemaGenerator() *schemaGenerator {
+	return &schemaGenerator{
+		typeNamer: newTypeNamer(),
+		schemas:   make(map[string]*JSONSchema),
+	}
+}
+
+// params returns the schema of fn's parameters. Its struct is inlined, as
+// the fields bridge calls require depend on the function (see
+// WithLaxValidation).
+func (g *schemaGenerator) params(fn *Function) *JSONSchema {
+	t := fn.InputType
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct || t == timeType {
+		return g.schemaOf(t)
+	}
+
+	s := g.object(t)
+	s.Required = nil
+
+	for _, f := range jsonFields(t) {
+		required := hasRule(f.field, "required")
+		if !fn.LaxValidation && !strings.Contains(f.field.Tag.Get("json"), "omitempty") {
+			// Bridge calls require non-zero values for fields without omitempty
+			required = true
+		}
+
+		if required {
+			s.Required = append(s.Required, f.name)
+		}
+	}
+
+	return s
+}
+
+// schemaOf returns the schema of values of t encoded as JSON.
+func (g *schemaGenerator) schemaOf(t reflect.Type) *JSONSchema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return &JSONSchema{Type: "string", Format: "date-time"}
+	case t == rawMessageType:
+		return &JSONSchema{}
+	case t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType):
+		return &JSONSchema{}
+	case t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType):
+		return &JSONSchema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &JSONSchema{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32:
+		return &JSONSchema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64:
+		return &JSONSchema{Type: "integer", Format: "int64"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return &JSONSchema{Type: "integer", Minimum: ptr(0.0)}
+	case reflect.Float32:
+		return &JSONSchema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &JSONSchema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &JSONSchema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+			return &JSONSchema{Type: "string", ContentEncoding: "base64"}
+		}
+
+		return &JSONSchema{Type: "array", Items: g.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &JSONSchema{Type: "object", AdditionalProperties: g.schemaOf(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.object(t)
+		}
+
+		name, first := g.name(t)
+		if first {
+			g.schemas[name] = &JSONSchema{}
+			*g.schemas[name] = *g.object(t)
+		}
+
+		return schemaRef(name)
+	default:
+		return &JSONSchema{}
+	}
+}
+
+// object returns the schema of the struct t. Fields that are neither
+// pointers nor omitempty are required, as are fields with a required rule.
+func (g *schemaGenerator) object(t reflect.Type) *JSONSchema {
+	s := &JSONSchema{Type: "object", Properties: make(map[string]*JSONSchema)}
+
+	for _, f := range jsonFields(t) {
+		prop := &JSONSchema{Type: "string"}
+		if !f.quoted {
+			prop = g.schemaOf(f.typ)
+		}
+
+		applyRules(prop, f.typ, f.field.Tag.Get("validate"))
+		s.Properties[f.name] = prop
+
+		if !f.optional || hasRule(f.field, "required") {
+			s.Required = append(s.Required, f.name)
+		}
+	}
+
+	return s
+}
+
+// hasRule reports whether the validate tag of field has rule.
+func hasRule(field reflect.StructField, rule string) bool {
+	for r := range strings.SplitSeq(field.Tag.Get("validate"), ",") {
+		if strings.TrimSpace(r) == rule {
+			return true
+		}
+	}
+
+	return false
+}
+
+// applyRules adds the constraints of a validate tag to the schema of a
+// field of type t.
+func applyRules(s *JSONSchema, t reflect.Type, tag string) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	kind := "number"
+
+	switch t.Kind() {
+	case reflect.String:
+		kind = "string"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		kind = "items"
+	}
+
+	for rule := range strings.SplitSeq(tag, ",") {
+		name, arg, _ := strings.Cut(strings.TrimSpace(rule), "=")
+
+		switch name {
+		case "email":
+			s.Format = "email"
+		case "url", "uri":
+			s.Format = "uri"
+		case "uuid":
+			s.Format = "uuid"
+		case "oneof":
+			for v := range strings.FieldsSeq(arg) {
+				s.Enum = append(s.Enum, enumValue(t, v))
+			}
+		case "min", "gte", "max", "lte", "len", "gt", "lt":
+			n, err := strconv.ParseFloat(arg, 64)
+			if err != nil {
+				continue
+			}
+
+			applyBound(s, kind, name, n)
+		}
+	}
+}
+
+// applyBound sets the bound a min, max, len, gt, gte, lt or lte rule puts
+// on the length of strings, the size of collections or numbers.
+func applyBound(s *JSONSchema, kind, rule string, n float64) {
+	lower := rule == "min" || rule == "gte" || rule == "gt" || rule == "len"
+	upper := rule == "max" || rule == "lte" || rule == "lt" || rule == "len"
+
+	if kind == "number" {
+		switch rule {
+		case "gt":
+			s.ExclusiveMinimum = ptr(n)
+		case "lt":
+			s.ExclusiveMaximum = ptr(n)
+		default:
+			if lower {
+				s.Minimum = ptr(n)
+			}
+
+			if upper {
+				s.Maximum = ptr(n)
+			}
+		}
+
+		return
+	}
+
+	size := int(n)
+
+	switch rule {
+	case "gt":
+		size++
+	case "lt":
+		size--
+	}
+
+	minP, maxP := &s.MinLength, &s.MaxLength
+	if kind == "items" {
+		minP, maxP = &s.MinItems, &s.MaxItems
+	}
+
+	if lower {
+		*minP = ptr(size)
+	}
+
+	if upper {
+		*maxP = ptr(size)
+	}
+}
+
+// enumValue returns a oneof value as a JSON value of type t.
+func enumValue(t reflect.Type, v string) any {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		if n, err := strconv.ParseFloat(v, 64); err == nil {
+			return n
+		}
+	}
+
+	return v
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,193 @@
+package bridge
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type oaCreateUser struct {
+	Name   string   `json:"name" validate:"required,min=2,max=50"`
+	Email  string   `json:"email" validate:"email"`
+	Age    int      `json:"age,omitempty" validate:"gte=18,lt=130"`
+	Role   string   `json:"role,omitempty" validate:"oneof=admin member"`
+	Tags   []string `json:"tags,omitempty" validate:"max=5"`
+	Invite *string  `json:"invite"`
+}
+
+type oaUser struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"createdAt"`
+	Manager   *oaUser   `json:"manager,omitempty"`
+}
+
+func openAPIBridge(t *testing.T) *Bridge {
+	t.Helper()
+
+	b := New()
+
+	if err := b.Register("users.create", func(ctx Context, p oaCreateUser) (oaUser, error) {
+		return oaUser{}, nil
+	}, WithDescription("Create a user"), RequireRoles("admin"), WithRateLimit(10)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := b.Register("users.list", func(ctx Context) ([]oaUser, error) {
+		return nil, nil
+	}, WithHTTPMethod("GET")); err != nil {
+		t.Fatal(err)
+	}
+
+	return b
+}
+
+func TestOpenAPI(t *testing.T) {
+	doc := openAPIBridge(t).OpenAPI(WithOpenAPIInfo("Users", "2.0.0", ""))
+
+	if doc.OpenAPI != "3.1.0" || doc.Info.Title != "Users" || doc.Info.Version != "2.0.0" {
+		t.Errorf("Expected an OpenAPI 3.1 document for Users 2.0.0, got %s %+v", doc.OpenAPI, doc.Info)
+	}
+
+	call := doc.Paths["/api/bridge/call"]["post"]
+	if call == nil {
+		t.Fatal("Expected the JSON-RPC call operation")
+	}
+
+	body := call.RequestBody.Content["application/json"].Schema
+	if len(body.OneOf) != 2 || body.Discriminator.Mapping["users.create"] != "#/components/schemas/users.create.Request" {
+		t.Errorf("Expected a request schema per function, got %+v", body)
+	}
+
+	req := doc.Components.Schemas["users.create.Request"]
+	if req.Properties["method"].Const != "users.create" || req.Description != "Create a user" {
+		t.Errorf("Expected the method and description in the request schema, got %+v", req)
+	}
+
+	if want := []OpenAPISecurityRequirement{{"session": {"admin"}}}; !reflect.DeepEqual(req.Security, want) {
+		t.Errorf("Expected security %v, got %v", want, req.Security)
+	}
+
+	params := req.Properties["params"]
+	if want := []string{"name", "email", "invite"}; !reflect.DeepEqual(params.Required, want) {
+		t.Errorf("Expected required params %v, got %v", want, params.Required)
+	}
+
+	name := params.Properties["name"]
+	if *name.MinLength != 2 || *name.MaxLength != 50 {
+		t.Errorf("Expected name length 2..50, got %v..%v", *name.MinLength, *name.MaxLength)
+	}
+
+	if params.Properties["email"].Format != "email" {
+		t.Error("Expected the email format")
+	}
+
+	age := params.Properties["age"]
+	if *age.Minimum != 18 || *age.ExclusiveMaximum != 130 {
+		t.Errorf("Expected age >= 18 and < 130, got %v %v", *age.Minimum, *age.ExclusiveMaximum)
+	}
+
+	if !reflect.DeepEqual(params.Properties["role"].Enum, []any{"admin", "member"}) {
+		t.Errorf("Expected the role enum, got %v", params.Properties["role"].Enum)
+	}
+
+	if *params.Properties["tags"].MaxItems != 5 {
+		t.Error("Expected at most 5 tags")
+	}
+
+	user := doc.Components.Schemas["OaUser"]
+	if user == nil {
+		t.Fatal("Expected the OaUser schema")
+	}
+
+	if user.Properties["createdAt"].Format != "date-time" || user.Properties["manager"].Ref != "#/components/schemas/OaUser" {
+		t.Errorf("Expected a date-time and a recursive reference, got %+v", user.Properties)
+	}
+
+	if want := []string{"id", "name", "createdAt"}; !reflect.DeepEqual(user.Required, want) {
+		t.Errorf("Expected required fields %v, got %v", want, user.Required)
+	}
+
+	create := doc.Paths["/api/bridge/fn/users.create"]
+	if create["post"] == nil || create["get"] != nil {
+		t.Errorf("Expected POST only for a function with input, got %v", create)
+	}
+
+	if create["post"].Responses["429"].Description == "" {
+		t.Error("Expected a 429 response for a rate-limited function")
+	}
+
+	if _, ok := doc.Components.SecuritySchemes["session"]; !ok {
+		t.Error("Expected the session security scheme")
+	}
+
+	list := doc.Paths["/api/bridge/fn/users.list"]
+	if list["get"] == nil || list["post"] != nil || list["get"].Security != nil {
+		t.Errorf("Expected an anonymous GET operation, got %v", list)
+	}
+
+	result := list["get"].Responses["200"].Content["application/json"].Schema
+	if result.Type != "array" || result.Items.Ref != "#/components/schemas/OaUser" {
+		t.Errorf("Expected an array of users, got %+v", result)
+	}
+
+	if _, err := json.Marshal(doc); err != nil {
+		t.Errorf("Expected the document to encode, got %v", err)
+	}
+}
+
+func TestOpenAPILaxValidation(t *testing.T) {
+	b := New()
+
+	if err := b.Register("users.create", func(ctx Context, p oaCreateUser) error {
+		return nil
+	}, WithLaxValidation()); err != nil {
+		t.Fatal(err)
+	}
+
+	params := b.OpenAPI().Components.Schemas["users.create.Request"].Properties["params"]
+	if want := []string{"name"}; !reflect.DeepEqual(params.Required, want) {
+		t.Errorf("Expected only validate:\"required\" fields to be required, got %v", params.Required)
+	}
+}
+
+func TestIntrospectionHandlerOpenAPI(t *testing.T) {
+	h := openAPIBridge(t).IntrospectionHandler()
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ui/bridge/openapi.json", nil))
+
+	var doc OpenAPIDocument
+	if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("Expected a JSON document, got %v", err)
+	}
+
+	if _, ok := doc.Paths["/ui/bridge/call"]; !ok {
+		t.Errorf("Expected paths next to the document, got %v", doc.Paths)
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ui/bridge/docs", nil))
+
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/html") {
+		t.Errorf("Expected an HTML docs page, got %q", ct)
+	}
+
+	body := w.Body.String()
+	for _, want := range []string{"users.create", "Create a user", "roles: admin", "POST /ui/bridge/fn/users.create", `href="openapi.json"`, "min length 2"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected the docs page to contain %q", want)
+		}
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/bridge/functions", nil))
+
+	if !strings.Contains(w.Body.String(), `"count":2`) {
+		t.Errorf("Expected the function list, got %s", w.Body.String())
+	}
+}
@@ -84,8 +84,30 @@ func mapToStruct(values url.Values, targetType reflect.Type) (reflect.Value, err
 		return reflect.New(targetType).Elem(), nil
 	}
 
-	valuePtr := reflect.New(targetType)
-	elem := valuePtr.Elem()
+	elem := reflect.New(targetType).Elem()
+
+	if errs := decodeFields(values, elem); len(errs) > 0 {
+		return reflect.Value{}, &Error{
+			Code:    ErrCodeInvalidParams,
+			Message: fmt.Sprintf("Invalid value for field '%s': %s", errs[0].key, errs[0].err.Error()),
+		}
+	}
+
+	return elem, nil
+}
+
+// fieldError is a value that could not be converted to its field's type
+type fieldError struct {
+	key string
+	err error
+}
+
+// decodeFields sets the fields of a struct value from url.Values, returning
+// the fields whose values could not be converted, in field order
+func decodeFields(values url.Values, elem reflect.Value) []fieldError {
+	var errs []fieldError
+
+	targetType := elem.Type()
 
 	for i := range targetType.NumField() {
 		field := targetType.Field(i)
@@ -93,34 +115,39 @@ func mapToStruct(values url.Values, targetType reflect.Type) (reflect.Value, err
 			continue
 		}
 
-		// Determine the key: use json tag name if present, otherwise field name
-		key := field.Name
-		jsonTag := field.Tag.Get("json")
-		if jsonTag == "-" {
+		key, ok := formKey(field)
+		if !ok {
 			continue
 		}
-		if jsonTag != "" {
-			parts := strings.SplitN(jsonTag, ",", 2)
-			if parts[0] != "" {
-				key = parts[0]
-			}
-		}
 
 		vals, ok := values[key]
 		if !ok || len(vals) == 0 {
 			continue
 		}
 
-		fieldValue := elem.Field(i)
-		if err := setFieldFromString(fieldValue, vals[0]); err != nil {
-			return reflect.Value{}, &Error{
-				Code:    ErrCodeInvalidParams,
-				Message: fmt.Sprintf("Invalid value for field '%s': %s", key, err.Error()),
-			}
+		if err := setFieldFromString(elem.Field(i), vals[0]); err != nil {
+			errs = append(errs, fieldError{key: key, err: err})
 		}
 	}
 
-	return elem, nil
+	return errs
+}
+
+// formKey returns the form key of a struct field: its json tag name if
+// present, otherwise the field name. It returns false for json:"-".
+func formKey(field reflect.StructField) (string, bool) {
+	key := field.Name
+
+	jsonTag := field.Tag.Get("json")
+	if jsonTag == "-" {
+		return "", false
+	}
+
+	if name, _, _ := strings.Cut(jsonTag, ","); name != "" {
+		key = name
+	}
+
+	return key, true
 }
 
 // setFieldFromString sets a reflect.Value from a string, handling type conversions
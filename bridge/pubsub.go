@@ -0,0 +1,211 @@
+package bridge
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/xraph/forgeui/auth"
+)
+
+// Methods WebSocket clients call to manage subscriptions, and the
+// notifications they get, as JSON-RPC 2.0 messages
+const (
+	// MethodSubscribe subscribes to a topic: {"topic": "chat.42"}
+	MethodSubscribe = "$subscribe"
+
+	// MethodUnsubscribe ends a subscription: {"topic": "chat.42"}
+	MethodUnsubscribe = "$unsubscribe"
+
+	// MethodPresence returns the members of a topic with presence, and is
+	// the notification subscribers get when a member joins or leaves
+	MethodPresence = "$presence"
+
+	// MethodEvent is the notification of an event published to a topic
+	MethodEvent = "$event"
+)
+
+// ErrNoBridge is returned by Publish when ctx carries no bridge
+var ErrNoBridge = errors.New("bridge: no bridge in context")
+
+// WithBroker sets the broker carrying published events and presence
+// between instances. Default: a MemoryBroker
+func WithBroker(broker Broker) ConfigOption {
+	return func(c *Config) {
+		c.Broker = broker
+	}
+}
+
+// WithWebSocket enables the WebSocket endpoint, which forgeui.App serves
+// next to the call endpoint, as in /api/bridge/ws
+func WithWebSocket(enabled bool) ConfigOption {
+	return func(c *Config) {
+		c.EnableWebSocket = enabled
+	}
+}
+
+// TopicOption configures the topics matching a pattern (see
+// AuthorizeTopic)
+type TopicOption func(*topicRule)
+
+// WithPresence tracks the members of matching topics, making them rooms:
+// subscribing joins the room, subscribers get a $presence notification
+// when a member joins or leaves, and they can ask who is present.
+func WithPresence() TopicOption {
+	return func(r *topicRule) {
+		r.presence = true
+	}
+}
+
+// topicRule authorizes subscriptions to the topics matching a pattern.
+type topicRule struct {
+	segments []string
+	policy   auth.Policy
+	presence bool
+}
+
+// match returns the parameters of topic if it matches the rule's pattern.
+func (r *topicRule) match(topic string) (map[string]string, bool) {
+	parts := strings.Split(topic, ".")
+	if len(parts) != len(r.segments) {
+		return nil, false
+	}
+
+	params := make(map[string]string)
+
+	for i, seg := range r.segments {
+		switch {
+		case seg == "*":
+		case strings.HasPrefix(seg, "{"):
+			params[seg[1:len(seg)-1]] = parts[i]
+		case seg != parts[i]:
+			return nil, false
+		}
+
+		if parts[i] == "" {
+			return nil, false
+		}
+	}
+
+	return params, true
+}
+
+// AuthorizeTopic lets WebSocket clients subscribe to the topics matching
+// pattern when policy allows. Patterns are dot-separated segments: {name}
+// matches any segment and is available to the policy through auth.Param,
+// and * matches any segment:
+//
+//	b.AuthorizeTopic("chat.{room}", auth.Authenticated(), bridge.WithPresence())
+//	b.AuthorizeTopic("users.{id}.notifications", auth.Self("id"))
+//
+// A nil policy allows anyone. Topics follow the first pattern they match;
+// clients can't subscribe to topics matching none. Servers publish to any
+// topic (see Publish).
+func (b *Bridge) AuthorizeTopic(pattern string, policy auth.Policy, opts ...TopicOption) error {
+	segments := strings.Split(pattern, ".")
+
+	for _, seg := range segments {
+		if seg == "" || (strings.HasPrefix(seg, "{") != strings.HasSuffix(seg, "}")) || seg == "{}" {
+			return fmt.Errorf("invalid topic pattern %q", pattern)
+		}
+	}
+
+	rule := &topicRule{segments: segments, policy: policy}
+	for _, opt := range opts {
+		opt(rule)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.topics = append(b.topics, rule)
+
+	return nil
+}
+
+// topicRule returns the rule of topic and its parameters.
+func (b *Bridge) topicRule(topic string) (*topicRule, map[string]string) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, rule := range b.topics {
+		if params, ok := rule.match(topic); ok {
+			return rule, params
+		}
+	}
+
+	return nil, nil
+}
+
+// notification is a JSON-RPC 2.0 notification pushed to WebSocket clients.
+type notification struct {
+	JSONRPC string `json:"jsonrpc"`
+	Method  string `json:"method"`
+	Params  any    `json:"params"`
+}
+
+// TopicEvent is the params of $event notifications
+type TopicEvent struct {
+	Topic string `json:"topic"`
+	Event any    `json:"event"`
+}
+
+// PresenceEvent is the params of $presence notifications
+type PresenceEvent struct {
+	Topic  string  `json:"topic"`
+	Joined *Member `json:"joined,omitempty"`
+	Left   *Member `json:"left,omitempty"`
+}
+
+// Publish sends event to the WebSocket clients subscribed to topic on every
+// instance sharing the bridge's broker, as a $event notification.
+func (b *Bridge) Publish(ctx context.Context, topic string, event any) error {
+	return b.notify(ctx, topic, MethodEvent, TopicEvent{Topic: topic, Event: event})
+}
+
+// Presence returns the members present in topic (see WithPresence).
+func (b *Bridge) Presence(ctx context.Context, topic string) ([]Member, error) {
+	return b.config.Broker.Members(ctx, topic)
+}
+
+// notify publishes a notification to the subscribers of topic.
+func (b *Bridge) notify(ctx context.Context, topic, method string, params any) error {
+	data, err := json.Marshal(notification{JSONRPC: "2.0", Method: method, Params: params})
+	if err != nil {
+		return fmt.Errorf("bridge: encoding %s notification: %w", method, err)
+	}
+
+	return b.config.Broker.Publish(ctx, topic, data)
+}
+
+type bridgeKey struct{}
+
+// ContextWithBridge returns a copy of ctx carrying b, for Publish.
+// forgeui.App adds its bridge to every request.
+func ContextWithBridge(ctx context.Context, b *Bridge) context.Context {
+	return context.WithValue(ctx, bridgeKey{}, b)
+}
+
+// FromContext returns the bridge carried by ctx, or nil.
+func FromContext(ctx context.Context) *Bridge {
+	b, _ := ctx.Value(bridgeKey{}).(*Bridge)
+	return b
+}
+
+// Publish publishes event to topic through the bridge carried by ctx (see
+// Bridge.Publish), so page handlers and bridge functions of a forgeui.App
+// publish without holding the bridge:
+//
+//	bridge.Publish(ctx.Context(), "orders."+order.ID, OrderShipped{At: time.Now()})
+//
+// It returns ErrNoBridge when ctx carries none.
+func Publish(ctx context.Context, topic string, event any) error {
+	b := FromContext(ctx)
+	if b == nil {
+		return ErrNoBridge
+	}
+
+	return b.Publish(ctx, topic, event)
+}
@@ -0,0 +1,315 @@
+package bridge
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"nhooyr.io/websocket" //nolint:staticcheck // Library moved to github.com/coder/websocket - migration pending
+
+	"github.com/xraph/forgeui/auth"
+)
+
+// wsMessage is a response or notification read from a WebSocket.
+type wsMessage struct {
+	ID     string          `json:"id"`
+	Method string          `json:"method"`
+	Result json.RawMessage `json:"result"`
+	Error  *Error          `json:"error"`
+	Params json.RawMessage `json:"params"`
+}
+
+// newTopicServer serves b's WebSocket handler, with the user named by the
+// X-User header.
+func newTopicServer(t *testing.T, b *Bridge) *httptest.Server {
+	t.Helper()
+
+	authn := auth.AuthenticatorFunc(func(r *http.Request) (auth.User, error) {
+		if id := r.Header.Get("X-User"); id != "" {
+			return &SimpleUser{UserID: id, UserName: "User " + id}, nil
+		}
+
+		return nil, nil
+	})
+
+	srv := httptest.NewServer(AuthMiddleware(authn)(b.WebSocketHandler()))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func dialTopics(t *testing.T, srv *httptest.Server, user string) *websocket.Conn {
+	t.Helper()
+
+	header := http.Header{}
+	if user != "" {
+		header.Set("X-User", user)
+	}
+
+	conn, _, err := websocket.Dial(context.Background(), "ws"+strings.TrimPrefix(srv.URL, "http"), &websocket.DialOptions{HTTPHeader: header})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { _ = conn.CloseNow() })
+
+	return conn
+}
+
+func readMessage(t *testing.T, conn *websocket.Conn) wsMessage {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	_, data, err := conn.Read(ctx)
+	if err != nil {
+		t.Fatalf("Expected a message, got %v", err)
+	}
+
+	var msg wsMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		t.Fatal(err)
+	}
+
+	return msg
+}
+
+// request sends a request and returns its response.
+func request(t *testing.T, conn *websocket.Conn, id, method, topic string) wsMessage {
+	t.Helper()
+
+	data, _ := json.Marshal(map[string]any{"jsonrpc": "2.0", "id": id, "method": method, "params": map[string]string{"topic": topic}})
+	if err := conn.Write(context.Background(), websocket.MessageText, data); err != nil {
+		t.Fatal(err)
+	}
+
+	msg := readMessage(t, conn)
+	if msg.ID != id {
+		t.Fatalf("Expected the response to %s, got %+v", id, msg)
+	}
+
+	return msg
+}
+
+func TestTopicRule_Match(t *testing.T) {
+	tests := []struct {
+		pattern    string
+		topic      string
+		wantMatch  bool
+		wantParams map[string]string
+	}{
+		{"news", "news", true, map[string]string{}},
+		{"news", "sports", false, nil},
+		{"chat.{room}", "chat.42", true, map[string]string{"room": "42"}},
+		{"chat.{room}", "chat", false, nil},
+		{"chat.{room}", "chat.42.typing", false, nil},
+		{"chat.{room}", "chat.", false, nil},
+		{"users.{id}.*", "users.7.orders", true, map[string]string{"id": "7"}},
+		{"*.events", "orders.events", true, map[string]string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.topic, func(t *testing.T) {
+			b := New()
+			if err := b.AuthorizeTopic(tt.pattern, nil); err != nil {
+				t.Fatal(err)
+			}
+
+			rule, params := b.topicRule(tt.topic)
+			if (rule != nil) != tt.wantMatch {
+				t.Fatalf("Expected match %v, got %v", tt.wantMatch, rule != nil)
+			}
+
+			for k, v := range tt.wantParams {
+				if params[k] != v {
+					t.Errorf("Expected param %s to be %q, got %q", k, v, params[k])
+				}
+			}
+		})
+	}
+}
+
+func TestAuthorizeTopic_InvalidPattern(t *testing.T) {
+	for _, pattern := range []string{"", "chat.", "chat..x", "chat.{room", "chat.{}"} {
+		if err := New().AuthorizeTopic(pattern, nil); err == nil {
+			t.Errorf("Expected pattern %q to be rejected", pattern)
+		}
+	}
+}
+
+func TestWebSocket_SubscribeAndPublish(t *testing.T) {
+	b := New()
+	_ = b.AuthorizeTopic("news.*", nil)
+
+	conn := dialTopics(t, newTopicServer(t, b), "")
+
+	if resp := request(t, conn, "1", MethodSubscribe, "news.world"); resp.Error != nil {
+		t.Fatalf("Expected subscription, got %v", resp.Error)
+	}
+
+	if err := b.Publish(context.Background(), "news.world", map[string]string{"title": "Hello"}); err != nil {
+		t.Fatal(err)
+	}
+
+	msg := readMessage(t, conn)
+	if msg.Method != MethodEvent {
+		t.Fatalf("Expected an event, got %+v", msg)
+	}
+
+	if want := `{"topic":"news.world","event":{"title":"Hello"}}`; string(msg.Params) != want {
+		t.Errorf("Expected %s, got %s", want, msg.Params)
+	}
+
+	request(t, conn, "2", MethodUnsubscribe, "news.world")
+
+	_ = b.Publish(context.Background(), "news.world", "ignored")
+
+	// Events are queued before Publish returns, so one would come first
+	if resp := request(t, conn, "3", MethodSubscribe, "news.sports"); resp.Error != nil {
+		t.Errorf("Expected subscription, got %v", resp.Error)
+	}
+}
+
+func TestWebSocket_TopicAuthorization(t *testing.T) {
+	b := New()
+	_ = b.AuthorizeTopic("users.{id}", auth.Self("id"))
+
+	tests := []struct {
+		name     string
+		user     string
+		topic    string
+		wantCode int
+	}{
+		{"own topic", "1", "users.1", 0},
+		{"other user's topic", "1", "users.2", ErrCodeForbidden},
+		{"anonymous", "", "users.1", ErrCodeUnauthorized},
+		{"unknown topic", "1", "admin.logs", ErrCodeForbidden},
+	}
+
+	srv := newTopicServer(t, b)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := request(t, dialTopics(t, srv, tt.user), "1", MethodSubscribe, tt.topic)
+
+			code := 0
+			if resp.Error != nil {
+				code = resp.Error.Code
+			}
+
+			if code != tt.wantCode {
+				t.Errorf("Expected code %d, got %d", tt.wantCode, code)
+			}
+		})
+	}
+}
+
+func TestWebSocket_Presence(t *testing.T) {
+	b := New()
+	_ = b.AuthorizeTopic("chat.{room}", auth.Authenticated(), WithPresence())
+
+	srv := newTopicServer(t, b)
+
+	alice := dialTopics(t, srv, "alice")
+	request(t, alice, "1", MethodSubscribe, "chat.1")
+
+	bob := dialTopics(t, srv, "bob")
+
+	var sub subscription
+	_ = json.Unmarshal(request(t, bob, "1", MethodSubscribe, "chat.1").Result, &sub)
+
+	if len(sub.Members) != 2 {
+		t.Fatalf("Expected 2 members, got %+v", sub.Members)
+	}
+
+	var joined PresenceEvent
+
+	msg := readMessage(t, alice)
+	_ = json.Unmarshal(msg.Params, &joined)
+
+	if msg.Method != MethodPresence || joined.Joined == nil || joined.Joined.UserID != "bob" || joined.Joined.Name != "User bob" {
+		t.Fatalf("Expected bob to join, got %+v", msg)
+	}
+
+	var members subscription
+	_ = json.Unmarshal(request(t, alice, "2", MethodPresence, "chat.1").Result, &members)
+
+	if len(members.Members) != 2 {
+		t.Errorf("Expected 2 members, got %+v", members.Members)
+	}
+
+	_ = bob.Close(websocket.StatusNormalClosure, "")
+
+	var left PresenceEvent
+
+	msg = readMessage(t, alice)
+	_ = json.Unmarshal(msg.Params, &left)
+
+	if left.Left == nil || left.Left.ConnID != joined.Joined.ConnID {
+		t.Fatalf("Expected bob to leave, got %+v", msg)
+	}
+
+	present, err := b.Presence(context.Background(), "chat.1")
+	if err != nil || len(present) != 1 || present[0].UserID != "alice" {
+		t.Errorf("Expected alice alone, got %+v, %v", present, err)
+	}
+}
+
+func TestWebSocket_ReplyAfterWritePumpStopped(t *testing.T) {
+	b := New()
+	_ = b.AuthorizeTopic("news", nil)
+
+	// A connection whose write pump has stopped with a full queue
+	wsConn := &wsConnection{
+		ctx:  NewContext(httptest.NewRequest(http.MethodGet, "/", nil)),
+		send: make(chan []byte, 1),
+		done: make(chan struct{}),
+		subs: make(map[string]func()),
+	}
+	wsConn.send <- []byte("event")
+	close(wsConn.done)
+
+	replied := make(chan struct{})
+
+	go func() {
+		defer close(replied)
+
+		h := b.WebSocketHandler()
+		h.processMessage(wsConn, []byte(`{"jsonrpc":"2.0","id":"1","method":"$subscribe","params":{"topic":"news"}}`))
+		h.processMessage(wsConn, []byte(`not json`))
+		h.unsubscribeAll(wsConn)
+	}()
+
+	select {
+	case <-replied:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected replies to be dropped once the write pump stopped")
+	}
+}
+
+func TestPublish_Context(t *testing.T) {
+	if err := Publish(context.Background(), "news", "x"); !errors.Is(err, ErrNoBridge) {
+		t.Errorf("Expected ErrNoBridge, got %v", err)
+	}
+
+	b := New()
+
+	var got []byte
+
+	unsubscribe, _ := b.GetConfig().Broker.Subscribe("news", func(data []byte) { got = data })
+	defer unsubscribe()
+
+	if err := Publish(ContextWithBridge(context.Background(), b), "news", "x"); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := `{"jsonrpc":"2.0","method":"$event","params":{"topic":"news","event":"x"}}`; string(got) != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+}
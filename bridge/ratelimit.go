@@ -1,11 +1,64 @@
 package bridge
 
 import (
+	"net/http"
 	"sync"
 	"time"
+
+	"github.com/xraph/forgeui/ratelimit"
 )
 
+// WithLimiter sets the limiter of functions with a rate limit, such as a
+// ratelimit.StoreLimiter shared by replicas. Default: a token bucket
+// allowing DefaultRateLimit calls per minute, in bursts of twice as many
+func WithLimiter(l ratelimit.Limiter) ConfigOption {
+	return func(c *Config) {
+		c.Limiter = l
+	}
+}
+
+// defaultLimiter returns the limiter used when the config has none.
+func defaultLimiter(config *Config) ratelimit.Limiter {
+	return ratelimit.NewTokenBucket(config.DefaultRateLimit, time.Minute, config.DefaultRateLimit*2)
+}
+
+// RateLimitData is the data of rate limit errors, telling clients when to
+// retry
+type RateLimitData struct {
+	// Limit is the number of calls the quota allows at once
+	Limit int `json:"limit"`
+
+	// Remaining is the number of calls left in the quota
+	Remaining int `json:"remaining"`
+
+	// Reset is the number of seconds until the quota is full again
+	Reset int `json:"reset"`
+
+	// RetryAfter is the number of seconds until calls are allowed again
+	RetryAfter int `json:"retryAfter"`
+}
+
+// rateLimitError returns the error of a call rejected with res.
+func rateLimitError(res ratelimit.Result) *Error {
+	return NewError(ErrCodeRateLimit, ErrRateLimit.Message, RateLimitData{
+		Limit:      res.Limit,
+		Remaining:  res.Remaining,
+		Reset:      ratelimit.Seconds(res.ResetAfter),
+		RetryAfter: ratelimit.Seconds(res.RetryAfter),
+	})
+}
+
+// setRateLimitHeaders sets the rate limit headers of a call limited with
+// res, if it was limited.
+func setRateLimitHeaders(w http.ResponseWriter, res *ratelimit.Result) {
+	if res != nil {
+		ratelimit.SetHeaders(w.Header(), *res)
+	}
+}
+
 // RateLimiter implements token bucket rate limiting
+//
+// Deprecated: Use ratelimit.NewTokenBucket, a ratelimit.Limiter.
 type RateLimiter struct {
 	mu      sync.RWMutex
 	buckets map[string]*bucket
@@ -1,8 +1,16 @@
 package bridge
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/xraph/forgeui/ratelimit"
 )
 
 func TestRateLimiter_Allow(t *testing.T) {
@@ -90,3 +98,197 @@ func TestRateLimiter_Count(t *testing.T) {
 		t.Errorf("count after 2 keys = %d, want 2", rl.Count())
 	}
 }
+
+// callFunction calls method over HTTP with the given headers.
+func callFunction(handler http.Handler, method string, header map[string]string) (*httptest.ResponseRecorder, Response) {
+	req := httptest.NewRequest(http.MethodPost, "/api/bridge/call", strings.NewReader(`{"jsonrpc":"2.0","id":"1","method":"`+method+`"}`))
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	var resp Response
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+
+	return w, resp
+}
+
+func TestHTTPHandler_RateLimitHeadersAndData(t *testing.T) {
+	b := New(WithCSRF(false))
+	_ = b.Register("search", func(ctx Context) (string, error) {
+		return "ok", nil
+	}, WithFunctionLimiter(ratelimit.NewTokenBucket(1, time.Minute, 1)))
+
+	handler := b.Handler()
+
+	w, resp := callFunction(handler, "search", nil)
+	if resp.Error != nil {
+		t.Fatalf("Expected the first call to pass, got %v", resp.Error)
+	}
+
+	if w.Header().Get(ratelimit.HeaderLimit) != "1" || w.Header().Get(ratelimit.HeaderRemaining) != "0" {
+		t.Errorf("Expected rate limit headers, got %v", w.Header())
+	}
+
+	w, resp = callFunction(handler, "search", nil)
+	if resp.Error == nil || resp.Error.Code != ErrCodeRateLimit {
+		t.Fatalf("Expected a rate limit error, got %+v", resp)
+	}
+
+	if got := w.Header().Get(ratelimit.HeaderRetryAfter); got != "60" {
+		t.Errorf("Expected Retry-After 60, got %q", got)
+	}
+
+	data, _ := json.Marshal(resp.Error.Data)
+	if want := `{"limit":1,"remaining":0,"reset":60,"retryAfter":60}`; string(data) != want {
+		t.Errorf("Expected error data %s, got %s", want, data)
+	}
+}
+
+func TestHTTPHandler_BatchRateLimited(t *testing.T) {
+	b := New(WithCSRF(false))
+	_ = b.Register("search", func(ctx Context) (string, error) {
+		return "ok", nil
+	}, WithFunctionLimiter(ratelimit.NewTokenBucket(1, time.Minute, 1)))
+
+	body := `[{"jsonrpc":"2.0","id":"1","method":"search"},{"jsonrpc":"2.0","id":"2","method":"search"}]`
+
+	w := httptest.NewRecorder()
+	b.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/bridge/call", strings.NewReader(body)))
+
+	var responses BatchResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &responses); err != nil {
+		t.Fatal(err)
+	}
+
+	var rejected []*Error
+
+	for _, resp := range responses {
+		if resp.Error != nil {
+			rejected = append(rejected, resp.Error)
+		}
+	}
+
+	if len(rejected) != 1 || rejected[0].Code != ErrCodeRateLimit {
+		t.Fatalf("Expected one call to be rate limited, got %+v", responses)
+	}
+
+	data, _ := json.Marshal(rejected[0].Data)
+	if want := `{"limit":1,"remaining":0,"reset":60,"retryAfter":60}`; string(data) != want {
+		t.Errorf("Expected error data %s, got %s", want, data)
+	}
+}
+
+func TestRateLimit_Keys(t *testing.T) {
+	b := New(WithCSRF(false), WithLimiter(ratelimit.NewSlidingWindow(1, time.Minute)))
+
+	handler := func(ctx Context) (string, error) { return "ok", nil }
+
+	_ = b.Register("a", handler, WithRateLimit(1))
+	_ = b.Register("b", handler, WithRateLimit(1))
+	_ = b.Register("tenant", handler, WithRateLimit(1), WithRateLimitKey(ratelimit.ByHeader("X-Tenant")))
+
+	tests := []struct {
+		name    string
+		method  string
+		tenant  string
+		allowed bool
+	}{
+		{"first call", "a", "", true},
+		{"same function", "a", "", false},
+		{"other function", "b", "", true},
+		{"tenant 1", "tenant", "1", true},
+		{"tenant 2", "tenant", "2", true},
+		{"tenant 1 again", "tenant", "1", false},
+	}
+
+	h := b.Handler()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, resp := callFunction(h, tt.method, map[string]string{"X-Tenant": tt.tenant})
+
+			if allowed := resp.Error == nil; allowed != tt.allowed {
+				t.Errorf("Expected allowed %v, got %v", tt.allowed, resp.Error)
+			}
+		})
+	}
+}
+
+type failingLimiter struct{}
+
+func (failingLimiter) Allow(context.Context, string) (ratelimit.Result, error) {
+	return ratelimit.Result{}, errors.New("store unavailable")
+}
+
+func TestRateLimit_LimiterFailure(t *testing.T) {
+	b := New(WithCSRF(false), WithLimiter(failingLimiter{}))
+	_ = b.Register("search", func(ctx Context) (string, error) {
+		return "ok", nil
+	}, WithRateLimit(1))
+
+	w, resp := callFunction(b.Handler(), "search", nil)
+	if resp.Error != nil || w.Header().Get(ratelimit.HeaderLimit) != "" {
+		t.Errorf("Expected calls to pass without headers when the limiter fails, got %v %v", resp.Error, w.Header())
+	}
+}
+
+func TestStream_RateLimited(t *testing.T) {
+	b := newCountBridge()
+	fn, _ := b.GetFunction("count")
+	fn.Limiter = ratelimit.NewTokenBucket(1, time.Minute, 1)
+
+	srv := httptest.NewServer(b.StreamHandler())
+	defer srv.Close()
+
+	url := srv.URL + `/api/bridge/stream/count?params={"n":1}`
+
+	readEvents(t, openStream(t, context.Background(), url, ""), isFinal)
+	events := readEvents(t, openStream(t, context.Background(), url, ""), isFinal)
+
+	if !strings.Contains(events[0].data, `"code":-32002`) || !strings.Contains(events[0].data, `"retryAfter":60`) {
+		t.Errorf("Expected a rate limit error, got %s", events[0].data)
+	}
+}
+
+func TestStream_RateLimitedWithLastEventID(t *testing.T) {
+	b := newCountBridge()
+	count, _ := b.GetFunction("count")
+	count.Limiter = ratelimit.NewTokenBucket(1, time.Minute, 1)
+
+	_ = b.Register("search", func(ctx Context) (string, error) {
+		return "ok", nil
+	})
+	search, _ := b.GetFunction("search")
+	search.Limiter = ratelimit.NewTokenBucket(1, time.Minute, 1)
+
+	srv := httptest.NewServer(b.StreamHandler())
+	defer srv.Close()
+
+	isLimited := func(events []sseEvent) bool {
+		return strings.Contains(events[len(events)-1].data, `"code":-32002`)
+	}
+
+	// A resumed stream runs nothing new, so it passes the limit
+	url := srv.URL + `/api/bridge/stream/count?params={"n":1}`
+
+	events := readEvents(t, openStream(t, context.Background(), url, ""), isFinal)
+	if resumed := readEvents(t, openStream(t, context.Background(), url, events[0].id), isFinal); isLimited(resumed) {
+		t.Errorf("Expected resuming a stream to skip the rate limit, got %+v", resumed)
+	}
+
+	// A made-up ID doesn't start a new stream past the limit
+	if got := readEvents(t, openStream(t, context.Background(), url, "unknown:1"), isFinal); isLimited(got) {
+		t.Errorf("Expected an unknown stream to fail to resume, got %+v", got)
+	}
+
+	// Other functions ignore the ID
+	url = srv.URL + `/api/bridge/stream/search?lastEventId=x`
+
+	readEvents(t, openStream(t, context.Background(), url, ""), isFinal)
+	if got := readEvents(t, openStream(t, context.Background(), url, "x"), isFinal); !isLimited(got) {
+		t.Errorf("Expected a last event ID not to bypass the rate limit, got %+v", got)
+	}
+}
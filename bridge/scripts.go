@@ -27,6 +27,7 @@ type ScriptConfig struct {
 	IncludeAlpine bool
 	IncludeHTMX   bool   // Include HTMX bridge integration script
 	StaticPath    string // Base path for static assets (e.g., "/static" or "/api/identity/ui/static")
+	TraceParent   string // W3C traceparent the client sends with every call, joining the page trace
 }
 
 // BridgeScripts returns script tags for the bridge client
@@ -71,6 +72,14 @@ if (window.ForgeBridge && window.bridge) {
 `, config.CSRFToken)
 		}
 
+		if config.TraceParent != "" {
+			configScript += fmt.Sprintf(`
+if (window.ForgeBridge && window.bridge) {
+  window.bridge.setTraceParent(%q);
+}
+`, config.TraceParent)
+		}
+
 		_, err := fmt.Fprintf(w, `<script type="text/javascript">%s</script>`, configScript)
 		return err
 	})
@@ -123,6 +132,14 @@ if (window.ForgeBridge && window.bridge) {
 `, config.CSRFToken)
 		}
 
+		if config.TraceParent != "" {
+			configScript += fmt.Sprintf(`
+if (window.ForgeBridge && window.bridge) {
+  window.bridge.setTraceParent(%q);
+}
+`, config.TraceParent)
+		}
+
 		_, err := fmt.Fprintf(w, `<script type="text/javascript">%s</script>`, configScript)
 		return err
 	})
@@ -1,17 +1,23 @@
 package bridge
 
 import (
+	"context"
 	"crypto/rand"
 	"crypto/subtle"
 	"encoding/base64"
+	"errors"
 	"net/http"
 	"slices"
 	"strings"
+
+	"github.com/xraph/forgeui/auth"
+	"github.com/xraph/forgeui/csrf"
+	"github.com/xraph/forgeui/ratelimit"
 )
 
 // Security handles authentication, authorization, and CSRF
 type Security struct {
-	rateLimiter *RateLimiter
+	limiter     ratelimit.Limiter
 	csrfEnabled bool
 	csrfHeader  string
 	csrfCookie  string
@@ -19,8 +25,13 @@ type Security struct {
 
 // NewSecurity creates a new security instance
 func NewSecurity(config *Config) *Security {
+	limiter := config.Limiter
+	if limiter == nil {
+		limiter = defaultLimiter(config)
+	}
+
 	return &Security{
-		rateLimiter: NewRateLimiter(config.DefaultRateLimit, config.DefaultRateLimit*2),
+		limiter:     limiter,
 		csrfEnabled: config.EnableCSRF,
 		csrfHeader:  config.CSRFTokenHeader,
 		csrfCookie:  config.CSRFCookieName,
@@ -29,6 +40,17 @@ func NewSecurity(config *Config) *Security {
 
 // CheckAuth verifies authentication
 func (s *Security) CheckAuth(ctx Context, fn *Function) error {
+	if fn.Policy != nil {
+		err := auth.Check(ctx.User(), ctx.Request(), fn.Policy)
+		if errors.Is(err, auth.ErrUnauthenticated) {
+			return ErrUnauthorized
+		}
+
+		if err != nil {
+			return ErrForbidden
+		}
+	}
+
 	if !fn.RequireAuth {
 		return nil
 	}
@@ -50,25 +72,72 @@ func (s *Security) CheckAuth(ctx Context, fn *Function) error {
 	return nil
 }
 
+// RateLimit applies the rate limit of fn to a call, with fn's limiter or
+// the bridge's, keyed by the function name and fn's key. It returns a nil
+// result for functions without a limit, and a rate limit error with
+// RateLimitData when the call is rejected. Calls are allowed when the
+// limiter fails.
+func (s *Security) RateLimit(ctx Context, fn *Function) (*ratelimit.Result, *Error) {
+	if !fn.rateLimited() {
+		// No rate limit for this function
+		return nil, nil
+	}
+
+	limiter := fn.Limiter
+	if limiter == nil {
+		limiter = s.limiter
+	}
+
+	key := func(r *http.Request) string {
+		if fn.RateLimitKey != nil {
+			if k := fn.RateLimitKey(r); k != "" {
+				return fn.Name + ":" + k
+			}
+
+			return ""
+		}
+
+		return fn.Name + ":" + getRateLimitKey(ctx)
+	}
+
+	res := ratelimit.Check(ctx.Request(), limiter, key)
+	if res.Limit == 0 {
+		return nil, nil
+	}
+
+	if !res.Allowed {
+		return &res, rateLimitError(res)
+	}
+
+	return &res, nil
+}
+
 // CheckRateLimit verifies rate limiting
+//
+// Deprecated: Use RateLimit, which scopes keys to the function and returns
+// the state of the quota.
 func (s *Security) CheckRateLimit(key string, fn *Function) error {
-	// Use function-specific rate limit if set, otherwise use default
-	rateLimit := fn.RateLimit
-	if rateLimit == 0 {
-		// No rate limit for this function
+	if !fn.rateLimited() {
 		return nil
 	}
 
-	if !s.rateLimiter.Allow(key) {
-		return ErrRateLimit
+	limiter := fn.Limiter
+	if limiter == nil {
+		limiter = s.limiter
+	}
+
+	res, err := limiter.Allow(context.Background(), key)
+	if err == nil && !res.Allowed {
+		return rateLimitError(res)
 	}
 
 	return nil
 }
 
-// CheckCSRF verifies CSRF token
+// CheckCSRF verifies CSRF token. Requests already validated by
+// csrf.Protect pass without the double-submit cookie check.
 func (s *Security) CheckCSRF(r *http.Request) error {
-	if !s.csrfEnabled {
+	if !s.csrfEnabled || csrf.Verified(r.Context()) {
 		return nil
 	}
 
@@ -124,30 +193,11 @@ func secureCompare(a, b string) bool {
 	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
 }
 
-// GetClientIP extracts the client IP from the request
+// GetClientIP extracts the client IP from the request: the connection's
+// address, or the client behind a proxy trusted with ratelimit.TrustProxies
+// (see ratelimit.ClientIP)
 func GetClientIP(r *http.Request) string {
-	// Check X-Forwarded-For header
-	forwarded := r.Header.Get("X-Forwarded-For")
-	if forwarded != "" {
-		// Take the first IP
-		ips := strings.Split(forwarded, ",")
-		return strings.TrimSpace(ips[0])
-	}
-
-	// Check X-Real-IP header
-	realIP := r.Header.Get("X-Real-IP")
-	if realIP != "" {
-		return realIP
-	}
-
-	// Fall back to RemoteAddr
-	ip := r.RemoteAddr
-	// Remove port if present
-	if colon := strings.LastIndex(ip, ":"); colon != -1 {
-		ip = ip[:colon]
-	}
-
-	return ip
+	return ratelimit.ClientIP(r)
 }
 
 // getRateLimitKey generates a rate limit key based on IP and user
@@ -4,6 +4,9 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+
+	"github.com/xraph/forgeui/auth"
+	"github.com/xraph/forgeui/ratelimit"
 )
 
 func TestSecurity_CheckAuth(t *testing.T) {
@@ -73,6 +76,40 @@ func TestSecurity_CheckAuth_Roles(t *testing.T) {
 	}
 }
 
+func TestSecurity_CheckAuth_Policy(t *testing.T) {
+	security := NewSecurity(DefaultConfig())
+
+	fn := &Function{Policy: auth.Any(auth.Roles("admin"), func(user auth.User, r *http.Request) bool {
+		return user != nil && r.Header.Get("X-Owner") == user.ID()
+	})}
+
+	tests := []struct {
+		name  string
+		user  User
+		owner string
+		want  error
+	}{
+		{"anonymous", nil, "", ErrUnauthorized},
+		{"admin", &SimpleUser{UserID: "1", UserRoles: []string{"admin"}}, "", nil},
+		{"owner", &SimpleUser{UserID: "2"}, "2", nil},
+		{"other user", &SimpleUser{UserID: "3"}, "2", ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			req.Header.Set("X-Owner", tt.owner)
+
+			// Users resolved by auth middleware are the context's users
+			req = req.WithContext(auth.NewContext(req.Context(), tt.user))
+
+			if err := security.CheckAuth(NewContext(req), fn); err != tt.want {
+				t.Errorf("Expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
 func TestGetClientIP(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -81,14 +118,22 @@ func TestGetClientIP(t *testing.T) {
 		want       string
 	}{
 		{
-			name:    "X-Forwarded-For",
-			headers: map[string]string{"X-Forwarded-For": "192.168.1.1, 10.0.0.1"},
-			want:    "192.168.1.1",
+			name:       "X-Forwarded-For from a trusted proxy",
+			headers:    map[string]string{"X-Forwarded-For": "192.168.1.1, 10.0.0.1"},
+			remoteAddr: "10.0.0.2:12345",
+			want:       "192.168.1.1",
 		},
 		{
-			name:    "X-Real-IP",
-			headers: map[string]string{"X-Real-IP": "192.168.1.1"},
-			want:    "192.168.1.1",
+			name:       "X-Real-IP from a trusted proxy",
+			headers:    map[string]string{"X-Real-IP": "192.168.1.1"},
+			remoteAddr: "10.0.0.2:12345",
+			want:       "192.168.1.1",
+		},
+		{
+			name:       "X-Forwarded-For from a client",
+			headers:    map[string]string{"X-Forwarded-For": "192.168.1.1"},
+			remoteAddr: "203.0.113.5:12345",
+			want:       "203.0.113.5",
 		},
 		{
 			name:       "RemoteAddr",
@@ -109,7 +154,12 @@ func TestGetClientIP(t *testing.T) {
 				req.RemoteAddr = tt.remoteAddr
 			}
 
-			got := GetClientIP(req)
+			var got string
+
+			ratelimit.TrustProxies("10.0.0.0/8")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = GetClientIP(r)
+			})).ServeHTTP(httptest.NewRecorder(), req)
+
 			if got != tt.want {
 				t.Errorf("GetClientIP() = %s, want %s", got, tt.want)
 			}
@@ -0,0 +1,87 @@
+package bridge
+
+import (
+	"context"
+	"sync"
+)
+
+// connTracker tracks the bridge's long-lived connections, SSE streams and
+// WebSockets, so Shutdown can end them and wait.
+type connTracker struct {
+	mu      sync.Mutex
+	closing bool
+	drain   chan struct{}
+	active  sync.WaitGroup
+}
+
+// Shutdown ends the bridge's SSE streams and WebSocket connections. New
+// ones are refused with 503 Service Unavailable, open WebSocket connections
+// are closed with status 1001 (going away) once their current message has
+// been handled, stream functions are canceled, and Shutdown waits for streams and connections to finish
+// until ctx is done. Plain calls are drained by http.Server.Shutdown.
+func (b *Bridge) Shutdown(ctx context.Context) error {
+	t := &b.conns
+
+	t.mu.Lock()
+	if !t.closing {
+		t.closing = true
+		close(t.draining())
+	}
+	t.mu.Unlock()
+
+	b.streams.cancelAll()
+
+	done := make(chan struct{})
+
+	go func() {
+		t.active.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// track registers a long-lived connection over transport. It returns a func
+// to call when the connection ends, or false when the bridge is shutting
+// down.
+func (b *Bridge) track(transport string) (func(), bool) {
+	t := &b.conns
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.closing {
+		return nil, false
+	}
+
+	t.active.Add(1)
+	disconnect := b.metrics.connect(transport)
+
+	return func() {
+		disconnect()
+		t.active.Done()
+	}, true
+}
+
+// draining returns a channel closed when Shutdown starts.
+func (b *Bridge) draining() <-chan struct{} {
+	b.conns.mu.Lock()
+	defer b.conns.mu.Unlock()
+
+	return b.conns.draining()
+}
+
+// draining returns the drain channel, creating it on first use. Callers hold
+// t.mu.
+func (t *connTracker) draining() chan struct{} {
+	if t.drain == nil {
+		t.drain = make(chan struct{})
+	}
+
+	return t.drain
+}
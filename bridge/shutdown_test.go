@@ -0,0 +1,58 @@
+package bridge
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestBridge_Shutdown(t *testing.T) {
+	b := New()
+
+	started, release := make(chan struct{}), make(chan struct{})
+	_ = b.Register("slow", func(ctx Context) (string, error) {
+		close(started)
+		<-release
+		return "done", nil
+	})
+
+	handler := b.StreamHandler()
+
+	// A stream in flight when Shutdown starts
+	streamDone := make(chan *httptest.ResponseRecorder)
+
+	go func() {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/bridge/stream?method=slow", nil))
+		streamDone <- w
+	}()
+
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if err := b.Shutdown(ctx); err == nil {
+		t.Error("Expected Shutdown to time out while the stream is open")
+	}
+
+	// New streams are refused
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/bridge/stream?method=slow", nil))
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status %d for a stream after Shutdown, got %d", http.StatusServiceUnavailable, w.Code)
+	}
+
+	close(release)
+
+	if err := b.Shutdown(context.Background()); err != nil {
+		t.Errorf("Expected Shutdown to finish once the stream has, got %v", err)
+	}
+
+	if w := <-streamDone; w.Code != http.StatusOK {
+		t.Errorf("Expected the in-flight stream to complete, got status %d", w.Code)
+	}
+}
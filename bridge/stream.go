@@ -4,9 +4,20 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
+	"time"
 )
 
-// SSEHandler handles Server-Sent Events streaming
+// SSEHandler handles Server-Sent Events streaming. The function is named
+// by the method query parameter or the last path segment, as in
+// /api/bridge/stream/logs.tail, and its JSON parameters are the params
+// query parameter.
+//
+// Stream functions (see Stream) send an event per chunk, then a final
+// event with done set and any error. Events carry IDs, so clients that
+// reconnect with a Last-Event-ID header (or lastEventId query parameter)
+// resume the stream after it, within the buffered events (see
+// WithStreamBuffer). Other functions send a single, final event.
 type SSEHandler struct {
 	bridge   *Bridge
 	security *Security
@@ -22,6 +33,14 @@ func NewSSEHandler(bridge *Bridge) *SSEHandler {
 
 // ServeHTTP handles SSE requests
 func (h *SSEHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Refuse new streams while shutting down
+	done, ok := h.bridge.track(transportSSE)
+	if !ok {
+		http.Error(w, "Server shutting down", http.StatusServiceUnavailable)
+		return
+	}
+	defer done()
+
 	// Set SSE headers
 	w.Header().Set("Content-Type", "text/event-stream")
 	w.Header().Set("Cache-Control", "no-cache")
@@ -36,10 +55,15 @@ func (h *SSEHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Create bridge context
+	r = h.bridge.extractTrace(r)
 	ctx := NewContext(r)
 
-	// Get function name from query
+	// Get function name from query or path
 	funcName := r.URL.Query().Get("method")
+	if i := strings.LastIndex(r.URL.Path, "/stream/"); funcName == "" && i >= 0 {
+		funcName = r.URL.Path[i+len("/stream/"):]
+	}
+
 	if funcName == "" {
 		h.sendError(w, flusher, "Method parameter required")
 		return
@@ -66,8 +90,141 @@ func (h *SSEHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Execute function and stream results
-	h.streamExecution(w, flusher, ctx, funcName, params)
+	// Resuming a stream function runs nothing new, so it skips the rate
+	// limit. Other functions ignore the last event ID.
+	var (
+		resumed *liveStream
+		seq     uint64
+	)
+
+	if lastID := lastEventID(r); lastID != "" && fn.SignatureType == SigStream {
+		var ok bool
+		if resumed, seq, ok = h.resumable(ctx, fn, lastID); !ok {
+			h.sendStreamError(w, flusher, NewError(ErrCodeBadRequest, "Stream can no longer be resumed"))
+			return
+		}
+	}
+
+	if resumed == nil {
+		res, rlErr := h.security.RateLimit(ctx, fn)
+		setRateLimitHeaders(w, res)
+
+		if rlErr != nil {
+			h.bridge.metrics.rateLimit(fn.Name)
+			h.sendStreamError(w, flusher, rlErr)
+
+			return
+		}
+	}
+
+	if fn.SignatureType != SigStream {
+		// Execute function and stream results
+		h.streamExecution(w, flusher, ctx, funcName, params)
+		return
+	}
+
+	if resumed != nil {
+		h.follow(w, flusher, r, resumed, seq)
+		return
+	}
+
+	paramValue, paramErr := decodeParams(fn, params)
+	if paramErr != nil {
+		h.sendStreamError(w, flusher, paramErr)
+		return
+	}
+
+	s := h.bridge.startStream(ctx, fn, paramValue)
+
+	// An event without data gives the client an ID to resume with before
+	// the first chunk
+	_, _ = fmt.Fprintf(w, "id: %s\n\n", eventID(s, 0))
+
+	h.follow(w, flusher, r, s, 0)
+}
+
+// lastEventID returns the ID of the last event the client received.
+func lastEventID(r *http.Request) string {
+	if id := r.Header.Get("Last-Event-ID"); id != "" {
+		return id
+	}
+
+	return r.URL.Query().Get("lastEventId")
+}
+
+// resumable returns the stream of fn the client received event lastID of,
+// and the event's sequence number. Only the user who started a stream can
+// resume it.
+func (h *SSEHandler) resumable(ctx Context, fn *Function, lastID string) (*liveStream, uint64, bool) {
+	var userID string
+	if user := ctx.User(); user != nil {
+		userID = user.ID()
+	}
+
+	s, seq, ok := h.bridge.streams.lookup(lastID)
+	if !ok || s.fn != fn.Name || s.userID != userID {
+		return nil, 0, false
+	}
+
+	return s, seq, true
+}
+
+// follow writes the events of s after seq until the final one, the client
+// goes away or the bridge shuts down, with heartbeat comments while the
+// stream is idle.
+func (h *SSEHandler) follow(w http.ResponseWriter, flusher http.Flusher, r *http.Request, s *liveStream, seq uint64) {
+	s.attach()
+	defer s.detach()
+
+	var heartbeat <-chan time.Time
+
+	if interval := h.bridge.config.StreamHeartbeat; interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		heartbeat = ticker.C
+	}
+
+	draining := h.bridge.draining()
+
+	for {
+		events, changed, ok := s.since(seq)
+		if !ok {
+			h.sendStreamError(w, flusher, NewError(ErrCodeBadRequest, "Stream can no longer be resumed"))
+			return
+		}
+
+		for _, e := range events {
+			_, _ = fmt.Fprintf(w, "id: %s\ndata: %s\n\n", eventID(s, e.seq), e.data)
+			seq = e.seq
+
+			if e.final {
+				flusher.Flush()
+				return
+			}
+		}
+
+		flusher.Flush()
+		s.ack(seq)
+
+		select {
+		case <-changed:
+		case <-heartbeat:
+			_, _ = fmt.Fprint(w, ": heartbeat\n\n")
+		case <-r.Context().Done():
+			return
+		case <-draining:
+			return
+		}
+	}
+}
+
+// sendStreamError sends a final event with err.
+func (h *SSEHandler) sendStreamError(w http.ResponseWriter, flusher http.Flusher, err *Error) {
+	data, _ := json.Marshal(StreamChunk{Error: err, Done: true})
+
+	_, _ = fmt.Fprintf(w, "data: %s\n\n", data)
+	flusher.Flush()
 }
 
 // streamExecution executes a function and streams the results
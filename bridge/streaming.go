@@ -0,0 +1,460 @@
+package bridge
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime/debug"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/xraph/forgeui/tracing"
+)
+
+// Stream sends the chunks of a stream function to the client, one SSE
+// event each. Stream functions take it as their last parameter:
+//
+//	b.Register("logs.tail", func(ctx bridge.Context, params TailParams, out bridge.Stream[string]) error {
+//	    for line := range tail(ctx.Context(), params.File) {
+//	        if err := out.Send(line); err != nil {
+//	            return err
+//	        }
+//	    }
+//	    return nil
+//	})
+//
+// Clients call them through the stream endpoint (see SSEHandler). The
+// function's context is canceled once the client has gone away for good
+// (see WithStreamResumeWindow) or the bridge shuts down; ctx.Request()
+// belongs to the first connection only.
+type Stream[T any] func(chunk T) error
+
+// Send pushes chunk to the client. Chunks are buffered for clients that
+// reconnect, and Send blocks while the client is a full buffer behind
+// (see WithStreamBuffer). It returns ErrStreamClosed once the function's
+// context is done.
+func (s Stream[T]) Send(chunk T) error {
+	return s(chunk)
+}
+
+// ErrStreamClosed is returned by Stream.Send once the stream's context is
+// done
+var ErrStreamClosed = errors.New("bridge: stream closed")
+
+// WithStreamBuffer sets how many chunks of a stream are kept for clients
+// resuming it with Last-Event-ID. Stream.Send blocks while the client is
+// that many chunks behind. Default: 256
+func WithStreamBuffer(events int) ConfigOption {
+	return func(c *Config) {
+		c.StreamBuffer = events
+	}
+}
+
+// WithStreamResumeWindow sets how long a stream function keeps running
+// after its client disconnected, waiting for it to resume, and how long a
+// finished stream can still be resumed. 0 cancels the function on
+// disconnect. Default: 10s
+func WithStreamResumeWindow(d time.Duration) ConfigOption {
+	return func(c *Config) {
+		c.StreamResumeWindow = d
+	}
+}
+
+// WithStreamHeartbeat sets the interval of the comments sent on idle
+// streams so proxies don't close them. 0 disables them. Default: 15s
+func WithStreamHeartbeat(d time.Duration) ConfigOption {
+	return func(c *Config) {
+		c.StreamHeartbeat = d
+	}
+}
+
+var streamPkgPath = reflect.TypeFor[Stream[any]]().PkgPath()
+
+// isStreamType reports whether t is an instance of Stream.
+func isStreamType(t reflect.Type) bool {
+	return t.Kind() == reflect.Func && t.PkgPath() == streamPkgPath && strings.HasPrefix(t.Name(), "Stream[")
+}
+
+// errStreamOnly is the error of calls to stream functions through other
+// transports.
+func errStreamOnly(fn *Function) *Error {
+	return NewError(ErrCodeInvalidRequest, fmt.Sprintf("Function '%s' streams; call it through the stream endpoint", fn.Name))
+}
+
+// streamData is the event of a chunk. Unlike StreamChunk it keeps zero
+// values.
+type streamData struct {
+	Data any  `json:"data"`
+	Done bool `json:"done"`
+}
+
+type streamEvent struct {
+	seq   uint64
+	data  []byte
+	final bool
+}
+
+// liveStream is a running stream function with the last events it sent,
+// which connections follow.
+type liveStream struct {
+	id     string
+	fn     string
+	userID string
+	cancel context.CancelFunc
+	size   int
+	window time.Duration
+
+	mu     sync.Mutex
+	events []streamEvent
+	last   uint64
+	// changed is closed when an event is pushed
+	changed chan struct{}
+	// delivered is the last event a client received, and progress is
+	// closed when it moves
+	delivered uint64
+	progress  chan struct{}
+	clients   int
+	idle      *time.Timer
+	done      bool
+}
+
+func newLiveStream(fn *Function, userID string, cancel context.CancelFunc, config *Config) *liveStream {
+	return &liveStream{
+		id:       rand.Text(),
+		fn:       fn.Name,
+		userID:   userID,
+		cancel:   cancel,
+		size:     max(config.StreamBuffer, 1),
+		window:   config.StreamResumeWindow,
+		changed:  make(chan struct{}),
+		progress: make(chan struct{}),
+	}
+}
+
+// send pushes a chunk once the client is less than a buffer behind, unless
+// ctx is done.
+func (s *liveStream) send(ctx context.Context, chunk any) error {
+	data, err := json.Marshal(streamData{Data: chunk})
+	if err != nil {
+		return fmt.Errorf("bridge: encoding stream chunk: %w", err)
+	}
+
+	for {
+		if ctx.Err() != nil {
+			return ErrStreamClosed
+		}
+
+		progress := s.tryPush(data)
+		if progress == nil {
+			return nil
+		}
+
+		select {
+		case <-progress:
+		case <-ctx.Done():
+		}
+	}
+}
+
+// tryPush pushes a chunk, unless the client is a full buffer behind. Then
+// it returns a channel closed when the client receives more events.
+func (s *liveStream) tryPush(data []byte) <-chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.last-s.delivered >= uint64(s.size) {
+		return s.progress
+	}
+
+	s.pushLocked(data, false)
+
+	return nil
+}
+
+// push appends an event regardless of the client.
+func (s *liveStream) push(data []byte, final bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pushLocked(data, final)
+}
+
+// pushLocked appends an event, dropping the oldest one when the buffer is
+// full, and wakes up the connections waiting for it. The buffer has room
+// for the final event on top of the chunks. Callers hold s.mu.
+func (s *liveStream) pushLocked(data []byte, final bool) {
+	s.last++
+	s.events = append(s.events, streamEvent{seq: s.last, data: data, final: final})
+
+	if len(s.events) > s.size+1 {
+		s.events = slices.Delete(s.events, 0, len(s.events)-s.size-1)
+	}
+
+	if final {
+		s.done = true
+	}
+
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+// since returns the events after seq and a channel closed when the next
+// one is pushed. ok is false when some of them were dropped.
+func (s *liveStream) since(seq uint64) (events []streamEvent, changed <-chan struct{}, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case seq > s.last:
+		return nil, nil, false
+	case seq == s.last:
+		return nil, s.changed, true
+	case seq+1 < s.events[0].seq:
+		return nil, nil, false
+	}
+
+	return slices.Clone(s.events[seq+1-s.events[0].seq:]), s.changed, true
+}
+
+// ack records that a client received the events up to seq.
+func (s *liveStream) ack(seq uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if seq > s.delivered {
+		s.delivered = seq
+		close(s.progress)
+		s.progress = make(chan struct{})
+	}
+}
+
+// attach registers a connection following the stream.
+func (s *liveStream) attach() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.clients++
+
+	if s.idle != nil {
+		s.idle.Stop()
+		s.idle = nil
+	}
+}
+
+// detach unregisters a connection. When the last one leaves a running
+// stream, the function is canceled unless a client resumes the stream
+// within the resume window.
+func (s *liveStream) detach() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.clients--
+	if s.clients > 0 || s.done {
+		return
+	}
+
+	if s.window <= 0 {
+		s.cancel()
+		return
+	}
+
+	s.idle = time.AfterFunc(s.window, s.cancel)
+}
+
+// streamRegistry holds the streams clients can resume.
+type streamRegistry struct {
+	mu      sync.Mutex
+	streams map[string]*liveStream
+}
+
+func (r *streamRegistry) add(s *liveStream) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.streams == nil {
+		r.streams = make(map[string]*liveStream)
+	}
+
+	r.streams[s.id] = s
+}
+
+func (r *streamRegistry) remove(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.streams, id)
+}
+
+// lookup returns the stream and sequence number of an event ID.
+func (r *streamRegistry) lookup(eventID string) (*liveStream, uint64, bool) {
+	id, seqText, found := strings.Cut(eventID, ":")
+	if !found {
+		return nil, 0, false
+	}
+
+	seq, err := strconv.ParseUint(seqText, 10, 64)
+	if err != nil {
+		return nil, 0, false
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s, ok := r.streams[id]
+
+	return s, seq, ok
+}
+
+// cancelAll cancels every running stream function.
+func (r *streamRegistry) cancelAll() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, s := range r.streams {
+		s.cancel()
+	}
+}
+
+// eventID returns the SSE event ID of event seq of s.
+func eventID(s *liveStream, seq uint64) string {
+	return s.id + ":" + strconv.FormatUint(seq, 10)
+}
+
+// startStream starts the stream function fn in the background. Its
+// context outlives the request: connections come and go until the
+// stream is canceled (see liveStream.detach).
+func (b *Bridge) startStream(ctx Context, fn *Function, paramValue reflect.Value) *liveStream {
+	base := context.WithoutCancel(ctx.Context())
+
+	var (
+		streamCtx context.Context
+		cancel    context.CancelFunc
+	)
+
+	if fn.Timeout > 0 {
+		streamCtx, cancel = context.WithTimeout(base, fn.Timeout)
+	} else {
+		streamCtx, cancel = context.WithCancel(base)
+	}
+
+	var userID string
+	if user := ctx.User(); user != nil {
+		userID = user.ID()
+	}
+
+	s := newLiveStream(fn, userID, cancel, b.config)
+	b.streams.add(s)
+
+	go b.runStream(withContext(ctx, streamCtx), s, fn, paramValue)
+
+	return s
+}
+
+// runStream runs the stream function fn, then pushes the final event.
+func (b *Bridge) runStream(ctx Context, s *liveStream, fn *Function, paramValue reflect.Value) {
+	startTime := time.Now()
+
+	ctx, span := startSpan(ctx, "bridge.stream", tracing.String(tracing.KeyFunction, fn.Name))
+
+	b.hooks.Trigger(BeforeCall, ctx, HookData{
+		FunctionName: fn.Name,
+	})
+
+	callErr := b.callStream(ctx, s, fn, paramValue)
+
+	data, err := json.Marshal(StreamChunk{Error: callErr, Done: true})
+	if err != nil {
+		data = []byte(`{"done":true}`)
+	}
+
+	s.push(data, true)
+	s.cancel()
+
+	elapsed := time.Since(startTime)
+
+	hookData := HookData{
+		FunctionName: fn.Name,
+		Duration:     elapsed.Microseconds(),
+	}
+
+	if fn.HasInput {
+		hookData.Params = paramValue.Interface()
+	}
+
+	if callErr != nil {
+		hookData.Error = callErr
+		b.hooks.Trigger(OnError, ctx, hookData)
+	} else {
+		b.hooks.Trigger(OnSuccess, ctx, hookData)
+	}
+
+	b.hooks.Trigger(AfterCall, ctx, hookData)
+
+	b.logCall(ctx, fn.Name, elapsed, callErr)
+	endSpan(span, callErr)
+
+	// Keep the tail of the stream for clients that missed it
+	if s.window <= 0 {
+		b.streams.remove(s.id)
+	} else {
+		time.AfterFunc(s.window, func() { b.streams.remove(s.id) })
+	}
+}
+
+// callStream calls the stream function fn with a Stream pushing to s.
+func (b *Bridge) callStream(ctx Context, s *liveStream, fn *Function, paramValue reflect.Value) (callErr *Error) {
+	defer func() {
+		if r := recover(); r != nil {
+			callErr = NewError(ErrCodeInternal, "Function panicked", map[string]any{
+				"panic": fmt.Sprint(r),
+				"stack": string(debug.Stack()),
+			})
+		}
+	}()
+
+	streamCtx := ctx.Context()
+	handlerType := fn.Handler.Type()
+	errorType := reflect.TypeFor[error]()
+
+	out := reflect.MakeFunc(handlerType.In(handlerType.NumIn()-1), func(args []reflect.Value) []reflect.Value {
+		result := reflect.New(errorType).Elem()
+		if err := s.send(streamCtx, args[0].Interface()); err != nil {
+			result.Set(reflect.ValueOf(err))
+		}
+
+		return []reflect.Value{result}
+	})
+
+	args := []reflect.Value{reflect.ValueOf(ctx)}
+	if fn.HasInput {
+		args = append(args, paramValue)
+	}
+
+	results := fn.Handler.Call(append(args, out))
+	if results[0].IsNil() {
+		return nil
+	}
+
+	fnErr := results[0].Interface().(error)
+
+	// A function giving up because its context is done
+	switch ctxErr := streamCtx.Err(); {
+	case errors.Is(ctxErr, context.DeadlineExceeded):
+		return NewError(ErrCodeTimeout, fmt.Sprintf("Stream timed out after %v", fn.Timeout))
+	case ctxErr != nil:
+		return NewError(ErrCodeCanceled, "Stream canceled")
+	}
+
+	var bridgeErr *Error
+	if errors.As(fnErr, &bridgeErr) {
+		return bridgeErr
+	}
+
+	return NewError(ErrCodeInternal, fnErr.Error())
+}
@@ -0,0 +1,342 @@
+package bridge
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type countParams struct {
+	N int `json:"n" validate:"min=1"`
+}
+
+// sseEvent is an event read from a stream, or a comment.
+type sseEvent struct {
+	id      string
+	data    string
+	comment string
+}
+
+// readEvents reads SSE events from r, without the ones without data, until
+// stop returns true.
+func readEvents(t *testing.T, r *bufio.Reader, stop func(sseEvent) bool) []sseEvent {
+	t.Helper()
+
+	var (
+		events []sseEvent
+		e      sseEvent
+	)
+
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("Expected more events, got %v after %+v", err, events)
+		}
+
+		line = strings.TrimSuffix(line, "\n")
+
+		switch {
+		case strings.HasPrefix(line, "id: "):
+			e.id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "data: "):
+			e.data = strings.TrimPrefix(line, "data: ")
+		case strings.HasPrefix(line, ": "):
+			e.comment = strings.TrimPrefix(line, ": ")
+		case line == "":
+			if e.data != "" || e.comment != "" {
+				events = append(events, e)
+				if stop(e) {
+					return events
+				}
+			}
+
+			e = sseEvent{}
+		}
+	}
+}
+
+func isFinal(e sseEvent) bool {
+	return strings.Contains(e.data, `"done":true`)
+}
+
+func openStream(t *testing.T, ctx context.Context, url, lastID string) *bufio.Reader {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if lastID != "" {
+		req.Header.Set("Last-Event-ID", lastID)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { _ = resp.Body.Close() })
+
+	return bufio.NewReader(resp.Body)
+}
+
+func newCountBridge(opts ...ConfigOption) *Bridge {
+	b := New(opts...)
+
+	_ = b.Register("count", func(ctx Context, params countParams, out Stream[int]) error {
+		for i := range params.N {
+			if err := out.Send(i); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	return b
+}
+
+func TestRegister_StreamSignatures(t *testing.T) {
+	tests := []struct {
+		name      string
+		handler   any
+		wantErr   bool
+		wantInput bool
+	}{
+		{"params and stream", func(Context, countParams, Stream[string]) error { return nil }, false, true},
+		{"stream only", func(Context, Stream[int]) error { return nil }, false, false},
+		{"result", func(Context, Stream[int]) (int, error) { return 0, nil }, true, false},
+		{"no error", func(Context, Stream[int]) {}, true, false},
+		{"too many params", func(Context, int, int, Stream[int]) error { return nil }, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := New()
+
+			err := b.Register("fn", tt.handler)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Register() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			fn, _ := b.GetFunction("fn")
+			if fn.SignatureType != SigStream {
+				t.Errorf("Expected SigStream, got %v", fn.SignatureType)
+			}
+
+			if fn.HasInput != tt.wantInput {
+				t.Errorf("Expected HasInput %v, got %v", tt.wantInput, fn.HasInput)
+			}
+
+			if fn.ChunkType == nil {
+				t.Error("Expected a chunk type")
+			}
+		})
+	}
+}
+
+func TestStream_Chunks(t *testing.T) {
+	b := newCountBridge()
+
+	srv := httptest.NewServer(b.StreamHandler())
+	defer srv.Close()
+
+	r := openStream(t, context.Background(), srv.URL+`/api/bridge/stream/count?params={"n":3}`, "")
+	events := readEvents(t, r, isFinal)
+
+	want := []string{`{"data":0,"done":false}`, `{"data":1,"done":false}`, `{"data":2,"done":false}`, `{"done":true}`}
+	if len(events) != len(want) {
+		t.Fatalf("Expected %d events, got %+v", len(want), events)
+	}
+
+	for i, e := range events {
+		if e.data != want[i] {
+			t.Errorf("Expected event %d to be %s, got %s", i, want[i], e.data)
+		}
+
+		if e.id == "" {
+			t.Errorf("Expected event %d to have an ID", i)
+		}
+	}
+}
+
+func TestStream_InvalidParams(t *testing.T) {
+	b := newCountBridge()
+
+	srv := httptest.NewServer(b.StreamHandler())
+	defer srv.Close()
+
+	r := openStream(t, context.Background(), srv.URL+`/api/bridge/stream/count?params={"n":0}`, "")
+	events := readEvents(t, r, isFinal)
+
+	if !strings.Contains(events[0].data, `"code":-32602`) {
+		t.Errorf("Expected an invalid params error, got %s", events[0].data)
+	}
+}
+
+func TestStream_Resume(t *testing.T) {
+	b := newCountBridge(WithStreamBuffer(3))
+
+	srv := httptest.NewServer(b.StreamHandler())
+	defer srv.Close()
+
+	url := srv.URL + `/api/bridge/stream/count?params={"n":4}`
+
+	events := readEvents(t, openStream(t, context.Background(), url, ""), isFinal)
+	if len(events) != 5 {
+		t.Fatalf("Expected 5 events, got %+v", events)
+	}
+
+	tests := []struct {
+		name   string
+		lastID string
+		want   []string
+	}{
+		{
+			name:   "within the buffer",
+			lastID: events[1].id,
+			want:   []string{`{"data":2,"done":false}`, `{"data":3,"done":false}`, `{"done":true}`},
+		},
+		{
+			name:   "evicted",
+			lastID: strings.Split(events[0].id, ":")[0] + ":0",
+			want:   []string{`{"error":{"code":-32004,"message":"Stream can no longer be resumed"},"done":true}`},
+		},
+		{
+			name:   "unknown stream",
+			lastID: "unknown:1",
+			want:   []string{`{"error":{"code":-32004,"message":"Stream can no longer be resumed"},"done":true}`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resumed := readEvents(t, openStream(t, context.Background(), url, tt.lastID), isFinal)
+			if len(resumed) != len(tt.want) {
+				t.Fatalf("Expected %d events, got %+v", len(tt.want), resumed)
+			}
+
+			for i, e := range resumed {
+				if e.data != tt.want[i] {
+					t.Errorf("Expected event %d to be %s, got %s", i, tt.want[i], e.data)
+				}
+			}
+		})
+	}
+}
+
+func TestStream_CancelOnDisconnect(t *testing.T) {
+	b := New(WithStreamResumeWindow(0))
+
+	canceled := make(chan error, 1)
+	_ = b.Register("wait", func(ctx Context, out Stream[string]) error {
+		_ = out.Send("ready")
+
+		<-ctx.Context().Done()
+
+		canceled <- out.Send("late")
+
+		return ctx.Context().Err()
+	})
+
+	srv := httptest.NewServer(b.StreamHandler())
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	r := openStream(t, ctx, srv.URL+"/api/bridge/stream/wait", "")
+	readEvents(t, r, func(e sseEvent) bool { return strings.Contains(e.data, "ready") })
+
+	cancel()
+
+	select {
+	case err := <-canceled:
+		if err != ErrStreamClosed {
+			t.Errorf("Expected Send to return ErrStreamClosed after cancellation, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected the function's context to be canceled when the client disconnected")
+	}
+}
+
+func TestStream_Heartbeat(t *testing.T) {
+	b := New(WithStreamHeartbeat(10 * time.Millisecond))
+
+	release := make(chan struct{})
+	defer close(release)
+
+	_ = b.Register("idle", func(ctx Context, out Stream[string]) error {
+		<-release
+		return nil
+	})
+
+	srv := httptest.NewServer(b.StreamHandler())
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	events := readEvents(t, openStream(t, ctx, srv.URL+"/api/bridge/stream/idle", ""), func(e sseEvent) bool {
+		return e.comment != ""
+	})
+
+	if got := events[len(events)-1].comment; got != "heartbeat" {
+		t.Errorf("Expected a heartbeat comment, got %q", got)
+	}
+}
+
+func TestExecute_RejectsStreams(t *testing.T) {
+	b := newCountBridge()
+
+	ctx := NewContext(httptest.NewRequest(http.MethodPost, "/", nil))
+
+	result := b.execute(ctx, "count", json.RawMessage(`{"n":1}`))
+	if result.Error == nil || result.Error.Code != ErrCodeInvalidRequest {
+		t.Errorf("Expected an invalid request error, got %v", result.Error)
+	}
+}
+
+func TestStream_Declarations(t *testing.T) {
+	b := newCountBridge()
+
+	var buf bytes.Buffer
+	if err := b.GenerateTypeScript(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	ts := buf.String()
+
+	if !strings.Contains(ts, `"count": { params: CountParams; chunk: number };`) {
+		t.Errorf("Expected count in BridgeStreams, got:\n%s", ts)
+	}
+
+	if strings.Contains(ts, `"count": { params: CountParams; result`) {
+		t.Error("Expected stream functions to be left out of BridgeFunctions")
+	}
+
+	doc := b.OpenAPI()
+
+	op := doc.Paths["/api/bridge/stream/count"]["get"]
+	if op == nil {
+		t.Fatal("Expected a GET operation for the stream endpoint")
+	}
+
+	if _, ok := op.Responses["200"].Content["text/event-stream"]; !ok {
+		t.Error("Expected an event stream response")
+	}
+
+	if _, ok := doc.Components.Schemas["count.Request"]; ok {
+		t.Error("Expected stream functions to be left out of JSON-RPC calls")
+	}
+}
@@ -0,0 +1,68 @@
+package bridge
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/xraph/forgeui/tracing"
+)
+
+// WithTracer traces calls for requests that don't carry a tracer (see
+// tracing.Middleware). Every call gets a bridge.execute span, and batches a
+// bridge.batch span around them.
+func WithTracer(t tracing.Tracer) ConfigOption {
+	return func(c *Config) {
+		c.Tracer = t
+	}
+}
+
+// extractTrace returns r with the configured tracer and the remote parent of
+// its traceparent header. EventSource and WebSocket clients can't set
+// headers, so a traceparent query parameter is accepted too.
+func (b *Bridge) extractTrace(r *http.Request) *http.Request {
+	if r.Header.Get(tracing.TraceParentHeader) == "" {
+		if tp := r.URL.Query().Get(tracing.TraceParentHeader); tp != "" {
+			r = r.Clone(r.Context())
+			r.Header.Set(tracing.TraceParentHeader, tp)
+		}
+	}
+
+	return tracing.Extract(r, b.config.Tracer)
+}
+
+// startSpan starts a span as a child of the request behind ctx. The
+// returned Context carries the span, so functions can start spans of
+// their own from ctx.Context().
+func startSpan(ctx Context, name string, attrs ...tracing.Attr) (Context, tracing.Span) {
+	reqCtx := ctx.Context()
+	if reqCtx == nil || !tracing.Enabled(reqCtx) {
+		return ctx, tracing.SpanFromContext(context.Background())
+	}
+
+	spanCtx, span := tracing.Start(reqCtx, name, attrs...)
+
+	return withContext(ctx, spanCtx), span
+}
+
+// endSpan ends span, recording callErr if the call failed.
+func endSpan(span tracing.Span, callErr *Error) {
+	if callErr != nil {
+		span.RecordError(callErr)
+	}
+
+	span.End()
+}
+
+// withContext returns a copy of ctx whose Context() is c. Contexts not
+// created by NewContext are returned unchanged.
+func withContext(ctx Context, c context.Context) Context {
+	bc, ok := ctx.(*bridgeContext)
+	if !ok {
+		return ctx
+	}
+
+	cp := *bc
+	cp.ctx = c
+
+	return &cp
+}
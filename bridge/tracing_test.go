@@ -0,0 +1,132 @@
+package bridge
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/xraph/forgeui/tracing"
+)
+
+const testTraceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+// newTracedBridge returns a traced bridge and a func returning the trace
+// parent its "ok" function last saw. Batches call it concurrently.
+func newTracedBridge(t *testing.T) (*Bridge, *tracing.InMemoryExporter, func() string) {
+	t.Helper()
+
+	exporter := tracing.NewInMemoryExporter()
+	b := New(WithCSRF(false), WithTracer(tracing.NewTracer(exporter)), WithLogger(slog.New(slog.DiscardHandler)))
+
+	var (
+		mu   sync.Mutex
+		seen string
+	)
+
+	_ = b.Register("ok", func(ctx Context) (string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		seen = tracing.TraceParent(ctx.Context())
+
+		return "done", nil
+	})
+	_ = b.Register("fail", func(ctx Context) (string, error) {
+		return "", errors.New("boom")
+	})
+
+	return b, exporter, func() string {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return seen
+	}
+}
+
+func TestBridge_TracesCalls(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		wantErr   bool
+		wantBatch bool
+	}{
+		{"single call", `{"jsonrpc":"2.0","id":"1","method":"ok"}`, false, false},
+		{"failed call", `{"jsonrpc":"2.0","id":"1","method":"fail"}`, true, false},
+		{"batch", `[{"jsonrpc":"2.0","id":"1","method":"ok"},{"jsonrpc":"2.0","id":"2","method":"ok"}]`, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, exporter, _ := newTracedBridge(t)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/bridge/call", strings.NewReader(tt.body))
+			req.Header.Set(tracing.TraceParentHeader, testTraceParent)
+			NewHTTPHandler(b).ServeHTTP(httptest.NewRecorder(), req)
+
+			calls := exporter.Named("bridge.execute")
+			if len(calls) == 0 {
+				t.Fatal("Expected bridge.execute spans")
+			}
+
+			batches := exporter.Named("bridge.batch")
+			if tt.wantBatch != (len(batches) == 1) {
+				t.Fatalf("Expected batch span: %v, got %d", tt.wantBatch, len(batches))
+			}
+
+			for _, call := range calls {
+				if call.TraceID.String() != "4bf92f3577b34da6a3ce929d0e0e4736" {
+					t.Errorf("Expected the call to join the remote trace, got %s", call.TraceID)
+				}
+
+				wantParent := "00f067aa0ba902b7"
+				if tt.wantBatch {
+					wantParent = batches[0].SpanID.String()
+				}
+
+				if call.ParentID.String() != wantParent {
+					t.Errorf("Expected parent %s, got %s", wantParent, call.ParentID)
+				}
+
+				if (call.Err != nil) != tt.wantErr {
+					t.Errorf("Expected error recorded: %v, got %v", tt.wantErr, call.Err)
+				}
+			}
+		})
+	}
+}
+
+func TestBridge_TraceContextReachesFunction(t *testing.T) {
+	b, exporter, seen := newTracedBridge(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/bridge/call", strings.NewReader(`{"jsonrpc":"2.0","id":"1","method":"ok"}`))
+	NewHTTPHandler(b).ServeHTTP(httptest.NewRecorder(), req)
+
+	calls := exporter.Named("bridge.execute")
+	if len(calls) != 1 {
+		t.Fatalf("Expected 1 bridge.execute span, got %d", len(calls))
+	}
+
+	want := tracing.SpanContext{TraceID: calls[0].TraceID, SpanID: calls[0].SpanID, Flags: 1}.TraceParent()
+	if got := seen(); got != want {
+		t.Errorf("Expected the function context to carry %s, got %q", want, got)
+	}
+
+	if name, _ := calls[0].Attr(tracing.KeyFunction); name != "ok" {
+		t.Errorf("Expected function attribute ok, got %v", name)
+	}
+}
+
+func TestBridge_ExtractTraceQuery(t *testing.T) {
+	b := New()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/bridge/stream?traceparent="+testTraceParent, nil)
+	req = b.extractTrace(req)
+
+	if got := tracing.TraceParent(req.Context()); got != testTraceParent {
+		t.Errorf("Expected remote parent %s, got %q", testTraceParent, got)
+	}
+}
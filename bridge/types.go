@@ -47,6 +47,7 @@ const (
 	ErrCodeTimeout      = -32003
 	ErrCodeBadRequest   = -32004
 	ErrCodeForbidden    = -32005
+	ErrCodeCanceled     = -32006
 )
 
 // Error implements the error interface
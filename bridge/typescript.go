@@ -0,0 +1,534 @@
+package bridge
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TypeScriptHeader is the first line of files written by GenerateTypeScript.
+const TypeScriptHeader = "// Code generated by forgeui bridge gen-ts. DO NOT EDIT."
+
+// GenerateTypeScript writes TypeScript declarations for the registered
+// functions to w, for use with forge-bridge.js:
+//
+//   - an interface for every struct type used by function parameters and
+//     results, with fields named by their json tags. Pointer and omitempty
+//     fields are optional, time.Time is a string and maps are Records.
+//   - BridgeFunctions, mapping each function name to its params and result
+//   - BridgeClient, the shape of ForgeBridge.client(), with functions
+//     nested by the dots in their names
+//   - BridgeStreams, mapping each stream function name to its params and
+//     chunks (see Stream); stream functions aren't in BridgeFunctions or
+//     BridgeClient
+//   - BridgeMember, the members of topics with presence (see WithPresence)
+//   - TypedForgeBridge, a typed view of ForgeBridge
+//
+// The output is deterministic, so a checked-in copy can be compared with
+// a fresh one (see `forgeui bridge gen-ts --check`):
+//
+//	import type { BridgeClient } from "./bridge";
+//
+//	const api = new ForgeBridge().client() as BridgeClient;
+//	const user = await api.users.get({ id: "42" });
+func (b *Bridge) GenerateTypeScript(w io.Writer) error {
+	names := b.ListFunctions()
+	sort.Strings(names)
+
+	g := newTSGenerator()
+
+	type signature struct {
+		fn     *Function
+		params string
+		result string
+	}
+
+	sigs := make([]signature, 0, len(names))
+
+	var streams []signature
+
+	for _, name := range names {
+		fn, err := b.GetFunction(name)
+		if err != nil {
+			continue
+		}
+
+		sig := signature{fn: fn, params: "void", result: "void"}
+		if fn.HasInput {
+			sig.params = g.typeOf(fn.InputType)
+		}
+
+		if fn.HasOutput {
+			sig.result = g.typeOf(fn.OutputType)
+		}
+
+		if fn.SignatureType == SigStream {
+			sig.result = g.typeOf(fn.ChunkType)
+			streams = append(streams, sig)
+
+			continue
+		}
+
+		sigs = append(sigs, sig)
+	}
+
+	var buf bytes.Buffer
+
+	buf.WriteString(TypeScriptHeader + "\n")
+
+	g.writeInterfaces(&buf)
+
+	buf.WriteString("\n/** Parameters and results of the bridge functions, by name. */\n")
+	buf.WriteString("export interface BridgeFunctions {\n")
+
+	for _, s := range sigs {
+		fmt.Fprintf(&buf, "  %s: { params: %s; result: %s };\n", tsString(s.fn.Name), s.params, s.result)
+	}
+
+	buf.WriteString("}\n")
+
+	root := &tsNamespace{}
+	for _, s := range sigs {
+		root.add(strings.Split(s.fn.Name, "."), s.fn, s.params, s.result)
+	}
+
+	buf.WriteString("\n/** The bridge functions as methods, as returned by ForgeBridge.client(). */\n")
+	buf.WriteString("export interface BridgeClient ")
+	root.write(&buf, 0)
+	buf.WriteString("\n")
+
+	buf.WriteString("\n/** Parameters and chunks of the bridge stream functions, by name. */\n")
+	buf.WriteString("export interface BridgeStreams {\n")
+
+	for _, s := range streams {
+		fmt.Fprintf(&buf, "  %s: { params: %s; chunk: %s };\n", tsString(s.fn.Name), s.params, s.result)
+	}
+
+	buf.WriteString("}\n")
+
+	buf.WriteString(`
+/** The chunks of a stream, as returned by ForgeBridge.stream(). */
+export interface BridgeStream<T> extends AsyncIterableIterator<T> {
+  close(): void;
+}
+
+/** A connection present in a topic with presence. */
+export interface BridgeMember {
+  connId: string;
+  userId?: string;
+  name?: string;
+}
+
+/** Callbacks of ForgeBridge.subscribe(). */
+export interface BridgeSubscribeOptions {
+  onPresence?(change: { joined?: BridgeMember; left?: BridgeMember }): void;
+  onError?(error: Error): void;
+}
+
+/** ForgeBridge with typed calls. */
+export interface TypedForgeBridge {
+  call<K extends keyof BridgeFunctions>(
+    method: K,
+    ...params: BridgeFunctions[K]["params"] extends void ? [] : [BridgeFunctions[K]["params"]]
+  ): Promise<BridgeFunctions[K]["result"]>;
+  stream<K extends keyof BridgeStreams>(
+    method: K,
+    ...params: BridgeStreams[K]["params"] extends void ? [] : [BridgeStreams[K]["params"]]
+  ): BridgeStream<BridgeStreams[K]["chunk"]>;
+  subscribe<T = unknown>(topic: string, onEvent: (event: T) => void, options?: BridgeSubscribeOptions): () => void;
+  presence(topic: string): Promise<BridgeMember[]>;
+  client(): BridgeClient;
+}
+`)
+
+	_, err := buf.WriteTo(w)
+
+	return err
+}
+
+var (
+	timeType          = reflect.TypeFor[time.Time]()
+	rawMessageType    = reflect.TypeFor[json.RawMessage]()
+	jsonMarshalerType = reflect.TypeFor[json.Marshaler]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// typeNamer names the named struct types of generated declarations.
+type typeNamer struct {
+	// names maps struct types to their names
+	names map[reflect.Type]string
+
+	// taken maps names to their types, to detect clashes
+	taken map[string]reflect.Type
+}
+
+func newTypeNamer() typeNamer {
+	return typeNamer{
+		names: make(map[reflect.Type]string),
+		taken: make(map[string]reflect.Type),
+	}
+}
+
+// name returns the name of the named struct t, and whether t is named for
+// the first time.
+func (n typeNamer) name(t reflect.Type) (string, bool) {
+	if name, ok := n.names[t]; ok {
+		return name, false
+	}
+
+	name := typeName(t.Name())
+	if other, ok := n.taken[name]; ok && other != t {
+		// Types of the same name from different packages
+		name = typeName(t.PkgPath()[strings.LastIndex(t.PkgPath(), "/")+1:]) + name
+	}
+
+	n.names[t] = name
+	n.taken[name] = t
+
+	return name, true
+}
+
+// tsGenerator maps Go types to TypeScript, collecting the interfaces of
+// named structs.
+type tsGenerator struct {
+	typeNamer
+
+	// bodies are the interface bodies by name
+	bodies map[string]string
+}
+
+func newTSGenerator() *tsGenerator {
+	return &tsGenerator{
+		typeNamer: newTypeNamer(),
+		bodies:    make(map[string]string),
+	}
+}
+
+// typeOf returns the TypeScript type of values of t encoded as JSON.
+func (g *tsGenerator) typeOf(t reflect.Type) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return "string"
+	case t == rawMessageType:
+		return "unknown"
+	case t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType):
+		return "unknown"
+	case t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType):
+		return "string"
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.String:
+		return "string"
+	case reflect.Slice, reflect.Array:
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+			// []byte is base64 encoded
+			return "string"
+		}
+
+		elem := g.typeOf(t.Elem())
+		if strings.ContainsAny(elem, " |") {
+			elem = "(" + elem + ")"
+		}
+
+		return elem + "[]"
+	case reflect.Map:
+		return "Record<string, " + g.typeOf(t.Elem()) + ">"
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.object(t, false)
+		}
+
+		return g.named(t)
+	default:
+		return "unknown"
+	}
+}
+
+// named returns the interface name of the named struct t, generating the
+// interface the first time.
+func (g *tsGenerator) named(t reflect.Type) string {
+	name, first := g.name(t)
+	if first {
+		// Named before its body is generated, so recursive types refer to themselves
+		g.bodies[name] = g.object(t, true)
+	}
+
+	return name
+}
+
+// object returns the TypeScript object type of the struct t, with a line
+// per field for interfaces and on one line for anonymous structs.
+func (g *tsGenerator) object(t reflect.Type, multiline bool) string {
+	fields := jsonFields(t)
+	if len(fields) == 0 {
+		return "{}"
+	}
+
+	members := make([]string, 0, len(fields))
+
+	for _, f := range fields {
+		typ := "string"
+		if !f.quoted {
+			typ = g.typeOf(f.typ)
+		}
+
+		opt := ""
+		if f.optional {
+			opt = "?"
+		}
+
+		members = append(members, tsKey(f.name)+opt+": "+typ+";")
+	}
+
+	if !multiline {
+		return "{ " + strings.Join(members, " ") + " }"
+	}
+
+	return "{\n  " + strings.Join(members, "\n  ") + "\n}"
+}
+
+func (g *tsGenerator) writeInterfaces(w io.Writer) {
+	names := make([]string, 0, len(g.bodies))
+	for name := range g.bodies {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		fmt.Fprintf(w, "\nexport interface %s %s\n", name, g.bodies[name])
+	}
+}
+
+// jsonField is a struct field as encoding/json sees it.
+type jsonField struct {
+	name     string
+	typ      reflect.Type
+	field    reflect.StructField
+	optional bool // pointer, omitempty or omitzero
+	quoted   bool // the ",string" option
+}
+
+// jsonFields returns the fields of the struct t encoded by encoding/json,
+// in order, with the fields of embedded structs promoted. Fields of outer
+// structs hide promoted fields of the same name.
+func jsonFields(t reflect.Type) []jsonField {
+	var fields []jsonField
+
+	seen := make(map[string]bool)
+
+	type level struct {
+		t        reflect.Type
+		optional bool
+	}
+
+	current := []level{{t: t}}
+	visited := map[reflect.Type]bool{t: true}
+
+	for len(current) > 0 {
+		var next []level
+
+		for _, lv := range current {
+			for i := range lv.t.NumField() {
+				sf := lv.t.Field(i)
+
+				tag := sf.Tag.Get("json")
+				if tag == "-" {
+					continue
+				}
+
+				name, opts, _ := strings.Cut(tag, ",")
+
+				ft := sf.Type
+				if sf.Anonymous && name == "" {
+					et := ft
+					if et.Kind() == reflect.Pointer {
+						et = et.Elem()
+					}
+
+					if et.Kind() == reflect.Struct {
+						// Promote the embedded struct's fields
+						if !visited[et] {
+							visited[et] = true
+							next = append(next, level{t: et, optional: lv.optional || ft.Kind() == reflect.Pointer})
+						}
+
+						continue
+					}
+				}
+
+				if !sf.IsExported() {
+					continue
+				}
+
+				if name == "" {
+					name = sf.Name
+				}
+
+				if seen[name] {
+					continue
+				}
+
+				seen[name] = true
+
+				f := jsonField{name: name, typ: ft, field: sf, optional: lv.optional || ft.Kind() == reflect.Pointer}
+
+				for _, opt := range strings.Split(opts, ",") {
+					switch opt {
+					case "omitempty", "omitzero":
+						f.optional = true
+					case "string":
+						f.quoted = isScalar(ft)
+					}
+				}
+
+				fields = append(fields, f)
+			}
+		}
+
+		current = next
+	}
+
+	return fields
+}
+
+// isScalar reports whether the ",string" option applies to t.
+func isScalar(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.String:
+		return true
+	default:
+		return false
+	}
+}
+
+// tsNamespace is a level of BridgeClient: functions named a.b.c are
+// methods c of namespace b of namespace a.
+type tsNamespace struct {
+	methods []tsMethod
+	names   []string
+	nested  map[string]*tsNamespace
+}
+
+type tsMethod struct {
+	name   string
+	fn     *Function
+	params string
+	result string
+}
+
+func (n *tsNamespace) add(path []string, fn *Function, params, result string) {
+	if len(path) == 1 {
+		n.methods = append(n.methods, tsMethod{name: path[0], fn: fn, params: params, result: result})
+		return
+	}
+
+	if n.nested == nil {
+		n.nested = make(map[string]*tsNamespace)
+	}
+
+	child, ok := n.nested[path[0]]
+	if !ok {
+		child = &tsNamespace{}
+		n.nested[path[0]] = child
+		n.names = append(n.names, path[0])
+	}
+
+	child.add(path[1:], fn, params, result)
+}
+
+func (n *tsNamespace) write(w io.Writer, depth int) {
+	indent := strings.Repeat("  ", depth+1)
+
+	fmt.Fprint(w, "{\n")
+
+	for _, m := range n.methods {
+		if m.fn.Description != "" {
+			fmt.Fprintf(w, "%s/** %s */\n", indent, strings.ReplaceAll(m.fn.Description, "*/", "* /"))
+		}
+
+		params := ""
+		if m.params != "void" {
+			params = "params: " + m.params
+		}
+
+		fmt.Fprintf(w, "%s%s(%s): Promise<%s>;\n", indent, tsKey(m.name), params, m.result)
+	}
+
+	for _, name := range n.names {
+		fmt.Fprintf(w, "%s%s: ", indent, tsKey(name))
+		n.nested[name].write(w, depth+1)
+		fmt.Fprint(w, ";\n")
+	}
+
+	fmt.Fprint(w, strings.Repeat("  ", depth)+"}")
+}
+
+var tsIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// tsKey returns name as a property key, quoted unless it is an identifier.
+func tsKey(name string) string {
+	if tsIdentifier.MatchString(name) {
+		return name
+	}
+
+	return tsString(name)
+}
+
+// tsString returns s as a string literal.
+func tsString(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
+
+// typeName turns a Go type name into a declaration name. Type arguments
+// of generic types are appended without their packages, so Page[pkg.User]
+// becomes PageUser.
+func typeName(name string) string {
+	var b strings.Builder
+
+	for part := range strings.FieldsFuncSeq(name, func(r rune) bool {
+		return r == '[' || r == ']' || r == ',' || r == ' ' || r == '*'
+	}) {
+		part = part[strings.LastIndex(part, ".")+1:]
+		part = strings.Map(func(r rune) rune {
+			if r == '_' || r == '$' || r >= '0' && r <= '9' || r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z' {
+				return r
+			}
+
+			return -1
+		}, part)
+
+		if part == "" {
+			continue
+		}
+
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+
+	return b.String()
+}
@@ -0,0 +1,155 @@
+package bridge
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+type tsAudit struct {
+	CreatedAt time.Time  `json:"createdAt"`
+	DeletedAt *time.Time `json:"deletedAt"`
+}
+
+type tsAddress struct {
+	Street string `json:"street"`
+}
+
+type tsUser struct {
+	tsAudit
+
+	ID       string            `json:"id"`
+	Name     string            `json:"name,omitempty"`
+	Age      int               `json:"age,string"`
+	Address  *tsAddress        `json:"address"`
+	Tags     []string          `json:"tags"`
+	Labels   map[string]string `json:"labels"`
+	Friends  []*tsUser         `json:"friends"`
+	Avatar   []byte            `json:"avatar"`
+	Extra    any               `json:"extra"`
+	Password string            `json:"-"`
+	Untagged bool
+	internal string
+}
+
+type tsGetParams struct {
+	ID string `json:"id"`
+}
+
+type tsPage[T any] struct {
+	Items []T `json:"items"`
+	Total int `json:"total"`
+}
+
+func generateTS(t *testing.T, b *Bridge) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := b.GenerateTypeScript(&buf); err != nil {
+		t.Fatalf("GenerateTypeScript() error = %v", err)
+	}
+
+	return buf.String()
+}
+
+func TestGenerateTypeScript(t *testing.T) {
+	b := New()
+
+	mustRegister := func(name string, handler any, opts ...FunctionOption) {
+		t.Helper()
+
+		if err := b.Register(name, handler, opts...); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	mustRegister("users.get", func(ctx Context, p tsGetParams) (*tsUser, error) {
+		return nil, nil
+	}, WithDescription("Get a user by ID"))
+	mustRegister("users.list", func(ctx Context) (tsPage[tsUser], error) {
+		return tsPage[tsUser]{}, nil
+	})
+	mustRegister("ping", func(ctx Context) error { return nil })
+	mustRegister("log-event", func(ctx Context, p struct {
+		Event string `json:"event"`
+	}) error {
+		return nil
+	})
+
+	got := generateTS(t, b)
+
+	want := []string{
+		TypeScriptHeader + "\n",
+		`export interface TsUser {
+  id: string;
+  name?: string;
+  age: string;
+  address?: TsAddress;
+  tags: string[];
+  labels: Record<string, string>;
+  friends: TsUser[];
+  avatar: string;
+  extra: unknown;
+  Untagged: boolean;
+  createdAt: string;
+  deletedAt?: string;
+}`,
+		`export interface TsAddress {
+  street: string;
+}`,
+		`export interface TsPageTsUser {
+  items: TsUser[];
+  total: number;
+}`,
+		`  "log-event": { params: { event: string; }; result: void };`,
+		`  "users.get": { params: TsGetParams; result: TsUser };`,
+		`  "ping": { params: void; result: void };`,
+		`export interface BridgeClient {
+  "log-event"(params: { event: string; }): Promise<void>;
+  ping(): Promise<void>;
+  users: {
+    /** Get a user by ID */
+    get(params: TsGetParams): Promise<TsUser>;
+    list(): Promise<TsPageTsUser>;
+  };
+}`,
+		"export interface TypedForgeBridge {",
+	}
+
+	for _, w := range want {
+		if !strings.Contains(got, w) {
+			t.Errorf("Expected output to contain:\n%s\n\ngot:\n%s", w, got)
+		}
+	}
+
+	for _, unwanted := range []string{"Password", "internal", "tsAudit"} {
+		if strings.Contains(got, unwanted) {
+			t.Errorf("Expected no %q in the output", unwanted)
+		}
+	}
+
+	if again := generateTS(t, b); again != got {
+		t.Error("Expected deterministic output")
+	}
+}
+
+func TestTypeName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"User", "User"},
+		{"page[github.com/acme/app/models.User]", "PageUser"},
+		{"Pair[string,int]", "PairStringInt"},
+		{"Map[string,*example.com/x.Item]", "MapStringItem"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := typeName(tt.name); got != tt.want {
+				t.Errorf("typeName(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
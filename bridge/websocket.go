@@ -2,17 +2,23 @@ package bridge
 
 import (
 	"context"
+	"crypto/rand"
 	"encoding/json"
 	"errors"
-	"log"
 	"net/http"
+	"strings"
 	"sync"
 	"time"
 
 	"nhooyr.io/websocket" //nolint:staticcheck // Library moved to github.com/coder/websocket - migration pending
+
+	"github.com/xraph/forgeui/auth"
+	"github.com/xraph/forgeui/logging"
 )
 
-// WSHandler handles WebSocket connections
+// WSHandler handles WebSocket connections. Clients call functions with
+// JSON-RPC 2.0 messages, and subscribe to topics with the $subscribe and
+// $unsubscribe methods (see AuthorizeTopic and Publish).
 type WSHandler struct {
 	bridge      *Bridge
 	security    *Security
@@ -24,7 +30,13 @@ type wsConnection struct {
 	conn   *websocket.Conn //nolint:staticcheck // Library moved to github.com/coder/websocket
 	ctx    Context
 	send   chan []byte
+	done   chan struct{} // closed when writePump stops
 	userID string
+	member Member
+	busy   sync.Mutex // held while a message is handled
+
+	subsMu sync.Mutex
+	subs   map[string]func() // unsubscribe funcs by topic
 }
 
 // NewWSHandler creates a new WebSocket handler
@@ -37,53 +49,96 @@ func NewWSHandler(bridge *Bridge) *WSHandler {
 
 // ServeHTTP handles WebSocket upgrade requests
 func (h *WSHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Refuse new connections while shutting down
+	done, ok := h.bridge.track(transportWebSocket)
+	if !ok {
+		http.Error(w, "Server shutting down", http.StatusServiceUnavailable)
+		return
+	}
+
 	// Upgrade to WebSocket
 	conn, err := websocket.Accept(w, r, &websocket.AcceptOptions{ //nolint:staticcheck // Library moved to github.com/coder/websocket
 		OriginPatterns: h.bridge.config.AllowedOrigins,
 	})
 	if err != nil {
-		log.Printf("WebSocket upgrade failed: %v", err)
+		h.bridge.logger(r.Context()).Warn("websocket upgrade failed", logging.KeyError, err.Error())
+		done()
+
 		return
 	}
 
-	// Create bridge context
+	// Create bridge context. The request's context ends when ServeHTTP
+	// returns, the connection's when it closes.
+	r = h.bridge.extractTrace(r)
 	ctx := NewContext(r)
+	ctx = withContext(ctx, context.WithoutCancel(ctx.Context()))
+
+	connID := rand.Text()
 
 	// Create connection
 	wsConn := &wsConnection{
-		conn: conn,
-		ctx:  ctx,
-		send: make(chan []byte, 256),
+		conn:   conn,
+		ctx:    ctx,
+		send:   make(chan []byte, 256),
+		done:   make(chan struct{}),
+		member: Member{ConnID: connID},
+		subs:   make(map[string]func()),
+	}
+
+	if user := ctx.User(); user != nil {
+		wsConn.userID = user.ID()
+		wsConn.member.UserID = user.ID()
+		wsConn.member.Name = user.Name()
 	}
 
 	// Register connection
-	connID := generateRequestID()
 	h.connections.Store(connID, wsConn)
 
 	// Handle connection
-	go h.handleConnection(connID, wsConn)
+	go func() {
+		defer done()
+		h.handleConnection(connID, wsConn)
+	}()
 }
 
 // handleConnection manages a WebSocket connection
 func (h *WSHandler) handleConnection(connID string, wsConn *wsConnection) {
 	defer func() {
 		h.connections.Delete(connID)
+		h.unsubscribeAll(wsConn)
 		_ = wsConn.conn.Close(websocket.StatusNormalClosure, "connection closed") //nolint:staticcheck // Library moved to github.com/coder/websocket
 	}()
 
 	// Start write pump
 	go h.writePump(wsConn)
 
+	// Close the connection when the bridge shuts down, between messages
+	closed := make(chan struct{})
+	defer close(closed)
+
+	go func() {
+		select {
+		case <-h.bridge.draining():
+			wsConn.busy.Lock()
+			defer wsConn.busy.Unlock()
+
+			_ = wsConn.conn.Close(websocket.StatusGoingAway, "server shutting down") //nolint:staticcheck // Library moved to github.com/coder/websocket
+		case <-closed:
+		}
+	}()
+
 	// Read messages
 	for {
 		_, message, err := wsConn.conn.Read(context.Background()) //nolint:staticcheck // Library moved to github.com/coder/websocket
 		if err != nil {
-			log.Printf("WebSocket read error: %v", err)
+			h.bridge.logger(wsConn.ctx.Context()).Debug("websocket read failed", logging.KeyError, err.Error())
 			return
 		}
 
 		// Process message
+		wsConn.busy.Lock()
 		h.processMessage(wsConn, message)
+		wsConn.busy.Unlock()
 	}
 }
 
@@ -95,6 +150,12 @@ func (h *WSHandler) processMessage(wsConn *wsConnection, message []byte) {
 		return
 	}
 
+	// Subscription methods
+	if strings.HasPrefix(req.Method, "$") {
+		h.processTopicMessage(wsConn, req)
+		return
+	}
+
 	// Get function
 	fn, err := h.bridge.GetFunction(req.Method)
 	if err != nil {
@@ -114,6 +175,14 @@ func (h *WSHandler) processMessage(wsConn *wsConnection, message []byte) {
 		return
 	}
 
+	// Check rate limit
+	if _, rlErr := h.security.RateLimit(wsConn.ctx, fn); rlErr != nil {
+		h.bridge.metrics.rateLimit(fn.Name)
+		h.sendError(wsConn, req.ID, rlErr)
+
+		return
+	}
+
 	// Execute function
 	result := h.bridge.execute(wsConn.ctx, req.Method, req.Params)
 
@@ -137,7 +206,199 @@ func (h *WSHandler) processMessage(wsConn *wsConnection, message []byte) {
 		return
 	}
 
-	wsConn.send <- data
+	h.reply(wsConn, data)
+}
+
+// subscription is the result of $subscribe.
+type subscription struct {
+	Topic   string   `json:"topic"`
+	Members []Member `json:"members,omitempty"`
+}
+
+// processTopicMessage handles the $subscribe, $unsubscribe and $presence
+// methods.
+func (h *WSHandler) processTopicMessage(wsConn *wsConnection, req Request) {
+	var params struct {
+		Topic string `json:"topic"`
+	}
+
+	if err := json.Unmarshal(req.Params, &params); err != nil || params.Topic == "" {
+		h.sendError(wsConn, req.ID, NewError(ErrCodeInvalidParams, "Topic required"))
+		return
+	}
+
+	var (
+		result any
+		err    *Error
+	)
+
+	switch req.Method {
+	case MethodSubscribe:
+		result, err = h.subscribe(wsConn, params.Topic)
+	case MethodUnsubscribe:
+		h.unsubscribe(wsConn, params.Topic)
+		result = subscription{Topic: params.Topic}
+	case MethodPresence:
+		result, err = h.presence(wsConn, params.Topic)
+	default:
+		err = ErrMethodNotFound
+	}
+
+	if err != nil {
+		h.sendError(wsConn, req.ID, err)
+		return
+	}
+
+	data, marshalErr := json.Marshal(Response{JSONRPC: "2.0", ID: req.ID, Result: result})
+	if marshalErr != nil {
+		h.sendError(wsConn, req.ID, NewError(ErrCodeInternal, "Failed to marshal response"))
+		return
+	}
+
+	h.reply(wsConn, data)
+}
+
+// authorizeTopic returns the rule of topic if the connection's user may
+// subscribe to it.
+func (h *WSHandler) authorizeTopic(wsConn *wsConnection, topic string) (*topicRule, *Error) {
+	rule, params := h.bridge.topicRule(topic)
+	if rule == nil {
+		return nil, NewError(ErrCodeForbidden, "Topic not allowed")
+	}
+
+	if rule.policy == nil {
+		return rule, nil
+	}
+
+	err := auth.Check(wsConn.ctx.User(), auth.WithParams(wsConn.ctx.Request(), params), rule.policy)
+	if errors.Is(err, auth.ErrUnauthenticated) {
+		return nil, ErrUnauthorized
+	}
+
+	if err != nil {
+		return nil, ErrForbidden
+	}
+
+	return rule, nil
+}
+
+// subscribe forwards the events of topic to the connection and, for
+// topics with presence, joins it.
+func (h *WSHandler) subscribe(wsConn *wsConnection, topic string) (any, *Error) {
+	rule, authErr := h.authorizeTopic(wsConn, topic)
+	if authErr != nil {
+		return nil, authErr
+	}
+
+	wsConn.subsMu.Lock()
+	defer wsConn.subsMu.Unlock()
+
+	ctx := wsConn.ctx.Context()
+	broker := h.bridge.config.Broker
+	result := subscription{Topic: topic}
+
+	if _, ok := wsConn.subs[topic]; !ok {
+		if rule.presence {
+			if err := broker.Join(ctx, topic, wsConn.member); err != nil {
+				return nil, NewError(ErrCodeInternal, "Failed to join topic", err.Error())
+			}
+
+			member := wsConn.member
+			h.notifyPresence(ctx, PresenceEvent{Topic: topic, Joined: &member})
+		}
+
+		unsubscribe, err := broker.Subscribe(topic, func(data []byte) {
+			select {
+			case wsConn.send <- data:
+			default:
+				// Channel full, skip
+			}
+		})
+		if err != nil {
+			return nil, NewError(ErrCodeInternal, "Failed to subscribe", err.Error())
+		}
+
+		wsConn.subs[topic] = unsubscribe
+	}
+
+	if rule.presence {
+		members, err := broker.Members(ctx, topic)
+		if err != nil {
+			return nil, NewError(ErrCodeInternal, "Failed to list members", err.Error())
+		}
+
+		result.Members = members
+	}
+
+	return result, nil
+}
+
+// unsubscribe stops forwarding the events of topic and leaves it.
+func (h *WSHandler) unsubscribe(wsConn *wsConnection, topic string) {
+	wsConn.subsMu.Lock()
+	defer wsConn.subsMu.Unlock()
+
+	h.unsubscribeLocked(wsConn, topic)
+}
+
+// unsubscribeAll ends the subscriptions of a closed connection.
+func (h *WSHandler) unsubscribeAll(wsConn *wsConnection) {
+	wsConn.subsMu.Lock()
+	defer wsConn.subsMu.Unlock()
+
+	for topic := range wsConn.subs {
+		h.unsubscribeLocked(wsConn, topic)
+	}
+}
+
+// unsubscribeLocked ends a subscription. Callers hold wsConn.subsMu.
+func (h *WSHandler) unsubscribeLocked(wsConn *wsConnection, topic string) {
+	unsubscribe, ok := wsConn.subs[topic]
+	if !ok {
+		return
+	}
+
+	unsubscribe()
+	delete(wsConn.subs, topic)
+
+	if rule, _ := h.bridge.topicRule(topic); rule != nil && rule.presence {
+		ctx := wsConn.ctx.Context()
+
+		if err := h.bridge.config.Broker.Leave(ctx, topic, wsConn.member); err != nil {
+			h.bridge.logger(ctx).Warn("websocket leave failed", "topic", topic, logging.KeyError, err.Error())
+		}
+
+		member := wsConn.member
+		h.notifyPresence(ctx, PresenceEvent{Topic: topic, Left: &member})
+	}
+}
+
+// presence returns the members of topic, for subscribers of topics with
+// presence.
+func (h *WSHandler) presence(wsConn *wsConnection, topic string) (any, *Error) {
+	rule, authErr := h.authorizeTopic(wsConn, topic)
+	if authErr != nil {
+		return nil, authErr
+	}
+
+	if !rule.presence {
+		return nil, NewError(ErrCodeBadRequest, "Topic has no presence")
+	}
+
+	members, err := h.bridge.config.Broker.Members(wsConn.ctx.Context(), topic)
+	if err != nil {
+		return nil, NewError(ErrCodeInternal, "Failed to list members", err.Error())
+	}
+
+	return subscription{Topic: topic, Members: members}, nil
+}
+
+// notifyPresence tells the subscribers of a topic that a member joined or
+// left.
+func (h *WSHandler) notifyPresence(ctx context.Context, event PresenceEvent) {
+	if err := h.bridge.notify(ctx, event.Topic, MethodPresence, event); err != nil {
+		h.bridge.logger(ctx).Warn("websocket presence notification failed", "topic", event.Topic, logging.KeyError, err.Error())
+	}
 }
 
 // sendError sends an error response
@@ -154,11 +415,23 @@ func (h *WSHandler) sendError(wsConn *wsConnection, id any, err *Error) {
 		data = []byte(`{"jsonrpc":"2.0","error":{"code":-32603,"message":"Internal error"}}`)
 	}
 
-	wsConn.send <- data
+	h.reply(wsConn, data)
+}
+
+// reply queues a response to the connection's request. It waits for room
+// in the queue, and drops the response once writePump has stopped, since
+// nothing drains the queue anymore.
+func (h *WSHandler) reply(wsConn *wsConnection, data []byte) {
+	select {
+	case wsConn.send <- data:
+	case <-wsConn.done:
+	}
 }
 
 // writePump sends messages to the WebSocket
 func (h *WSHandler) writePump(wsConn *wsConnection) {
+	defer close(wsConn.done)
+
 	ticker := time.NewTicker(54 * time.Second)
 	defer ticker.Stop()
 
@@ -175,7 +448,7 @@ func (h *WSHandler) writePump(wsConn *wsConnection) {
 			cancel()
 
 			if err != nil {
-				log.Printf("WebSocket write error: %v", err)
+				h.bridge.logger(wsConn.ctx.Context()).Debug("websocket write failed", logging.KeyError, err.Error())
 				return
 			}
 
@@ -193,11 +466,21 @@ func (h *WSHandler) writePump(wsConn *wsConnection) {
 	}
 }
 
+// WebSocketHandler returns the bridge's WebSocket handler. It is shared, so
+// Broadcast and SendToUser reach every connection it serves.
+func (b *Bridge) WebSocketHandler() *WSHandler {
+	b.wsOnce.Do(func() {
+		b.ws = NewWSHandler(b)
+	})
+
+	return b.ws
+}
+
 // Broadcast sends a message to all connected clients
 func (h *WSHandler) Broadcast(event Event) {
 	data, err := json.Marshal(event)
 	if err != nil {
-		log.Printf("Failed to marshal event: %v", err)
+		h.bridge.logger(context.Background()).Error("websocket event marshal failed", "event", event.Type, logging.KeyError, err.Error())
 		return
 	}
 
@@ -218,7 +501,7 @@ func (h *WSHandler) Broadcast(event Event) {
 func (h *WSHandler) SendToUser(userID string, event Event) {
 	data, err := json.Marshal(event)
 	if err != nil {
-		log.Printf("Failed to marshal event: %v", err)
+		h.bridge.logger(context.Background()).Error("websocket event marshal failed", "event", event.Type, logging.KeyError, err.Error())
 		return
 	}
 
@@ -0,0 +1,141 @@
+package commands
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/xraph/forgeui/cli"
+	"github.com/xraph/forgeui/cli/util"
+)
+
+//nolint:gochecknoinits // init used for command registration
+func init() {
+	cli.RegisterCommand(BridgeCommand())
+}
+
+// BridgeCommand returns the bridge command with subcommands
+func BridgeCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "bridge",
+		Short: "Work with bridge functions",
+		Long:  `Generate client code from the app's bridge functions.`,
+		Usage: "forgeui bridge <command> [flags]",
+		Subcommands: []*cli.Command{
+			BridgeGenTSCommand(),
+		},
+	}
+}
+
+// BridgeGenTSCommand returns the TypeScript generation subcommand
+func BridgeGenTSCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "gen-ts",
+		Short: "Generate TypeScript declarations for bridge functions",
+		Long: `Run the app with FORGEUI_BRIDGE_TS set and write the TypeScript declarations
+of its bridge functions: an interface per parameter and result type, and
+typed views of the forge-bridge.js client (BridgeClient, TypedForgeBridge).
+
+The app's main must call App.Run, or App.BridgeTypeScriptFromEnv before it
+starts serving; an app that doesn't call either within 10 seconds, or is
+still running after a minute, fails the command.
+
+With --check nothing is written; the command fails when the file is
+missing or stale, for use in CI.`,
+		Usage: "forgeui bridge gen-ts [flags]",
+		Flags: []cli.Flag{
+			cli.StringFlag("out", "o", "Output file", "web/bridge.d.ts"),
+			cli.BoolFlag("check", "c", "Fail if the output file is not up to date"),
+		},
+		Run: runBridgeGenTS,
+	}
+}
+
+func runBridgeGenTS(ctx *cli.Context) error {
+	projectRoot, err := util.GetProjectRoot()
+	if err != nil {
+		return fmt.Errorf("not in a Go project: %w", err)
+	}
+
+	outFile := ctx.GetString("out")
+	if !filepath.IsAbs(outFile) {
+		outFile = filepath.Join(projectRoot, outFile)
+	}
+
+	generated, err := generateBridgeTypeScript(projectRoot)
+	if err != nil {
+		return err
+	}
+
+	if ctx.GetBool("check") {
+		if err := checkGenerated(outFile, generated); err != nil {
+			return err
+		}
+
+		ctx.Printf("%s✓ %s is up to date%s\n", util.ColorGreen, outFile, util.ColorReset)
+
+		return nil
+	}
+
+	if err := util.CreateFile(outFile, string(generated)); err != nil {
+		return err
+	}
+
+	ctx.Printf("\n%s✓ Generated TypeScript declarations%s\n\n", util.ColorGreen, util.ColorReset)
+	ctx.Printf("Location: %s\n\n", outFile)
+
+	return nil
+}
+
+// genTSTimeout bounds the run of the app generating TypeScript declarations.
+const genTSTimeout = time.Minute
+
+// generateBridgeTypeScript runs the app in dir with FORGEUI_BRIDGE_TS set,
+// so its call to App.BridgeTypeScriptFromEnv writes the declarations, and
+// returns them.
+func generateBridgeTypeScript(dir string) ([]byte, error) {
+	tmp, err := os.CreateTemp("", "forgeui-bridge-*.d.ts")
+	if err != nil {
+		return nil, err
+	}
+
+	_ = tmp.Close()
+
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if err := runAppHook(dir, "FORGEUI_BRIDGE_TS="+tmp.Name(), "BridgeTypeScriptFromEnv", genTSTimeout); err != nil {
+		return nil, fmt.Errorf("generation failed: %w", err)
+	}
+
+	data, err := os.ReadFile(tmp.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) == 0 {
+		return nil, errors.New("the app wrote no declarations; does main call App.Run or App.BridgeTypeScriptFromEnv?")
+	}
+
+	return data, nil
+}
+
+// checkGenerated returns an error unless the file at path holds generated.
+func checkGenerated(path string, generated []byte) error {
+	current, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("%s does not exist; run forgeui bridge gen-ts", path)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal(current, generated) {
+		return fmt.Errorf("%s is stale; run forgeui bridge gen-ts", path)
+	}
+
+	return nil
+}
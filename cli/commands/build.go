@@ -1,11 +1,14 @@
 package commands
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime"
+	"time"
 
 	"github.com/xraph/forgeui/cli"
 	"github.com/xraph/forgeui/cli/util"
@@ -27,13 +30,20 @@ This command:
   - Processes and optimizes assets (CSS, JS)
   - Generates fingerprinted asset files
   - Creates a manifest file
-  - Optionally compiles a Go binary`,
+  - Optionally compiles a Go binary
+
+With --static, the app is run with FORGEUI_EXPORT set to the output
+directory and exports every page to HTML through App.ExportFromEnv,
+failing on broken internal links, when the app doesn't start exporting
+within 10 seconds because its main doesn't call App.Run or
+App.ExportFromEnv, or when the export takes more than 10 minutes.`,
 		Usage: "forgeui build [flags]",
 		Flags: []cli.Flag{
 			cli.StringFlag("output", "o", "Output directory", "dist"),
 			cli.BoolFlag("binary", "b", "Compile Go binary"),
 			cli.BoolFlag("minify", "m", "Minify assets"),
 			cli.BoolFlag("embed", "e", "Embed assets in binary"),
+			cli.BoolFlag("static", "s", "Export a static site"),
 		},
 		Run: runBuild,
 	}
@@ -57,6 +67,7 @@ func runBuild(ctx *cli.Context) error {
 	buildBinary := ctx.GetBool("binary") || ctx.Config.Build.Binary
 	minify := ctx.GetBool("minify") || ctx.Config.Build.Minify
 	embed := ctx.GetBool("embed") || ctx.Config.Build.EmbedAssets
+	static := ctx.GetBool("static") || ctx.Config.Build.Static
 
 	ctx.Printf("\n%sBuilding for production...%s\n\n", util.ColorBlue, util.ColorReset)
 
@@ -71,16 +82,28 @@ func runBuild(ctx *cli.Context) error {
 
 	spinner.Success("Output directory created")
 
-	// Copy static assets
-	spinner = util.NewSpinner("Copying static assets")
-	spinner.Start()
+	// Export pages, or copy static assets for the server
+	if static {
+		spinner = util.NewSpinner("Exporting static site")
+		spinner.Start()
 
-	if err := copyStaticAssets(outputDir, ctx.Config.Build.PublicDir); err != nil {
-		spinner.Error(fmt.Sprintf("Failed: %v", err))
-		return err
-	}
+		if err := exportStaticSite(outputDir); err != nil {
+			spinner.Error(fmt.Sprintf("Failed: %v", err))
+			return err
+		}
+
+		spinner.Success("Static site exported")
+	} else {
+		spinner = util.NewSpinner("Copying static assets")
+		spinner.Start()
+
+		if err := copyStaticAssets(outputDir, ctx.Config.Build.PublicDir); err != nil {
+			spinner.Error(fmt.Sprintf("Failed: %v", err))
+			return err
+		}
 
-	spinner.Success("Static assets copied")
+		spinner.Success("Static assets copied")
+	}
 
 	// Process assets if configured
 	if len(ctx.Config.Assets.CSS) > 0 || len(ctx.Config.Assets.JS) > 0 {
@@ -147,6 +170,123 @@ func copyStaticAssets(outputDir, publicDir string) error {
 	return util.CopyDir(publicDir, staticDir)
 }
 
+// exportTimeout bounds the export run of the app.
+const exportTimeout = 10 * time.Minute
+
+// exportStaticSite runs the app with FORGEUI_EXPORT set, so its call to
+// App.ExportFromEnv writes the site to outputDir. The app's main must call
+// ExportFromEnv before it starts serving.
+func exportStaticSite(outputDir string) error {
+	absDir, err := filepath.Abs(outputDir)
+	if err != nil {
+		return err
+	}
+
+	if err := runAppHook("", "FORGEUI_EXPORT="+absDir, "ExportFromEnv", exportTimeout); err != nil {
+		return fmt.Errorf("export failed: %w", err)
+	}
+
+	return nil
+}
+
+// hookStartTimeout bounds how long runAppHook waits for the app to call
+// the hook, which signals it by creating the file named by
+// FORGEUI_HOOK_STARTED (see forgeui.HookStartedEnv).
+var hookStartTimeout = 10 * time.Second
+
+// runAppHook builds the app in dir and runs it with env set, for a main
+// that calls hook, such as App.ExportFromEnv, and exits. An app whose main
+// doesn't call it starts serving instead; it is stopped once it hasn't
+// called the hook within hookStartTimeout. The run is stopped after timeout.
+func runAppHook(dir, env, hook string, timeout time.Duration) error {
+	tmpDir, err := os.MkdirTemp("", "forgeui-app-*")
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	binary := filepath.Join(tmpDir, "app")
+	if runtime.GOOS == "windows" {
+		binary += ".exe"
+	}
+
+	var stderr bytes.Buffer
+
+	// Build first, so the timeout covers only the run, and stopping the
+	// app doesn't leave a `go run` child behind
+	build := exec.CommandContext(context.Background(), "go", "build", "-o", binary, ".")
+	build.Dir = dir
+	build.Stderr = &stderr
+
+	if err := build.Run(); err != nil {
+		return fmt.Errorf("build failed: %w\n%s", err, stderr.String())
+	}
+
+	stderr.Reset()
+
+	runCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	started := filepath.Join(tmpDir, "started")
+
+	cmd := exec.CommandContext(runCtx, binary)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), env, "FORGEUI_HOOK_STARTED="+started)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
+
+	if !waitForFile(started, hookStartTimeout, exited) {
+		cancel()
+		<-exited
+
+		return fmt.Errorf("the app didn't call App.%s within %v; does main call App.Run or App.%s before it starts serving?", hook, hookStartTimeout, hook)
+	}
+
+	err = <-exited
+	if runCtx.Err() != nil {
+		return fmt.Errorf("the app was still running App.%s after %v", hook, timeout)
+	}
+
+	if err != nil {
+		return fmt.Errorf("%w\n%s", err, stderr.String())
+	}
+
+	return nil
+}
+
+// waitForFile reports whether path exists within timeout. It returns true
+// early when exited receives, putting the result back for the caller: an
+// app that exits has finished, whether or not it called the hook.
+func waitForFile(path string, timeout time.Duration, exited chan error) bool {
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+
+	poll := time.NewTicker(20 * time.Millisecond)
+	defer poll.Stop()
+
+	for {
+		select {
+		case err := <-exited:
+			exited <- err
+			return true
+		case <-poll.C:
+			if util.FileExists(path) {
+				return true
+			}
+		case <-deadline.C:
+			return util.FileExists(path)
+		}
+	}
+}
+
 func processAssets(outputDir string, config *cli.Config, minify bool) error {
 	// This would integrate with the assets package
 	// For now, just create placeholder files
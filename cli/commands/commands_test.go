@@ -2,10 +2,14 @@ package commands
 
 import (
 	"os"
+	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/xraph/forgeui/cli"
+	"github.com/xraph/forgeui/cli/templates"
 	"github.com/xraph/forgeui/cli/util"
 )
 
@@ -71,6 +75,67 @@ func TestBuildCommand(t *testing.T) {
 	}
 }
 
+func TestRunAppHook(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go not installed")
+	}
+
+	dir := t.TempDir()
+
+	// An app that calls the hook when HOOK is set, taking SLOW to finish,
+	// and otherwise serves
+	writeFiles(t, dir, map[string]string{
+		"go.mod": "module example.com/app\n\ngo 1.24\n",
+		"main.go": `package main
+
+import (
+	"os"
+	"time"
+)
+
+func main() {
+	if os.Getenv("HOOK") != "" {
+		_ = os.WriteFile(os.Getenv("FORGEUI_HOOK_STARTED"), nil, 0600)
+
+		if os.Getenv("SLOW") != "" {
+			time.Sleep(time.Hour)
+		}
+
+		return
+	}
+
+	time.Sleep(time.Hour)
+}
+`,
+	})
+
+	defer func(timeout time.Duration) { hookStartTimeout = timeout }(hookStartTimeout)
+	hookStartTimeout = 500 * time.Millisecond
+
+	if err := runAppHook(dir, "HOOK=1", "Hook", time.Minute); err != nil {
+		t.Errorf("Expected the hook run to succeed, got %v", err)
+	}
+
+	// A missing hook fails within hookStartTimeout, not the run's timeout
+	start := time.Now()
+
+	err := runAppHook(dir, "OTHER=1", "Hook", time.Minute)
+	if err == nil || !strings.Contains(err.Error(), "does main call App.Run or App.Hook") {
+		t.Errorf("Expected a missing hook error, got %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed > 30*time.Second {
+		t.Errorf("Expected a missing hook to fail quickly, took %v", elapsed)
+	}
+
+	t.Setenv("SLOW", "1")
+
+	err = runAppHook(dir, "HOOK=1", "Hook", 2*time.Second)
+	if err == nil || !strings.Contains(err.Error(), "still running App.Hook") {
+		t.Errorf("Expected a timeout error, got %v", err)
+	}
+}
+
 func TestPluginCommand(t *testing.T) {
 	cmd := PluginCommand()
 
@@ -233,3 +298,248 @@ func TestConfig(t *testing.T) {
 		t.Errorf("Loaded config name = %v, want %v", loaded.Name, config.Name)
 	}
 }
+
+func TestRoutesCommand(t *testing.T) {
+	cmd := RoutesCommand()
+
+	if cmd.Name != "routes" {
+		t.Errorf("RoutesCommand().Name = %v, want %v", cmd.Name, "routes")
+	}
+
+	if len(cmd.Subcommands) == 0 || cmd.Subcommands[0].Name != "generate" {
+		t.Error("RoutesCommand() should have generate subcommand")
+	}
+}
+
+// writeFiles creates files under dir from a map of relative paths to content.
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+
+	for name, content := range files {
+		if err := util.CreateFile(filepath.Join(dir, name), content); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+}
+
+func TestGenerateRoutes(t *testing.T) {
+	dir := t.TempDir()
+
+	writeFiles(t, dir, map[string]string{
+		"go.mod":                          "module example.com/app\n\ngo 1.24\n",
+		"pages/layout.templ":              "package pages\n\ntempl Layout(ctx *router.PageContext, content templ.Component) {}\n",
+		"pages/index.templ":               "package pages\n\ntempl IndexPage(ctx *router.PageContext) {}\n",
+		"pages/about.templ":               "package pages\n\ntempl AboutPage(ctx *router.PageContext) {}\n",
+		"pages/loader.go":                 "package pages\n\nfunc IndexLoader(ctx context.Context, params router.Params) (any, error) { return nil, nil }\n",
+		"pages/users/layout.templ":        "package users\n\ntempl Layout(ctx *router.PageContext, content templ.Component) {}\n",
+		"pages/users/index.templ":         "package users\n\ntempl IndexPage(ctx *router.PageContext) {}\n",
+		"pages/users/param_id.templ":      "package users\n\ntempl ParamIdPage(ctx *router.PageContext) {}\n",
+		"pages/users/loader.go":           "package users\n\nfunc ParamIdLoader(ctx context.Context, params router.Params) (any, error) { return nil, nil }\n\nfunc LayoutLoader(ctx context.Context, params router.Params) (any, error) { return nil, nil }\n",
+		"pages/users/param_id/edit.templ": "package param_id\n\ntempl EditPage(ctx *router.PageContext) {}\n",
+		"pages/docs/catchall_path.templ":  "package docs\n\ntempl CatchallPathPage(ctx *router.PageContext) {}\n",
+		"pages/blog/optional_page.templ":  "package blog\n\ntempl OptionalPagePage(ctx *router.PageContext) {}\n",
+		"pages/users/profile.templ":       "package users\n\ntempl ProfilePage(ctx *router.PageContext, data ProfileData) {}\n",
+		"pages/users/profile.go":          "package users\n\nfunc Profile(ctx *router.PageContext) (templ.Component, error) { return ProfilePage(ctx, ProfileData{}), nil }\n",
+		"pages/_drafts/ignored.templ":     "package drafts\n",
+		"pages/components/readme.md":      "no pages here\n",
+	})
+
+	tree, err := templates.ScanPages(filepath.Join(dir, "pages"))
+	if err != nil {
+		t.Fatalf("ScanPages() error = %v", err)
+	}
+
+	patterns := make([]string, 0, len(tree.Pages))
+	for _, page := range tree.Pages {
+		patterns = append(patterns, page.Pattern)
+	}
+
+	wantPatterns := []string{"/about", "/", "/blog/{page?}", "/docs/*path", "/users", "/users/{id}", "/users/profile", "/users/{id}/edit"}
+	if strings.Join(patterns, " ") != strings.Join(wantPatterns, " ") {
+		t.Errorf("Patterns = %v, want %v", patterns, wantPatterns)
+	}
+
+	src, err := tree.Generate(filepath.Join(dir, "pages", "routes_gen.go"))
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	code := string(src)
+
+	for _, want := range []string{
+		"// Code generated by \"forgeui routes generate\"; DO NOT EDIT.",
+		"package pages",
+		`users "example.com/app/pages/users"`,
+		`users_param_id "example.com/app/pages/users/param_id"`,
+		`r.RegisterLayout("pages", Layout)`,
+		`r.RegisterLayout("pages/users", users.Layout, router.WithParentLayout("pages"), router.WithLayoutLoader(users.LayoutLoader))`,
+		`rootGroup := r.Group("", router.GroupLayout("pages"))`,
+		`Handler(router.ComponentHandler(IndexPage))`,
+		"Loader(IndexLoader)",
+		`usersParamIdGroup := r.Group("/users/{id}", router.GroupLayout("pages/users"))`,
+		"Loader(users.ParamIdLoader)",
+		"Handler(users.Profile)",
+		`Name("users.param_id.edit")`,
+		`Name("docs.catchall_path")`,
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("Generated code missing %q\n%s", want, code)
+		}
+	}
+
+	if strings.Contains(code, "drafts") {
+		t.Error("Generated code should skip directories starting with an underscore")
+	}
+
+	// Output is deterministic
+	again, err := templates.ScanPages(filepath.Join(dir, "pages"))
+	if err != nil {
+		t.Fatalf("ScanPages() error = %v", err)
+	}
+
+	src2, err := again.Generate(filepath.Join(dir, "pages", "routes_gen.go"))
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	if string(src2) != code {
+		t.Error("Generate() output should be deterministic")
+	}
+}
+
+func TestGenerateRoutesFromPageTemplates(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"go.mod": "module example.com/app\n"})
+
+	for _, pageType := range []string{"simple", "dynamic", "form", "list", "detail"} {
+		tmpl, err := templates.GetPageTemplate(pageType)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		name := pageType + "_page"
+		opts := templates.PageOptions{Name: name, Package: "pages", Path: "/" + name}
+
+		if err := tmpl.Generate(filepath.Join(dir, "pages", name+".go"), opts); err != nil {
+			t.Fatalf("Generate(%s) error = %v", pageType, err)
+		}
+	}
+
+	tree, err := templates.ScanPages(filepath.Join(dir, "pages"))
+	if err != nil {
+		t.Fatalf("ScanPages() error = %v", err)
+	}
+
+	src, err := tree.Generate(filepath.Join(dir, "pages", "routes_gen.go"))
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	// Every page type renders through its handler, whatever the component takes
+	for _, want := range []string{"SimplePage", "DynamicPage", "FormPage", "ListPage", "DetailPage"} {
+		if !strings.Contains(string(src), "Handler("+want+")") {
+			t.Errorf("Generated code missing handler %s\n%s", want, src)
+		}
+	}
+}
+
+func TestGenerateRoutesErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+	}{
+		{
+			name: "conflicting params",
+			files: map[string]string{
+				"pages/param_id.templ":   "package pages\n\ntempl ParamIdPage(ctx *router.PageContext) {}\n",
+				"pages/param_slug.templ": "package pages\n\ntempl ParamSlugPage(ctx *router.PageContext) {}\n",
+			},
+		},
+		{
+			name: "missing component",
+			files: map[string]string{
+				"pages/about.templ": "package pages\n\ntempl About(ctx *router.PageContext) {}\n",
+			},
+		},
+		{
+			name: "component with data and no handler",
+			files: map[string]string{
+				"pages/about.templ": "package pages\n\ntempl AboutPage(ctx *router.PageContext, data AboutData) {}\n",
+			},
+		},
+		{
+			name: "layout without content",
+			files: map[string]string{
+				"pages/layout.templ": "package pages\n\ntempl Layout(ctx *router.PageContext) {}\n",
+			},
+		},
+		{
+			name: "bracketed page",
+			files: map[string]string{
+				"pages/users/[id].templ": "package users\n\ntempl ParamIdPage(ctx *router.PageContext) {}\n",
+			},
+		},
+		{
+			name: "bracketed directory",
+			files: map[string]string{
+				"pages/users/[id]/edit.templ": "package users\n\ntempl EditPage(ctx *router.PageContext) {}\n",
+			},
+		},
+		{
+			name: "underscored layout",
+			files: map[string]string{
+				"pages/_layout.templ": "package pages\n\ntempl Layout(ctx *router.PageContext, content templ.Component) {}\n",
+			},
+		},
+		{
+			name: "bad loader signature",
+			files: map[string]string{
+				"pages/about.templ": "package pages\n\ntempl AboutPage(ctx *router.PageContext) {}\n",
+				"pages/loader.go":   "package pages\n\nfunc AboutLoader() {}\n",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			tt.files["go.mod"] = "module example.com/app\n"
+			writeFiles(t, dir, tt.files)
+
+			if _, err := templates.ScanPages(filepath.Join(dir, "pages")); err == nil {
+				t.Error("ScanPages() should fail")
+			}
+		})
+	}
+}
+
+func TestBridgeCommand(t *testing.T) {
+	cmd := BridgeCommand()
+
+	if cmd.Name != "bridge" {
+		t.Errorf("BridgeCommand().Name = %v, want %v", cmd.Name, "bridge")
+	}
+
+	if len(cmd.Subcommands) == 0 || cmd.Subcommands[0].Name != "gen-ts" {
+		t.Error("BridgeCommand() should have gen-ts subcommand")
+	}
+}
+
+func TestCheckGenerated(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "bridge.d.ts")
+
+	if err := checkGenerated(path, []byte("v1")); err == nil || !strings.Contains(err.Error(), "does not exist") {
+		t.Errorf("Expected a missing file error, got %v", err)
+	}
+
+	writeFiles(t, dir, map[string]string{"bridge.d.ts": "v1"})
+
+	if err := checkGenerated(path, []byte("v1")); err != nil {
+		t.Errorf("Expected an up to date file, got %v", err)
+	}
+
+	if err := checkGenerated(path, []byte("v2")); err == nil || !strings.Contains(err.Error(), "stale") {
+		t.Errorf("Expected a stale file error, got %v", err)
+	}
+}
@@ -210,6 +210,7 @@ func runGeneratePage(ctx *cli.Context) error {
 	ctx.Printf("Route: %s%s%s\n\n", util.ColorCyan, routePath, util.ColorReset)
 	ctx.Printf("Next steps:\n")
 	ctx.Printf("  1. Add route to your router: app.Router.Get(\"%s\", pages.%s)\n", routePath, util.ToPascalCase(pageName))
+	ctx.Printf("     or run %sforgeui routes generate%s to register the pages directory\n", util.ColorCyan, util.ColorReset)
 	ctx.Printf("  2. Run: %sforgeui dev%s\n\n", util.ColorCyan, util.ColorReset)
 
 	return nil
@@ -0,0 +1,95 @@
+package commands
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/xraph/forgeui/cli"
+	"github.com/xraph/forgeui/cli/templates"
+	"github.com/xraph/forgeui/cli/util"
+)
+
+//nolint:gochecknoinits // init used for command registration
+func init() {
+	cli.RegisterCommand(RoutesCommand())
+}
+
+// RoutesCommand returns the routes command with subcommands
+func RoutesCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "routes",
+		Short: "Manage file-system routes",
+		Long:  `Generate route registration code from the pages directory.`,
+		Usage: "forgeui routes <command> [flags]",
+		Subcommands: []*cli.Command{
+			RoutesGenerateCommand(),
+		},
+	}
+}
+
+// RoutesGenerateCommand returns the routes generation subcommand
+func RoutesGenerateCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "generate",
+		Short: "Generate route registration from the pages directory",
+		Long: `Scan the pages directory and generate a Go file that registers every page,
+layout and loader with the router.
+
+Conventions:
+  index.templ            the directory's own route (IndexPage)
+  about.templ            /about (AboutPage)
+  layout.templ           layout for the directory and below (Layout)
+  param_id.templ         /{id} (ParamIdPage); also works for directories
+  catchall_path.templ    /*path (CatchallPathPage)
+  optional_page.templ    /{page?} (OptionalPagePage)
+  loader.go              <Name>Loader functions for pages, LayoutLoader for the layout`,
+		Usage:   "forgeui routes generate [flags]",
+		Aliases: []string{"gen"},
+		Flags: []cli.Flag{
+			cli.StringFlag("dir", "d", "Pages directory", "pages"),
+			cli.StringFlag("out", "o", "Output file (default <dir>/routes_gen.go)", ""),
+		},
+		Run: runRoutesGenerate,
+	}
+}
+
+func runRoutesGenerate(ctx *cli.Context) error {
+	projectRoot, err := util.GetProjectRoot()
+	if err != nil {
+		return fmt.Errorf("not in a Go project: %w", err)
+	}
+
+	pagesDir := filepath.Join(projectRoot, ctx.GetString("dir"))
+
+	outFile := ctx.GetString("out")
+	if outFile == "" {
+		outFile = filepath.Join(pagesDir, "routes_gen.go")
+	} else if !filepath.IsAbs(outFile) {
+		outFile = filepath.Join(projectRoot, outFile)
+	}
+
+	tree, err := templates.ScanPages(pagesDir)
+	if err != nil {
+		return err
+	}
+
+	src, err := tree.Generate(outFile)
+	if err != nil {
+		return err
+	}
+
+	if err := util.CreateFile(outFile, string(src)); err != nil {
+		return err
+	}
+
+	ctx.Printf("\n%s✓ Generated %d routes and %d layouts%s\n\n", util.ColorGreen, len(tree.Pages), len(tree.Layouts), util.ColorReset)
+	ctx.Printf("Location: %s\n", outFile)
+
+	for _, page := range tree.Pages {
+		ctx.Printf("  %s%-30s%s %s\n", util.ColorCyan, page.Pattern, util.ColorReset, page.File)
+	}
+
+	ctx.Printf("\nCall pages.RegisterRoutes(app.Router()) to register them.\n\n")
+
+	return nil
+}
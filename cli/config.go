@@ -33,6 +33,7 @@ type BuildConfig struct {
 	Minify      bool   `json:"minify"`
 	Binary      bool   `json:"binary"`
 	EmbedAssets bool   `json:"embed_assets"`
+	Static      bool   `json:"static"`
 }
 
 // AssetsConfig holds asset configuration
@@ -0,0 +1,739 @@
+package templates
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/a-h/templ"
+
+	"github.com/xraph/forgeui/cli/util"
+	"github.com/xraph/forgeui/router"
+)
+
+// File-system routing conventions.
+//
+// templ derives its output names from .templ files, and Go refuses to build
+// files starting with a bracket ([id]_templ.go), ignores files starting with
+// an underscore (_layout_templ.go) and rejects brackets in import paths, so
+// the conventions use Go-safe prefixes instead of [id] or _layout. Scanning
+// fails with the name to use for such files and directories:
+//
+//	pages/index.templ                  -> /            (IndexPage)
+//	pages/about.templ                  -> /about       (AboutPage)
+//	pages/layout.templ                 -> layout for pages/ and below (Layout)
+//	pages/users/param_id.templ         -> /users/{id}  (ParamIdPage)
+//	pages/users/param_id/edit.templ    -> /users/{id}/edit
+//	pages/docs/catchall_path.templ     -> /docs/*path  (CatchallPathPage)
+//	pages/blog/optional_page.templ     -> /blog/{page?}
+//	pages/users/loader.go              -> loaders for pages/users
+//
+// A page file declares a `templ <Name>Page(...)` component, where <Name> is
+// its file name in PascalCase, as generated by `forgeui generate page`. A
+// `func <Name>(ctx *router.PageContext) (templ.Component, error)` handler in
+// the directory's Go files renders the page; without one, the component
+// must take the page context alone. loader.go declares `<Name>Loader`
+// functions with the router.LoaderFunc signature for pages, and
+// `LayoutLoader` for the directory's layout, a
+// `templ Layout(ctx *router.PageContext, content templ.Component)`.
+const (
+	paramPrefix    = "param_"
+	catchAllPrefix = "catchall_"
+	optionalPrefix = "optional_"
+	layoutFile     = "layout.templ"
+	loaderFile     = "loader.go"
+	indexName      = "index"
+)
+
+var (
+	templDeclRe   = regexp.MustCompile(`(?m)^templ\s+(\w+)\s*\(([^)]*)\)`)
+	packageDeclRe = regexp.MustCompile(`(?m)^package\s+(\w+)`)
+	moduleDeclRe  = regexp.MustCompile(`(?m)^module\s+"?([^"\s]+)"?`)
+)
+
+// PageTree is the result of scanning a pages directory.
+type PageTree struct {
+	// Dir is the scanned directory
+	Dir string
+
+	// Pages are the pages found, in path order
+	Pages []ScannedPage
+
+	// Layouts are the layouts found, parents first
+	Layouts []ScannedLayout
+
+	dirs       []*pageDir
+	modulePath string
+	moduleRoot string
+}
+
+// ScannedPage is a page found in the pages directory.
+type ScannedPage struct {
+	Name      string // route name, e.g. "users.param_id"
+	Pattern   string // route pattern, e.g. "/users/{id}"
+	File      string // path relative to the pages directory
+	Component string // templ component, e.g. "ParamIdPage"
+	Handler   string // page handler rendering Component, empty if none
+	Loader    string // loader function, empty if none
+	Layout    string // nearest layout name, empty if none
+}
+
+// ScannedLayout is a layout found in the pages directory.
+type ScannedLayout struct {
+	Name   string // layout name, e.g. "pages/users"
+	File   string // path relative to the pages directory
+	Parent string // parent layout name, empty for the root layout
+	Loader string // loader function, empty if none
+}
+
+// pageDir is a scanned directory, which is one Go package.
+type pageDir struct {
+	rel     string // relative to the pages directory, "." for the root
+	prefix  string // route prefix
+	pkgName string
+	layout  string // nearest layout name
+	pages   []int  // indexes into PageTree.Pages
+	own     int    // index into PageTree.Layouts of this directory's layout, or -1
+}
+
+// ScanPages scans dir for pages, layouts and loaders.
+func ScanPages(dir string) (*PageTree, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if !util.DirExists(dir) {
+		return nil, fmt.Errorf("pages directory not found: %s", dir)
+	}
+
+	root, modPath, err := findModule(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	tree := &PageTree{Dir: dir, moduleRoot: root, modulePath: modPath}
+	layoutsByDir := make(map[string]string)
+
+	// WalkDir visits entries in lexical order, which keeps the output stable
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !d.IsDir() {
+			return nil
+		}
+
+		name := d.Name()
+		if path != dir && (strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") || name == "testdata") {
+			return filepath.SkipDir
+		}
+
+		if safe := goSafeName(name); safe != name {
+			return fmt.Errorf("%s: Go rejects brackets in import paths; rename the directory to %s", path, safe)
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		return tree.scanDir(path, filepath.ToSlash(rel), layoutsByDir)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tree.validate(); err != nil {
+		return nil, err
+	}
+
+	return tree, nil
+}
+
+// scanDir adds the layout and pages of one directory.
+func (t *PageTree) scanDir(path, rel string, layoutsByDir map[string]string) error {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return err
+	}
+
+	pd := &pageDir{rel: rel, prefix: t.routePrefix(rel), own: -1}
+
+	// Inherit the nearest layout
+	if rel != "." {
+		pd.layout = layoutsByDir[filepath.ToSlash(filepath.Dir(rel))]
+	}
+
+	var templFiles []string
+
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".templ") {
+			continue
+		}
+
+		if base := strings.TrimSuffix(e.Name(), ".templ"); goSafeName(base) != base {
+			return fmt.Errorf("%s: Go can't build the code templ generates for this file; rename it to %s.templ",
+				filepath.Join(path, e.Name()), goSafeName(base))
+		}
+
+		templFiles = append(templFiles, e.Name())
+	}
+
+	if len(templFiles) == 0 {
+		layoutsByDir[rel] = pd.layout
+		return nil
+	}
+
+	pd.pkgName, err = packageName(path, templFiles)
+	if err != nil {
+		return err
+	}
+
+	loaders, err := scanLoaders(filepath.Join(path, loaderFile))
+	if err != nil {
+		return err
+	}
+
+	handlers, err := scanHandlers(path)
+	if err != nil {
+		return err
+	}
+
+	if slices.Contains(templFiles, layoutFile) {
+		if err := requireComponent(path, layoutFile, "Layout", 2); err != nil {
+			return err
+		}
+
+		layout := ScannedLayout{
+			Name:   filepath.ToSlash(filepath.Join(filepath.Base(t.Dir), rel)),
+			File:   filepath.ToSlash(filepath.Join(rel, layoutFile)),
+			Parent: pd.layout,
+		}
+		if loaders["LayoutLoader"] {
+			layout.Loader = "LayoutLoader"
+		}
+
+		pd.own = len(t.Layouts)
+		pd.layout = layout.Name
+		t.Layouts = append(t.Layouts, layout)
+	}
+
+	layoutsByDir[rel] = pd.layout
+
+	for _, file := range templFiles {
+		if file == layoutFile {
+			continue
+		}
+
+		base := strings.TrimSuffix(file, ".templ")
+		name := util.ToPascalCase(base)
+
+		page := ScannedPage{
+			Name:      routeName(rel, base),
+			Pattern:   pd.prefix + pageSegment(base),
+			File:      filepath.ToSlash(filepath.Join(rel, file)),
+			Component: name + "Page",
+			Layout:    pd.layout,
+		}
+
+		// A handler can pass the component whatever it takes; otherwise
+		// ComponentHandler passes it the page context alone
+		arity := 1
+		if handlers[name] {
+			page.Handler = name
+			arity = -1
+		}
+
+		if err := requireComponent(path, file, page.Component, arity); err != nil {
+			return err
+		}
+
+		if page.Pattern == "" {
+			page.Pattern = "/"
+		}
+
+		if loader := util.ToPascalCase(base) + "Loader"; loaders[loader] {
+			page.Loader = loader
+		}
+
+		pd.pages = append(pd.pages, len(t.Pages))
+		t.Pages = append(t.Pages, page)
+	}
+
+	t.dirs = append(t.dirs, pd)
+
+	return nil
+}
+
+// validate registers every page on a scratch router, so conflicting or
+// ambiguous routes fail at generation time rather than at startup.
+func (t *PageTree) validate() (err error) {
+	var current ScannedPage
+
+	defer func() {
+		if rv := recover(); rv != nil {
+			err = fmt.Errorf("%s: %v", current.File, rv)
+		}
+	}()
+
+	r := router.New()
+	noop := router.ComponentHandler(func(*router.PageContext) templ.Component { return templ.NopComponent })
+
+	for _, page := range t.Pages {
+		current = page
+		r.Get(page.Pattern, noop)
+	}
+
+	return nil
+}
+
+// routePrefix converts a directory path to a route prefix.
+func (t *PageTree) routePrefix(rel string) string {
+	if rel == "." {
+		return ""
+	}
+
+	var b strings.Builder
+
+	for elem := range strings.SplitSeq(rel, "/") {
+		b.WriteString("/")
+		b.WriteString(segmentFor(elem))
+	}
+
+	return b.String()
+}
+
+// pageSegment returns the last route segment for a page file name.
+func pageSegment(base string) string {
+	if base == indexName {
+		return ""
+	}
+
+	return "/" + segmentFor(base)
+}
+
+// segmentFor converts a file or directory name to a route segment.
+func segmentFor(name string) string {
+	switch {
+	case strings.HasPrefix(name, paramPrefix):
+		return "{" + strings.TrimPrefix(name, paramPrefix) + "}"
+	case strings.HasPrefix(name, catchAllPrefix):
+		return "*" + strings.TrimPrefix(name, catchAllPrefix)
+	case strings.HasPrefix(name, optionalPrefix):
+		return "{" + strings.TrimPrefix(name, optionalPrefix) + "?}"
+	default:
+		return name
+	}
+}
+
+// goSafeName converts the bracket and underscore conventions of other
+// frameworks to the prefixes above: [id] to param_id, [...path] to
+// catchall_path, [[page]] to optional_page and _layout to layout.
+func goSafeName(name string) string {
+	switch {
+	case strings.HasPrefix(name, "[[") && strings.HasSuffix(name, "]]"):
+		return optionalPrefix + strings.TrimSuffix(strings.TrimPrefix(name, "[["), "]]")
+	case strings.HasPrefix(name, "[...") && strings.HasSuffix(name, "]"):
+		return catchAllPrefix + strings.TrimSuffix(strings.TrimPrefix(name, "[..."), "]")
+	case strings.HasPrefix(name, "[") && strings.HasSuffix(name, "]"):
+		return paramPrefix + strings.TrimSuffix(strings.TrimPrefix(name, "["), "]")
+	case name == "_layout":
+		return "layout"
+	default:
+		return name
+	}
+}
+
+// routeName returns the route name for a page, e.g. "users.param_id".
+func routeName(rel, base string) string {
+	var parts []string
+	if rel != "." {
+		parts = strings.Split(rel, "/")
+	}
+
+	if base != indexName || len(parts) == 0 {
+		parts = append(parts, base)
+	}
+
+	return strings.Join(parts, ".")
+}
+
+// requireComponent checks that a .templ file declares the given component,
+// taking arity parameters unless arity is negative.
+func requireComponent(dir, file, component string, arity int) error {
+	path := filepath.Join(dir, file)
+
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range templDeclRe.FindAllSubmatch(src, -1) {
+		if string(m[1]) != component {
+			continue
+		}
+
+		if n := countParams(string(m[2])); arity >= 0 && n != arity {
+			if arity == 1 {
+				return fmt.Errorf("%s: %s takes %d parameters; add a func %s(ctx *router.PageContext) (templ.Component, error) handler to render it",
+					path, component, n, strings.TrimSuffix(component, "Page"))
+			}
+
+			return fmt.Errorf("%s: %s must take %d parameters, got %d", path, component, arity, n)
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("%s: expected a templ component named %s", path, component)
+}
+
+// countParams counts the parameters in a parameter list.
+func countParams(params string) int {
+	if strings.TrimSpace(params) == "" {
+		return 0
+	}
+
+	n, depth := 1, 0
+
+	for _, r := range params {
+		switch r {
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				n++
+			}
+		}
+	}
+
+	return n
+}
+
+// scanHandlers returns the page handlers declared in a directory's Go
+// files: exported functions taking one parameter and returning two results,
+// as router.PageHandler does.
+func scanHandlers(dir string) (map[string]bool, error) {
+	handlers := make(map[string]bool)
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, path := range paths {
+		// templ output and tests aren't where handlers live
+		if strings.HasSuffix(path, "_templ.go") || strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+
+		file, err := parser.ParseFile(token.NewFileSet(), path, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv != nil || !fn.Name.IsExported() {
+				continue
+			}
+
+			if fn.Type.Params.NumFields() == 1 && fn.Type.Results.NumFields() == 2 {
+				handlers[fn.Name.Name] = true
+			}
+		}
+	}
+
+	return handlers, nil
+}
+
+// scanLoaders returns the loader functions declared in a loader.go file.
+func scanLoaders(path string) (map[string]bool, error) {
+	loaders := make(map[string]bool)
+
+	if !util.FileExists(path) {
+		return loaders, nil
+	}
+
+	file, err := parser.ParseFile(token.NewFileSet(), path, nil, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv != nil || !strings.HasSuffix(fn.Name.Name, "Loader") || !fn.Name.IsExported() {
+			continue
+		}
+
+		if fn.Type.Params.NumFields() != 2 || fn.Type.Results.NumFields() != 2 {
+			return nil, fmt.Errorf("%s: %s must have the signature func(context.Context, router.Params) (any, error)", path, fn.Name.Name)
+		}
+
+		loaders[fn.Name.Name] = true
+	}
+
+	return loaders, nil
+}
+
+// packageName returns the Go package name declared by a directory's files.
+func packageName(dir string, templFiles []string) (string, error) {
+	src, err := os.ReadFile(filepath.Join(dir, templFiles[0]))
+	if err != nil {
+		return "", err
+	}
+
+	if m := packageDeclRe.FindSubmatch(src); m != nil {
+		return string(m[1]), nil
+	}
+
+	return "", fmt.Errorf("%s: missing package declaration", filepath.Join(dir, templFiles[0]))
+}
+
+// findModule returns the root directory and path of the module containing dir.
+func findModule(dir string) (string, string, error) {
+	for current := dir; ; current = filepath.Dir(current) {
+		if src, err := os.ReadFile(filepath.Join(current, "go.mod")); err == nil {
+			m := moduleDeclRe.FindSubmatch(src)
+			if m == nil {
+				return "", "", fmt.Errorf("%s: missing module declaration", filepath.Join(current, "go.mod"))
+			}
+
+			return current, string(m[1]), nil
+		}
+
+		if filepath.Dir(current) == current {
+			return "", "", fmt.Errorf("no go.mod found above %s", dir)
+		}
+	}
+}
+
+// routesTemplate is the generated registration file.
+const routesTemplate = `// Code generated by "forgeui routes generate"; DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"github.com/xraph/forgeui/router"
+{{range .Imports}}
+	{{.Alias}} "{{.Path}}"
+{{- end}}
+)
+
+// RegisterRoutes registers the pages and layouts found in {{.Dir}}/.
+func RegisterRoutes(r *router.Router) {
+{{- range .Layouts}}
+	r.RegisterLayout({{printf "%q" .Name}}, {{.Func}}
+		{{- if .Parent}}, router.WithParentLayout({{printf "%q" .Parent}}){{end}}
+		{{- if .Loader}}, router.WithLayoutLoader({{.Loader}}){{end}})
+{{- end}}
+{{range .Groups}}
+	{{.Var}} := r.Group({{printf "%q" .Prefix}}{{if .Layout}}, router.GroupLayout({{printf "%q" .Layout}}){{end}})
+{{- $group := .}}
+{{- range .Pages}}
+	{{$group.Var}}.Page({{printf "%q" .Pattern}}).
+		Handler({{if .Handler}}{{.Handler}}{{else}}router.ComponentHandler({{.Component}}){{end}}).
+{{- if .Loader}}
+		Loader({{.Loader}}).
+{{- end}}
+		Name({{printf "%q" .Name}}).
+		Register()
+{{- end}}
+{{end -}}
+}
+`
+
+// Generate returns the Go source of a file at outFile that registers every
+// page and layout in the tree. The output only depends on the tree, so it
+// diffs cleanly between runs.
+func (t *PageTree) Generate(outFile string) ([]byte, error) {
+	outDir, err := filepath.Abs(filepath.Dir(outFile))
+	if err != nil {
+		return nil, err
+	}
+
+	outPkg := identifier(filepath.Base(outDir))
+	for _, pd := range t.dirs {
+		if filepath.Join(t.Dir, pd.rel) == outDir {
+			outPkg = pd.pkgName
+		}
+	}
+
+	type imp struct{ Alias, Path string }
+
+	type layout struct{ Name, Func, Parent, Loader string }
+
+	type group struct {
+		Var, Prefix, Layout string
+		Pages               []ScannedPage
+	}
+
+	var (
+		imports []imp
+		layouts []layout
+		groups  []group
+		used    = map[string]bool{"r": true, "router": true}
+	)
+
+	for _, pd := range t.dirs {
+		qualifier := ""
+
+		if filepath.Join(t.Dir, pd.rel) != outDir {
+			alias := uniqueIdent(importAlias(filepath.Base(t.Dir), pd.rel), used)
+			importPath, err := t.importPath(pd.rel)
+			if err != nil {
+				return nil, err
+			}
+
+			imports = append(imports, imp{Alias: alias, Path: importPath})
+			qualifier = alias + "."
+		}
+
+		if pd.own >= 0 {
+			l := t.Layouts[pd.own]
+			entry := layout{Name: l.Name, Func: qualifier + "Layout", Parent: l.Parent}
+
+			if l.Loader != "" {
+				entry.Loader = qualifier + l.Loader
+			}
+
+			layouts = append(layouts, entry)
+		}
+
+		if len(pd.pages) == 0 {
+			continue
+		}
+
+		g := group{
+			Var:    uniqueIdent(util.ToCamelCase(groupName(pd.rel))+"Group", used),
+			Prefix: pd.prefix,
+			Layout: pd.layout,
+		}
+
+		for _, i := range pd.pages {
+			page := t.Pages[i]
+			page.Pattern = strings.TrimPrefix(page.Pattern, pd.prefix)
+			page.Component = qualifier + page.Component
+
+			if page.Handler != "" {
+				page.Handler = qualifier + page.Handler
+			}
+
+			if page.Loader != "" {
+				page.Loader = qualifier + page.Loader
+			}
+
+			g.Pages = append(g.Pages, page)
+		}
+
+		groups = append(groups, g)
+	}
+
+	sort.Slice(imports, func(i, j int) bool { return imports[i].Path < imports[j].Path })
+
+	relDir, err := filepath.Rel(t.moduleRoot, t.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl, err := template.New("routes").Parse(routesTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+
+	err = tmpl.Execute(&buf, map[string]any{
+		"Package": outPkg,
+		"Dir":     filepath.ToSlash(relDir),
+		"Imports": imports,
+		"Layouts": layouts,
+		"Groups":  groups,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated routes: %w", err)
+	}
+
+	return src, nil
+}
+
+// importPath returns the import path of a directory in the tree.
+func (t *PageTree) importPath(rel string) (string, error) {
+	modRel, err := filepath.Rel(t.moduleRoot, filepath.Join(t.Dir, rel))
+	if err != nil {
+		return "", err
+	}
+
+	if modRel == "." {
+		return t.modulePath, nil
+	}
+
+	return t.modulePath + "/" + filepath.ToSlash(modRel), nil
+}
+
+// importAlias returns the import alias for a directory, e.g. "users_param_id".
+func importAlias(rootName, rel string) string {
+	if rel == "." {
+		return identifier(rootName)
+	}
+
+	return identifier(strings.ReplaceAll(rel, "/", "_"))
+}
+
+// groupName returns the base of a group variable name for a directory.
+func groupName(rel string) string {
+	if rel == "." {
+		return "root"
+	}
+
+	return identifier(strings.ReplaceAll(rel, "/", "_"))
+}
+
+// identifier replaces characters that can't appear in a Go identifier.
+func identifier(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+
+		return '_'
+	}, s)
+
+	if s == "" || (s[0] >= '0' && s[0] <= '9') {
+		s = "p" + s
+	}
+
+	return s
+}
+
+// uniqueIdent returns name, suffixed with a number if it is already used.
+func uniqueIdent(name string, used map[string]bool) string {
+	candidate := name
+	for i := 2; used[candidate]; i++ {
+		candidate = fmt.Sprintf("%s%d", name, i)
+	}
+
+	used[candidate] = true
+
+	return candidate
+}
@@ -0,0 +1,44 @@
+package csrf
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+
+	"github.com/a-h/templ"
+)
+
+// Field renders a hidden input carrying the CSRF token. Place it inside
+// every form that submits with an unsafe method. It renders nothing when the
+// request has no session.
+func Field() templ.Component {
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		token := Token(ctx)
+		if token == "" {
+			return nil
+		}
+
+		_, err := fmt.Fprintf(w, `<input type="hidden" name="%s" value="%s">`,
+			html.EscapeString(configFromContext(ctx).Field), token)
+
+		return err
+	})
+}
+
+// HXHeaders returns an hx-headers attribute that makes HTMX send the CSRF
+// token with every request from the element and its descendants. Spread it
+// on <body>:
+//
+//	<body { csrf.HXHeaders(ctx)... }>
+func HXHeaders(ctx context.Context) templ.Attributes {
+	token := Token(ctx)
+	if token == "" {
+		return templ.Attributes{}
+	}
+
+	headers, _ := json.Marshal(map[string]string{configFromContext(ctx).Header: token})
+
+	return templ.Attributes{"hx-headers": string(headers)}
+}
@@ -0,0 +1,299 @@
+// Package csrf protects ForgeUI pages, form actions and bridge endpoints
+// against cross-site request forgery.
+//
+// Protect validates every unsafe request (POST, PUT, PATCH, DELETE), as well
+// as bridge SSE streams and WebSocket upgrades, which browsers open with GET.
+// It checks two things:
+//   - the request's Origin and Sec-Fetch-Site headers, when present, must
+//     name this site or a trusted origin
+//   - the request must carry a token bound to the user's session, in the
+//     X-CSRF-Token header, the _csrf form field or, for streams and
+//     WebSockets, the _csrf query parameter
+//
+// Tokens are masked differently on every render, so they can't be recovered
+// from compressed responses (BREACH). Protect needs a session: install it
+// inside session.Middleware, or use forgeui.WithSessions and forgeui.WithCSRF.
+//
+// # Basic Usage
+//
+//	app := forgeui.New(
+//	    forgeui.WithSessions(store),
+//	    forgeui.WithCSRF(csrf.WithTrustedOrigins("https://admin.example.com")),
+//	)
+//
+//	// In templates
+//	<form method="post">
+//	    @csrf.Field()
+//	    ...
+//	</form>
+//
+//	<body { csrf.HXHeaders(ctx)... }>
+package csrf
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/xraph/forgeui/logging"
+	"github.com/xraph/forgeui/session"
+)
+
+// Validation errors, passed to the error handler.
+var (
+	ErrNoSession      = errors.New("csrf: no session in request context")
+	ErrBadOrigin      = errors.New("csrf: request origin is not trusted")
+	ErrTokenMissing   = errors.New("csrf: token missing")
+	ErrTokenInvalid   = errors.New("csrf: token invalid")
+	ErrCrossSiteFetch = errors.New("csrf: cross-site request")
+)
+
+// Config configures CSRF protection.
+type Config struct {
+	// Header is the request header carrying the token. Default: "X-CSRF-Token"
+	Header string
+
+	// Field is the form field and query parameter carrying the token.
+	// Default: "_csrf"
+	Field string
+
+	// TrustedOrigins are other origins allowed to send unsafe requests,
+	// such as "https://admin.example.com". The request's own host is
+	// always trusted.
+	TrustedOrigins []string
+
+	// ExemptPaths are path prefixes that skip validation, such as webhooks
+	// authenticated by other means
+	ExemptPaths []string
+
+	// ProtectedPaths are path prefixes validated for every method, such as
+	// endpoints that run code on GET requests
+	ProtectedPaths []string
+
+	// ErrorHandler writes the response for rejected requests.
+	// Default: 403 Forbidden
+	ErrorHandler func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+// Option configures CSRF protection.
+type Option func(*Config)
+
+// DefaultConfig returns the default CSRF configuration.
+func DefaultConfig() Config {
+	return Config{
+		Header:       "X-CSRF-Token",
+		Field:        "_csrf",
+		ErrorHandler: defaultErrorHandler,
+	}
+}
+
+// WithHeader sets the request header carrying the token.
+func WithHeader(header string) Option {
+	return func(c *Config) { c.Header = header }
+}
+
+// WithField sets the form field and query parameter carrying the token.
+func WithField(field string) Option {
+	return func(c *Config) { c.Field = field }
+}
+
+// WithTrustedOrigins adds origins allowed to send unsafe requests.
+func WithTrustedOrigins(origins ...string) Option {
+	return func(c *Config) { c.TrustedOrigins = append(c.TrustedOrigins, origins...) }
+}
+
+// WithExemptPaths skips validation for requests under the given path prefixes.
+func WithExemptPaths(prefixes ...string) Option {
+	return func(c *Config) { c.ExemptPaths = append(c.ExemptPaths, prefixes...) }
+}
+
+// WithProtectedPaths validates every request under the given path prefixes,
+// including GET requests.
+func WithProtectedPaths(prefixes ...string) Option {
+	return func(c *Config) { c.ProtectedPaths = append(c.ProtectedPaths, prefixes...) }
+}
+
+// WithErrorHandler sets the handler for rejected requests.
+func WithErrorHandler(fn func(w http.ResponseWriter, r *http.Request, err error)) Option {
+	return func(c *Config) { c.ErrorHandler = fn }
+}
+
+func defaultErrorHandler(w http.ResponseWriter, r *http.Request, err error) {
+	logging.FromContext(r.Context()).Warn("csrf validation failed",
+		logging.KeyMethod, r.Method,
+		logging.KeyPath, r.URL.Path,
+		logging.KeyError, err.Error(),
+	)
+	http.Error(w, "CSRF validation failed", http.StatusForbidden)
+}
+
+type configKey struct{}
+
+type verifiedKey struct{}
+
+// Protect returns HTTP middleware that validates CSRF tokens and makes the
+// token available to templates (see Token, Field and HXHeaders).
+// It must run inside session middleware.
+func Protect(opts ...Option) func(http.Handler) http.Handler {
+	config := DefaultConfig()
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), configKey{}, &config)
+
+			s := session.FromContext(ctx)
+			if s != nil {
+				// Create the secret now, while the session can still be saved
+				ensureSecret(s)
+			}
+
+			if config.needsValidation(r) && !config.exempt(r.URL.Path) {
+				if err := config.validate(r, s); err != nil {
+					config.ErrorHandler(w, r, err)
+					return
+				}
+
+				ctx = context.WithValue(ctx, verifiedKey{}, true)
+			}
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// Verified reports whether Protect validated the request's token. Bridge
+// handlers use it to skip their own CSRF check.
+func Verified(ctx context.Context) bool {
+	v, _ := ctx.Value(verifiedKey{}).(bool)
+	return v
+}
+
+// configFromContext returns the configuration Protect stored in ctx.
+func configFromContext(ctx context.Context) *Config {
+	if c, ok := ctx.Value(configKey{}).(*Config); ok {
+		return c
+	}
+
+	c := DefaultConfig()
+
+	return &c
+}
+
+// needsValidation reports whether the request can change state: unsafe
+// methods, plus streams, WebSocket upgrades and protected paths, which run
+// code on GET.
+func (c *Config) needsValidation(r *http.Request) bool {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return isWebSocket(r) || isEventStream(r) || hasPrefix(r.URL.Path, c.ProtectedPaths)
+	}
+
+	return true
+}
+
+func isWebSocket(r *http.Request) bool {
+	return strings.EqualFold(r.Header.Get("Upgrade"), "websocket")
+}
+
+func isEventStream(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), "text/event-stream")
+}
+
+// exempt reports whether path is under one of the exempt prefixes.
+func (c *Config) exempt(path string) bool {
+	return hasPrefix(path, c.ExemptPaths)
+}
+
+// hasPrefix reports whether path starts with one of prefixes.
+func hasPrefix(path string, prefixes []string) bool {
+	return slices.ContainsFunc(prefixes, func(prefix string) bool {
+		return strings.HasPrefix(path, prefix)
+	})
+}
+
+// validate checks the request's origin and token.
+func (c *Config) validate(r *http.Request, s *session.Session) error {
+	if err := c.checkOrigin(r); err != nil {
+		return err
+	}
+
+	if s == nil {
+		return ErrNoSession
+	}
+
+	token := c.requestToken(r)
+	if token == "" {
+		return ErrTokenMissing
+	}
+
+	if !validToken(s, token) {
+		return ErrTokenInvalid
+	}
+
+	return nil
+}
+
+// checkOrigin rejects requests whose Origin is another site, and cross-site
+// fetches from browsers that send Sec-Fetch-Site but no usable Origin.
+func (c *Config) checkOrigin(r *http.Request) error {
+	if origin := r.Header.Get("Origin"); origin != "" && origin != "null" {
+		if !c.trusted(r, origin) {
+			return ErrBadOrigin
+		}
+
+		return nil
+	}
+
+	if r.Header.Get("Sec-Fetch-Site") == "cross-site" {
+		return ErrCrossSiteFetch
+	}
+
+	return nil
+}
+
+// trusted reports whether origin is the request's own host or a trusted origin.
+func (c *Config) trusted(r *http.Request, origin string) bool {
+	u, err := url.Parse(origin)
+	if err != nil || u.Host == "" {
+		return false
+	}
+
+	if strings.EqualFold(u.Host, r.Host) {
+		return true
+	}
+
+	return slices.ContainsFunc(c.TrustedOrigins, func(trusted string) bool {
+		return strings.EqualFold(strings.TrimSuffix(trusted, "/"), origin)
+	})
+}
+
+// requestToken returns the token from the header, the form body or, for
+// streams, WebSockets and protected paths, the query string.
+func (c *Config) requestToken(r *http.Request) string {
+	if token := r.Header.Get(c.Header); token != "" {
+		return token
+	}
+
+	contentType := r.Header.Get("Content-Type")
+	if strings.HasPrefix(contentType, "application/x-www-form-urlencoded") ||
+		strings.HasPrefix(contentType, "multipart/form-data") {
+		// Parsing keeps the values in r.Form for the handler
+		_ = r.ParseMultipartForm(32 << 20)
+
+		if token := r.PostFormValue(c.Field); token != "" {
+			return token
+		}
+	}
+
+	if isWebSocket(r) || isEventStream(r) || hasPrefix(r.URL.Path, c.ProtectedPaths) {
+		return r.URL.Query().Get(c.Field)
+	}
+
+	return ""
+}
@@ -0,0 +1,300 @@
+package csrf
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xraph/forgeui/session"
+)
+
+// testApp serves GET /form, which renders a token, and accepts anything
+// else once Protect lets it through.
+type testApp struct {
+	handler http.Handler
+	cookie  *http.Cookie
+	token   string
+}
+
+func newTestApp(t *testing.T, opts ...Option) *testApp {
+	t.Helper()
+
+	app := &testApp{}
+
+	inner := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/form" {
+			_, _ = w.Write([]byte(Token(r.Context())))
+			return
+		}
+
+		_, _ = w.Write([]byte("ok:" + r.FormValue("name")))
+	})
+
+	app.handler = session.Middleware(session.NewMemoryStore(time.Minute))(Protect(opts...)(inner))
+
+	w := httptest.NewRecorder()
+	app.handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/form", nil))
+
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 {
+		t.Fatalf("Expected a session cookie for the token secret, got %d cookies", len(cookies))
+	}
+
+	app.cookie = cookies[0]
+	app.token = w.Body.String()
+
+	return app
+}
+
+func (a *testApp) do(req *http.Request) *httptest.ResponseRecorder {
+	req.AddCookie(a.cookie)
+
+	w := httptest.NewRecorder()
+	a.handler.ServeHTTP(w, req)
+
+	return w
+}
+
+func TestProtect(t *testing.T) {
+	app := newTestApp(t)
+
+	form := func(token string) *http.Request {
+		values := url.Values{"name": {"jane"}}
+		if token != "" {
+			values.Set("_csrf", token)
+		}
+
+		req := httptest.NewRequest(http.MethodPost, "/submit", strings.NewReader(values.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		return req
+	}
+
+	header := func(method, token string) *http.Request {
+		req := httptest.NewRequest(method, "/submit", bytes.NewReader([]byte(`{}`)))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-CSRF-Token", token)
+
+		return req
+	}
+
+	stream := func(token string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/stream?_csrf="+url.QueryEscape(token), nil)
+		req.Header.Set("Accept", "text/event-stream")
+
+		return req
+	}
+
+	websocket := func(token string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/ws?_csrf="+url.QueryEscape(token), nil)
+		req.Header.Set("Upgrade", "websocket")
+
+		return req
+	}
+
+	withHeader := func(req *http.Request, key, value string) *http.Request {
+		req.Header.Set(key, value)
+		return req
+	}
+
+	tests := []struct {
+		name   string
+		req    *http.Request
+		status int
+	}{
+		{"GET is safe", httptest.NewRequest(http.MethodGet, "/page", nil), http.StatusOK},
+		{"form field", form(app.token), http.StatusOK},
+		{"form without token", form(""), http.StatusForbidden},
+		{"form with garbage token", form("garbage"), http.StatusForbidden},
+		{"header on POST", header(http.MethodPost, app.token), http.StatusOK},
+		{"header on DELETE", header(http.MethodDelete, app.token), http.StatusOK},
+		{"missing header on PUT", header(http.MethodPut, ""), http.StatusForbidden},
+		{"SSE stream with token", stream(app.token), http.StatusOK},
+		{"SSE stream without token", stream(""), http.StatusForbidden},
+		{"WebSocket upgrade with token", websocket(app.token), http.StatusOK},
+		{"WebSocket upgrade without token", websocket(""), http.StatusForbidden},
+		{"same origin", withHeader(header(http.MethodPost, app.token), "Origin", "http://example.com"), http.StatusOK},
+		{"foreign origin", withHeader(header(http.MethodPost, app.token), "Origin", "https://evil.test"), http.StatusForbidden},
+		{"cross-site fetch", withHeader(header(http.MethodPost, app.token), "Sec-Fetch-Site", "cross-site"), http.StatusForbidden},
+		{"same-origin fetch", withHeader(header(http.MethodPost, app.token), "Sec-Fetch-Site", "same-origin"), http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := app.do(tt.req)
+
+			if w.Code != tt.status {
+				t.Errorf("Expected status %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+		})
+	}
+
+	// The handler still sees the parsed form
+	if w := app.do(form(app.token)); w.Body.String() != "ok:jane" {
+		t.Errorf("Expected form values to reach the handler, got %q", w.Body.String())
+	}
+}
+
+func TestProtect_TokenFromAnotherSession(t *testing.T) {
+	app := newTestApp(t)
+	other := newTestApp(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/submit", nil)
+	req.Header.Set("X-CSRF-Token", other.token)
+
+	if w := app.do(req); w.Code != http.StatusForbidden {
+		t.Errorf("Expected a token from another session to be rejected, got %d", w.Code)
+	}
+}
+
+func TestProtect_TrustedOrigins(t *testing.T) {
+	app := newTestApp(t, WithTrustedOrigins("https://admin.example.com"))
+
+	req := httptest.NewRequest(http.MethodPost, "/submit", nil)
+	req.Header.Set("X-CSRF-Token", app.token)
+	req.Header.Set("Origin", "https://admin.example.com")
+
+	if w := app.do(req); w.Code != http.StatusOK {
+		t.Errorf("Expected trusted origin to pass, got %d", w.Code)
+	}
+}
+
+func TestProtect_ExemptPaths(t *testing.T) {
+	app := newTestApp(t, WithExemptPaths("/webhooks/"))
+
+	if w := app.do(httptest.NewRequest(http.MethodPost, "/webhooks/stripe", nil)); w.Code != http.StatusOK {
+		t.Errorf("Expected exempt path to pass, got %d", w.Code)
+	}
+}
+
+func TestProtect_ProtectedPaths(t *testing.T) {
+	app := newTestApp(t, WithProtectedPaths("/stream/"))
+
+	if w := app.do(httptest.NewRequest(http.MethodGet, "/stream/logs", nil)); w.Code != http.StatusForbidden {
+		t.Errorf("Expected GET to a protected path without token to be rejected, got %d", w.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/stream/logs?_csrf="+url.QueryEscape(app.token), nil)
+	if w := app.do(req); w.Code != http.StatusOK {
+		t.Errorf("Expected GET to a protected path with token to pass, got %d", w.Code)
+	}
+}
+
+func TestProtect_WithoutSession(t *testing.T) {
+	var got error
+
+	handler := Protect(WithErrorHandler(func(w http.ResponseWriter, r *http.Request, err error) {
+		got = err
+		w.WriteHeader(http.StatusTeapot)
+	}))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", nil))
+
+	if w.Code != http.StatusTeapot || got != ErrNoSession {
+		t.Errorf("Expected ErrNoSession through the custom handler, got %d %v", w.Code, got)
+	}
+}
+
+func TestTokensAreMasked(t *testing.T) {
+	s := session.New()
+	ctx := session.NewContext(context.Background(), s)
+
+	first, second := Token(ctx), Token(ctx)
+
+	if first == second {
+		t.Error("Expected a differently masked token on every call")
+	}
+
+	if !validToken(s, first) || !validToken(s, second) {
+		t.Error("Expected both tokens to be valid")
+	}
+}
+
+func TestRotate(t *testing.T) {
+	s := session.New()
+	ctx := session.NewContext(context.Background(), s)
+
+	before := Token(ctx)
+
+	Rotate(ctx)
+	after := Token(ctx)
+
+	if !validToken(s, before) || !validToken(s, after) {
+		t.Error("Expected tokens from before the last rotation to stay valid")
+	}
+
+	Rotate(ctx)
+
+	if validToken(s, before) {
+		t.Error("Expected tokens from two rotations ago to be rejected")
+	}
+}
+
+func TestVerified(t *testing.T) {
+	s := session.New()
+	ctx := session.NewContext(context.Background(), s)
+	token := Token(ctx)
+
+	var verified bool
+
+	handler := Protect()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		verified = Verified(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil).WithContext(ctx)
+	req.Header.Set("X-CSRF-Token", token)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if !verified {
+		t.Error("Expected validated requests to be marked verified")
+	}
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx))
+
+	if verified {
+		t.Error("Expected safe requests not to be marked verified")
+	}
+}
+
+func TestField(t *testing.T) {
+	var buf bytes.Buffer
+
+	if err := Field().Render(context.Background(), &buf); err != nil || buf.Len() != 0 {
+		t.Errorf("Expected nothing without a session, got %q", buf.String())
+	}
+
+	s := session.New()
+	ctx := session.NewContext(context.Background(), s)
+
+	buf.Reset()
+	_ = Field().Render(ctx, &buf)
+
+	html := buf.String()
+	if !strings.HasPrefix(html, `<input type="hidden" name="_csrf" value="`) {
+		t.Fatalf("Unexpected field: %q", html)
+	}
+
+	token := strings.TrimSuffix(strings.TrimPrefix(html, `<input type="hidden" name="_csrf" value="`), `">`)
+	if !validToken(s, token) {
+		t.Error("Expected the field to carry a valid token")
+	}
+}
+
+func TestHXHeaders(t *testing.T) {
+	if attrs := HXHeaders(context.Background()); len(attrs) != 0 {
+		t.Errorf("Expected no attributes without a session, got %v", attrs)
+	}
+
+	ctx := session.NewContext(context.Background(), session.New())
+
+	headers, _ := HXHeaders(ctx)["hx-headers"].(string)
+	if !strings.HasPrefix(headers, `{"X-CSRF-Token":"`) {
+		t.Errorf("Unexpected hx-headers: %q", headers)
+	}
+}
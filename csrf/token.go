@@ -0,0 +1,105 @@
+package csrf
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+
+	"github.com/xraph/forgeui/session"
+)
+
+// Session keys holding the current and previous token secrets.
+const (
+	secretKey   = "_csrf"
+	previousKey = "_csrf_previous"
+)
+
+const secretSize = 32
+
+// Token returns a CSRF token for the request's session, masked differently
+// on every call. It returns "" when ctx has no session.
+func Token(ctx context.Context) string {
+	s := session.FromContext(ctx)
+	if s == nil {
+		return ""
+	}
+
+	return mask(ensureSecret(s))
+}
+
+// Rotate replaces the session's token secret. Call it when privileges change,
+// such as on login, together with Session.Renew. Tokens issued before the
+// rotation stay valid until the next one, so forms open in other tabs still
+// submit.
+func Rotate(ctx context.Context) {
+	s := session.FromContext(ctx)
+	if s == nil {
+		return
+	}
+
+	if current := s.GetString(secretKey); current != "" {
+		s.Set(previousKey, current)
+	}
+
+	s.Set(secretKey, newSecret())
+}
+
+// ensureSecret returns the session's token secret, creating it if needed.
+func ensureSecret(s *session.Session) []byte {
+	if secret, ok := decodeSecret(s.GetString(secretKey)); ok {
+		return secret
+	}
+
+	encoded := newSecret()
+	s.Set(secretKey, encoded)
+
+	secret, _ := decodeSecret(encoded)
+
+	return secret
+}
+
+// validToken reports whether token unmasks to the session's current or
+// previous secret.
+func validToken(s *session.Session, token string) bool {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(raw) != 2*secretSize {
+		return false
+	}
+
+	secret := make([]byte, secretSize)
+	subtle.XORBytes(secret, raw[:secretSize], raw[secretSize:])
+
+	for _, key := range []string{secretKey, previousKey} {
+		if expected, ok := decodeSecret(s.GetString(key)); ok && subtle.ConstantTimeCompare(secret, expected) == 1 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// mask returns base64(pad || pad XOR secret) with a random pad.
+func mask(secret []byte) string {
+	raw := make([]byte, 2*secretSize)
+	_, _ = rand.Read(raw[:secretSize])
+	subtle.XORBytes(raw[secretSize:], raw[:secretSize], secret)
+
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func newSecret() string {
+	b := make([]byte, secretSize)
+	_, _ = rand.Read(b)
+
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeSecret(encoded string) ([]byte, bool) {
+	secret, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil || len(secret) != secretSize {
+		return nil, false
+	}
+
+	return secret, true
+}
@@ -47,7 +47,7 @@ func main() {
 	// SSE endpoint for hot reload
 	if app.IsDev() {
 		if handler := app.Assets.SSEHandler(); handler != nil {
-			http.Handle("/_forgeui/reload", handler.(http.Handler))
+			http.Handle(app.HotReloadPath(), handler.(http.Handler))
 		}
 	}
 
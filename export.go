@@ -0,0 +1,361 @@
+package forgeui
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/xraph/forgeui/assets"
+	"github.com/xraph/forgeui/router"
+)
+
+// ExportEnv is the environment variable that makes ExportFromEnv export the
+// app into the directory it names. `forgeui build --static` sets it.
+const ExportEnv = "FORGEUI_EXPORT"
+
+// ExportReport describes the files written by Export.
+type ExportReport struct {
+	// Pages are the URL paths rendered to HTML files
+	Pages []string
+
+	// Files are other URL paths fetched through the app, such as scripts
+	// and stylesheets served by handlers
+	Files []string
+
+	// Assets maps asset paths to their fingerprinted names
+	Assets assets.Manifest
+
+	// Skipped are the patterns of parameterized GET routes without
+	// StaticParams, which were not rendered
+	Skipped []string
+}
+
+// BrokenLink is an internal link that didn't resolve during export.
+type BrokenLink struct {
+	From   string // URL path of the page containing the link
+	To     string // URL path the link points to
+	Status int
+}
+
+// ExportError is returned by Export when pages link to URLs that don't
+// resolve in the exported site.
+type ExportError struct {
+	Links []BrokenLink
+}
+
+func (e *ExportError) Error() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "export found %d broken internal link(s)", len(e.Links))
+
+	for _, l := range e.Links {
+		fmt.Fprintf(&b, "\n  %s -> %s (%d)", l.From, l.To, l.Status)
+	}
+
+	return b.String()
+}
+
+// Export renders the app to static files in outDir, for hosting without a
+// Go server. Call Initialize first.
+//
+// Every GET route is rendered: static routes once, parameterized routes once
+// per parameter set from their StaticParams enumerator. Pages are written as
+// path/index.html. Assets are copied with their fingerprinted names and the
+// asset manifest. Internal links (href and src attributes) found in rendered
+// pages are crawled, so pages and files reachable only by link are exported
+// too. Links that don't resolve make Export fail with an *ExportError, after
+// everything else has been written.
+func (a *App) Export(ctx context.Context, outDir string) (*ExportReport, error) {
+	if err := os.MkdirAll(outDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create export directory: %w", err)
+	}
+
+	manifest, err := a.Assets.Export(filepath.Join(outDir, filepath.FromSlash(strings.Trim(a.Paths().Static(), "/"))))
+	if err != nil {
+		return nil, err
+	}
+
+	e := &exporter{
+		handler: a.Handler(),
+		outDir:  outDir,
+		seen:    make(map[string]bool),
+		report:  &ExportReport{Assets: manifest},
+	}
+
+	for _, route := range a.router.Routes() {
+		if route.Method != http.MethodGet {
+			continue
+		}
+
+		urls, err := route.StaticURLs(ctx)
+		if errors.Is(err, router.ErrNoStaticParams) {
+			e.report.Skipped = append(e.report.Skipped, route.Pattern)
+			continue
+		}
+
+		if err != nil {
+			return e.report, err
+		}
+
+		for _, u := range urls {
+			e.enqueue("", u)
+		}
+	}
+
+	if err := e.crawl(ctx); err != nil {
+		return e.report, err
+	}
+
+	if len(e.broken) > 0 {
+		return e.report, &ExportError{Links: e.broken}
+	}
+
+	return e.report, nil
+}
+
+// ExportFromEnv exports the app when the FORGEUI_EXPORT environment variable
+// names an output directory, and reports whether it did. It initializes
+// the app first, unless it is ready already. App.Run calls it; apps that
+// run their own server call it from main after registering routes, so
+// `forgeui build --static` can export the app:
+//
+//	if ok, err := app.ExportFromEnv(ctx); ok {
+//	    if err != nil {
+//	        log.Fatal(err)
+//	    }
+//	    return
+//	}
+func (a *App) ExportFromEnv(ctx context.Context) (bool, error) {
+	outDir := os.Getenv(ExportEnv)
+	if outDir == "" {
+		return false, nil
+	}
+
+	hookStarted()
+
+	if !a.Ready() {
+		if err := a.Initialize(ctx); err != nil {
+			return true, err
+		}
+	}
+
+	_, err := a.Export(ctx, outDir)
+
+	return true, err
+}
+
+// exportLink is a URL path waiting to be exported, with the page linking to
+// it ("" for route URLs).
+type exportLink struct {
+	from string
+	path string
+}
+
+// exporter crawls the app through its handler and writes the responses.
+type exporter struct {
+	handler http.Handler
+	outDir  string
+	queue   []exportLink
+	seen    map[string]bool
+	broken  []BrokenLink
+	report  *ExportReport
+}
+
+func (e *exporter) enqueue(from, p string) {
+	if e.seen[p] {
+		return
+	}
+
+	e.seen[p] = true
+	e.queue = append(e.queue, exportLink{from: from, path: p})
+}
+
+func (e *exporter) crawl(ctx context.Context) error {
+	for len(e.queue) > 0 {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		link := e.queue[0]
+		e.queue = e.queue[1:]
+
+		// Links to copied assets resolve without a request
+		if link.from != "" && e.exists(link.path) {
+			continue
+		}
+
+		if err := e.fetch(ctx, link); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// fetch requests a URL path from the app and writes the response.
+func (e *exporter) fetch(ctx context.Context, link exportLink) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, link.path, nil)
+	if err != nil {
+		return err
+	}
+
+	req.RequestURI = link.path
+
+	w := &exportResponse{header: make(http.Header)}
+	e.handler.ServeHTTP(w, req)
+	w.WriteHeader(http.StatusOK) // no-op unless the handler wrote nothing
+
+	switch {
+	case w.status >= 300 && w.status < 400 && w.header.Get("Location") != "":
+		target, ok := resolveLink(link.path, w.header.Get("Location"))
+		if !ok {
+			// Redirects off-site are written as-is
+			target = w.header.Get("Location")
+		} else {
+			e.enqueue(link.path, target)
+		}
+
+		page := fmt.Sprintf(`<!DOCTYPE html><meta http-equiv="refresh" content="0; url=%[1]s"><link rel="canonical" href="%[1]s">`,
+			html.EscapeString(target))
+
+		return e.write(link.path, []byte(page), true)
+
+	case w.status != http.StatusOK:
+		if link.from == "" {
+			return fmt.Errorf("export %s: status %d", link.path, w.status)
+		}
+
+		e.broken = append(e.broken, BrokenLink{From: link.from, To: link.path, Status: w.status})
+
+		return nil
+	}
+
+	body := w.body.Bytes()
+
+	contentType := w.header.Get("Content-Type")
+	if contentType == "" {
+		contentType = http.DetectContentType(body)
+	}
+
+	isHTML := strings.HasPrefix(contentType, "text/html")
+
+	if err := e.write(link.path, body, isHTML); err != nil {
+		return err
+	}
+
+	if !isHTML {
+		e.report.Files = append(e.report.Files, link.path)
+		return nil
+	}
+
+	e.report.Pages = append(e.report.Pages, link.path)
+
+	for _, ref := range extractLinks(body) {
+		if target, ok := resolveLink(link.path, ref); ok {
+			e.enqueue(link.path, target)
+		}
+	}
+
+	return nil
+}
+
+func (e *exporter) write(urlPath string, body []byte, isHTML bool) error {
+	file := filepath.Join(e.outDir, exportFile(urlPath, isHTML))
+
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(file, body, 0644)
+}
+
+// exists reports whether a URL path is already written as a file.
+func (e *exporter) exists(urlPath string) bool {
+	info, err := os.Stat(filepath.Join(e.outDir, exportFile(urlPath, false)))
+	return err == nil && info.Mode().IsRegular()
+}
+
+// exportFile maps a URL path to a file path relative to the output directory.
+// HTML pages without an extension become directory indexes.
+func exportFile(urlPath string, isHTML bool) string {
+	p := path.Clean("/" + urlPath)
+	if isHTML && path.Ext(p) == "" {
+		p = path.Join(p, "index.html")
+	}
+
+	return filepath.FromSlash(strings.TrimPrefix(p, "/"))
+}
+
+var linkAttrRegex = regexp.MustCompile(`(?i)\s(?:href|src)\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+
+// extractLinks returns the href and src attribute values in an HTML document.
+func extractLinks(body []byte) []string {
+	matches := linkAttrRegex.FindAllSubmatch(body, -1)
+	links := make([]string, 0, len(matches))
+
+	for _, m := range matches {
+		value := m[1]
+		if value == nil {
+			value = m[2]
+		}
+
+		links = append(links, html.UnescapeString(string(value)))
+	}
+
+	return links
+}
+
+// resolveLink resolves ref against the page at base and returns its path,
+// or false for links that leave the site or point within the same page.
+func resolveLink(base, ref string) (string, bool) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" || strings.HasPrefix(ref, "#") {
+		return "", false
+	}
+
+	u, err := url.Parse(ref)
+	if err != nil || u.Scheme != "" || u.Host != "" {
+		return "", false
+	}
+
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return "", false
+	}
+
+	return path.Clean(baseURL.ResolveReference(u).Path), true
+}
+
+// exportResponse records a response served during export.
+type exportResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (w *exportResponse) Header() http.Header {
+	return w.header
+}
+
+func (w *exportResponse) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+func (w *exportResponse) Write(b []byte) (int, error) {
+	w.WriteHeader(http.StatusOK)
+	return w.body.Write(b)
+}
+
+// Flush is a no-op; responses are written once the handler returns.
+func (w *exportResponse) Flush() {}
@@ -0,0 +1,199 @@
+package forgeui
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/a-h/templ"
+
+	"github.com/xraph/forgeui/router"
+)
+
+func htmlPage(body string) router.PageHandler {
+	return func(ctx *router.PageContext) (templ.Component, error) {
+		return templ.ComponentFunc(func(_ context.Context, w io.Writer) error {
+			_, err := io.WriteString(w, body)
+			return err
+		}), nil
+	}
+}
+
+func newExportApp() *App {
+	app := New(WithAssetFileSystem(fstest.MapFS{
+		"app.css": {Data: []byte("body{}")},
+	}))
+
+	css := app.Assets.URL("app.css")
+
+	app.Get("/", htmlPage(`<html><head><link rel="stylesheet" href="`+css+`"></head><body>`+
+		`<a href="/about">About</a> <a href='blog/hello?ref=home#top'>Post</a> <a href="#main">Skip</a>`+
+		`<a href="https://example.com/">Out</a> <a href="mailto:a@example.com">Mail</a> <a href="/old">Old</a>`+
+		`</body></html>`))
+	app.Get("/about", htmlPage(`<html><body><a href="/hidden">Only linked</a></body></html>`))
+	app.Get("/hidden", htmlPage(`<html><body>Hidden</body></html>`))
+	app.Get("/old", func(ctx *router.PageContext) (templ.Component, error) {
+		http.Redirect(ctx.ResponseWriter, ctx.Request, "/about", http.StatusMovedPermanently)
+		return nil, nil
+	})
+
+	app.Page("/blog/:slug").
+		Handler(func(ctx *router.PageContext) (templ.Component, error) {
+			return htmlPage(`<html><body>` + ctx.Param("slug") + `</body></html>`)(ctx)
+		}).
+		StaticParams(func(context.Context) ([]router.Params, error) {
+			return []router.Params{{"slug": "hello"}, {"slug": "world"}}, nil
+		}).
+		Register()
+
+	app.Get("/users/:id", htmlPage("user"))
+	app.Post("/contact", htmlPage("sent"))
+
+	return app
+}
+
+func TestApp_Export(t *testing.T) {
+	app := newExportApp()
+	dir := t.TempDir()
+
+	report, err := app.Export(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	tests := []struct {
+		file string
+		want string
+	}{
+		{"index.html", `href="/about"`},
+		{"about/index.html", "/hidden"},
+		{"hidden/index.html", "Hidden"},
+		{"blog/hello/index.html", "hello"},
+		{"blog/world/index.html", "world"},
+		{"old/index.html", `url=/about`},
+		{"static/app.css", "body{}"},
+		{"static/" + report.Assets["app.css"], "body{}"},
+		{"static/manifest.json", `"app.css"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(tt.file)))
+			if err != nil {
+				t.Fatalf("Expected %s to be exported: %v", tt.file, err)
+			}
+
+			if !strings.Contains(string(data), tt.want) {
+				t.Errorf("Expected %s to contain %q, got %q", tt.file, tt.want, data)
+			}
+		})
+	}
+
+	if !slices.Equal(report.Skipped, []string{"/users/:id"}) {
+		t.Errorf("Expected /users/:id to be skipped, got %v", report.Skipped)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "contact")); !os.IsNotExist(err) {
+		t.Error("Expected POST routes not to be exported")
+	}
+}
+
+func TestApp_ExportBrokenLinks(t *testing.T) {
+	app := New(WithAssetFileSystem(fstest.MapFS{}))
+	app.Get("/", htmlPage(`<a href="/missing">Missing</a><img src="/static/gone.png">`))
+
+	dir := t.TempDir()
+
+	_, err := app.Export(context.Background(), dir)
+
+	var exportErr *ExportError
+	if !errors.As(err, &exportErr) {
+		t.Fatalf("Expected an ExportError, got %v", err)
+	}
+
+	if len(exportErr.Links) != 2 {
+		t.Fatalf("Expected 2 broken links, got %v", exportErr.Links)
+	}
+
+	if link := exportErr.Links[0]; link.From != "/" || link.To != "/missing" || link.Status != http.StatusNotFound {
+		t.Errorf("Unexpected broken link: %+v", link)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "index.html")); err != nil {
+		t.Error("Expected pages to be written before failing")
+	}
+}
+
+func TestApp_ExportEnumeratorError(t *testing.T) {
+	app := New(WithAssetFileSystem(fstest.MapFS{}))
+	app.Get("/posts/:id", htmlPage("post")).StaticParams(func(context.Context) ([]router.Params, error) {
+		return nil, errors.New("database down")
+	})
+
+	if _, err := app.Export(context.Background(), t.TempDir()); err == nil || !strings.Contains(err.Error(), "database down") {
+		t.Errorf("Expected the enumerator error, got %v", err)
+	}
+}
+
+func TestApp_ExportFromEnv(t *testing.T) {
+	app := New(WithAssetFileSystem(fstest.MapFS{}))
+	app.Get("/", htmlPage("home"))
+
+	t.Setenv(ExportEnv, "")
+
+	if ok, _ := app.ExportFromEnv(context.Background()); ok {
+		t.Error("Expected no export without the environment variable")
+	}
+
+	dir := t.TempDir()
+	started := filepath.Join(t.TempDir(), "started")
+	t.Setenv(ExportEnv, dir)
+	t.Setenv(HookStartedEnv, started)
+
+	if ok, err := app.ExportFromEnv(context.Background()); !ok || err != nil {
+		t.Fatalf("Expected export, got %v %v", ok, err)
+	}
+
+	if _, err := os.Stat(started); err != nil {
+		t.Error("Expected ExportFromEnv to signal the CLI it started")
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "index.html")); err != nil {
+		t.Error("Expected index.html to be exported")
+	}
+	if !app.Ready() {
+		t.Error("Expected ExportFromEnv to initialize the app")
+	}
+}
+
+func TestResolveLink(t *testing.T) {
+	tests := []struct {
+		base string
+		ref  string
+		want string
+		ok   bool
+	}{
+		{"/", "/about", "/about", true},
+		{"/blog/post", "other", "/blog/other", true},
+		{"/blog/post", "../about?x=1#top", "/about", true},
+		{"/", "#top", "", false},
+		{"/", "https://example.com", "", false},
+		{"/", "//cdn.example.com/x.js", "", false},
+		{"/", "mailto:a@example.com", "", false},
+		{"/", "javascript:void(0)", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := resolveLink(tt.base, tt.ref)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("resolveLink(%q, %q) = %q, %v, want %q, %v", tt.base, tt.ref, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,219 @@
+// Package i18n provides message catalogs, pluralization and locale
+// detection for ForgeUI applications.
+//
+// A Bundle holds one catalog per locale, loaded from JSON or TOML files
+// (for example embedded with embed.FS). Nested tables become dotted keys,
+// and tables of CLDR plural categories become plural messages:
+//
+//	// locales/de.json
+//	{
+//	    "nav": {"home": "Startseite"},
+//	    "cart": {
+//	        "items": {"one": "{count} Artikel", "other": "{count} Artikel"}
+//	    },
+//	    "greeting": "Hallo, {name}!"
+//	}
+//
+// Middleware picks the locale for each request from the URL prefix
+// (/de/...), a cookie or the Accept-Language header, and stores a Localizer
+// in the request context for T:
+//
+//	bundle := i18n.NewBundle("en")
+//	if err := bundle.LoadFS(localesFS, "locales"); err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	app := forgeui.New(forgeui.WithI18n(bundle))
+//
+//	// In templates
+//	<h1>{ i18n.T(ctx, "greeting", i18n.Args{"name": user.Name}) }</h1>
+//	<p>{ i18n.T(ctx, "cart.items", i18n.Args{"count": len(items)}) }</p>
+package i18n
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"golang.org/x/text/language"
+)
+
+// Bundle holds the message catalogs of every supported locale.
+type Bundle struct {
+	mu            sync.RWMutex
+	defaultLocale language.Tag
+	catalogs      map[language.Tag]map[string]*message
+	tags          []language.Tag
+	matcher       language.Matcher
+}
+
+// NewBundle creates an empty bundle. Messages missing from a locale's
+// catalog fall back to defaultLocale. It panics if defaultLocale is not a
+// valid BCP 47 tag.
+func NewBundle(defaultLocale string) *Bundle {
+	tag := language.MustParse(defaultLocale)
+
+	b := &Bundle{
+		defaultLocale: tag,
+		catalogs:      make(map[language.Tag]map[string]*message),
+	}
+	b.addLocale(tag)
+
+	return b
+}
+
+// DefaultLocale returns the fallback locale.
+func (b *Bundle) DefaultLocale() string {
+	return b.defaultLocale.String()
+}
+
+// Locales returns the supported locales, the default locale first and the
+// others sorted.
+func (b *Bundle) Locales() []string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	locales := make([]string, len(b.tags))
+	for i, tag := range b.tags {
+		locales[i] = tag.String()
+	}
+
+	return locales
+}
+
+// AddMessages adds messages to a locale's catalog, replacing existing keys.
+// Values are strings, nested tables (map[string]any) or plural tables.
+func (b *Bundle) AddMessages(locale string, messages map[string]any) error {
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return fmt.Errorf("i18n: invalid locale %q: %w", locale, err)
+	}
+
+	flat := make(map[string]*message)
+	if err := flatten("", messages, flat); err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	catalog := b.addLocale(tag)
+	for k, m := range flat {
+		catalog[k] = m
+	}
+
+	return nil
+}
+
+// addLocale returns the catalog for tag, creating it if needed.
+// The caller must hold the lock, except in NewBundle.
+func (b *Bundle) addLocale(tag language.Tag) map[string]*message {
+	if catalog, ok := b.catalogs[tag]; ok {
+		return catalog
+	}
+
+	catalog := make(map[string]*message)
+	b.catalogs[tag] = catalog
+	b.tags = append(b.tags, tag)
+
+	// Keep the default locale first and sort the rest, so matching doesn't
+	// depend on the order locales were added in
+	slices.SortFunc(b.tags[1:], func(x, y language.Tag) int {
+		return strings.Compare(x.String(), y.String())
+	})
+
+	b.matcher = language.NewMatcher(b.tags)
+
+	return catalog
+}
+
+// Match returns the supported locale that best matches the given language
+// preferences, such as Accept-Language values or locale names, or the
+// default locale when none match.
+func (b *Bundle) Match(preferences ...string) string {
+	var tags []language.Tag
+
+	for _, pref := range preferences {
+		parsed, _, err := language.ParseAcceptLanguage(pref)
+		if err == nil {
+			tags = append(tags, parsed...)
+		}
+	}
+
+	if len(tags) == 0 {
+		return b.DefaultLocale()
+	}
+
+	b.mu.RLock()
+	matcher, supported := b.matcher, b.tags
+	b.mu.RUnlock()
+
+	_, index, confidence := matcher.Match(tags...)
+	if confidence == language.No {
+		return b.DefaultLocale()
+	}
+
+	return supported[index].String()
+}
+
+// Supported returns the supported locale named locale, compared without
+// regard to case, and whether there is one.
+func (b *Bundle) Supported(locale string) (string, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	i := slices.IndexFunc(b.tags, func(tag language.Tag) bool {
+		return strings.EqualFold(tag.String(), locale)
+	})
+	if i < 0 {
+		return "", false
+	}
+
+	return b.tags[i].String(), true
+}
+
+// Localizer returns a Localizer for locale. Messages are looked up in the
+// locale, then its parent locales (de-AT, then de), then the default locale.
+// An unsupported or invalid locale uses the default locale.
+func (b *Bundle) Localizer(locale string) *Localizer {
+	tag, err := language.Parse(locale)
+	if err != nil {
+		tag = b.defaultLocale
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	var chain []language.Tag
+
+	for t := tag; ; t = t.Parent() {
+		if _, ok := b.catalogs[t]; ok && !slices.Contains(chain, t) {
+			chain = append(chain, t)
+		}
+
+		if t == language.Und {
+			break
+		}
+	}
+
+	if !slices.Contains(chain, b.defaultLocale) {
+		chain = append(chain, b.defaultLocale)
+	}
+
+	return &Localizer{bundle: b, tag: chain[0], chain: chain}
+}
+
+// lookup returns the message for key in the first catalog of chain that has it.
+func (b *Bundle) lookup(chain []language.Tag, key string) (*message, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, tag := range chain {
+		if m, ok := b.catalogs[tag][key]; ok {
+			return m, true
+		}
+	}
+
+	return nil, false
+}
@@ -0,0 +1,197 @@
+package i18n
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func newTestBundle(t *testing.T) *Bundle {
+	t.Helper()
+
+	b := NewBundle("en")
+
+	catalogs := []struct {
+		locale   string
+		messages map[string]any
+	}{
+		{"en", map[string]any{
+			"greeting": "Hello, {name}!",
+			"nav":      map[string]any{"home": "Home", "about": "About"},
+			"cart": map[string]any{
+				"items": map[string]any{"zero": "Your cart is empty", "one": "{count} item", "other": "{count} items"},
+			},
+		}},
+		{"de", map[string]any{
+			"greeting": "Hallo, {name}!",
+			"nav":      map[string]any{"home": "Startseite"},
+			"cart": map[string]any{
+				"items": map[string]any{"one": "{count} Artikel", "other": "{count} Artikel"},
+			},
+		}},
+		{"de-AT", map[string]any{
+			"nav": map[string]any{"home": "Startseite (AT)"},
+		}},
+		{"pl", map[string]any{
+			"files": map[string]any{"one": "{count} plik", "few": "{count} pliki", "many": "{count} plików", "other": "{count} pliku"},
+		}},
+	}
+
+	for _, c := range catalogs {
+		if err := b.AddMessages(c.locale, c.messages); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return b
+}
+
+func TestLocalizer_T(t *testing.T) {
+	b := newTestBundle(t)
+
+	tests := []struct {
+		name   string
+		locale string
+		key    string
+		args   Args
+		want   string
+	}{
+		{"simple", "en", "nav.home", nil, "Home"},
+		{"interpolation", "de", "greeting", Args{"name": "Jane"}, "Hallo, Jane!"},
+		{"unknown placeholder kept", "en", "greeting", Args{"other": 1}, "Hello, {name}!"},
+		{"fallback to default locale", "de", "nav.about", nil, "About"},
+		{"fallback to parent locale", "de-AT", "greeting", Args{"name": "Jane"}, "Hallo, Jane!"},
+		{"regional override", "de-AT", "nav.home", nil, "Startseite (AT)"},
+		{"unsupported locale", "fr", "nav.home", nil, "Home"},
+		{"missing key", "en", "nav.missing", nil, "nav.missing"},
+		{"plural one", "en", "cart.items", Args{"count": 1}, "1 item"},
+		{"plural other", "en", "cart.items", Args{"count": 5}, "5 items"},
+		{"plural zero", "en", "cart.items", Args{"count": 0}, "Your cart is empty"},
+		{"plural fraction", "en", "cart.items", Args{"count": 1.5}, "1.5 items"},
+		{"plural without count", "en", "cart.items", nil, "{count} items"},
+		{"plural zero falls back to CLDR form", "de", "cart.items", Args{"count": 0}, "0 Artikel"},
+		{"polish one", "pl", "files", Args{"count": 1}, "1 plik"},
+		{"polish few", "pl", "files", Args{"count": 3}, "3 pliki"},
+		{"polish many", "pl", "files", Args{"count": 5}, "5 plików"},
+		{"polish few again", "pl", "files", Args{"count": 22}, "22 pliki"},
+		{"polish fraction", "pl", "files", Args{"count": 1.5}, "1.5 pliku"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := b.Localizer(tt.locale).T(tt.key, tt.args); got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestBundle_Localizer(t *testing.T) {
+	b := newTestBundle(t)
+
+	tests := []struct {
+		locale string
+		want   string
+	}{
+		{"de", "de"},
+		{"de-AT", "de-AT"},
+		{"de-CH", "de"},
+		{"fr", "en"},
+		{"not a locale!", "en"},
+	}
+
+	for _, tt := range tests {
+		if got := b.Localizer(tt.locale).Locale(); got != tt.want {
+			t.Errorf("Localizer(%q).Locale() = %q, want %q", tt.locale, got, tt.want)
+		}
+	}
+}
+
+func TestBundle_Match(t *testing.T) {
+	b := newTestBundle(t)
+
+	tests := []struct {
+		accept string
+		want   string
+	}{
+		{"de-DE,de;q=0.9,en;q=0.8", "de"},
+		{"fr-FR,pl;q=0.5", "pl"},
+		{"ja", "en"},
+		{"", "en"},
+		{"garbage;;q=x", "en"},
+	}
+
+	for _, tt := range tests {
+		if got := b.Match(tt.accept); got != tt.want {
+			t.Errorf("Match(%q) = %q, want %q", tt.accept, got, tt.want)
+		}
+	}
+}
+
+func TestBundle_MatchOrder(t *testing.T) {
+	for _, order := range [][]string{{"de", "de-AT"}, {"de-AT", "de"}} {
+		b := NewBundle("en")
+		for _, locale := range order {
+			_ = b.AddMessages(locale, map[string]any{"k": locale})
+		}
+
+		if got := b.Match("de-CH"); got != "de" {
+			t.Errorf("Expected de for locales added as %v, got %s", order, got)
+		}
+
+		if got := b.Locales(); !slices.Equal(got, []string{"en", "de", "de-AT"}) {
+			t.Errorf("Expected sorted locales, got %v", got)
+		}
+	}
+}
+
+func TestBundle_Locales(t *testing.T) {
+	b := newTestBundle(t)
+
+	locales := b.Locales()
+	if locales[0] != "en" || len(locales) != 4 || !slices.Contains(locales, "de-AT") {
+		t.Errorf("Unexpected locales: %v", locales)
+	}
+
+	if got, ok := b.Supported("DE-at"); !ok || got != "de-AT" {
+		t.Errorf("Expected case-insensitive lookup, got %q %v", got, ok)
+	}
+}
+
+func TestBundle_AddMessagesErrors(t *testing.T) {
+	b := NewBundle("en")
+
+	if err := b.AddMessages("???", map[string]any{"a": "b"}); err == nil {
+		t.Error("Expected error for an invalid locale")
+	}
+
+	if err := b.AddMessages("en", map[string]any{"a": 1}); err == nil {
+		t.Error("Expected error for a non-string message")
+	}
+}
+
+func TestContextHelpers(t *testing.T) {
+	b := newTestBundle(t)
+
+	if T(context.Background(), "nav.home") != "nav.home" || Locale(context.Background()) != "" {
+		t.Error("Expected keys to pass through without a localizer")
+	}
+
+	ctx := NewContext(context.Background(), b.Localizer("de"))
+
+	if got := T(ctx, "nav.home"); got != "Startseite" {
+		t.Errorf("Expected translation, got %q", got)
+	}
+
+	if Locale(ctx) != "de" {
+		t.Errorf("Expected locale de, got %q", Locale(ctx))
+	}
+
+	if got := Translate(ctx, "Plain title"); got != "Plain title" {
+		t.Errorf("Expected text without a message to pass through, got %q", got)
+	}
+
+	if got := Translate(ctx, "nav.home"); got != "Startseite" {
+		t.Errorf("Expected Translate to translate keys, got %q", got)
+	}
+}
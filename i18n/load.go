@@ -0,0 +1,258 @@
+package i18n
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// LoadFS loads every catalog file in dir of fsys. Files are named after
+// their locale: de.json, pt-BR.toml. Other files are ignored.
+func (b *Bundle) LoadFS(fsys fs.FS, dir string) error {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return fmt.Errorf("i18n: %w", err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		switch path.Ext(entry.Name()) {
+		case ".json", ".toml":
+			if err := b.LoadFile(fsys, path.Join(dir, entry.Name())); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// LoadFile loads one JSON or TOML catalog file named after its locale.
+func (b *Bundle) LoadFile(fsys fs.FS, name string) error {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return fmt.Errorf("i18n: %w", err)
+	}
+
+	ext := path.Ext(name)
+	locale := strings.TrimSuffix(path.Base(name), ext)
+
+	var messages map[string]any
+
+	switch ext {
+	case ".json":
+		err = json.Unmarshal(data, &messages)
+	case ".toml":
+		messages, err = parseTOML(data)
+	default:
+		return fmt.Errorf("i18n: %s: unsupported catalog format", name)
+	}
+
+	if err != nil {
+		return fmt.Errorf("i18n: %s: %w", name, err)
+	}
+
+	if err := b.AddMessages(locale, messages); err != nil {
+		return fmt.Errorf("i18n: %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// parseTOML parses the subset of TOML used by catalogs: comments, [table]
+// headers, and key = "value" pairs with basic or literal strings. Keys may
+// be dotted or quoted.
+func parseTOML(data []byte) (map[string]any, error) {
+	root := make(map[string]any)
+	table := root
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+			continue
+
+		case strings.HasPrefix(line, "["):
+			end := strings.LastIndexByte(line, ']')
+			if end < 0 || strings.HasPrefix(line, "[[") {
+				return nil, fmt.Errorf("line %d: invalid table header", n)
+			}
+
+			keys, err := parseTOMLKey(line[1:end])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", n, err)
+			}
+
+			if table, err = tomlTable(root, keys); err != nil {
+				return nil, fmt.Errorf("line %d: %w", n, err)
+			}
+
+		default:
+			key, rest, ok := cutTOMLKey(line)
+			if !ok {
+				return nil, fmt.Errorf("line %d: expected key = value", n)
+			}
+
+			keys, err := parseTOMLKey(key)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", n, err)
+			}
+
+			value, err := parseTOMLString(strings.TrimSpace(rest))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", n, err)
+			}
+
+			parent, err := tomlTable(table, keys[:len(keys)-1])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", n, err)
+			}
+
+			parent[keys[len(keys)-1]] = value
+		}
+	}
+
+	return root, scanner.Err()
+}
+
+// cutTOMLKey splits a key/value line at the first "=" outside quotes.
+func cutTOMLKey(line string) (key, value string, ok bool) {
+	var quote byte
+
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '=':
+			return strings.TrimSpace(line[:i]), line[i+1:], true
+		}
+	}
+
+	return "", "", false
+}
+
+// parseTOMLKey splits a possibly dotted, possibly quoted key.
+func parseTOMLKey(s string) ([]string, error) {
+	var keys []string
+
+	for s = strings.TrimSpace(s); s != ""; {
+		var key string
+
+		if s[0] == '"' || s[0] == '\'' {
+			end := strings.IndexByte(s[1:], s[0])
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated quoted key")
+			}
+
+			key, s = s[1:end+1], s[end+2:]
+		} else {
+			end := strings.IndexByte(s, '.')
+			if end < 0 {
+				end = len(s)
+			}
+
+			key, s = strings.TrimSpace(s[:end]), s[end:]
+		}
+
+		if key == "" {
+			return nil, fmt.Errorf("empty key")
+		}
+
+		keys = append(keys, key)
+
+		s = strings.TrimSpace(s)
+		if s != "" {
+			if s[0] != '.' {
+				return nil, fmt.Errorf("invalid key")
+			}
+
+			s = strings.TrimSpace(s[1:])
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("empty key")
+	}
+
+	return keys, nil
+}
+
+// parseTOMLString parses a basic ("...") or literal ('...') string,
+// ignoring a trailing comment.
+func parseTOMLString(s string) (string, error) {
+	if s == "" {
+		return "", fmt.Errorf("missing value")
+	}
+
+	switch s[0] {
+	case '\'':
+		end := strings.IndexByte(s[1:], '\'')
+		if end < 0 {
+			return "", fmt.Errorf("unterminated string")
+		}
+
+		return s[1 : end+1], trailingComment(s[end+2:])
+
+	case '"':
+		for i := 1; i < len(s); i++ {
+			switch s[i] {
+			case '\\':
+				i++
+			case '"':
+				value, err := strconv.Unquote(s[:i+1])
+				if err != nil {
+					return "", fmt.Errorf("invalid string: %w", err)
+				}
+
+				return value, trailingComment(s[i+1:])
+			}
+		}
+
+		return "", fmt.Errorf("unterminated string")
+	}
+
+	return "", fmt.Errorf("only string values are supported")
+}
+
+func trailingComment(rest string) error {
+	if rest = strings.TrimSpace(rest); rest != "" && !strings.HasPrefix(rest, "#") {
+		return fmt.Errorf("unexpected %q after value", rest)
+	}
+
+	return nil
+}
+
+// tomlTable returns the nested table at keys, creating tables as needed.
+func tomlTable(root map[string]any, keys []string) (map[string]any, error) {
+	table := root
+
+	for _, key := range keys {
+		switch v := table[key].(type) {
+		case nil:
+			next := make(map[string]any)
+			table[key] = next
+			table = next
+		case map[string]any:
+			table = v
+		default:
+			return nil, fmt.Errorf("key %q is already a value", key)
+		}
+	}
+
+	return table, nil
+}
@@ -0,0 +1,91 @@
+package i18n
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestBundle_LoadFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"locales/en.json": {Data: []byte(`{"nav": {"home": "Home"}, "items": {"one": "{count} item", "other": "{count} items"}}`)},
+		"locales/de.toml": {Data: []byte(`# German
+title = "Willkommen" # trailing comment
+"quoted.key" = 'wörtlich \n'
+nav.home = "Start\tseite"
+
+[items]
+one = "{count} Eintrag"
+other = "{count} Einträge"
+
+[errors.validation]
+required = "{field} ist erforderlich"
+`)},
+		"locales/README.md": {Data: []byte("ignored")},
+	}
+
+	b := NewBundle("en")
+	if err := b.LoadFS(fsys, "locales"); err != nil {
+		t.Fatalf("LoadFS() error = %v", err)
+	}
+
+	de := b.Localizer("de")
+
+	tests := []struct {
+		key  string
+		args Args
+		want string
+	}{
+		{"title", nil, "Willkommen"},
+		{"quoted.key", nil, `wörtlich \n`},
+		{"nav.home", nil, "Start\tseite"},
+		{"items", Args{"count": 1}, "1 Eintrag"},
+		{"items", Args{"count": 2}, "2 Einträge"},
+		{"errors.validation.required", Args{"field": "Name"}, "Name ist erforderlich"},
+	}
+
+	for _, tt := range tests {
+		if got := de.T(tt.key, tt.args); got != tt.want {
+			t.Errorf("T(%q) = %q, want %q", tt.key, got, tt.want)
+		}
+	}
+
+	if got := b.Localizer("en").T("items", Args{"count": 3}); got != "3 items" {
+		t.Errorf("Expected JSON catalog, got %q", got)
+	}
+}
+
+func TestParseTOML_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"missing equals", `title "x"`},
+		{"number value", `count = 3`},
+		{"unterminated string", `title = "x`},
+		{"junk after value", `title = "x" y`},
+		{"array of tables", `[[items]]`},
+		{"value redefined as table", "a = \"x\"\n[a]"},
+		{"empty key", `= "x"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseTOML([]byte(tt.input)); err == nil {
+				t.Error("Expected a parse error")
+			}
+		})
+	}
+}
+
+func TestBundle_LoadFSErrors(t *testing.T) {
+	b := NewBundle("en")
+
+	if err := b.LoadFS(fstest.MapFS{}, "missing"); err == nil {
+		t.Error("Expected error for a missing directory")
+	}
+
+	bad := fstest.MapFS{"locales/en.json": {Data: []byte(`{`)}}
+	if err := b.LoadFS(bad, "locales"); err == nil {
+		t.Error("Expected error for invalid JSON")
+	}
+}
@@ -0,0 +1,104 @@
+package i18n
+
+import (
+	"context"
+
+	"golang.org/x/text/language"
+)
+
+// Localizer translates messages for one locale.
+type Localizer struct {
+	bundle *Bundle
+	tag    language.Tag
+	chain  []language.Tag
+}
+
+// Locale returns the locale messages are translated to, such as "de".
+func (l *Localizer) Locale() string {
+	return l.tag.String()
+}
+
+// Tag returns the locale as a language tag.
+func (l *Localizer) Tag() language.Tag {
+	return l.tag
+}
+
+// T translates the message key, selecting the plural form for
+// args["count"] and filling in {name} placeholders. It returns the key
+// itself when no catalog has the message.
+func (l *Localizer) T(key string, args ...Args) string {
+	m, ok := l.bundle.lookup(l.chain, key)
+	if !ok {
+		return key
+	}
+
+	return m.format(l.tag, merge(args))
+}
+
+// Has reports whether the message key exists for this locale or a fallback.
+func (l *Localizer) Has(key string) bool {
+	_, ok := l.bundle.lookup(l.chain, key)
+	return ok
+}
+
+func merge(args []Args) Args {
+	switch len(args) {
+	case 0:
+		return nil
+	case 1:
+		return args[0]
+	}
+
+	merged := make(Args)
+
+	for _, a := range args {
+		for k, v := range a {
+			merged[k] = v
+		}
+	}
+
+	return merged
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying the localizer.
+func NewContext(ctx context.Context, l *Localizer) context.Context {
+	return context.WithValue(ctx, contextKey{}, l)
+}
+
+// FromContext returns the localizer stored in ctx, or nil.
+func FromContext(ctx context.Context) *Localizer {
+	l, _ := ctx.Value(contextKey{}).(*Localizer)
+	return l
+}
+
+// Locale returns the request's locale, or "" when ctx has no localizer.
+func Locale(ctx context.Context) string {
+	if l := FromContext(ctx); l != nil {
+		return l.Locale()
+	}
+
+	return ""
+}
+
+// T translates the message key in the request's locale (see Localizer.T).
+// It returns the key itself when ctx has no localizer.
+func T(ctx context.Context, key string, args ...Args) string {
+	if l := FromContext(ctx); l != nil {
+		return l.T(key, args...)
+	}
+
+	return key
+}
+
+// Translate returns the translation of text when it is a message key, and
+// text unchanged otherwise. Use it for strings that may or may not be keys,
+// such as page titles.
+func Translate(ctx context.Context, text string, args ...Args) string {
+	if l := FromContext(ctx); l != nil && l.Has(text) {
+		return l.T(text, args...)
+	}
+
+	return text
+}
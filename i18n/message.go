@@ -0,0 +1,216 @@
+package i18n
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+)
+
+// Args are the values interpolated into a message's {name} placeholders.
+// The "count" argument also selects the plural form.
+type Args map[string]any
+
+// CountArg is the argument that selects a message's plural form.
+const CountArg = "count"
+
+// pluralForms maps the CLDR plural category names used in catalogs to forms.
+var pluralForms = map[string]plural.Form{
+	"zero":  plural.Zero,
+	"one":   plural.One,
+	"two":   plural.Two,
+	"few":   plural.Few,
+	"many":  plural.Many,
+	"other": plural.Other,
+}
+
+// message is a translated message, with a text per plural form when it has
+// plural variants.
+type message struct {
+	other string
+	forms map[plural.Form]string
+}
+
+// format selects the plural form for args[CountArg] and fills in placeholders.
+func (m *message) format(tag language.Tag, args Args) string {
+	text := m.other
+
+	if len(m.forms) > 0 {
+		if count, ok := args[CountArg]; ok {
+			text = m.plural(tag, count)
+		}
+	}
+
+	return interpolate(text, args)
+}
+
+// plural returns the text for count, falling back to the "other" form.
+// An explicit "zero" form is used for 0 in every language.
+func (m *message) plural(tag language.Tag, count any) string {
+	n, ok := toFloat(count)
+	if !ok {
+		return m.other
+	}
+
+	if text, ok := m.forms[plural.Zero]; ok && n == 0 {
+		return text
+	}
+
+	if text, ok := m.forms[pluralForm(tag, n)]; ok {
+		return text
+	}
+
+	return m.other
+}
+
+// pluralForm returns the CLDR cardinal plural form of n in the given language.
+func pluralForm(tag language.Tag, n float64) plural.Form {
+	n = math.Abs(n)
+
+	// CLDR operands: i integer digits, v visible fraction digit count,
+	// w the same without trailing zeros, f and t the fraction digits
+	digits := strconv.FormatFloat(n, 'f', -1, 64)
+
+	intPart, frac, _ := strings.Cut(digits, ".")
+
+	i, err := strconv.Atoi(intPart)
+	if err != nil {
+		// Too large to matter for plural rules
+		i = math.MaxInt32
+	}
+
+	f, _ := strconv.Atoi("0" + frac)
+	trimmed := strings.TrimRight(frac, "0")
+	t, _ := strconv.Atoi("0" + trimmed)
+
+	return plural.Cardinal.MatchPlural(tag, i, len(frac), len(trimmed), f, t)
+}
+
+// toFloat converts a numeric argument to float64.
+func toFloat(v any) (float64, bool) {
+	rv := reflect.ValueOf(v)
+
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	case reflect.String:
+		f, err := strconv.ParseFloat(rv.String(), 64)
+		return f, err == nil
+	}
+
+	return 0, false
+}
+
+// interpolate replaces {name} placeholders with args. Placeholders without
+// a matching argument are left as they are.
+func interpolate(text string, args Args) string {
+	if len(args) == 0 || !strings.Contains(text, "{") {
+		return text
+	}
+
+	var b strings.Builder
+
+	for {
+		start := strings.IndexByte(text, '{')
+		if start < 0 {
+			break
+		}
+
+		end := strings.IndexByte(text[start:], '}')
+		if end < 0 {
+			break
+		}
+
+		end += start
+		name := text[start+1 : end]
+
+		value, ok := args[name]
+		if !ok {
+			b.WriteString(text[:end+1])
+			text = text[end+1:]
+
+			continue
+		}
+
+		b.WriteString(text[:start])
+		fmt.Fprint(&b, value)
+		text = text[end+1:]
+	}
+
+	b.WriteString(text)
+
+	return b.String()
+}
+
+// flatten converts a nested catalog into messages keyed by dotted paths.
+// A table whose keys are all plural categories, including "other", is a
+// plural message.
+func flatten(prefix string, tree map[string]any, out map[string]*message) error {
+	keys := make([]string, 0, len(tree))
+	for k := range tree {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+
+		switch v := tree[k].(type) {
+		case string:
+			out[key] = &message{other: v}
+		case map[string]any:
+			if m, ok := pluralMessage(v); ok {
+				out[key] = m
+				continue
+			}
+
+			if err := flatten(key, v, out); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("i18n: %s: unsupported value of type %T", key, v)
+		}
+	}
+
+	return nil
+}
+
+// pluralMessage builds a plural message from a table of plural forms.
+func pluralMessage(table map[string]any) (*message, bool) {
+	if _, ok := table["other"]; !ok {
+		return nil, false
+	}
+
+	m := &message{forms: make(map[plural.Form]string, len(table))}
+
+	for k, v := range table {
+		form, ok := pluralForms[k]
+		if !ok {
+			return nil, false
+		}
+
+		text, ok := v.(string)
+		if !ok {
+			return nil, false
+		}
+
+		m.forms[form] = text
+	}
+
+	m.other = m.forms[plural.Other]
+
+	return m, true
+}
@@ -0,0 +1,153 @@
+package i18n
+
+import (
+	"net/http"
+	"strings"
+)
+
+// DefaultCookieName is the cookie Middleware reads the locale from.
+const DefaultCookieName = "forgeui_locale"
+
+// Options configures locale detection.
+type Options struct {
+	// CookieName is the cookie holding a chosen locale. Default: "forgeui_locale"
+	CookieName string
+
+	// URLPrefix detects the locale from the first path segment, as in
+	// /de/about. Default: true
+	URLPrefix bool
+
+	// BasePath is the path the app is served under, skipped before reading
+	// the URL prefix, as in /app/de/about
+	BasePath string
+}
+
+// Option configures locale detection.
+type Option func(*Options)
+
+// WithCookieName sets the cookie holding a chosen locale.
+func WithCookieName(name string) Option {
+	return func(o *Options) { o.CookieName = name }
+}
+
+// WithoutURLPrefix ignores the first path segment when detecting the locale.
+func WithoutURLPrefix() Option {
+	return func(o *Options) { o.URLPrefix = false }
+}
+
+// WithBasePath sets the path the app is served under. forgeui.WithI18n and
+// router.I18n set it from the app's base path.
+func WithBasePath(path string) Option {
+	return func(o *Options) { o.BasePath = strings.TrimSuffix(path, "/") }
+}
+
+func newOptions(opts []Option) Options {
+	o := Options{
+		CookieName: DefaultCookieName,
+		URLPrefix:  true,
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+// Detect returns the locale for a request. It uses, in order, a supported
+// locale in the first path segment after the base path, the locale cookie, the Accept-Language
+// header, and the default locale.
+func (b *Bundle) Detect(r *http.Request, opts ...Option) string {
+	return b.detect(r, newOptions(opts))
+}
+
+func (b *Bundle) detect(r *http.Request, o Options) string {
+	if o.URLPrefix {
+		segment, _, _ := strings.Cut(strings.TrimPrefix(trimBase(r.URL.Path, o.BasePath), "/"), "/")
+		if locale, ok := b.Supported(segment); ok {
+			return locale
+		}
+	}
+
+	if o.CookieName != "" {
+		if c, err := r.Cookie(o.CookieName); err == nil {
+			if locale, ok := b.Supported(c.Value); ok {
+				return locale
+			}
+		}
+	}
+
+	if accept := r.Header.Get("Accept-Language"); accept != "" {
+		return b.Match(accept)
+	}
+
+	return b.DefaultLocale()
+}
+
+// trimBase removes base from the start of path, when path is under it.
+func trimBase(path, base string) string {
+	if rest, ok := strings.CutPrefix(path, base); ok && (rest == "" || rest[0] == '/') {
+		return rest
+	}
+
+	return path
+}
+
+// SetCookie stores a chosen locale in the locale cookie, for example from a
+// language switcher. Unsupported locales are ignored.
+func (b *Bundle) SetCookie(w http.ResponseWriter, locale string, opts ...Option) {
+	locale, ok := b.Supported(locale)
+	if !ok {
+		return
+	}
+
+	o := newOptions(opts)
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     o.CookieName,
+		Value:    locale,
+		Path:     "/",
+		MaxAge:   365 * 24 * 60 * 60,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// Middleware returns HTTP middleware that detects each request's locale
+// (see Detect), stores a Localizer in the request context for T, and sets
+// the Content-Language response header. Requests that already carry a
+// localizer are left alone.
+func Middleware(b *Bundle, opts ...Option) func(http.Handler) http.Handler {
+	o := newOptions(opts)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if FromContext(r.Context()) != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			next.ServeHTTP(w, localize(w, r, b, o))
+		})
+	}
+}
+
+// Localize detects the request's locale and returns the request with a
+// Localizer in its context. It also sets the Content-Language header and
+// adds Accept-Language and Cookie to Vary.
+func Localize(w http.ResponseWriter, r *http.Request, b *Bundle, opts ...Option) *http.Request {
+	return localize(w, r, b, newOptions(opts))
+}
+
+func localize(w http.ResponseWriter, r *http.Request, b *Bundle, o Options) *http.Request {
+	l := b.Localizer(b.detect(r, o))
+
+	w.Header().Set("Content-Language", l.Locale())
+	w.Header().Add("Vary", "Accept-Language")
+
+	if o.CookieName != "" {
+		w.Header().Add("Vary", "Cookie")
+	}
+
+	return r.WithContext(NewContext(r.Context(), l))
+}
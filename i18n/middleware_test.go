@@ -0,0 +1,102 @@
+package i18n
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestBundle_Detect(t *testing.T) {
+	b := newTestBundle(t)
+
+	tests := []struct {
+		name   string
+		path   string
+		cookie string
+		accept string
+		opts   []Option
+		want   string
+	}{
+		{name: "default", path: "/", want: "en"},
+		{name: "URL prefix", path: "/de/about", want: "de"},
+		{name: "URL prefix only", path: "/pl", want: "pl"},
+		{name: "URL prefix beats cookie", path: "/de/about", cookie: "pl", want: "de"},
+		{name: "unsupported prefix", path: "/fr/about", want: "en"},
+		{name: "URL prefix after base path", path: "/app/de/about", opts: []Option{WithBasePath("/app")}, want: "de"},
+		{name: "base path only", path: "/app", opts: []Option{WithBasePath("/app/")}, want: "en"},
+		{name: "outside base path", path: "/application/de", opts: []Option{WithBasePath("/app")}, want: "en"},
+		{name: "prefix disabled", path: "/de/about", opts: []Option{WithoutURLPrefix()}, want: "en"},
+		{name: "cookie", path: "/about", cookie: "de-AT", want: "de-AT"},
+		{name: "unsupported cookie", path: "/about", cookie: "fr", accept: "pl", want: "pl"},
+		{name: "Accept-Language", path: "/about", accept: "de-CH, en;q=0.5", want: "de"},
+		{name: "custom cookie", path: "/", cookie: "de", opts: []Option{WithCookieName("lang")}, want: "en"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: DefaultCookieName, Value: tt.cookie})
+			}
+
+			if tt.accept != "" {
+				req.Header.Set("Accept-Language", tt.accept)
+			}
+
+			if got := b.Detect(req, tt.opts...); got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	b := newTestBundle(t)
+
+	var got, locale string
+
+	handler := Middleware(b)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = T(r.Context(), "nav.home")
+		locale = Locale(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Language", "de")
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if got != "Startseite" {
+		t.Errorf("Expected the German localizer, got %q", got)
+	}
+
+	if w.Header().Get("Content-Language") != "de" {
+		t.Errorf("Expected Content-Language de, got %q", w.Header().Get("Content-Language"))
+	}
+
+	if vary := w.Header().Values("Vary"); len(vary) != 2 {
+		t.Errorf("Expected Vary on Accept-Language and Cookie, got %v", vary)
+	}
+
+	// An existing localizer wins
+	req = httptest.NewRequest(http.MethodGet, "/de/", nil)
+	req = req.WithContext(NewContext(req.Context(), b.Localizer("pl")))
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if locale != "pl" {
+		t.Errorf("Expected the existing Polish localizer to be kept, got %q", locale)
+	}
+}
+
+func TestBundle_SetCookie(t *testing.T) {
+	b := newTestBundle(t)
+
+	w := httptest.NewRecorder()
+	b.SetCookie(w, "DE")
+	b.SetCookie(w, "fr")
+
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != DefaultCookieName || cookies[0].Value != "de" {
+		t.Errorf("Expected one cookie for the supported locale, got %v", cookies)
+	}
+}
@@ -0,0 +1,145 @@
+// Package adaptertest is the conformance suite of the router adapters: an
+// app with a base path, typed routes, a layout, a bridge function with a
+// WebSocket endpoint and assets, and the requests that must work once it is served through
+// another router.
+package adaptertest
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/a-h/templ"
+	"nhooyr.io/websocket" //nolint:staticcheck // Library moved to github.com/coder/websocket - migration pending
+
+	"github.com/xraph/forgeui"
+	"github.com/xraph/forgeui/bridge"
+	"github.com/xraph/forgeui/router"
+)
+
+// BasePath is the base path of the app returned by NewApp.
+const BasePath = "/ui"
+
+// NewApp returns the app the suite runs against.
+func NewApp() *forgeui.App {
+	app := forgeui.New(
+		forgeui.WithBasePath(BasePath),
+		forgeui.WithBridge(bridge.WithCSRF(false), bridge.WithWebSocket(true)),
+		forgeui.WithDefaultLayout("main"),
+		forgeui.WithAssetFileSystem(fstest.MapFS{
+			"css/app.css": {Data: []byte("body{}")},
+		}),
+	)
+
+	app.RegisterLayout("main", func(ctx *router.PageContext, content templ.Component) templ.Component {
+		return templ.ComponentFunc(func(c context.Context, w io.Writer) error {
+			_, _ = io.WriteString(w, "<main>")
+
+			if err := content.Render(c, w); err != nil {
+				return err
+			}
+
+			_, err := io.WriteString(w, "</main>")
+
+			return err
+		})
+	})
+
+	app.Router().Name("user", app.Get("/users/{id:int}", func(ctx *router.PageContext) (templ.Component, error) {
+		return templ.Raw("user " + ctx.Param("id")), nil
+	}))
+
+	app.Get("/files/*path", func(ctx *router.PageContext) (templ.Component, error) {
+		return templ.Raw("file " + ctx.Param("path")), nil
+	})
+
+	_ = app.Bridge().Register("greet", func(ctx bridge.Context, params struct {
+		Name string `json:"name"`
+	}) (string, error) {
+		return "hello " + params.Name, nil
+	})
+
+	return app
+}
+
+// Run checks that handler, which serves the app returned by NewApp through
+// another router, serves its pages, bridge and assets under BasePath.
+func Run(t *testing.T, app *forgeui.App, handler http.Handler) {
+	t.Helper()
+
+	if got := app.Router().URL("user", 42); got != BasePath+"/users/42" {
+		t.Errorf("Expected URL %s/users/42, got %q", BasePath, got)
+	}
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		body     string
+		wantCode int
+		wantBody string
+	}{
+		{"page", http.MethodGet, BasePath + "/users/42", "", http.StatusOK, "<main>user 42</main>"},
+		{"constraint", http.MethodGet, BasePath + "/users/abc", "", http.StatusNotFound, ""},
+		{"catch-all", http.MethodGet, BasePath + "/files/a/b.txt", "", http.StatusOK, "<main>file a/b.txt</main>"},
+		{
+			"bridge", http.MethodPost, app.BridgeCallPath(),
+			`{"jsonrpc":"2.0","id":"1","method":"greet","params":{"name":"jane"}}`,
+			http.StatusOK, `"result":"hello jane"`,
+		},
+		{"bridge client", http.MethodGet, app.BridgeScriptPath(), "", http.StatusOK, ""},
+		{"asset", http.MethodGet, app.StaticPath() + "/css/app.css", "", http.StatusOK, "body{}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			if tt.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("%s %s: expected status %d, got %d: %s", tt.method, tt.path, tt.wantCode, w.Code, w.Body.String())
+			}
+
+			if !strings.Contains(w.Body.String(), tt.wantBody) {
+				t.Errorf("%s %s: expected body containing %q, got %q", tt.method, tt.path, tt.wantBody, w.Body.String())
+			}
+		})
+	}
+	t.Run("bridge WebSocket", func(t *testing.T) {
+		srv := httptest.NewServer(handler)
+		defer srv.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		conn, _, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(srv.URL, "http")+app.BridgeWebSocketPath(), nil)
+		if err != nil {
+			t.Fatalf("Expected a WebSocket at %s, got %v", app.BridgeWebSocketPath(), err)
+		}
+		defer func() { _ = conn.CloseNow() }()
+
+		msg := `{"jsonrpc":"2.0","id":"1","method":"greet","params":{"name":"jane"}}`
+		if err := conn.Write(ctx, websocket.MessageText, []byte(msg)); err != nil {
+			t.Fatal(err)
+		}
+
+		_, data, err := conn.Read(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !strings.Contains(string(data), `"result":"hello jane"`) {
+			t.Errorf("Expected the call's result, got %s", data)
+		}
+	})
+}
@@ -0,0 +1,42 @@
+package adaptertest
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/xraph/forgeui/router"
+)
+
+// The suite against net/http.ServeMux, the router the adapters are
+// modelled on.
+
+func TestServeMux_Handler(t *testing.T) {
+	app := NewApp()
+
+	mux := http.NewServeMux()
+	mux.Handle(BasePath+"/", app.Handler())
+
+	Run(t, app, mux)
+}
+
+func TestServeMux_Routes(t *testing.T) {
+	app := NewApp()
+	rt := app.Router()
+
+	mux := http.NewServeMux()
+	mux.Handle(BasePath+"/", app.Handler())
+
+	for _, route := range rt.Routes() {
+		h := app.Wrap(rt.RouteHandler(route))
+
+		for _, pattern := range route.Patterns(router.ServeMuxSyntax) {
+			mux.HandleFunc(route.Method+" "+pattern, func(w http.ResponseWriter, req *http.Request) {
+				h.ServeHTTP(w, router.WithParams(req, route.ParamsFrom(func(name string, _ bool) string {
+					return req.PathValue(name)
+				})))
+			})
+		}
+	}
+
+	Run(t, app, mux)
+}
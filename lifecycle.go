@@ -0,0 +1,294 @@
+package forgeui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/xraph/forgeui/logging"
+)
+
+// HookStartedEnv is the environment variable naming a file that Run,
+// ExportFromEnv and BridgeTypeScriptFromEnv create as they take over the
+// run, so the CLI can tell an app whose main doesn't call them, and starts
+// serving instead, from a slow one.
+const HookStartedEnv = "FORGEUI_HOOK_STARTED"
+
+// LifecycleHook runs when the app starts or stops (see OnStart and OnStop).
+type LifecycleHook func(ctx context.Context) error
+
+// lifecycle holds the state of a running app.
+type lifecycle struct {
+	mu      sync.Mutex
+	onStart []LifecycleHook
+	onStop  []LifecycleHook
+	server  *http.Server
+	addr    net.Addr
+	ready   atomic.Bool
+}
+
+// OnStart registers hooks that Run calls in order after Initialize, before
+// the server accepts requests. An error stops Run, which then shuts the app
+// down.
+func (a *App) OnStart(hooks ...LifecycleHook) *App {
+	a.lifecycle.mu.Lock()
+	defer a.lifecycle.mu.Unlock()
+
+	a.lifecycle.onStart = append(a.lifecycle.onStart, hooks...)
+
+	return a
+}
+
+// OnStop registers hooks that Shutdown calls in order once the server has
+// drained, before plugins shut down. Every hook runs even if an earlier one
+// fails.
+func (a *App) OnStop(hooks ...LifecycleHook) *App {
+	a.lifecycle.mu.Lock()
+	defer a.lifecycle.mu.Unlock()
+
+	a.lifecycle.onStop = append(a.lifecycle.onStop, hooks...)
+
+	return a
+}
+
+// Ready reports whether Initialize has finished and Shutdown hasn't started.
+// The readiness endpoint (see WithHealthChecks) serves it.
+func (a *App) Ready() bool {
+	return a.lifecycle.ready.Load()
+}
+
+// Addr returns the address the server listens on once Run has started it,
+// or nil. Useful with ":0" addresses.
+func (a *App) Addr() net.Addr {
+	a.lifecycle.mu.Lock()
+	defer a.lifecycle.mu.Unlock()
+
+	return a.lifecycle.addr
+}
+
+// Run initializes the app and serves it on addr until ctx is cancelled or
+// the process receives SIGINT or SIGTERM, then shuts down gracefully within
+// the shutdown timeout (see WithShutdownTimeout and Shutdown). In
+// development mode (see IsDev) it runs the asset dev server alongside.
+//
+// When the FORGEUI_EXPORT environment variable is set, Run exports the app
+// instead of serving it (see ExportFromEnv). Likewise FORGEUI_BRIDGE_TS
+// makes it write the bridge's TypeScript declarations (see
+// BridgeTypeScriptFromEnv). Either way it signals the CLI before
+// initializing (see HookStartedEnv).
+//
+// Example:
+//
+//	app := forgeui.New(forgeui.WithPlugins(registry))
+//	app.OnStop(func(ctx context.Context) error { return db.Close() })
+//
+//	if err := app.Run(context.Background(), ":8080"); err != nil {
+//	    log.Fatal(err)
+//	}
+func (a *App) Run(ctx context.Context, addr string) error {
+	if os.Getenv(ExportEnv) != "" || os.Getenv(BridgeTypeScriptEnv) != "" {
+		hookStarted()
+	}
+
+	if err := a.Initialize(ctx); err != nil {
+		return err
+	}
+
+	if ok, err := a.ExportFromEnv(ctx); ok {
+		return err
+	}
+
+	if ok, err := a.BridgeTypeScriptFromEnv(); ok {
+		return err
+	}
+
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	srv := &http.Server{
+		Handler:           a.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+		ErrorLog:          slog.NewLogLogger(a.Logger().Handler(), slog.LevelError),
+	}
+
+	a.lifecycle.mu.Lock()
+	a.lifecycle.server = srv
+	a.lifecycle.addr = ln.Addr()
+	onStart := a.lifecycle.onStart
+	a.lifecycle.mu.Unlock()
+
+	for _, hook := range onStart {
+		if err := hook(ctx); err != nil {
+			_ = ln.Close()
+			return errors.Join(fmt.Errorf("start hook failed: %w", err), a.Shutdown(ctx))
+		}
+	}
+
+	if a.IsDev() {
+		if err := a.Assets.StartDevServer(ctx); err != nil {
+			a.Logger().WarnContext(ctx, "asset dev server not started", logging.KeyError, err.Error())
+		}
+	}
+
+	sigCtx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	served := make(chan error, 1)
+
+	go func() {
+		served <- srv.Serve(ln)
+	}()
+
+	a.Logger().InfoContext(ctx, "server started", "addr", ln.Addr().String())
+
+	var serveErr error
+
+	select {
+	case serveErr = <-served:
+	case <-sigCtx.Done():
+	}
+
+	// A second signal terminates the process
+	stop()
+
+	a.Logger().InfoContext(ctx, "shutting down", "timeout", a.config.ShutdownTimeout)
+
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), a.config.ShutdownTimeout)
+	defer cancel()
+
+	err = a.Shutdown(shutdownCtx)
+
+	if serveErr == nil {
+		serveErr = <-served
+	}
+
+	if errors.Is(serveErr, http.ErrServerClosed) {
+		serveErr = nil
+	}
+
+	return errors.Join(serveErr, err)
+}
+
+// Shutdown stops the app gracefully: it marks the app not ready, stops the
+// server started by Run while draining in-flight requests, bridge SSE
+// streams and WebSocket connections, runs the OnStop hooks, stops the
+// asset dev server and shuts down plugins in reverse initialization order.
+// Mounted apps shut down alongside the server.
+// Work still running when ctx is done is abandoned; the errors of every
+// step are joined.
+func (a *App) Shutdown(ctx context.Context) error {
+	a.lifecycle.ready.Store(false)
+
+	a.lifecycle.mu.Lock()
+	srv := a.lifecycle.server
+	a.lifecycle.server = nil
+	onStop := a.lifecycle.onStop
+	a.lifecycle.mu.Unlock()
+
+	var errs []error
+
+	// Streams and WebSockets, including those of mounted apps, end
+	// alongside the server's requests
+	bridgeErr := make(chan error, 1)
+
+	if a.HasBridge() {
+		go func() { bridgeErr <- a.bridge.Shutdown(ctx) }()
+	} else {
+		bridgeErr <- nil
+	}
+
+	mountErrs := make(chan error, len(a.mounts))
+
+	for _, sub := range a.mounts {
+		go func() {
+			if err := sub.Shutdown(ctx); err != nil {
+				mountErrs <- fmt.Errorf("mounted app %s: %w", sub.Paths().Base(), err)
+				return
+			}
+
+			mountErrs <- nil
+		}()
+	}
+
+	if srv != nil {
+		if err := srv.Shutdown(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("server shutdown failed: %w", err))
+		}
+	}
+
+	if err := <-bridgeErr; err != nil {
+		errs = append(errs, fmt.Errorf("bridge shutdown failed: %w", err))
+	}
+
+	for range a.mounts {
+		if err := <-mountErrs; err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	for _, hook := range onStop {
+		if err := hook(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("stop hook failed: %w", err))
+		}
+	}
+
+	if a.IsDev() {
+		if err := a.Assets.StopDevServer(); err != nil {
+			errs = append(errs, fmt.Errorf("dev server shutdown failed: %w", err))
+		}
+	}
+
+	if a.HasPlugins() {
+		if err := a.config.Plugins.Shutdown(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("plugin shutdown failed: %w", err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// healthHandler serves the liveness endpoint: 200 while the process serves
+// requests.
+func (a *App) healthHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-store")
+		_, _ = w.Write([]byte("ok\n"))
+	})
+}
+
+// readyHandler serves the readiness endpoint: 200 once Initialize has
+// finished, 503 before and during shutdown.
+func (a *App) readyHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-store")
+
+		if !a.Ready() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte("not ready\n"))
+
+			return
+		}
+
+		_, _ = w.Write([]byte("ready\n"))
+	})
+}
+
+// hookStarted creates the file named by HookStartedEnv, if any.
+func hookStarted() {
+	if path := os.Getenv(HookStartedEnv); path != "" {
+		_ = os.WriteFile(path, nil, 0600)
+	}
+}
@@ -0,0 +1,257 @@
+package forgeui
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/a-h/templ"
+
+	"github.com/xraph/forgeui/router"
+)
+
+// runApp runs app until the returned cancel func is called, and returns
+// the app's base URL once it serves requests.
+func runApp(t *testing.T, app *App) (string, context.CancelFunc, <-chan error) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+
+	go func() { done <- app.Run(ctx, "127.0.0.1:0") }()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if addr := app.Addr(); addr != nil {
+			resp, err := http.Get("http://" + addr.String() + "/")
+			if err == nil {
+				_ = resp.Body.Close()
+				return "http://" + addr.String(), cancel, done
+			}
+		}
+
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	cancel()
+	t.Fatal("Expected the app to start serving")
+
+	return "", nil, nil
+}
+
+func TestApp_Run(t *testing.T) {
+	app := New(WithLogger(slog.New(slog.DiscardHandler)))
+
+	var (
+		mu    sync.Mutex
+		calls []string
+	)
+
+	hook := func(name string) LifecycleHook {
+		return func(ctx context.Context) error {
+			mu.Lock()
+			defer mu.Unlock()
+
+			calls = append(calls, name)
+
+			return nil
+		}
+	}
+
+	app.OnStart(hook("start 1"), hook("start 2"))
+	app.OnStop(hook("stop 1"))
+	app.OnStop(hook("stop 2"))
+
+	app.Get("/", func(ctx *router.PageContext) (templ.Component, error) {
+		return templ.Raw("home"), nil
+	})
+
+	url, cancel, done := runApp(t, app)
+
+	resp, err := http.Get(url + "/")
+	if err != nil {
+		t.Fatalf("GET / failed: %v", err)
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+
+	if string(body) != "home" {
+		t.Errorf("Expected 'home', got %q", body)
+	}
+
+	cancel()
+
+	if err := <-done; err != nil {
+		t.Errorf("Expected Run to return nil after shutdown, got %v", err)
+	}
+
+	want := []string{"start 1", "start 2", "stop 1", "stop 2"}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if len(calls) != len(want) {
+		t.Fatalf("Expected hooks %v, got %v", want, calls)
+	}
+
+	for i := range want {
+		if calls[i] != want[i] {
+			t.Errorf("Expected hooks %v, got %v", want, calls)
+			break
+		}
+	}
+
+	if app.Ready() {
+		t.Error("Expected the app not to be ready after shutdown")
+	}
+}
+
+func TestApp_Run_DrainsRequests(t *testing.T) {
+	app := New(WithLogger(slog.New(slog.DiscardHandler)))
+
+	started, release := make(chan struct{}), make(chan struct{})
+
+	app.Get("/slow", func(ctx *router.PageContext) (templ.Component, error) {
+		close(started)
+		<-release
+
+		return templ.Raw("finished"), nil
+	})
+
+	url, cancel, done := runApp(t, app)
+
+	type result struct {
+		body string
+		err  error
+	}
+
+	results := make(chan result, 1)
+
+	go func() {
+		resp, err := http.Get(url + "/slow")
+		if err != nil {
+			results <- result{err: err}
+			return
+		}
+
+		body, _ := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		results <- result{body: string(body)}
+	}()
+
+	<-started
+	cancel()
+
+	select {
+	case err := <-done:
+		t.Fatalf("Expected Run to wait for the in-flight request, returned %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+
+	if r := <-results; r.err != nil || r.body != "finished" {
+		t.Errorf("Expected the in-flight request to finish, got %q, %v", r.body, r.err)
+	}
+
+	if err := <-done; err != nil {
+		t.Errorf("Expected Run to return nil, got %v", err)
+	}
+}
+
+func TestApp_Run_StartHookError(t *testing.T) {
+	app := New(WithLogger(slog.New(slog.DiscardHandler)))
+
+	stopped := false
+	app.OnStart(func(ctx context.Context) error { return errors.New("no database") })
+	app.OnStop(func(ctx context.Context) error {
+		stopped = true
+		return nil
+	})
+
+	err := app.Run(context.Background(), "127.0.0.1:0")
+	if err == nil {
+		t.Fatal("Expected Run to fail when a start hook fails")
+	}
+
+	if !stopped {
+		t.Error("Expected the app to shut down after a failed start hook")
+	}
+}
+
+func TestApp_HealthChecks(t *testing.T) {
+	app := New(WithHealthChecks("/healthz", "/readyz"))
+	handler := app.Handler()
+
+	probe := func(path string) int {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+
+		return w.Code
+	}
+
+	tests := []struct {
+		name      string
+		step      func()
+		wantReady int
+	}{
+		{"before Initialize", func() {}, http.StatusServiceUnavailable},
+		{"after Initialize", func() { _ = app.Initialize(context.Background()) }, http.StatusOK},
+		{"after Shutdown", func() { _ = app.Shutdown(context.Background()) }, http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.step()
+
+			if got := probe("/healthz"); got != http.StatusOK {
+				t.Errorf("Expected /healthz status %d, got %d", http.StatusOK, got)
+			}
+
+			if got := probe("/readyz"); got != tt.wantReady {
+				t.Errorf("Expected /readyz status %d, got %d", tt.wantReady, got)
+			}
+		})
+	}
+}
+
+func TestApp_HealthChecksOptIn(t *testing.T) {
+	probe := func(app *App, path string) int {
+		w := httptest.NewRecorder()
+		app.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+
+		return w.Code
+	}
+
+	if got := probe(New(), "/healthz"); got != http.StatusNotFound {
+		t.Errorf("Expected no probe by default, got %d", got)
+	}
+
+	// Apps register their own /healthz route
+	app := New()
+	app.Get("/healthz", func(ctx *router.PageContext) (templ.Component, error) {
+		return templ.Raw("mine"), nil
+	})
+
+	sub := New(WithHealthChecks("/healthz", "/readyz"))
+	app.Mount("/admin", sub)
+
+	if got := probe(app, "/healthz"); got != http.StatusOK {
+		t.Errorf("Expected the app's own /healthz route, got %d", got)
+	}
+
+	if path := sub.Paths().Ready(); path != "" {
+		t.Errorf("Expected mounted apps not to serve probes, got %q", path)
+	}
+
+	if got := probe(app, "/admin/readyz"); got != http.StatusNotFound {
+		t.Errorf("Expected no probe for the mounted app, got %d", got)
+	}
+}
@@ -0,0 +1,87 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+)
+
+// AccessOptions configures access logs written by router.Logger and
+// bridge.LoggerMiddleware.
+type AccessOptions struct {
+	// Logger writes the records. Default: the request context's logger
+	Logger *slog.Logger
+
+	// Level is used for successful and redirected requests. Default: Info
+	Level slog.Level
+
+	// ClientErrorLevel is used for 4xx responses. Default: Warn
+	ClientErrorLevel slog.Level
+
+	// ServerErrorLevel is used for 5xx responses. Default: Error
+	ServerErrorLevel slog.Level
+}
+
+// AccessOption configures access logs.
+type AccessOption func(*AccessOptions)
+
+// WithLogger writes access logs to l instead of the request's logger.
+func WithLogger(l *slog.Logger) AccessOption {
+	return func(o *AccessOptions) { o.Logger = l }
+}
+
+// WithLevel sets the level of successful requests.
+func WithLevel(level slog.Level) AccessOption {
+	return func(o *AccessOptions) { o.Level = level }
+}
+
+// WithClientErrorLevel sets the level of 4xx responses.
+func WithClientErrorLevel(level slog.Level) AccessOption {
+	return func(o *AccessOptions) { o.ClientErrorLevel = level }
+}
+
+// WithServerErrorLevel sets the level of 5xx responses.
+func WithServerErrorLevel(level slog.Level) AccessOption {
+	return func(o *AccessOptions) { o.ServerErrorLevel = level }
+}
+
+// NewAccessOptions applies opts to the defaults.
+func NewAccessOptions(opts ...AccessOption) AccessOptions {
+	o := AccessOptions{
+		Level:            slog.LevelInfo,
+		ClientErrorLevel: slog.LevelWarn,
+		ServerErrorLevel: slog.LevelError,
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+// LevelFor returns the level for a response status.
+func (o AccessOptions) LevelFor(status int) slog.Level {
+	switch {
+	case status >= http.StatusInternalServerError:
+		return o.ServerErrorLevel
+	case status >= http.StatusBadRequest:
+		return o.ClientErrorLevel
+	default:
+		return o.Level
+	}
+}
+
+// LoggerFor returns the logger in ctx, or the configured logger with the
+// request ID of ctx added.
+func (o AccessOptions) LoggerFor(ctx context.Context) *slog.Logger {
+	if o.Logger == nil {
+		return FromContext(ctx)
+	}
+
+	if id := RequestID(ctx); id != "" {
+		return o.Logger.With(KeyRequestID, id)
+	}
+
+	return o.Logger
+}
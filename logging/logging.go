@@ -0,0 +1,100 @@
+// Package logging provides the structured logging conventions shared by
+// ForgeUI's router, bridge and asset pipeline.
+//
+// Every subsystem logs through log/slog. The application's logger travels
+// in the request context, so records written while serving a request carry
+// the same attributes (request ID, route, bridge function) wherever they
+// come from:
+//
+//	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+//	app := forgeui.New(forgeui.WithLogger(logger))
+//
+//	// In a handler, loader or bridge function
+//	logging.FromContext(ctx).Info("order placed", "order", order.ID)
+//
+// Attribute keys are the Key constants below; use them for your own records
+// to keep logs consistent.
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+)
+
+// Attribute keys used by ForgeUI log records.
+const (
+	KeyRequestID = "request_id"
+	KeyMethod    = "method"
+	KeyPath      = "path"
+	KeyStatus    = "status"
+	KeyDuration  = "duration"
+	KeyRoute     = "route"
+	KeyFunction  = "function"
+	KeyClientIP  = "client_ip"
+	KeyError     = "error"
+)
+
+// RequestIDHeader is the header carrying the request ID.
+const RequestIDHeader = "X-Request-ID"
+
+type (
+	loggerKey    struct{}
+	requestIDKey struct{}
+)
+
+// NewContext returns a copy of ctx carrying the logger.
+func NewContext(ctx context.Context, l *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, l)
+}
+
+// FromContext returns the logger stored in ctx, or slog.Default().
+func FromContext(ctx context.Context) *slog.Logger {
+	if l, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok && l != nil {
+		return l
+	}
+
+	return slog.Default()
+}
+
+// HasLogger reports whether ctx carries a logger.
+func HasLogger(ctx context.Context) bool {
+	_, ok := ctx.Value(loggerKey{}).(*slog.Logger)
+	return ok
+}
+
+// With returns a copy of ctx whose logger has the given attributes added.
+func With(ctx context.Context, args ...any) context.Context {
+	return NewContext(ctx, FromContext(ctx).With(args...))
+}
+
+// WithRequestID returns a copy of ctx carrying the request ID, with the ID
+// added to its logger as the request_id attribute.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	ctx = context.WithValue(ctx, requestIDKey{}, id)
+	return With(ctx, KeyRequestID, id)
+}
+
+// RequestID returns the request ID stored in ctx, or "".
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// Middleware returns HTTP middleware that stores l in the context of
+// requests that do not carry a logger yet. A nil l is a no-op.
+func Middleware(l *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if l == nil {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !HasLogger(r.Context()) {
+				r = r.WithContext(NewContext(r.Context(), l))
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newBufferLogger() (*slog.Logger, *bytes.Buffer) {
+	var buf bytes.Buffer
+	return slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})), &buf
+}
+
+func decodeRecord(t *testing.T, buf *bytes.Buffer) map[string]any {
+	t.Helper()
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("Expected one JSON log record, got %q: %v", buf.String(), err)
+	}
+
+	return record
+}
+
+func TestFromContext(t *testing.T) {
+	if FromContext(context.Background()) != slog.Default() {
+		t.Error("Expected slog.Default() for a context without a logger")
+	}
+
+	l, _ := newBufferLogger()
+	if FromContext(NewContext(context.Background(), l)) != l {
+		t.Error("Expected the stored logger")
+	}
+}
+
+func TestWithRequestID(t *testing.T) {
+	l, buf := newBufferLogger()
+
+	ctx := WithRequestID(NewContext(context.Background(), l), "abc123")
+
+	if RequestID(ctx) != "abc123" {
+		t.Errorf("Expected request ID abc123, got %q", RequestID(ctx))
+	}
+
+	FromContext(ctx).Info("hello")
+
+	record := decodeRecord(t, buf)
+	if record[KeyRequestID] != "abc123" {
+		t.Errorf("Expected request_id attribute abc123, got %v", record[KeyRequestID])
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	appLogger, _ := newBufferLogger()
+	outerLogger, _ := newBufferLogger()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want *slog.Logger
+	}{
+		{"stores logger", context.Background(), appLogger},
+		{"keeps existing logger", NewContext(context.Background(), outerLogger), outerLogger},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *slog.Logger
+
+			handler := Middleware(appLogger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = FromContext(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(tt.ctx)
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			if got != tt.want {
+				t.Error("Expected the request to carry the wanted logger")
+			}
+		})
+	}
+}
+
+func TestAccessOptions_LevelFor(t *testing.T) {
+	o := NewAccessOptions(WithLevel(slog.LevelDebug))
+
+	tests := []struct {
+		status int
+		want   slog.Level
+	}{
+		{http.StatusOK, slog.LevelDebug},
+		{http.StatusFound, slog.LevelDebug},
+		{http.StatusNotFound, slog.LevelWarn},
+		{http.StatusServiceUnavailable, slog.LevelError},
+	}
+
+	for _, tt := range tests {
+		if got := o.LevelFor(tt.status); got != tt.want {
+			t.Errorf("LevelFor(%d) = %v, want %v", tt.status, got, tt.want)
+		}
+	}
+}
+
+type plainWriter struct {
+	header http.Header
+}
+
+func (w *plainWriter) Header() http.Header         { return w.header }
+func (w *plainWriter) Write(b []byte) (int, error) { return len(b), nil }
+func (w *plainWriter) WriteHeader(int)             {}
+
+func TestNewStatusRecorder(t *testing.T) {
+	rec, w := NewStatusRecorder(httptest.NewRecorder())
+
+	if _, ok := w.(http.Flusher); !ok {
+		t.Error("Expected a Flusher for a flushable ResponseWriter")
+	}
+
+	w.WriteHeader(http.StatusTeapot)
+	w.WriteHeader(http.StatusOK)
+
+	if rec.Status() != http.StatusTeapot {
+		t.Errorf("Expected status %d, got %d", http.StatusTeapot, rec.Status())
+	}
+
+	_, w = NewStatusRecorder(&plainWriter{header: http.Header{}})
+	if _, ok := w.(http.Flusher); ok {
+		t.Error("Expected no Flusher for a ResponseWriter that can't flush")
+	}
+}
@@ -0,0 +1,81 @@
+package logging
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+)
+
+// StatusRecorder wraps a ResponseWriter to record the response status for
+// access logs. It passes Hijack through for WebSocket upgrades.
+type StatusRecorder struct {
+	http.ResponseWriter
+
+	status int
+}
+
+// NewStatusRecorder returns a StatusRecorder writing to w, and the
+// ResponseWriter handlers should write to: the recorder itself, or, when w
+// is an http.Flusher, a wrapper that flushes too. Streaming code checks for
+// http.Flusher, so the wrapper only offers it when w does.
+func NewStatusRecorder(w http.ResponseWriter) (*StatusRecorder, http.ResponseWriter) {
+	rec := &StatusRecorder{ResponseWriter: w}
+
+	if _, ok := w.(http.Flusher); ok {
+		return rec, flushRecorder{rec}
+	}
+
+	return rec, rec
+}
+
+// Status returns the status written so far, or 0 if nothing was written.
+func (w *StatusRecorder) Status() int {
+	return w.status
+}
+
+// WriteHeader records and writes the status code. Like net/http, the
+// first call wins.
+func (w *StatusRecorder) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Write records an implicit 200 OK before the first write.
+func (w *StatusRecorder) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	return w.ResponseWriter.Write(b)
+}
+
+// Hijack implements http.Hijacker for WebSocket upgrades.
+func (w *StatusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if w.status == 0 {
+		w.status = http.StatusSwitchingProtocols
+	}
+
+	return http.NewResponseController(w.ResponseWriter).Hijack()
+}
+
+// Unwrap returns the underlying ResponseWriter for http.ResponseController.
+func (w *StatusRecorder) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// flushRecorder is a StatusRecorder for a ResponseWriter that can flush.
+type flushRecorder struct {
+	*StatusRecorder
+}
+
+// Flush implements http.Flusher for streamed responses.
+func (w flushRecorder) Flush() {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	w.ResponseWriter.(http.Flusher).Flush()
+}
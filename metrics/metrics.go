@@ -0,0 +1,259 @@
+// Package metrics collects counters, gauges and histograms and exposes them
+// in the Prometheus text format, without depending on a Prometheus client.
+//
+// The router (router.WithMetrics) and bridge (bridge.WithMetrics) register
+// their metrics in a Registry; forgeui.WithMetrics serves it from App.Handler:
+//
+//	app := forgeui.New(forgeui.WithMetrics("/metrics"))
+//
+//	orders := app.Metrics().Counter("shop_orders_total", "Orders placed.", "status")
+//	orders.Inc("paid")
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ContentType is the media type of the Prometheus text format.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// DefaultBuckets are histogram buckets in seconds, suited to request
+// latencies.
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Registry holds metrics and writes them in the Prometheus text format.
+// It is safe for concurrent use.
+type Registry struct {
+	mu      sync.RWMutex
+	metrics map[string]metric
+	order   []string
+}
+
+// metric is a registered metric family.
+type metric interface {
+	kind() string
+	labelNames() []string
+	write(w io.Writer, name string) error
+}
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{metrics: make(map[string]metric)}
+}
+
+// Counter registers a counter partitioned by the label names. Registering
+// a name again returns the existing counter; it panics if the name is taken
+// by a different kind of metric or with different labels.
+func (r *Registry) Counter(name, help string, labels ...string) *Counter {
+	return register(r, name, "counter", labels, func() *Counter {
+		return &Counter{series: newSeries[float64](help, labels)}
+	})
+}
+
+// Gauge registers a gauge partitioned by the label names. Registering a name
+// again returns the existing gauge, as for Counter.
+func (r *Registry) Gauge(name, help string, labels ...string) *Gauge {
+	return register(r, name, "gauge", labels, func() *Gauge {
+		return &Gauge{series: newSeries[float64](help, labels)}
+	})
+}
+
+// Histogram registers a histogram with the upper bounds of its buckets,
+// partitioned by the label names. Nil buckets use DefaultBuckets.
+// Registering a name again returns the existing histogram, as for Counter.
+func (r *Registry) Histogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	if buckets == nil {
+		buckets = DefaultBuckets
+	}
+
+	buckets = slices.Clone(buckets)
+	slices.Sort(buckets)
+
+	return register(r, name, "histogram", labels, func() *Histogram {
+		return &Histogram{series: newSeries[*histogramData](help, labels), buckets: buckets}
+	})
+}
+
+// GaugeFunc registers a gauge whose value is read from fn when the registry
+// is written.
+func (r *Registry) GaugeFunc(name, help string, fn func() float64) {
+	register(r, name, "gauge", nil, func() *funcMetric {
+		return &funcMetric{help: help, typ: "gauge", fn: fn}
+	})
+}
+
+// CounterFunc registers a counter whose value is read from fn when the
+// registry is written. fn must never return a smaller value.
+func (r *Registry) CounterFunc(name, help string, fn func() float64) {
+	register(r, name, "counter", nil, func() *funcMetric {
+		return &funcMetric{help: help, typ: "counter", fn: fn}
+	})
+}
+
+func register[M metric](r *Registry, name, kind string, labels []string, create func() M) M {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if existing, ok := r.metrics[name]; ok {
+		m, ok := existing.(M)
+		if !ok || existing.kind() != kind || !slices.Equal(existing.labelNames(), labels) {
+			panic(fmt.Sprintf("metrics: %s already registered with a different type or labels", name))
+		}
+
+		return m
+	}
+
+	m := create()
+	r.metrics[name] = m
+	r.order = append(r.order, name)
+
+	return m
+}
+
+// WriteTo writes every metric in the Prometheus text format, in the order
+// they were registered.
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	r.mu.RLock()
+	names := slices.Clone(r.order)
+	metrics := make([]metric, len(names))
+
+	for i, name := range names {
+		metrics[i] = r.metrics[name]
+	}
+	r.mu.RUnlock()
+
+	cw := &countingWriter{w: w}
+
+	for i, m := range metrics {
+		if err := m.write(cw, names[i]); err != nil {
+			return cw.n, err
+		}
+	}
+
+	return cw.n, nil
+}
+
+// Handler returns an http.Handler serving the registry in the Prometheus
+// text format.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet && req.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", ContentType)
+		w.Header().Set("Cache-Control", "no-store")
+
+		if req.Method == http.MethodHead {
+			return
+		}
+
+		_, _ = r.WriteTo(w)
+	})
+}
+
+// countingWriter counts the bytes written for WriteTo.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+
+	return n, err
+}
+
+// writeHeader writes the HELP and TYPE lines of a metric family.
+func writeHeader(w io.Writer, name, help, typ string) error {
+	if help != "" {
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n", name, helpEscaper.Replace(help)); err != nil {
+			return err
+		}
+	}
+
+	_, err := fmt.Fprintf(w, "# TYPE %s %s\n", name, typ)
+
+	return err
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+// formatLabels formats label pairs as {a="1",b="2"}, or "" without labels.
+func formatLabels(names, values []string, extra ...string) string {
+	if len(names) == 0 && len(extra) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+
+	b.WriteByte('{')
+
+	for i, name := range names {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+
+		fmt.Fprintf(&b, `%s="%s"`, name, labelEscaper.Replace(values[i]))
+	}
+
+	for i := 0; i+1 < len(extra); i += 2 {
+		if b.Len() > 1 {
+			b.WriteByte(',')
+		}
+
+		fmt.Fprintf(&b, `%s="%s"`, extra[i], labelEscaper.Replace(extra[i+1]))
+	}
+
+	b.WriteByte('}')
+
+	return b.String()
+}
+
+// formatValue formats a sample value.
+func formatValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	default:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+}
+
+// funcMetric is a gauge or counter read from a function.
+type funcMetric struct {
+	help string
+	typ  string
+	fn   func() float64
+}
+
+func (m *funcMetric) kind() string         { return m.typ }
+func (m *funcMetric) labelNames() []string { return nil }
+
+func (m *funcMetric) write(w io.Writer, name string) error {
+	if err := writeHeader(w, name, m.help, m.typ); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintf(w, "%s %s\n", name, formatValue(m.fn()))
+
+	return err
+}
@@ -0,0 +1,155 @@
+package metrics
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestRegistry_WriteTo(t *testing.T) {
+	r := NewRegistry()
+
+	requests := r.Counter("http_requests_total", "Requests served.", "route", "status")
+	requests.Inc("/", "200")
+	requests.Inc("/", "200")
+	requests.Add(3, "/users/:id", "404")
+
+	conns := r.Gauge("connections", "Open connections.", "transport")
+	conns.Inc("sse")
+	conns.Inc("sse")
+	conns.Dec("sse")
+
+	latency := r.Histogram("latency_seconds", "Latency.", []float64{0.1, 1}, "route")
+	latency.Observe(0.05, "/")
+	latency.Observe(0.5, "/")
+	latency.Observe(5, "/")
+
+	r.GaugeFunc("up", "", func() float64 { return 1 })
+
+	var buf bytes.Buffer
+	if _, err := r.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo() error = %v", err)
+	}
+
+	want := `# HELP http_requests_total Requests served.
+# TYPE http_requests_total counter
+http_requests_total{route="/",status="200"} 2
+http_requests_total{route="/users/:id",status="404"} 3
+# HELP connections Open connections.
+# TYPE connections gauge
+connections{transport="sse"} 1
+# HELP latency_seconds Latency.
+# TYPE latency_seconds histogram
+latency_seconds_bucket{route="/",le="0.1"} 1
+latency_seconds_bucket{route="/",le="1"} 2
+latency_seconds_bucket{route="/",le="+Inf"} 3
+latency_seconds_sum{route="/"} 5.55
+latency_seconds_count{route="/"} 3
+# TYPE up gauge
+up 1
+`
+
+	if buf.String() != want {
+		t.Errorf("Expected:\n%s\ngot:\n%s", want, buf.String())
+	}
+}
+
+func TestRegistry_LabelEscaping(t *testing.T) {
+	r := NewRegistry()
+	r.Counter("c", "Line one\nline two.", "v").Inc("a\"b\\c\nd")
+
+	var buf bytes.Buffer
+	_, _ = r.WriteTo(&buf)
+
+	for _, want := range []string{`# HELP c Line one\nline two.`, `c{v="a\"b\\c\nd"} 1`} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("Expected %s in %q", want, buf.String())
+		}
+	}
+}
+
+func TestRegistry_Reregister(t *testing.T) {
+	r := NewRegistry()
+
+	if r.Counter("c", "", "a") != r.Counter("c", "", "a") {
+		t.Error("Expected registering a name twice to return the same counter")
+	}
+
+	tests := []struct {
+		name     string
+		register func()
+	}{
+		{"different kind", func() { r.Gauge("c", "", "a") }},
+		{"different labels", func() { r.Counter("c", "", "b") }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("Expected a panic")
+				}
+			}()
+
+			tt.register()
+		})
+	}
+}
+
+func TestCounter_Concurrent(t *testing.T) {
+	c := NewRegistry().Counter("c", "", "k")
+
+	var wg sync.WaitGroup
+
+	for range 50 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			c.Inc("x")
+		}()
+	}
+
+	wg.Wait()
+
+	if c.Value("x") != 50 {
+		t.Errorf("Expected 50, got %v", c.Value("x"))
+	}
+}
+
+func TestRegistry_Handler(t *testing.T) {
+	r := NewRegistry()
+	r.Counter("c", "").Inc()
+
+	tests := []struct {
+		method     string
+		wantStatus int
+		wantBody   string
+	}{
+		{http.MethodGet, http.StatusOK, "c 1\n"},
+		{http.MethodHead, http.StatusOK, ""},
+		{http.MethodPost, http.StatusMethodNotAllowed, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.Handler().ServeHTTP(w, httptest.NewRequest(tt.method, "/metrics", nil))
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+
+			if tt.wantStatus == http.StatusOK && w.Header().Get("Content-Type") != ContentType {
+				t.Errorf("Expected Content-Type %s, got %s", ContentType, w.Header().Get("Content-Type"))
+			}
+
+			if tt.wantBody != "" && !strings.HasSuffix(w.Body.String(), tt.wantBody) {
+				t.Errorf("Expected body ending in %q, got %q", tt.wantBody, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,274 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// series holds the values of a metric family by label values.
+type series[V any] struct {
+	help   string
+	labels []string
+
+	mu     sync.Mutex
+	values map[string]V
+	keys   map[string][]string
+}
+
+func newSeries[V any](help string, labels []string) *series[V] {
+	return &series[V]{
+		help:   help,
+		labels: slices.Clone(labels),
+		values: make(map[string]V),
+		keys:   make(map[string][]string),
+	}
+}
+
+func (s *series[V]) labelNames() []string { return s.labels }
+
+// key returns the map key of the label values. It panics when the number of
+// values doesn't match the label names.
+func (s *series[V]) key(values []string) string {
+	if len(values) != len(s.labels) {
+		panic(fmt.Sprintf("metrics: got %d label values for labels %v", len(values), s.labels))
+	}
+
+	return strings.Join(values, "\x00")
+}
+
+// update calls fn with the value of the label values under the lock.
+func (s *series[V]) update(values []string, fn func(*V)) {
+	k := s.key(values)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	v, ok := s.values[k]
+	if !ok {
+		s.keys[k] = slices.Clone(values)
+	}
+
+	fn(&v)
+	s.values[k] = v
+}
+
+// get returns the value of the label values.
+func (s *series[V]) get(values []string) (V, bool) {
+	k := s.key(values)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	v, ok := s.values[k]
+
+	return v, ok
+}
+
+// each calls fn for every series, sorted by label values. Values are
+// copied with clone, if set, while the series is locked.
+func (s *series[V]) each(clone func(V) V, fn func(values []string, v V) error) error {
+	s.mu.Lock()
+	keys := make([]string, 0, len(s.values))
+
+	for k := range s.values {
+		keys = append(keys, k)
+	}
+
+	slices.Sort(keys)
+
+	labels := make([][]string, len(keys))
+	values := make([]V, len(keys))
+
+	for i, k := range keys {
+		labels[i] = s.keys[k]
+		values[i] = s.values[k]
+
+		if clone != nil {
+			values[i] = clone(values[i])
+		}
+	}
+	s.mu.Unlock()
+
+	for i := range keys {
+		if err := fn(labels[i], values[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Counter is a monotonically increasing value, partitioned by labels.
+type Counter struct {
+	*series[float64]
+}
+
+// Inc adds 1 to the counter with the label values.
+func (c *Counter) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add adds v, which must not be negative, to the counter with the label
+// values.
+func (c *Counter) Add(v float64, labelValues ...string) {
+	if v < 0 {
+		panic("metrics: counters can't decrease")
+	}
+
+	c.update(labelValues, func(n *float64) { *n += v })
+}
+
+// Value returns the counter with the label values.
+func (c *Counter) Value(labelValues ...string) float64 {
+	v, _ := c.get(labelValues)
+	return v
+}
+
+func (c *Counter) kind() string { return "counter" }
+
+func (c *Counter) write(w io.Writer, name string) error {
+	return writeSamples(w, name, "counter", c.series)
+}
+
+// Gauge is a value that can go up and down, partitioned by labels.
+type Gauge struct {
+	*series[float64]
+}
+
+// Set sets the gauge with the label values.
+func (g *Gauge) Set(v float64, labelValues ...string) {
+	g.update(labelValues, func(n *float64) { *n = v })
+}
+
+// Add adds v to the gauge with the label values.
+func (g *Gauge) Add(v float64, labelValues ...string) {
+	g.update(labelValues, func(n *float64) { *n += v })
+}
+
+// Inc adds 1 to the gauge with the label values.
+func (g *Gauge) Inc(labelValues ...string) {
+	g.Add(1, labelValues...)
+}
+
+// Dec subtracts 1 from the gauge with the label values.
+func (g *Gauge) Dec(labelValues ...string) {
+	g.Add(-1, labelValues...)
+}
+
+// Value returns the gauge with the label values.
+func (g *Gauge) Value(labelValues ...string) float64 {
+	v, _ := g.get(labelValues)
+	return v
+}
+
+func (g *Gauge) kind() string { return "gauge" }
+
+func (g *Gauge) write(w io.Writer, name string) error {
+	return writeSamples(w, name, "gauge", g.series)
+}
+
+// writeSamples writes a counter or gauge family.
+func writeSamples(w io.Writer, name, typ string, s *series[float64]) error {
+	if err := writeHeader(w, name, s.help, typ); err != nil {
+		return err
+	}
+
+	return s.each(nil, func(values []string, v float64) error {
+		_, err := fmt.Fprintf(w, "%s%s %s\n", name, formatLabels(s.labels, values), formatValue(v))
+		return err
+	})
+}
+
+// Histogram counts observations in buckets, partitioned by labels.
+type Histogram struct {
+	*series[*histogramData]
+
+	buckets []float64
+}
+
+type histogramData struct {
+	counts []uint64 // per bucket, not cumulative
+	count  uint64
+	sum    float64
+}
+
+// Observe records v in the histogram with the label values.
+func (h *Histogram) Observe(v float64, labelValues ...string) {
+	h.update(labelValues, func(d **histogramData) {
+		if *d == nil {
+			*d = &histogramData{counts: make([]uint64, len(h.buckets))}
+		}
+
+		if i, _ := slices.BinarySearch(h.buckets, v); i < len(h.buckets) {
+			(*d).counts[i]++
+		}
+
+		(*d).count++
+		(*d).sum += v
+	})
+}
+
+// Count returns the number of observations with the label values.
+func (h *Histogram) Count(labelValues ...string) uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if d := h.values[h.key(labelValues)]; d != nil {
+		return d.count
+	}
+
+	return 0
+}
+
+// Sum returns the sum of the observations with the label values.
+func (h *Histogram) Sum(labelValues ...string) float64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if d := h.values[h.key(labelValues)]; d != nil {
+		return d.sum
+	}
+
+	return 0
+}
+
+func (h *Histogram) kind() string { return "histogram" }
+
+func (h *Histogram) write(w io.Writer, name string) error {
+	if err := writeHeader(w, name, h.help, "histogram"); err != nil {
+		return err
+	}
+
+	return h.each(cloneHistogram, func(values []string, d *histogramData) error {
+		var cumulative uint64
+
+		for i, le := range h.buckets {
+			cumulative += d.counts[i]
+
+			if _, err := fmt.Fprintf(w, "%s_bucket%s %d\n", name, formatLabels(h.labels, values, "le", formatValue(le)), cumulative); err != nil {
+				return err
+			}
+		}
+
+		if _, err := fmt.Fprintf(w, "%s_bucket%s %d\n", name, formatLabels(h.labels, values, "le", formatValue(math.Inf(1))), d.count); err != nil {
+			return err
+		}
+
+		if _, err := fmt.Fprintf(w, "%s_sum%s %s\n", name, formatLabels(h.labels, values), formatValue(d.sum)); err != nil {
+			return err
+		}
+
+		_, err := fmt.Fprintf(w, "%s_count%s %d\n", name, formatLabels(h.labels, values), d.count)
+
+		return err
+	})
+}
+
+// cloneHistogram copies d so it can be written without the lock.
+func cloneHistogram(d *histogramData) *histogramData {
+	return &histogramData{counts: slices.Clone(d.counts), count: d.count, sum: d.sum}
+}
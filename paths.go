@@ -0,0 +1,191 @@
+package forgeui
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Paths resolves the URL paths of an app's endpoints: pages, static assets,
+// bridge calls and streams, the bridge client scripts, hot reload, health
+// probes and metrics. Handler serves every endpoint at the path Paths
+// returns for it and the script helpers link to the same paths, so they
+// agree with WithBasePath and Mount.
+//
+// Paths are resolved when called, so call them after Mount.
+type Paths struct {
+	base    string
+	config  *AppConfig
+	mounted bool
+}
+
+// Paths returns the path resolver of the app.
+func (a *App) Paths() Paths {
+	return Paths{base: a.basePath(), config: a.config, mounted: a.parent != nil}
+}
+
+// basePath returns the mount prefixes of the app and its parents followed
+// by its own BasePath.
+func (a *App) basePath() string {
+	if a.parent == nil {
+		return a.config.BasePath
+	}
+
+	return a.parent.basePath() + a.mountPrefix + a.config.BasePath
+}
+
+// Base returns the path every endpoint of the app lives under, or "" for an
+// app served at the root.
+func (p Paths) Base() string {
+	return p.base
+}
+
+// Page returns the URL path of the page registered as path.
+func (p Paths) Page(path string) string {
+	return p.base + path
+}
+
+// Static returns the URL path prefix of static assets.
+func (p Paths) Static() string {
+	return p.base + p.config.StaticPath
+}
+
+// BridgeCall returns the path of the bridge call endpoint. Apps without a
+// base path keep the legacy "/api/bridge" prefix.
+func (p Paths) BridgeCall() string {
+	return p.bridge() + "/call"
+}
+
+// BridgeStream returns the path of the bridge stream endpoint, which takes
+// the function name as its last segment.
+func (p Paths) BridgeStream() string {
+	return p.bridge() + "/stream/"
+}
+
+// BridgeWebSocket returns the path of the bridge WebSocket endpoint, served
+// when the bridge enables it (see bridge.WithWebSocket).
+func (p Paths) BridgeWebSocket() string {
+	return p.bridge() + "/ws"
+}
+
+func (p Paths) bridge() string {
+	if p.base == "" {
+		return "/api/bridge"
+	}
+
+	return p.base + "/bridge"
+}
+
+// BridgeScript returns the path of the forge-bridge.js client.
+func (p Paths) BridgeScript() string {
+	return p.Static() + "/js/forge-bridge.js"
+}
+
+// AlpineBridgeScript returns the path of the alpine-bridge.js client.
+func (p Paths) AlpineBridgeScript() string {
+	return p.Static() + "/js/alpine-bridge.js"
+}
+
+// HotReload returns the path of the hot reload SSE endpoint.
+func (p Paths) HotReload() string {
+	return p.base + "/_forgeui/reload"
+}
+
+// Health returns the path of the liveness probe, or "" when disabled or
+// the app is mounted.
+func (p Paths) Health() string {
+	if p.mounted {
+		return ""
+	}
+
+	return p.optional(p.config.HealthPath)
+}
+
+// Ready returns the path of the readiness probe, or "" when disabled or
+// the app is mounted.
+func (p Paths) Ready() string {
+	if p.mounted {
+		return ""
+	}
+
+	return p.optional(p.config.ReadyPath)
+}
+
+// Metrics returns the path of the metrics endpoint, or "" when disabled.
+func (p Paths) Metrics() string {
+	return p.optional(p.config.MetricsPath)
+}
+
+func (p Paths) optional(path string) string {
+	if path == "" {
+		return ""
+	}
+
+	return p.base + path
+}
+
+// Mount serves sub under prefix within this app's Handler, so several apps,
+// such as an admin UI and a customer portal, run in one binary. The
+// sub-app keeps its own routes, layouts, themes, bridge functions and
+// middleware; its endpoints move under this app's base path followed by
+// prefix and its own BasePath, and the URLs it builds follow them.
+// Initialize and Shutdown cover mounted apps too.
+//
+// Mount apps before serving requests. It panics if prefix isn't a path
+// like "/admin", or if sub is already mounted.
+//
+// Example:
+//
+//	app := forgeui.New()
+//	app.Mount("/admin", admin)   // pages at /admin/..., bridge at /admin/bridge/call
+//	app.Mount("/portal", portal) // pages at /portal/...
+func (a *App) Mount(prefix string, sub *App) *App {
+	if !strings.HasPrefix(prefix, "/") || strings.HasSuffix(prefix, "/") {
+		panic(fmt.Sprintf("forgeui: mount prefix %q must start and not end with /", prefix))
+	}
+
+	if sub == a || sub.parent != nil {
+		panic(fmt.Sprintf("forgeui: app mounted at %q is already mounted", prefix))
+	}
+
+	sub.parent = a
+	sub.mountPrefix = prefix
+	sub.rebase()
+
+	a.mounts = append(a.mounts, sub)
+
+	return a
+}
+
+// rebase moves the routes, assets and hot reload stream of the app and its
+// mounted apps to their current base paths.
+func (a *App) rebase() {
+	paths := a.Paths()
+
+	a.router.SetBasePath(paths.Base())
+	a.Assets.SetStaticPath(paths.Static())
+	a.Assets.SetReloadPath(paths.HotReload())
+
+	for _, sub := range a.mounts {
+		sub.rebase()
+	}
+}
+
+// mountMux is the ServeMux shared by an app and its mounted apps. Each
+// endpoint runs behind the middleware of the app that registered it.
+type mountMux struct {
+	mux    *http.ServeMux
+	root   http.Handler            // the root app's middleware around mux
+	chains map[string]http.Handler // mounted apps' middleware by mux pattern
+}
+
+func (m *mountMux) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if len(m.chains) > 0 {
+		if _, pattern := m.mux.Handler(r); m.chains[pattern] != nil {
+			m.chains[pattern].ServeHTTP(w, r)
+			return
+		}
+	}
+
+	m.root.ServeHTTP(w, r)
+}
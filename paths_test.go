@@ -0,0 +1,283 @@
+package forgeui
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+
+	"github.com/xraph/forgeui/bridge"
+	"github.com/xraph/forgeui/router"
+)
+
+func TestPaths(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       []AppOption
+		mount      string
+		wantCall   string
+		wantStream string
+		wantStatic string
+		wantReload string
+		wantHealth string
+	}{
+		{
+			name:       "root",
+			opts:       []AppOption{WithHealthChecks("/healthz", "")},
+			wantCall:   "/api/bridge/call",
+			wantStream: "/api/bridge/stream/",
+			wantStatic: "/static",
+			wantReload: "/_forgeui/reload",
+			wantHealth: "/healthz",
+		},
+		{
+			name:       "base path",
+			opts:       []AppOption{WithBasePath("/ui"), WithHealthChecks("/healthz", "")},
+			wantCall:   "/ui/bridge/call",
+			wantStream: "/ui/bridge/stream/",
+			wantStatic: "/ui/static",
+			wantReload: "/ui/_forgeui/reload",
+			wantHealth: "/ui/healthz",
+		},
+		{
+			name:       "mounted",
+			opts:       []AppOption{WithBasePath("/ui"), WithStaticPath("/assets"), WithHealthChecks("/healthz", "")},
+			mount:      "/admin",
+			wantCall:   "/admin/ui/bridge/call",
+			wantStream: "/admin/ui/bridge/stream/",
+			wantStatic: "/admin/ui/assets",
+			wantReload: "/admin/ui/_forgeui/reload",
+			wantHealth: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := New(tt.opts...)
+			if tt.mount != "" {
+				New().Mount(tt.mount, app)
+			}
+
+			p := app.Paths()
+
+			if got := p.BridgeCall(); got != tt.wantCall {
+				t.Errorf("BridgeCall() = %v, want %v", got, tt.wantCall)
+			}
+
+			if got := p.BridgeStream(); got != tt.wantStream {
+				t.Errorf("BridgeStream() = %v, want %v", got, tt.wantStream)
+			}
+
+			if got, want := p.BridgeWebSocket(), strings.TrimSuffix(tt.wantCall, "/call")+"/ws"; got != want {
+				t.Errorf("BridgeWebSocket() = %v, want %v", got, want)
+			}
+
+			if got := p.Static(); got != tt.wantStatic {
+				t.Errorf("Static() = %v, want %v", got, tt.wantStatic)
+			}
+
+			if got := p.HotReload(); got != tt.wantReload {
+				t.Errorf("HotReload() = %v, want %v", got, tt.wantReload)
+			}
+
+			if got := p.Health(); got != tt.wantHealth {
+				t.Errorf("Health() = %v, want %v", got, tt.wantHealth)
+			}
+
+			if got := p.Metrics(); got != "" {
+				t.Errorf("Expected no metrics path when disabled, got %v", got)
+			}
+
+			if app.StaticPath() != p.Static() || app.BridgeCallPath() != p.BridgeCall() {
+				t.Error("Expected the App path helpers to agree with Paths")
+			}
+		})
+	}
+}
+
+func TestApp_BasePathPages(t *testing.T) {
+	app := New(WithBasePath("/ui"))
+
+	app.Router().Name("about", app.Get("/about", func(ctx *router.PageContext) (templ.Component, error) {
+		return templ.Raw("About"), nil
+	}))
+
+	handler := app.Handler()
+
+	tests := []struct {
+		path string
+		want int
+	}{
+		{"/ui/about", http.StatusOK},
+		{"/ui/ui/about", http.StatusNotFound},
+		{"/about", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+		if w.Code != tt.want {
+			t.Errorf("GET %s: expected status %d, got %d", tt.path, tt.want, w.Code)
+		}
+	}
+
+	if got := app.Router().URL("about"); got != "/ui/about" {
+		t.Errorf("Expected URL /ui/about, got %q", got)
+	}
+}
+
+// newMountedApp returns an app whose page, layout and bridge function all
+// answer with name.
+func newMountedApp(name string) *App {
+	app := New(WithBridge(bridge.WithCSRF(false)), WithDefaultLayout("main"))
+
+	app.RegisterLayout("main", func(ctx *router.PageContext, content templ.Component) templ.Component {
+		return templ.ComponentFunc(func(c context.Context, w io.Writer) error {
+			_, _ = io.WriteString(w, "<"+name+">")
+
+			if err := content.Render(c, w); err != nil {
+				return err
+			}
+
+			_, err := io.WriteString(w, "</"+name+">")
+
+			return err
+		})
+	})
+
+	app.Router().Name("user", app.Get("/users/:id", func(ctx *router.PageContext) (templ.Component, error) {
+		return templ.Raw(name + " user " + ctx.Param("id")), nil
+	}))
+
+	_ = app.Bridge().Register("whoami", func(ctx bridge.Context, _ struct{}) (string, error) {
+		return name, nil
+	})
+
+	return app
+}
+
+func TestApp_Mount(t *testing.T) {
+	admin := newMountedApp("admin")
+	portal := newMountedApp("portal")
+
+	app := New()
+	app.Get("/", func(ctx *router.PageContext) (templ.Component, error) {
+		return templ.Raw("home"), nil
+	})
+
+	app.Mount("/admin", admin).Mount("/portal", portal)
+
+	handler := app.Handler()
+
+	pages := []struct {
+		path string
+		want string
+	}{
+		{"/", "home"},
+		{"/admin/users/1", "<admin>admin user 1</admin>"},
+		{"/portal/users/2", "<portal>portal user 2</portal>"},
+	}
+
+	for _, tt := range pages {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+		if w.Code != http.StatusOK || w.Body.String() != tt.want {
+			t.Errorf("GET %s: expected 200 %q, got %d %q", tt.path, tt.want, w.Code, w.Body.String())
+		}
+	}
+
+	if got := admin.Router().URL("user", 3); got != "/admin/users/3" {
+		t.Errorf("Expected URL /admin/users/3, got %q", got)
+	}
+
+	for _, sub := range []*App{admin, portal} {
+		req := httptest.NewRequest(http.MethodPost, sub.BridgeCallPath(),
+			strings.NewReader(`{"jsonrpc":"2.0","id":"1","method":"whoami","params":{}}`))
+		req.Header.Set("Content-Type", "application/json")
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		want := `"result":"` + strings.TrimPrefix(sub.Paths().Base(), "/") + `"`
+		if !strings.Contains(w.Body.String(), want) {
+			t.Errorf("POST %s: expected %s, got %s", sub.BridgeCallPath(), want, w.Body.String())
+		}
+
+		w = httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, sub.BridgeScriptPath(), nil))
+
+		if w.Code != http.StatusOK {
+			t.Errorf("GET %s: expected status 200, got %d", sub.BridgeScriptPath(), w.Code)
+		}
+	}
+
+	// The root app has no bridge
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/bridge/call", nil))
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for the root app's bridge, got %d", w.Code)
+	}
+}
+
+func TestApp_MountLifecycle(t *testing.T) {
+	sub := New()
+
+	var stopped bool
+	sub.OnStop(func(context.Context) error {
+		stopped = true
+		return nil
+	})
+
+	app := New().Mount("/admin", sub)
+
+	if err := app.Initialize(context.Background()); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+
+	if !sub.Ready() {
+		t.Error("Expected the mounted app to be initialized")
+	}
+
+	if err := app.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+
+	if !stopped {
+		t.Error("Expected the mounted app's stop hooks to run")
+	}
+}
+
+func TestApp_MountPanics(t *testing.T) {
+	mounted := New()
+	New().Mount("/admin", mounted)
+
+	tests := []struct {
+		name   string
+		prefix string
+		sub    *App
+	}{
+		{"empty prefix", "", New()},
+		{"root prefix", "/", New()},
+		{"trailing slash", "/admin/", New()},
+		{"already mounted", "/other", mounted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("Expected Mount to panic")
+				}
+			}()
+
+			New().Mount(tt.prefix, tt.sub)
+		})
+	}
+}
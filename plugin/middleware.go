@@ -76,3 +76,18 @@ func (m *MiddlewarePluginBase) Priority() int {
 
 	return m.priority
 }
+
+// WrapHandler wraps next in the middleware of every registered
+// MiddlewarePlugin. Plugins with a lower priority wrap the outside of the
+// chain, so they see the request first.
+func (r *Registry) WrapHandler(next http.Handler) http.Handler {
+	middleware := r.CollectMiddleware()
+
+	for i := len(middleware) - 1; i >= 0; i-- {
+		if mw := middleware[i].Middleware(); mw != nil {
+			next = mw(next)
+		}
+	}
+
+	return next
+}
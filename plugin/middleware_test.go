@@ -173,3 +173,39 @@ func TestMiddlewarePluginLifecycle(t *testing.T) {
 		t.Fatalf("Shutdown() error = %v", err)
 	}
 }
+
+func TestWrapHandler(t *testing.T) {
+	registry := NewRegistry()
+
+	var calls []string
+
+	record := func(name string) func(http.Handler) http.Handler {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls = append(calls, name)
+				next.ServeHTTP(w, r)
+			})
+		}
+	}
+
+	_ = registry.Register(NewMiddlewarePluginBase(PluginInfo{Name: "late", Version: "1.0.0"}, record("late"), 90))
+	_ = registry.Register(NewMiddlewarePluginBase(PluginInfo{Name: "early", Version: "1.0.0"}, record("early"), 10))
+
+	handler := registry.WrapHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, "handler")
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	want := []string{"early", "late", "handler"}
+	if len(calls) != len(want) {
+		t.Fatalf("expected calls %v, got %v", want, calls)
+	}
+
+	for i := range want {
+		if calls[i] != want[i] {
+			t.Errorf("expected calls %v, got %v", want, calls)
+			break
+		}
+	}
+}
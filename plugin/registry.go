@@ -4,9 +4,15 @@ import (
 	"errors"
 	"fmt"
 	"maps"
+	"sort"
 	"sync"
+
+	"github.com/xraph/forgeui"
 )
 
+// Registry satisfies the interface forgeui.App uses to drive plugins.
+var _ forgeui.PluginRegistry = (*Registry)(nil)
+
 // Registry manages plugin registration and lifecycle.
 type Registry struct {
 	mu      sync.RWMutex
@@ -216,14 +222,45 @@ func (r *Registry) GetThemePlugin(name string) (ThemePlugin, bool) {
 	return p, ok
 }
 
+// inOrder returns the values of m in initialization (dependency) order.
+// Entries that have not been initialized follow, sorted by name.
+// Must be called with lock held.
+func inOrder[T any](order []string, m map[string]T) []T {
+	result := make([]T, 0, len(m))
+	seen := make(map[string]bool, len(m))
+
+	for _, name := range order {
+		if v, ok := m[name]; ok {
+			result = append(result, v)
+			seen[name] = true
+		}
+	}
+
+	rest := make([]string, 0, len(m)-len(result))
+	for name := range m {
+		if !seen[name] {
+			rest = append(rest, name)
+		}
+	}
+
+	sort.Strings(rest)
+
+	for _, name := range rest {
+		result = append(result, m[name])
+	}
+
+	return result
+}
+
 // CollectScripts collects all scripts from Alpine plugins.
-// Scripts are returned in priority order (lower priority first).
+// Scripts are returned in priority order (lower priority first); scripts
+// with equal priority keep the dependency order of their plugins.
 func (r *Registry) CollectScripts() []Script {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
 	var scripts []Script
-	for _, ap := range r.alpine {
+	for _, ap := range inOrder(r.order, r.alpine) {
 		scripts = append(scripts, ap.Scripts()...)
 	}
 
@@ -252,52 +289,56 @@ func (r *Registry) CollectScripts() []Script {
 	return scripts
 }
 
-// CollectDirectives collects all Alpine directives from Alpine plugins.
+// CollectDirectives collects all Alpine directives from Alpine plugins
+// in dependency order.
 func (r *Registry) CollectDirectives() []AlpineDirective {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
 	var directives []AlpineDirective
-	for _, ap := range r.alpine {
+	for _, ap := range inOrder(r.order, r.alpine) {
 		directives = append(directives, ap.Directives()...)
 	}
 
 	return directives
 }
 
-// CollectStores collects all Alpine stores from Alpine plugins.
+// CollectStores collects all Alpine stores from Alpine plugins
+// in dependency order.
 func (r *Registry) CollectStores() []AlpineStore {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
 	var stores []AlpineStore
-	for _, ap := range r.alpine {
+	for _, ap := range inOrder(r.order, r.alpine) {
 		stores = append(stores, ap.Stores()...)
 	}
 
 	return stores
 }
 
-// CollectMagics collects all Alpine magic properties from Alpine plugins.
+// CollectMagics collects all Alpine magic properties from Alpine plugins
+// in dependency order.
 func (r *Registry) CollectMagics() []AlpineMagic {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
 	var magics []AlpineMagic
-	for _, ap := range r.alpine {
+	for _, ap := range inOrder(r.order, r.alpine) {
 		magics = append(magics, ap.Magics()...)
 	}
 
 	return magics
 }
 
-// CollectAlpineComponents collects all Alpine.data components from Alpine plugins.
+// CollectAlpineComponents collects all Alpine.data components from Alpine plugins
+// in dependency order.
 func (r *Registry) CollectAlpineComponents() []AlpineComponent {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
 	var components []AlpineComponent
-	for _, ap := range r.alpine {
+	for _, ap := range inOrder(r.order, r.alpine) {
 		components = append(components, ap.AlpineComponents()...)
 	}
 
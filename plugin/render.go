@@ -0,0 +1,166 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+
+	"github.com/a-h/templ"
+
+	"github.com/xraph/forgeui/theme"
+)
+
+// HeadComponent returns a templ.Component that renders everything plugins
+// contribute to the document <head>:
+//   - font links and CSS from theme plugins
+//   - external and inline scripts from Alpine plugins, in priority order
+//
+// Render it in the <head> of your layout, before the Alpine.js script tag.
+func (r *Registry) HeadComponent() templ.Component {
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		var (
+			fonts []theme.Font
+			css   strings.Builder
+		)
+
+		for _, tp := range r.collectThemes() {
+			fonts = append(fonts, tp.Fonts()...)
+
+			if extra := strings.TrimSpace(tp.CSS()); extra != "" {
+				css.WriteString(extra)
+				css.WriteString("\n")
+			}
+		}
+
+		if len(fonts) > 0 {
+			if err := theme.FontLink(fonts...).Render(ctx, w); err != nil {
+				return err
+			}
+		}
+
+		if css.Len() > 0 {
+			if _, err := fmt.Fprintf(w, "<style>%s</style>", css.String()); err != nil {
+				return err
+			}
+		}
+
+		for _, s := range r.CollectScripts() {
+			if _, err := io.WriteString(w, scriptTag(s)); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// BodyComponent returns a templ.Component that registers every collected
+// Alpine directive, magic, store and Alpine.data component inside a single
+// alpine:init listener. Registrations follow plugin dependency order.
+//
+// Render it anywhere in the page as long as Alpine.js is loaded with defer
+// (or after this component).
+func (r *Registry) BodyComponent() templ.Component {
+	return templ.ComponentFunc(func(_ context.Context, w io.Writer) error {
+		directives := r.CollectDirectives()
+		magics := r.CollectMagics()
+		stores := r.CollectStores()
+		components := r.CollectAlpineComponents()
+
+		if len(directives)+len(magics)+len(stores)+len(components) == 0 {
+			return nil
+		}
+
+		var js strings.Builder
+		js.WriteString("<script>document.addEventListener('alpine:init', () => {\n")
+
+		for _, d := range directives {
+			fmt.Fprintf(&js, "  Alpine.directive(%s, %s);\n", jsString(d.Name), strings.TrimSpace(d.Definition))
+		}
+
+		for _, m := range magics {
+			fmt.Fprintf(&js, "  Alpine.magic(%s, %s);\n", jsString(m.Name), strings.TrimSpace(m.Definition))
+		}
+
+		for _, s := range stores {
+			state, err := json.Marshal(s.InitialState)
+			if err != nil || s.InitialState == nil {
+				state = []byte("{}")
+			}
+
+			if methods := strings.TrimSpace(s.Methods); methods != "" {
+				fmt.Fprintf(&js, "  Alpine.store(%s, { ...%s, %s });\n", jsString(s.Name), state, methods)
+			} else {
+				fmt.Fprintf(&js, "  Alpine.store(%s, %s);\n", jsString(s.Name), state)
+			}
+		}
+
+		for _, c := range components {
+			fmt.Fprintf(&js, "  Alpine.data(%s, %s);\n", jsString(c.Name), strings.TrimSpace(c.Definition))
+		}
+
+		js.WriteString("});</script>")
+
+		_, err := io.WriteString(w, js.String())
+
+		return err
+	})
+}
+
+// collectThemes returns the theme plugins in dependency order.
+func (r *Registry) collectThemes() []ThemePlugin {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return inOrder(r.order, r.themes)
+}
+
+// scriptTag renders a Script as an HTML <script> element.
+func scriptTag(s Script) string {
+	var b strings.Builder
+
+	b.WriteString("<script")
+
+	if s.Module {
+		b.WriteString(` type="module"`)
+	}
+
+	if s.URL != "" {
+		fmt.Fprintf(&b, ` src="%s"`, html.EscapeString(s.URL))
+	}
+
+	if s.Defer {
+		b.WriteString(" defer")
+	}
+
+	if s.Async {
+		b.WriteString(" async")
+	}
+
+	if s.Integrity != "" {
+		fmt.Fprintf(&b, ` integrity="%s"`, html.EscapeString(s.Integrity))
+	}
+
+	if s.Crossorigin != "" {
+		fmt.Fprintf(&b, ` crossorigin="%s"`, html.EscapeString(s.Crossorigin))
+	}
+
+	b.WriteString(">")
+
+	if s.URL == "" {
+		b.WriteString(s.Inline)
+	}
+
+	b.WriteString("</script>")
+
+	return b.String()
+}
+
+// jsString quotes s as a JavaScript string literal.
+func jsString(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
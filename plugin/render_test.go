@@ -0,0 +1,133 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/xraph/forgeui/theme"
+)
+
+func TestHeadComponent(t *testing.T) {
+	registry := NewRegistry()
+
+	_ = registry.Register(&priorityPlugin{
+		AlpinePluginBase: NewAlpinePluginBase(PluginInfo{Name: "charts", Version: "1.0.0"}),
+		scripts: []Script{
+			{Name: "app", Inline: "window.app = {};", Priority: 90},
+			{Name: "chartjs", URL: "https://cdn.example.com/chart.js?v=1&min=1", Defer: true, Priority: 5},
+		},
+	})
+
+	themePlugin := NewThemePluginBaseWithFonts(
+		PluginInfo{Name: "brand-theme", Version: "1.0.0"},
+		nil,
+		"",
+		[]theme.Font{{Family: "Brand", URL: "https://fonts.example.com/brand.css"}},
+	)
+	_ = registry.Register(themePlugin)
+
+	var buf bytes.Buffer
+	if err := registry.HeadComponent().Render(context.Background(), &buf); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	out := buf.String()
+
+	if !strings.Contains(out, `<link rel="stylesheet" href="https://fonts.example.com/brand.css">`) {
+		t.Errorf("expected theme font link, got %s", out)
+	}
+
+	chart := strings.Index(out, `<script src="https://cdn.example.com/chart.js?v=1&amp;min=1" defer></script>`)
+	inline := strings.Index(out, `<script>window.app = {};</script>`)
+
+	if chart == -1 || inline == -1 {
+		t.Fatalf("expected both scripts, got %s", out)
+	}
+
+	if chart > inline {
+		t.Error("expected lower priority script to render first")
+	}
+}
+
+func TestBodyComponent(t *testing.T) {
+	registry := NewRegistry()
+
+	_ = registry.Register(&mockAlpinePlugin{
+		AlpinePluginBase: NewAlpinePluginBase(PluginInfo{Name: "alpine-test", Version: "1.0.0"}),
+	})
+
+	var buf bytes.Buffer
+	if err := registry.BodyComponent().Render(context.Background(), &buf); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	out := buf.String()
+
+	for _, want := range []string{
+		"document.addEventListener('alpine:init'",
+		`Alpine.directive("test", (el) => {});`,
+		`Alpine.magic("test", (el) => ({}));`,
+		`Alpine.store("test", {"count":0});`,
+		`Alpine.data("test", () => ({}));`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got %s", want, out)
+		}
+	}
+}
+
+func TestBodyComponentEmpty(t *testing.T) {
+	registry := NewRegistry()
+
+	var buf bytes.Buffer
+	if err := registry.BodyComponent().Render(context.Background(), &buf); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	if buf.Len() != 0 {
+		t.Errorf("expected no output without Alpine plugins, got %s", buf.String())
+	}
+}
+
+type storePlugin struct {
+	*AlpinePluginBase
+
+	deps []Dependency
+}
+
+func (p *storePlugin) Dependencies() []Dependency {
+	return p.deps
+}
+
+func (p *storePlugin) Stores() []AlpineStore {
+	return []AlpineStore{{Name: p.Name()}}
+}
+
+func TestBodyComponentDependencyOrder(t *testing.T) {
+	registry := NewRegistry()
+
+	// "aaa" depends on "zzz", so zzz's store must be registered first
+	_ = registry.Register(&storePlugin{
+		AlpinePluginBase: NewAlpinePluginBase(PluginInfo{Name: "aaa", Version: "1.0.0"}),
+		deps:             []Dependency{{Name: "zzz"}},
+	})
+	_ = registry.Register(&storePlugin{
+		AlpinePluginBase: NewAlpinePluginBase(PluginInfo{Name: "zzz", Version: "1.0.0"}),
+	})
+
+	if err := registry.Initialize(context.Background()); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := registry.BodyComponent().Render(context.Background(), &buf); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	out := buf.String()
+	if strings.Index(out, `Alpine.store("zzz"`) > strings.Index(out, `Alpine.store("aaa"`) {
+		t.Errorf("expected dependency store first, got %s", out)
+	}
+}
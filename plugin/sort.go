@@ -1,8 +1,13 @@
 package plugin
 
-import "errors"
+import (
+	"errors"
+	"sort"
+)
 
 // TopologicalSort returns plugins in dependency order using Kahn's algorithm.
+// Plugins without an ordering constraint between them are sorted by name, so
+// the result is deterministic.
 // Returns an error if a circular dependency is detected.
 func (r *Registry) TopologicalSort() ([]Plugin, error) {
 	r.mu.RLock()
@@ -34,6 +39,11 @@ func (r *Registry) TopologicalSort() ([]Plugin, error) {
 		}
 	}
 
+	// Sort adjacency lists so independent plugins come out in a stable order
+	for name := range graph {
+		sort.Strings(graph[name])
+	}
+
 	// Find all nodes with no incoming edges
 	var queue []string
 
@@ -43,6 +53,8 @@ func (r *Registry) TopologicalSort() ([]Plugin, error) {
 		}
 	}
 
+	sort.Strings(queue)
+
 	// Kahn's algorithm
 	var sorted []Plugin
 
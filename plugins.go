@@ -0,0 +1,61 @@
+package forgeui
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/a-h/templ"
+)
+
+// PluginRegistry is the part of *plugin.Registry the App depends on.
+// It is declared here because the plugin package imports forgeui, so the
+// App cannot reference plugin.Registry directly.
+type PluginRegistry interface {
+	// Initialize resolves dependencies and initializes plugins in order
+	Initialize(ctx context.Context) error
+
+	// Shutdown shuts plugins down in reverse initialization order
+	Shutdown(ctx context.Context) error
+
+	// WrapHandler wraps next in the middleware plugin chain
+	WrapHandler(next http.Handler) http.Handler
+
+	// HeadComponent renders plugin fonts, CSS and scripts for <head>
+	HeadComponent() templ.Component
+
+	// BodyComponent renders Alpine directive, magic, store and data registrations
+	BodyComponent() templ.Component
+}
+
+// Plugins returns the plugin registry (may be nil if none was configured).
+// Cast to *plugin.Registry to access the full registry API.
+func (a *App) Plugins() PluginRegistry {
+	return a.config.Plugins
+}
+
+// HasPlugins returns true if a plugin registry is configured
+func (a *App) HasPlugins() bool {
+	return a.config.Plugins != nil
+}
+
+// PluginHead returns a templ.Component emitting the font links, CSS and
+// scripts contributed by plugins. Render it in the layout's <head>, before
+// the Alpine.js script tag.
+func (a *App) PluginHead() templ.Component {
+	if !a.HasPlugins() {
+		return templ.NopComponent
+	}
+
+	return a.config.Plugins.HeadComponent()
+}
+
+// PluginBody returns a templ.Component registering every plugin directive,
+// store, magic and Alpine.data component in dependency order.
+// Render it in the layout's <body>.
+func (a *App) PluginBody() templ.Component {
+	if !a.HasPlugins() {
+		return templ.NopComponent
+	}
+
+	return a.config.Plugins.BodyComponent()
+}
@@ -1,4 +1,9 @@
 // Package seo provides SEO meta tag and structured data management for ForgeUI.
+//
+// FromPage builds meta tags from a page's route metadata, including hreflang
+// alternates for routes in a router.LocalePrefix group:
+//
+//	@seo.MetaTagsNode(seo.FromPage(ctx, "https://example.com"))
 package seo
 
 import (
@@ -13,6 +18,7 @@ import (
 	"github.com/a-h/templ"
 
 	"github.com/xraph/forgeui/plugin"
+	"github.com/xraph/forgeui/router"
 )
 
 // SEO plugin.
@@ -68,6 +74,9 @@ type MetaTags struct {
 	Robots    string
 	GoogleBot string
 	BingBot   string
+
+	// Alternates are rendered as hreflang links. URLs should be absolute.
+	Alternates []router.Alternate
 }
 
 // DefaultMetaTags returns default meta tags.
@@ -81,7 +90,7 @@ func DefaultMetaTags() MetaTags {
 
 // MetaTagsNode generates meta tag nodes.
 func MetaTagsNode(tags MetaTags) templ.Component {
-	return templ.ComponentFunc(func(_ context.Context, w io.Writer) error {
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
 		if tags.Title != "" {
 			if _, err := fmt.Fprintf(w, `<title>%s</title>`, stdhtml.EscapeString(tags.Title)); err != nil {
 				return err
@@ -161,10 +170,68 @@ func MetaTagsNode(tags MetaTags) templ.Component {
 			}
 		}
 
+		return Hreflang("", tags.Alternates).Render(ctx, w)
+	})
+}
+
+// FromPage returns meta tags for the current page from its route metadata
+// (already translated by i18n middleware) and its language alternates.
+// Relative alternate URLs are made absolute with baseURL, such as
+// "https://example.com".
+func FromPage(ctx *router.PageContext, baseURL string) MetaTags {
+	tags := DefaultMetaTags()
+
+	if meta := ctx.Meta; meta != nil {
+		tags.Title = meta.Title
+		tags.Description = meta.Description
+		tags.Keywords = meta.Keywords
+		tags.Canonical = meta.CanonicalURL
+		tags.OGTitle = meta.Title
+		tags.OGDescription = meta.Description
+		tags.OGImage = meta.OGImage
+
+		if meta.OGType != "" {
+			tags.OGType = meta.OGType
+		}
+
+		if meta.NoIndex {
+			tags.Robots = "noindex,nofollow"
+		}
+	}
+
+	for _, alt := range ctx.Alternates() {
+		tags.Alternates = append(tags.Alternates, router.Alternate{
+			Locale: alt.Locale,
+			URL:    absoluteURL(baseURL, alt.URL),
+		})
+	}
+
+	return tags
+}
+
+// Hreflang renders an alternate language link for each alternate. Relative
+// URLs are made absolute with baseURL.
+func Hreflang(baseURL string, alternates []router.Alternate) templ.Component {
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		for _, alt := range alternates {
+			if err := AlternateLink(alt.Locale, absoluteURL(baseURL, alt.URL)).Render(ctx, w); err != nil {
+				return err
+			}
+		}
+
 		return nil
 	})
 }
 
+// absoluteURL prefixes root-relative URLs with baseURL.
+func absoluteURL(baseURL, u string) string {
+	if baseURL == "" || !strings.HasPrefix(u, "/") || strings.HasPrefix(u, "//") {
+		return u
+	}
+
+	return strings.TrimSuffix(baseURL, "/") + u
+}
+
 // StructuredData contains JSON-LD structured data.
 type StructuredData struct {
 	Type string
@@ -2,16 +2,22 @@ package toast
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"html"
 	"io"
 
 	"github.com/a-h/templ"
 
 	"github.com/xraph/forgeui/alpine"
 	"github.com/xraph/forgeui/icons"
+	"github.com/xraph/forgeui/session"
 )
 
 // Container renders the toast container that holds all toasts.
+//
+// When the request has a session (see session.Middleware), its flash
+// messages are shown as toasts once the page loads.
 func Container() templ.Component {
 	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
 		if _, err := io.WriteString(w, `<div class="fixed z-50 pointer-events-none"`); err != nil {
@@ -26,6 +32,9 @@ func Container() templ.Component {
 			'bottom-4 left-4': $store.toasts.position === 'bottom-left',
 			'bottom-4 left-1/2 -translate-x-1/2': $store.toasts.position === 'bottom-center'
 		}`))
+		if err := writeFlashes(ctx, w); err != nil {
+			return err
+		}
 		if _, err := io.WriteString(w, `>`); err != nil {
 			return err
 		}
@@ -140,6 +149,30 @@ func renderToastItem(ctx context.Context, w io.Writer) error {
 	return nil
 }
 
+// writeFlashes writes an x-init attribute that shows the session's flash
+// messages as toasts.
+func writeFlashes(ctx context.Context, w io.Writer) error {
+	s := session.FromContext(ctx)
+	if s == nil {
+		return nil
+	}
+
+	flashes := s.Flashes()
+	if len(flashes) == 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(flashes)
+	if err != nil {
+		return err
+	}
+
+	script := fmt.Sprintf("%s.forEach(f => $store.toasts.show(f.message, f.kind))", data)
+	_, err = fmt.Fprintf(w, ` x-init="%s"`, html.EscapeString(script))
+
+	return err
+}
+
 // writeAlpineAttrs writes templ.Attributes as HTML attributes.
 func writeAlpineAttrs(w io.Writer, attrs templ.Attributes) {
 	for k, v := range attrs {
@@ -15,6 +15,15 @@
 //   - Queue management (max 5 visible)
 //   - Position options (top-right, bottom-center, etc.)
 //   - Custom styling per variant
+//   - Session flash messages shown as toasts
+//
+// # Flash Messages
+//
+// Container shows the flash messages of the request's session (see the
+// session package), so a message added before a redirect appears as a toast
+// on the next page:
+//
+//	ctx.Session().AddFlash(session.FlashSuccess, "Profile saved")
 package toast
 
 import (
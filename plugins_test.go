@@ -0,0 +1,115 @@
+package forgeui
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/a-h/templ"
+)
+
+// fakeRegistry records how the App drives its plugin registry.
+type fakeRegistry struct {
+	initialized bool
+	shutdown    bool
+	initErr     error
+}
+
+func (f *fakeRegistry) Initialize(_ context.Context) error {
+	f.initialized = true
+	return f.initErr
+}
+
+func (f *fakeRegistry) Shutdown(_ context.Context) error {
+	f.shutdown = true
+	return nil
+}
+
+func (f *fakeRegistry) WrapHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Plugin", "wrapped")
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (f *fakeRegistry) HeadComponent() templ.Component {
+	return templ.ComponentFunc(func(_ context.Context, w io.Writer) error {
+		_, err := io.WriteString(w, "<head-plugins>")
+		return err
+	})
+}
+
+func (f *fakeRegistry) BodyComponent() templ.Component {
+	return templ.ComponentFunc(func(_ context.Context, w io.Writer) error {
+		_, err := io.WriteString(w, "<body-plugins>")
+		return err
+	})
+}
+
+func TestApp_WithPlugins(t *testing.T) {
+	registry := &fakeRegistry{}
+	app := New(WithPlugins(registry))
+
+	if !app.HasPlugins() {
+		t.Fatal("expected HasPlugins() to be true")
+	}
+
+	if err := app.Initialize(context.Background()); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+
+	if !registry.initialized {
+		t.Error("expected Initialize to initialize the plugin registry")
+	}
+
+	w := httptest.NewRecorder()
+	app.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if w.Header().Get("X-Plugin") != "wrapped" {
+		t.Error("expected Handler to be wrapped in plugin middleware")
+	}
+
+	var buf bytes.Buffer
+	_ = app.PluginHead().Render(context.Background(), &buf)
+	_ = app.PluginBody().Render(context.Background(), &buf)
+
+	if buf.String() != "<head-plugins><body-plugins>" {
+		t.Errorf("unexpected plugin output: %s", buf.String())
+	}
+
+	if err := app.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+
+	if !registry.shutdown {
+		t.Error("expected Shutdown to shut down the plugin registry")
+	}
+}
+
+func TestApp_WithPluginsInitError(t *testing.T) {
+	app := New(WithPlugins(&fakeRegistry{initErr: errors.New("boom")}))
+
+	if err := app.Initialize(context.Background()); err == nil {
+		t.Error("expected Initialize to return the plugin error")
+	}
+}
+
+func TestApp_NoPlugins(t *testing.T) {
+	app := New()
+
+	var buf bytes.Buffer
+	_ = app.PluginHead().Render(context.Background(), &buf)
+	_ = app.PluginBody().Render(context.Background(), &buf)
+
+	if buf.Len() != 0 {
+		t.Errorf("expected no plugin output, got %s", buf.String())
+	}
+
+	if err := app.Shutdown(context.Background()); err != nil {
+		t.Errorf("Shutdown() error = %v", err)
+	}
+}
@@ -0,0 +1,66 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// TokenBucket is an in-memory Limiter giving each key a bucket of burst
+// tokens, refilled at rate tokens per period. Each request takes a token.
+type TokenBucket struct {
+	memory[tokenBucket]
+
+	interval time.Duration
+	burst    int
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// NewTokenBucket creates a token bucket limiter allowing rate requests per
+// period, in bursts of up to burst requests:
+//
+//	ratelimit.NewTokenBucket(60, time.Minute, 10)
+func NewTokenBucket(rate int, period time.Duration, burst int) *TokenBucket {
+	return &TokenBucket{
+		memory:   newMemory[tokenBucket](),
+		interval: period / time.Duration(max(rate, 1)),
+		burst:    max(burst, 1),
+	}
+}
+
+// Allow takes a token from key's bucket
+func (l *TokenBucket) Allow(_ context.Context, key string) (Result, error) {
+	return l.update(key, func(now time.Time, b *tokenBucket) Result {
+		burst := float64(l.burst)
+
+		if b.last.IsZero() {
+			b.tokens = burst
+		} else {
+			b.tokens = min(burst, b.tokens+float64(now.Sub(b.last))/float64(l.interval))
+		}
+
+		b.last = now
+
+		res := Result{Limit: l.burst}
+
+		if b.tokens >= 1 {
+			b.tokens--
+			res.Allowed = true
+		} else {
+			res.RetryAfter = l.tokensTime(1 - b.tokens)
+		}
+
+		res.Remaining = int(b.tokens)
+		res.ResetAfter = l.tokensTime(burst - b.tokens)
+
+		return res
+	}), nil
+}
+
+// tokensTime returns the time it takes to refill n tokens.
+func (l *TokenBucket) tokensTime(n float64) time.Duration {
+	return time.Duration(n * float64(l.interval))
+}
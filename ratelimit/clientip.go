@@ -0,0 +1,110 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"slices"
+	"strings"
+)
+
+type clientIPKey struct{}
+
+// ClientIP returns the IP address of the client that sent r: the address
+// TrustProxies resolved for requests from a trusted proxy, or else the
+// connection's address. Forwarding headers are ignored by default, since
+// any client can send them.
+func ClientIP(r *http.Request) string {
+	if ip, ok := r.Context().Value(clientIPKey{}).(string); ok {
+		return ip
+	}
+
+	return remoteIP(r)
+}
+
+// TrustProxies returns HTTP middleware that trusts the X-Forwarded-For and
+// X-Real-IP headers of requests from the given proxies, given as addresses
+// or CIDRs, so ClientIP, ByIP and the bridge see the client behind them.
+// The client is the last X-Forwarded-For address that isn't a trusted
+// proxy. It panics if a proxy is neither an address nor a CIDR.
+//
+//	handler := ratelimit.TrustProxies("10.0.0.0/8")(app.Handler())
+func TrustProxies(proxies ...string) func(http.Handler) http.Handler {
+	prefixes := make([]netip.Prefix, len(proxies))
+
+	for i, proxy := range proxies {
+		prefix, err := parseProxy(proxy)
+		if err != nil {
+			panic(fmt.Sprintf("ratelimit: invalid proxy %q: %v", proxy, err))
+		}
+
+		prefixes[i] = prefix
+	}
+
+	trusted := func(ip string) bool {
+		addr, err := netip.ParseAddr(ip)
+		if err != nil {
+			return false
+		}
+
+		return slices.ContainsFunc(prefixes, func(p netip.Prefix) bool {
+			return p.Contains(addr.Unmap())
+		})
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if trusted(remoteIP(r)) {
+				if ip := forwardedIP(r, trusted); ip != "" {
+					r = r.WithContext(context.WithValue(r.Context(), clientIPKey{}, ip))
+				}
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// parseProxy parses an address or a CIDR.
+func parseProxy(proxy string) (netip.Prefix, error) {
+	if strings.Contains(proxy, "/") {
+		prefix, err := netip.ParsePrefix(proxy)
+		return prefix.Masked(), err
+	}
+
+	addr, err := netip.ParseAddr(proxy)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+
+	return netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()), nil
+}
+
+// forwardedIP returns the client address reported by a trusted proxy: the
+// last X-Forwarded-For hop that isn't a trusted proxy itself, or X-Real-IP.
+func forwardedIP(r *http.Request, trusted func(string) bool) string {
+	if values := r.Header.Values("X-Forwarded-For"); len(values) > 0 {
+		hops := strings.Split(strings.Join(values, ","), ",")
+
+		for i := len(hops) - 1; i >= 0; i-- {
+			hop := strings.TrimSpace(hops[i])
+			if i == 0 || !trusted(hop) {
+				return hop
+			}
+		}
+	}
+
+	return strings.TrimSpace(r.Header.Get("X-Real-IP"))
+}
+
+// remoteIP returns the address of the connection, without its port.
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
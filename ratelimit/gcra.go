@@ -0,0 +1,68 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// GCRA is an in-memory Limiter implementing the generic cell rate
+// algorithm: requests are allowed rate per period, in bursts of up to
+// burst requests, like TokenBucket, but the state of a key is a single
+// timestamp, its theoretical arrival time. StoreLimiter runs the same
+// algorithm against a shared Store.
+type GCRA struct {
+	memory[time.Time]
+
+	interval time.Duration
+	burst    int
+}
+
+// NewGCRA creates a GCRA limiter allowing rate requests per period, in
+// bursts of up to burst requests:
+//
+//	ratelimit.NewGCRA(100, time.Minute, 20)
+func NewGCRA(rate int, period time.Duration, burst int) *GCRA {
+	return &GCRA{
+		memory:   newMemory[time.Time](),
+		interval: period / time.Duration(max(rate, 1)),
+		burst:    max(burst, 1),
+	}
+}
+
+// Allow records a request of key if it conforms to the rate
+func (l *GCRA) Allow(_ context.Context, key string) (Result, error) {
+	return l.update(key, func(now time.Time, tat *time.Time) Result {
+		var res Result
+
+		*tat, res = gcra(now, *tat, l.interval, l.burst)
+
+		return res
+	}), nil
+}
+
+// gcra decides on a request at now of a key whose theoretical arrival time
+// is tat, and returns the key's new arrival time. A request conforms when
+// it arrives at most burst-1 intervals before its theoretical arrival.
+func gcra(now, tat time.Time, interval time.Duration, burst int) (time.Time, Result) {
+	if tat.Before(now) {
+		tat = now
+	}
+
+	res := Result{Limit: burst}
+
+	next := tat.Add(interval)
+	allowAt := next.Add(-time.Duration(burst) * interval)
+
+	if now.Before(allowAt) {
+		res.RetryAfter = allowAt.Sub(now)
+		res.ResetAfter = tat.Sub(now)
+
+		return tat, res
+	}
+
+	res.Allowed = true
+	res.Remaining = int(now.Sub(allowAt) / interval)
+	res.ResetAfter = next.Sub(now)
+
+	return next, res
+}
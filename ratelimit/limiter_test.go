@@ -0,0 +1,155 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// clock is a fake time source.
+type clock struct {
+	t time.Time
+}
+
+func newClock() *clock {
+	return &clock{t: time.Unix(1_700_000_000, 0)}
+}
+
+func (c *clock) now() time.Time          { return c.t }
+func (c *clock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+// step is a request made after waiting, and the expected decision.
+type step struct {
+	wait      time.Duration
+	allowed   bool
+	remaining int
+	retry     time.Duration
+}
+
+func runSteps(t *testing.T, l Limiter, c *clock, steps []step) {
+	t.Helper()
+
+	for i, s := range steps {
+		c.advance(s.wait)
+
+		res, err := l.Allow(context.Background(), "k")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if res.Allowed != s.allowed || res.Remaining != s.remaining || res.RetryAfter != s.retry {
+			t.Errorf("Step %d: expected allowed=%v remaining=%d retry=%v, got allowed=%v remaining=%d retry=%v",
+				i, s.allowed, s.remaining, s.retry, res.Allowed, res.Remaining, res.RetryAfter)
+		}
+	}
+}
+
+func TestLimiters(t *testing.T) {
+	// Each allows 3 requests at once, then one per second
+	burstThenRate := []step{
+		{0, true, 2, 0},
+		{0, true, 1, 0},
+		{0, true, 0, 0},
+		{0, false, 0, time.Second},
+		{500 * time.Millisecond, false, 0, 500 * time.Millisecond},
+		{500 * time.Millisecond, true, 0, 0},
+		{3 * time.Second, true, 2, 0},
+	}
+
+	tests := []struct {
+		name  string
+		new   func(c *clock) Limiter
+		steps []step
+	}{
+		{
+			name: "token bucket",
+			new: func(c *clock) Limiter {
+				l := NewTokenBucket(60, time.Minute, 3)
+				l.now = c.now
+				return l
+			},
+			steps: burstThenRate,
+		},
+		{
+			name: "gcra",
+			new: func(c *clock) Limiter {
+				l := NewGCRA(60, time.Minute, 3)
+				l.now = c.now
+				return l
+			},
+			steps: burstThenRate,
+		},
+		{
+			name: "sliding window",
+			new: func(c *clock) Limiter {
+				l := NewSlidingWindow(3, 10*time.Second)
+				l.now = c.now
+				return l
+			},
+			steps: []step{
+				{0, true, 2, 0},
+				{4 * time.Second, true, 1, 0},
+				{4 * time.Second, true, 0, 0},
+				{1 * time.Second, false, 0, time.Second},
+				// The first request leaves the window
+				{1 * time.Second, true, 0, 0},
+				{0, false, 0, 4 * time.Second},
+				{20 * time.Second, true, 2, 0},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newClock()
+			runSteps(t, tt.new(c), c, tt.steps)
+		})
+	}
+}
+
+func TestLimiters_KeysAreIndependent(t *testing.T) {
+	limiters := map[string]Limiter{
+		"token bucket":   NewTokenBucket(1, time.Hour, 1),
+		"gcra":           NewGCRA(1, time.Hour, 1),
+		"sliding window": NewSlidingWindow(1, time.Hour),
+	}
+
+	for name, l := range limiters {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			if res, _ := l.Allow(ctx, "a"); !res.Allowed {
+				t.Error("Expected the first request of a to be allowed")
+			}
+
+			if res, _ := l.Allow(ctx, "a"); res.Allowed {
+				t.Error("Expected the second request of a to be rejected")
+			}
+
+			if res, _ := l.Allow(ctx, "b"); !res.Allowed {
+				t.Error("Expected the first request of b to be allowed")
+			}
+		})
+	}
+}
+
+func TestMemory_Sweep(t *testing.T) {
+	c := newClock()
+
+	l := NewTokenBucket(60, time.Minute, 1)
+	l.now = c.now
+
+	_, _ = l.Allow(context.Background(), "a")
+	_, _ = l.Allow(context.Background(), "b")
+
+	if l.Len() != 2 {
+		t.Fatalf("Expected 2 keys, got %d", l.Len())
+	}
+
+	c.advance(2 * sweepInterval)
+	_, _ = l.Allow(context.Background(), "c")
+
+	if l.Len() != 1 {
+		t.Errorf("Expected keys with a full bucket to be dropped, got %d keys", l.Len())
+	}
+}
@@ -0,0 +1,68 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often in-memory limiters drop the state of keys
+// whose quota is full again.
+const sweepInterval = time.Minute
+
+// memory holds the per-key state of an in-memory limiter.
+type memory[S any] struct {
+	mu     sync.Mutex
+	states map[string]*memoryState[S]
+	swept  time.Time
+	now    func() time.Time
+}
+
+type memoryState[S any] struct {
+	state   S
+	expires time.Time
+}
+
+func newMemory[S any]() memory[S] {
+	return memory[S]{
+		states: make(map[string]*memoryState[S]),
+		now:    time.Now,
+	}
+}
+
+// update calls fn with the state of key, a zero S for new keys, under the
+// limiter's lock. The state is dropped once its quota has reset.
+func (m *memory[S]) update(key string, fn func(now time.Time, state *S) Result) Result {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+
+	if now.Sub(m.swept) > sweepInterval {
+		for k, s := range m.states {
+			if !now.Before(s.expires) {
+				delete(m.states, k)
+			}
+		}
+
+		m.swept = now
+	}
+
+	s, ok := m.states[key]
+	if !ok || !now.Before(s.expires) {
+		s = &memoryState[S]{}
+		m.states[key] = s
+	}
+
+	res := fn(now, &s.state)
+	s.expires = now.Add(res.ResetAfter)
+
+	return res
+}
+
+// Len returns the number of keys with state
+func (m *memory[S]) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return len(m.states)
+}
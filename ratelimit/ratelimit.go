@@ -0,0 +1,166 @@
+// Package ratelimit limits how often clients call ForgeUI pages and bridge
+// functions.
+//
+// A Limiter decides whether the request of a key, such as a user ID or an
+// IP address, is allowed, and reports the state of the key's quota. The
+// package provides three algorithms, each with its own trade-offs:
+//   - TokenBucket allows bursts, then a steady rate
+//   - SlidingWindow allows a number of requests in any window of time,
+//     keeping a timestamp per request
+//   - GCRA behaves like a token bucket with a single timestamp per key,
+//     and StoreLimiter runs it against a shared Store (Redis, SQL) so
+//     limits hold across replicas and restarts
+//
+// The same limiter serves pages (router.RateLimit), bridge functions
+// (bridge.WithLimiter, bridge.WithFunctionLimiter) and plain handlers
+// (Middleware). Rejected HTTP requests get 429 Too Many Requests with
+// Retry-After and RateLimit-Limit, RateLimit-Remaining and RateLimit-Reset
+// headers.
+//
+// # Basic Usage
+//
+//	limiter := ratelimit.NewGCRA(100, time.Minute, 20)
+//
+//	app.Use(router.RateLimit(limiter, ratelimit.ByUser))
+//	app.Bridge().Register("search", search,
+//	    bridge.WithFunctionLimiter(limiter),
+//	    bridge.WithRateLimitKey(ratelimit.ByHeader("X-API-Key")),
+//	)
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xraph/forgeui/auth"
+	"github.com/xraph/forgeui/logging"
+)
+
+// Limiter decides whether requests are allowed
+type Limiter interface {
+	// Allow records a request of key and reports whether it is allowed.
+	// Rejected requests don't use up the quota.
+	Allow(ctx context.Context, key string) (Result, error)
+}
+
+// Result is a Limiter's decision, with the state of the key's quota
+type Result struct {
+	// Allowed reports whether the request may proceed
+	Allowed bool
+
+	// Limit is the number of requests the quota allows at once
+	Limit int
+
+	// Remaining is the number of requests left in the quota
+	Remaining int
+
+	// ResetAfter is the time until the quota is full again
+	ResetAfter time.Duration
+
+	// RetryAfter is the time until a request is allowed again, zero when
+	// the request was allowed
+	RetryAfter time.Duration
+}
+
+// Header names set by SetHeaders
+const (
+	HeaderLimit      = "RateLimit-Limit"
+	HeaderRemaining  = "RateLimit-Remaining"
+	HeaderReset      = "RateLimit-Reset"
+	HeaderRetryAfter = "Retry-After"
+)
+
+// SetHeaders sets the RateLimit-* headers of res, and Retry-After when it
+// was rejected. Durations are rounded up to whole seconds.
+func SetHeaders(h http.Header, res Result) {
+	h.Set(HeaderLimit, strconv.Itoa(res.Limit))
+	h.Set(HeaderRemaining, strconv.Itoa(res.Remaining))
+	h.Set(HeaderReset, strconv.Itoa(Seconds(res.ResetAfter)))
+
+	if !res.Allowed {
+		h.Set(HeaderRetryAfter, strconv.Itoa(Seconds(res.RetryAfter)))
+	}
+}
+
+// Seconds returns d in whole seconds, rounded up
+func Seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+// KeyFunc returns the key a request is limited by. Requests returning an
+// empty key are not limited.
+type KeyFunc func(r *http.Request) string
+
+// ByIP limits requests by client IP (see ClientIP). Behind a proxy, trust
+// it with TrustProxies, or every client shares the proxy's quota.
+func ByIP(r *http.Request) string {
+	return "ip:" + ClientIP(r)
+}
+
+// ByUser limits requests by the ID of the user resolved by auth middleware,
+// and anonymous requests by client IP
+func ByUser(r *http.Request) string {
+	if user := auth.FromContext(r.Context()); user != nil {
+		return "user:" + user.ID()
+	}
+
+	return ByIP(r)
+}
+
+// ByHeader limits requests by the value of a header, such as an API key or
+// tenant ID. Requests without it are limited by ByUser.
+func ByHeader(name string) KeyFunc {
+	return func(r *http.Request) string {
+		if v := r.Header.Get(name); v != "" {
+			return strings.ToLower(name) + ":" + v
+		}
+
+		return ByUser(r)
+	}
+}
+
+// Middleware returns HTTP middleware limiting requests with l, keyed by key
+// (ByUser when nil). Rejected requests get 429 Too Many Requests. Requests
+// are allowed when the limiter fails, and the error is logged.
+func Middleware(l Limiter, key KeyFunc) func(http.Handler) http.Handler {
+	if key == nil {
+		key = ByUser
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			res := Check(r, l, key)
+			if res.Limit > 0 {
+				SetHeaders(w.Header(), res)
+			}
+
+			if !res.Allowed {
+				http.Error(w, "Rate limit exceeded", http.StatusTooManyRequests)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Check applies l to r, keyed by key. Requests with an empty key, or whose
+// limiter failed, are allowed with a zero Limit.
+func Check(r *http.Request, l Limiter, key KeyFunc) Result {
+	k := key(r)
+	if k == "" {
+		return Result{Allowed: true}
+	}
+
+	res, err := l.Allow(r.Context(), k)
+	if err != nil {
+		logging.FromContext(r.Context()).Warn("rate limiter failed, allowing request", logging.KeyError, err.Error())
+		return Result{Allowed: true}
+	}
+
+	return res
+}
@@ -0,0 +1,183 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/xraph/forgeui/auth"
+)
+
+type testUser struct {
+	auth.User
+	id string
+}
+
+func (u testUser) ID() string { return u.id }
+
+func TestSetHeaders(t *testing.T) {
+	tests := []struct {
+		name string
+		res  Result
+		want map[string]string
+	}{
+		{
+			name: "allowed",
+			res:  Result{Allowed: true, Limit: 10, Remaining: 7, ResetAfter: 2500 * time.Millisecond},
+			want: map[string]string{HeaderLimit: "10", HeaderRemaining: "7", HeaderReset: "3", HeaderRetryAfter: ""},
+		},
+		{
+			name: "rejected",
+			res:  Result{Limit: 10, ResetAfter: 10 * time.Second, RetryAfter: 100 * time.Millisecond},
+			want: map[string]string{HeaderLimit: "10", HeaderRemaining: "0", HeaderReset: "10", HeaderRetryAfter: "1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := http.Header{}
+			SetHeaders(h, tt.res)
+
+			for name, want := range tt.want {
+				if got := h.Get(name); got != want {
+					t.Errorf("Expected %s to be %q, got %q", name, want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestKeyFuncs(t *testing.T) {
+	tests := []struct {
+		name   string
+		key    KeyFunc
+		header map[string]string
+		user   auth.User
+		want   string
+	}{
+		{"ip", ByIP, nil, nil, "ip:192.0.2.1"},
+		{"untrusted forwarded ip", ByIP, map[string]string{"X-Forwarded-For": "203.0.113.5, 10.0.0.1"}, nil, "ip:192.0.2.1"},
+		{"user", ByUser, nil, testUser{id: "42"}, "user:42"},
+		{"anonymous user", ByUser, nil, nil, "ip:192.0.2.1"},
+		{"api key", ByHeader("X-API-Key"), map[string]string{"X-API-Key": "secret"}, nil, "x-api-key:secret"},
+		{"no api key", ByHeader("X-API-Key"), nil, testUser{id: "42"}, "user:42"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.RemoteAddr = "192.0.2.1:1234"
+
+			for k, v := range tt.header {
+				r.Header.Set(k, v)
+			}
+
+			if tt.user != nil {
+				r = r.WithContext(auth.NewContext(r.Context(), tt.user))
+			}
+
+			if got := tt.key(r); got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestTrustProxies(t *testing.T) {
+	tests := []struct {
+		name       string
+		remoteAddr string
+		header     map[string]string
+		want       string
+	}{
+		{"no proxy", "192.0.2.1:1234", nil, "192.0.2.1"},
+		{"untrusted proxy", "192.0.2.1:1234", map[string]string{"X-Forwarded-For": "203.0.113.5"}, "192.0.2.1"},
+		{"trusted proxy", "10.0.0.2:1234", map[string]string{"X-Forwarded-For": "203.0.113.5"}, "203.0.113.5"},
+		{"spoofed hop", "10.0.0.2:1234", map[string]string{"X-Forwarded-For": "1.2.3.4, 203.0.113.5"}, "203.0.113.5"},
+		{"trusted hops", "10.0.0.2:1234", map[string]string{"X-Forwarded-For": "203.0.113.5, 10.0.0.3"}, "203.0.113.5"},
+		{"single address", "172.16.0.1:1234", map[string]string{"X-Forwarded-For": "203.0.113.5"}, "203.0.113.5"},
+		{"X-Real-IP", "10.0.0.2:1234", map[string]string{"X-Real-IP": "203.0.113.5"}, "203.0.113.5"},
+		{"no header", "10.0.0.2:1234", nil, "10.0.0.2"},
+		{"IPv6", "[2001:db8::1]:1234", map[string]string{"X-Forwarded-For": "203.0.113.5"}, "203.0.113.5"},
+	}
+
+	var got string
+
+	handler := TrustProxies("10.0.0.0/8", "172.16.0.1", "2001:db8::/32")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = ClientIP(r)
+	}))
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.RemoteAddr = tt.remoteAddr
+
+			for k, v := range tt.header {
+				r.Header.Set(k, v)
+			}
+
+			handler.ServeHTTP(httptest.NewRecorder(), r)
+
+			if got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestTrustProxies_InvalidProxy(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected an invalid proxy to panic")
+		}
+	}()
+
+	TrustProxies("10.0.0.0/33")
+}
+
+type failingLimiter struct{}
+
+func (failingLimiter) Allow(context.Context, string) (Result, error) {
+	return Result{}, errors.New("store unavailable")
+}
+
+func TestMiddleware(t *testing.T) {
+	handler := Middleware(NewTokenBucket(1, time.Minute, 1), ByIP)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+
+	serve := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		return w
+	}
+
+	w := serve()
+	if w.Code != http.StatusOK || w.Header().Get(HeaderRemaining) != "0" {
+		t.Errorf("Expected the first request to pass with headers, got %d %v", w.Code, w.Header())
+	}
+
+	w = serve()
+	if w.Code != http.StatusTooManyRequests {
+		t.Errorf("Expected 429, got %d", w.Code)
+	}
+
+	if got := w.Header().Get(HeaderRetryAfter); got != "60" {
+		t.Errorf("Expected Retry-After 60, got %q", got)
+	}
+}
+
+func TestMiddleware_LimiterFailure(t *testing.T) {
+	handler := Middleware(failingLimiter{}, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if w.Code != http.StatusOK || w.Header().Get(HeaderLimit) != "" {
+		t.Errorf("Expected requests to pass without headers when the limiter fails, got %d %v", w.Code, w.Header())
+	}
+}
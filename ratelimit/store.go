@@ -0,0 +1,78 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrContention is returned by StoreLimiter when concurrent requests of a
+// key keep changing its state
+var ErrContention = errors.New("ratelimit: too many concurrent updates")
+
+// maxAttempts is how many times StoreLimiter retries a contended update.
+const maxAttempts = 10
+
+// Store is the shared state of a StoreLimiter, such as Redis or a SQL
+// table. Values are the theoretical arrival times of keys, in Unix
+// nanoseconds.
+type Store interface {
+	// Get returns the value of key, 0 when it is missing or expired
+	Get(ctx context.Context, key string) (int64, error)
+
+	// CompareAndSwap sets key to value, expiring after ttl, if its value
+	// is still old, a missing key matching 0. It reports whether it did.
+	CompareAndSwap(ctx context.Context, key string, old, value int64, ttl time.Duration) (bool, error)
+}
+
+// StoreLimiter is a GCRA Limiter keeping its state in a Store, so limits
+// hold across replicas and restarts. Replicas' clocks should be in sync.
+// Limiters sharing a store need distinct keys.
+type StoreLimiter struct {
+	store    Store
+	interval time.Duration
+	burst    int
+	now      func() time.Time
+}
+
+// NewStoreLimiter creates a limiter allowing rate requests per period, in
+// bursts of up to burst requests, with its state in store
+func NewStoreLimiter(store Store, rate int, period time.Duration, burst int) *StoreLimiter {
+	return &StoreLimiter{
+		store:    store,
+		interval: period / time.Duration(max(rate, 1)),
+		burst:    max(burst, 1),
+		now:      time.Now,
+	}
+}
+
+// Allow records a request of key if it conforms to the rate
+func (l *StoreLimiter) Allow(ctx context.Context, key string) (Result, error) {
+	for range maxAttempts {
+		old, err := l.store.Get(ctx, key)
+		if err != nil {
+			return Result{}, err
+		}
+
+		var tat time.Time
+		if old != 0 {
+			tat = time.Unix(0, old)
+		}
+
+		next, res := gcra(l.now(), tat, l.interval, l.burst)
+		if !res.Allowed {
+			return res, nil
+		}
+
+		ok, err := l.store.CompareAndSwap(ctx, key, old, next.UnixNano(), res.ResetAfter)
+		if err != nil {
+			return Result{}, err
+		}
+
+		if ok {
+			return res, nil
+		}
+	}
+
+	return Result{}, ErrContention
+}
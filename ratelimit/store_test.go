@@ -0,0 +1,122 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeStore is an in-memory Store.
+type fakeStore struct {
+	mu     sync.Mutex
+	values map[string]int64
+	ttls   map[string]time.Duration
+
+	// conflicts makes the next CompareAndSwap calls fail as if another
+	// replica updated the key first
+	conflicts int
+	err       error
+}
+
+func newFakeStore() *fakeStore {
+	return &fakeStore{values: make(map[string]int64), ttls: make(map[string]time.Duration)}
+}
+
+func (s *fakeStore) Get(_ context.Context, key string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.values[key], s.err
+}
+
+func (s *fakeStore) CompareAndSwap(_ context.Context, key string, old, value int64, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err != nil {
+		return false, s.err
+	}
+
+	if s.conflicts > 0 {
+		s.conflicts--
+		return false, nil
+	}
+
+	if s.values[key] != old {
+		return false, nil
+	}
+
+	s.values[key] = value
+	s.ttls[key] = ttl
+
+	return true, nil
+}
+
+func newStoreLimiter(store Store, c *clock) *StoreLimiter {
+	l := NewStoreLimiter(store, 60, time.Minute, 3)
+	l.now = c.now
+
+	return l
+}
+
+func TestStoreLimiter(t *testing.T) {
+	c := newClock()
+	store := newFakeStore()
+
+	runSteps(t, newStoreLimiter(store, c), c, []step{
+		{0, true, 2, 0},
+		{0, true, 1, 0},
+		{0, true, 0, 0},
+		{0, false, 0, time.Second},
+		{time.Second, true, 0, 0},
+	})
+
+	if ttl := store.ttls["k"]; ttl != 3*time.Second {
+		t.Errorf("Expected keys to expire once their quota resets, got TTL %v", ttl)
+	}
+}
+
+func TestStoreLimiter_SharedAcrossReplicas(t *testing.T) {
+	c := newClock()
+	store := newFakeStore()
+
+	a := newStoreLimiter(store, c)
+	b := newStoreLimiter(store, c)
+
+	for _, l := range []*StoreLimiter{a, b, a} {
+		if res, _ := l.Allow(context.Background(), "k"); !res.Allowed {
+			t.Fatal("Expected the burst to be allowed")
+		}
+	}
+
+	if res, _ := b.Allow(context.Background(), "k"); res.Allowed {
+		t.Error("Expected replicas to share the quota")
+	}
+}
+
+func TestStoreLimiter_Contention(t *testing.T) {
+	c := newClock()
+	store := newFakeStore()
+	l := newStoreLimiter(store, c)
+
+	store.conflicts = maxAttempts - 1
+	if res, err := l.Allow(context.Background(), "k"); err != nil || !res.Allowed {
+		t.Errorf("Expected the update to be retried, got %+v, %v", res, err)
+	}
+
+	store.conflicts = maxAttempts
+	if _, err := l.Allow(context.Background(), "k"); !errors.Is(err, ErrContention) {
+		t.Errorf("Expected ErrContention, got %v", err)
+	}
+}
+
+func TestStoreLimiter_StoreError(t *testing.T) {
+	store := newFakeStore()
+	store.err = errors.New("connection refused")
+
+	if _, err := newStoreLimiter(store, newClock()).Allow(context.Background(), "k"); !errors.Is(err, store.err) {
+		t.Errorf("Expected the store's error, got %v", err)
+	}
+}
@@ -0,0 +1,58 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// SlidingWindow is an in-memory Limiter allowing limit requests per key in
+// any window of time. It keeps the time of each request in the window, so
+// bursts at window boundaries can't double the limit as with fixed windows.
+type SlidingWindow struct {
+	memory[[]time.Time]
+
+	limit  int
+	window time.Duration
+}
+
+// NewSlidingWindow creates a sliding window log limiter allowing limit
+// requests per window:
+//
+//	ratelimit.NewSlidingWindow(100, time.Hour)
+func NewSlidingWindow(limit int, window time.Duration) *SlidingWindow {
+	return &SlidingWindow{
+		memory: newMemory[[]time.Time](),
+		limit:  max(limit, 1),
+		window: window,
+	}
+}
+
+// Allow records a request of key if fewer than limit were made in the
+// last window
+func (l *SlidingWindow) Allow(_ context.Context, key string) (Result, error) {
+	return l.update(key, func(now time.Time, log *[]time.Time) Result {
+		cutoff := now.Add(-l.window)
+
+		// Drop the requests that left the window
+		i := 0
+		for i < len(*log) && !(*log)[i].After(cutoff) {
+			i++
+		}
+
+		*log = (*log)[i:]
+
+		res := Result{Limit: l.limit}
+
+		if len(*log) < l.limit {
+			*log = append(*log, now)
+			res.Allowed = true
+		} else {
+			res.RetryAfter = (*log)[0].Add(l.window).Sub(now)
+		}
+
+		res.Remaining = l.limit - len(*log)
+		res.ResetAfter = (*log)[len(*log)-1].Add(l.window).Sub(now)
+
+		return res
+	}), nil
+}
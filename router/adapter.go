@@ -0,0 +1,216 @@
+package router
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/a-h/templ"
+)
+
+// FromHTTP adapts net/http middleware, such as chi's or gorilla's, to a
+// Middleware. The page handler runs inside mw with the request mw passed
+// on, so context values it adds reach loaders and PageContext.Context;
+// PageContext values set by earlier middleware are kept. When mw answers
+// the request itself without calling its handler, the page isn't rendered.
+//
+// The page renders after mw returns, so middleware that wraps the
+// ResponseWriter, such as compression, belongs around App.Handler instead.
+//
+//	r.Use(router.FromHTTP(middleware.RealIP))
+func FromHTTP(mw func(http.Handler) http.Handler) Middleware {
+	return func(next PageHandler) PageHandler {
+		return func(ctx *PageContext) (templ.Component, error) {
+			var (
+				comp   templ.Component
+				err    error
+				called bool
+			)
+
+			mw(http.HandlerFunc(func(_ http.ResponseWriter, req *http.Request) {
+				called = true
+				ctx.Request = req
+				comp, err = next(ctx)
+			})).ServeHTTP(ctx.ResponseWriter, ctx.Request)
+
+			if !called {
+				return nil, nil
+			}
+
+			return comp, err
+		}
+	}
+}
+
+// Syntax describes how another router writes path parameters, for
+// Route.Patterns.
+type Syntax struct {
+	// Param formats a parameter segment
+	Param func(name string) string
+
+	// CatchAll formats a catch-all segment
+	CatchAll func(name string) string
+
+	// Root is the pattern matching only "/". Default: "/"
+	Root string
+}
+
+// Route pattern syntaxes of common routers.
+var (
+	// ServeMuxSyntax is the syntax of net/http.ServeMux: /users/{id}, /files/{path...}
+	ServeMuxSyntax = Syntax{
+		Param:    func(name string) string { return "{" + name + "}" },
+		CatchAll: func(name string) string { return "{" + name + "...}" },
+		Root:     "/{$}",
+	}
+
+	// ChiSyntax is the syntax of chi: /users/{id}, /files/*
+	ChiSyntax = Syntax{
+		Param:    func(name string) string { return "{" + name + "}" },
+		CatchAll: func(string) string { return "*" },
+	}
+
+	// EchoSyntax is the syntax of echo: /users/:id, /files/*
+	EchoSyntax = Syntax{
+		Param:    func(name string) string { return ":" + name },
+		CatchAll: func(string) string { return "*" },
+	}
+
+	// GinSyntax is the syntax of gin and httprouter: /users/:id, /files/*path
+	GinSyntax = Syntax{
+		Param:    func(name string) string { return ":" + name },
+		CatchAll: func(name string) string { return "*" + name },
+	}
+)
+
+// Patterns returns the route's pattern in syntax s. Routers without
+// optional segments get one pattern per optional segment left out, longest
+// first. Constraints are dropped; RouteHandler still enforces them.
+func (r *Route) Patterns(s Syntax) []string {
+	var (
+		parts    []string
+		patterns []string
+	)
+
+	for _, seg := range r.segments {
+		if seg.optional {
+			patterns = append(patterns, s.join(parts))
+		}
+
+		switch seg.kind {
+		case segmentStatic:
+			parts = append(parts, seg.value)
+		case segmentParam:
+			parts = append(parts, s.Param(seg.value))
+		case segmentCatchAll:
+			parts = append(parts, s.CatchAll(seg.value))
+		}
+	}
+
+	patterns = append(patterns, s.join(parts))
+
+	// Longest first
+	for i, j := 0, len(patterns)-1; i < j; i, j = i+1, j-1 {
+		patterns[i], patterns[j] = patterns[j], patterns[i]
+	}
+
+	return patterns
+}
+
+func (s Syntax) join(parts []string) string {
+	if len(parts) == 0 && s.Root != "" {
+		return s.Root
+	}
+
+	return "/" + strings.Join(parts, "/")
+}
+
+// ParamsFrom collects the route's parameters from another router: value
+// returns the value of the named parameter, and is told whether it is the
+// catch-all, which routers like chi and echo store under "*". Empty values
+// are left out.
+//
+//	params := route.ParamsFrom(func(name string, _ bool) string { return req.PathValue(name) })
+func (r *Route) ParamsFrom(value func(name string, catchAll bool) string) Params {
+	params := make(Params, len(r.paramNames))
+
+	for _, seg := range r.segments {
+		if seg.kind == segmentStatic {
+			continue
+		}
+
+		if v := value(seg.value, seg.kind == segmentCatchAll); v != "" {
+			params[seg.value] = v
+		}
+	}
+
+	return params
+}
+
+type paramsKey struct{}
+
+// WithParams returns a copy of req carrying the path parameters another
+// router matched, for RouteHandler.
+func WithParams(req *http.Request, params Params) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), paramsKey{}, params))
+}
+
+// RouteHandler returns a handler that serves route when another router,
+// such as chi, echo or gin, has matched the request, with the router's
+// middleware, loaders, layouts and error pages. Parameters come from
+// WithParams, or else from matching the request path against the route.
+// Values that violate the route's constraints get the 404 page.
+//
+// Register it under each of route.Patterns(syntax); the adapters in the
+// forgeui/adapters module do this for every route.
+func (r *Router) RouteHandler(route *Route) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		params, ok := req.Context().Value(paramsKey{}).(Params)
+		if ok {
+			ok = route.accepts(params)
+		} else {
+			params, ok = route.Match(req.URL.Path)
+		}
+
+		m := &routeMatch{params: params}
+		if ok {
+			m.route = route
+		}
+
+		r.ServeHTTP(w, req.WithContext(context.WithValue(req.Context(), routeMatchKey{}, m)))
+	})
+}
+
+type routeMatchKey struct{}
+
+// routeMatch is a route matched by another router (see RouteHandler); a
+// nil route means the parameters were rejected.
+type routeMatch struct {
+	route  *Route
+	params Params
+}
+
+// accepts reports whether params has a valid value for every required
+// parameter of the route.
+func (r *Route) accepts(params Params) bool {
+	for _, seg := range r.segments {
+		if seg.kind == segmentStatic {
+			continue
+		}
+
+		v, ok := params[seg.value]
+		if !ok || v == "" {
+			if seg.optional {
+				continue
+			}
+
+			return false
+		}
+
+		if seg.kind == segmentParam && !seg.matches(v) {
+			return false
+		}
+	}
+
+	return true
+}
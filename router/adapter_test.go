@@ -0,0 +1,174 @@
+package router
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/a-h/templ"
+)
+
+type adapterKey struct{}
+
+func TestFromHTTP(t *testing.T) {
+	r := New()
+
+	r.Use(func(next PageHandler) PageHandler {
+		return func(ctx *PageContext) (templ.Component, error) {
+			ctx.Set("user", "jane")
+			return next(ctx)
+		}
+	})
+
+	r.Use(FromHTTP(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if req.Header.Get("Authorization") == "" {
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+
+			w.Header().Set("X-Std", "1")
+			next.ServeHTTP(w, req.WithContext(context.WithValue(req.Context(), adapterKey{}, "tenant-1")))
+		})
+	}))
+
+	r.Get("/", func(ctx *PageContext) (templ.Component, error) {
+		user, _ := ctx.Get("user")
+		tenant, _ := ctx.Context().Value(adapterKey{}).(string)
+
+		return templ.Raw(user.(string) + " " + tenant), nil
+	})
+
+	req := httptest.NewRequest(MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer x")
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Body.String() != "jane tenant-1" {
+		t.Errorf("Expected PageContext and request context values, got %q", w.Body.String())
+	}
+
+	if w.Header().Get("X-Std") != "1" {
+		t.Error("Expected the middleware's header")
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(MethodGet, "/", nil))
+
+	if w.Code != http.StatusUnauthorized || w.Body.String() != "unauthorized\n" {
+		t.Errorf("Expected the middleware's 401 response, got %d %q", w.Code, w.Body.String())
+	}
+}
+
+func TestRoute_Patterns(t *testing.T) {
+	tests := []struct {
+		pattern string
+		syntax  Syntax
+		want    []string
+	}{
+		{"/", ServeMuxSyntax, []string{"/{$}"}},
+		{"/", ChiSyntax, []string{"/"}},
+		{"/users/{id:int}", ServeMuxSyntax, []string{"/users/{id}"}},
+		{"/users/:id", ChiSyntax, []string{"/users/{id}"}},
+		{"/users/:id", EchoSyntax, []string{"/users/:id"}},
+		{"/files/*path", ServeMuxSyntax, []string{"/files/{path...}"}},
+		{"/files/*path", ChiSyntax, []string{"/files/*"}},
+		{"/files/*path", GinSyntax, []string{"/files/*path"}},
+		{"/blog/{page?}", GinSyntax, []string{"/blog/:page", "/blog"}},
+	}
+
+	for _, tt := range tests {
+		got := newRoute(tt.pattern, MethodGet, nil).Patterns(tt.syntax)
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("Patterns(%q) = %v, want %v", tt.pattern, got, tt.want)
+		}
+	}
+}
+
+// serveMuxRoutes registers the routes of r on a ServeMux, the way the
+// adapters register them on chi, echo and gin.
+func serveMuxRoutes(r *Router, withParams bool) *http.ServeMux {
+	mux := http.NewServeMux()
+
+	for _, route := range r.Routes() {
+		h := r.RouteHandler(route)
+
+		for _, pattern := range route.Patterns(ServeMuxSyntax) {
+			mux.Handle(route.Method+" "+pattern, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				if withParams {
+					req = WithParams(req, route.ParamsFrom(func(name string, _ bool) string {
+						return req.PathValue(name)
+					}))
+				}
+
+				h.ServeHTTP(w, req)
+			}))
+		}
+	}
+
+	return mux
+}
+
+func TestRouter_RouteHandler(t *testing.T) {
+	r := New(WithBasePath("/ui"))
+
+	r.Use(func(next PageHandler) PageHandler {
+		return func(ctx *PageContext) (templ.Component, error) {
+			ctx.SetHeader("X-Router", "forgeui")
+			return next(ctx)
+		}
+	})
+
+	r.Get("/users/{id:int}", func(ctx *PageContext) (templ.Component, error) {
+		return templ.Raw("user " + ctx.Param("id")), nil
+	})
+
+	r.Get("/blog/{page?}", func(ctx *PageContext) (templ.Component, error) {
+		return templ.Raw("blog " + ctx.Param("page")), nil
+	})
+
+	r.Get("/files/*path", func(ctx *PageContext) (templ.Component, error) {
+		return templ.Raw("file " + ctx.Param("path")), nil
+	})
+
+	tests := []struct {
+		path     string
+		wantCode int
+		wantBody string
+	}{
+		{"/ui/users/42", http.StatusOK, "user 42"},
+		{"/ui/users/abc", http.StatusNotFound, ""},
+		{"/ui/blog", http.StatusOK, "blog "},
+		{"/ui/blog/2", http.StatusOK, "blog 2"},
+		{"/ui/files/a/b.txt", http.StatusOK, "file a/b.txt"},
+	}
+
+	for _, withParams := range []bool{true, false} {
+		mux := serveMuxRoutes(r, withParams)
+
+		for _, tt := range tests {
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(MethodGet, tt.path, nil))
+
+			if w.Code != tt.wantCode {
+				t.Errorf("GET %s (params %v): expected status %d, got %d", tt.path, withParams, tt.wantCode, w.Code)
+				continue
+			}
+
+			if tt.wantBody == "" {
+				continue
+			}
+
+			if w.Body.String() != tt.wantBody {
+				t.Errorf("GET %s (params %v): expected %q, got %q", tt.path, withParams, tt.wantBody, w.Body.String())
+			}
+
+			if w.Header().Get("X-Router") != "forgeui" {
+				t.Errorf("GET %s: expected the router's middleware to run", tt.path)
+			}
+		}
+	}
+}
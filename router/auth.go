@@ -0,0 +1,118 @@
+package router
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/a-h/templ"
+
+	"github.com/xraph/forgeui/auth"
+	"github.com/xraph/forgeui/htmx"
+)
+
+// WithLoginPath sets the login page anonymous requests are redirected to
+// when a policy denies them (see Authorize). Like route patterns, path is
+// relative to the base path.
+func WithLoginPath(path string) RouterOption {
+	return func(r *Router) {
+		r.loginPath = path
+	}
+}
+
+// User returns the request's user, or nil for anonymous requests and when
+// no authentication middleware (Authenticate, auth.Middleware or
+// forgeui.WithAuthenticator) is installed. It is the same user bridge
+// functions get from bridge.Context.User().
+func (c *PageContext) User() auth.User {
+	return auth.FromContext(c.Context())
+}
+
+// Authenticate returns middleware that resolves the request's user with a,
+// unless auth.Middleware already did.
+func Authenticate(a auth.Authenticator) Middleware {
+	return func(next PageHandler) PageHandler {
+		return func(ctx *PageContext) (templ.Component, error) {
+			// Update the context in place so rendering sees the user too
+			ctx.Request = auth.Load(ctx.Request, a)
+
+			return next(ctx)
+		}
+	}
+}
+
+// Authorize returns middleware that applies policy to the request's user
+// and route parameters (see auth.Param). Denied requests get:
+//   - HTMX requests and requests preferring JSON: 401 for anonymous
+//     requests and 403 for users, as an error page or a JSON Envelope
+//   - other anonymous requests: a redirect to the login page with the
+//     requested URL in the next query parameter (see WithLoginPath), or
+//     401 without one
+//   - other users: the 403 error page
+//
+// Error pages are the nearest group's (see Group.ErrorPage), without the
+// route's layouts.
+func Authorize(policy auth.Policy) Middleware {
+	return func(next PageHandler) PageHandler {
+		return func(ctx *PageContext) (templ.Component, error) {
+			req := auth.WithParams(ctx.Request, ctx.Params)
+
+			err := auth.Check(ctx.User(), req, policy)
+			if err == nil {
+				return next(ctx)
+			}
+
+			return ctx.router.denied(ctx, errors.Is(err, auth.ErrUnauthenticated))
+		}
+	}
+}
+
+// Authorize applies policy to requests to this route (see the Authorize
+// middleware).
+func (r *Route) Authorize(policy auth.Policy) *Route {
+	return r.WithMiddleware(Authorize(policy))
+}
+
+// Authorize applies policy to the group's routes registered after it, and
+// to its nested groups (see the Authorize middleware).
+func (g *Group) Authorize(policy auth.Policy) *Group {
+	return g.Middleware(Authorize(policy))
+}
+
+// denied answers a request a policy denied.
+func (r *Router) denied(ctx *PageContext, anonymous bool) (templ.Component, error) {
+	status := http.StatusForbidden
+	if anonymous {
+		status = http.StatusUnauthorized
+	}
+
+	req := ctx.Request
+	partial := htmx.IsHTMX(req) || prefersJSON(req.Header.Get("Accept"))
+
+	// HTMX requests get the error page without any layout
+	if partial {
+		ctx.SkipLayout()
+	}
+
+	if anonymous && !partial && r != nil && r.loginPath != "" {
+		login := r.BasePath() + r.loginPath + "?next=" + url.QueryEscape(req.URL.RequestURI())
+		http.Redirect(ctx.ResponseWriter, req, login, http.StatusSeeOther)
+
+		return nil, nil
+	}
+
+	if prefersJSON(req.Header.Get("Accept")) {
+		return jsonError(ctx, status, http.StatusText(status)), nil
+	}
+
+	if r == nil {
+		ctx.SkipLayout()
+		return DefaultErrorPage(status)(ctx)
+	}
+
+	// A group's error page comes in the group's layouts, not the route's
+	comp, err := r.errorPage(ctx, status, r.getErrorPage(status))
+	ctx.SkipLayout()
+
+	return comp, err
+}
@@ -0,0 +1,171 @@
+package router
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/a-h/templ"
+
+	"github.com/xraph/forgeui/auth"
+	"github.com/xraph/forgeui/bridge"
+)
+
+// userAuthenticator resolves users from the X-User header: "ada" is an
+// admin, "bob" a member.
+var userAuthenticator = auth.AuthenticatorFunc(func(r *http.Request) (auth.User, error) {
+	switch r.Header.Get("X-User") {
+	case "ada":
+		return &bridge.SimpleUser{UserID: "1", UserRoles: []string{"admin"}}, nil
+	case "bob":
+		return &bridge.SimpleUser{UserID: "2", UserRoles: []string{"member"}}, nil
+	default:
+		return nil, nil
+	}
+})
+
+func TestAuthorize(t *testing.T) {
+	r := New(WithBasePath("/app"), WithLoginPath("/login"))
+	r.RegisterLayout("main", wrapLayout("main"))
+	r.SetDefaultLayout("main")
+	r.Use(Authenticate(userAuthenticator))
+
+	page := func(ctx *PageContext) (templ.Component, error) {
+		return templ.Raw("hello " + ctx.User().ID()), nil
+	}
+
+	r.Get("/admin", page).Authorize(auth.Roles("admin"))
+	r.Get("/users/:id", page).Authorize(auth.Any(auth.Roles("admin"), auth.Self("id")))
+
+	tests := []struct {
+		name         string
+		target       string
+		headers      map[string]string
+		wantStatus   int
+		wantBody     string
+		wantLocation string
+	}{
+		{"allowed", "/app/admin", map[string]string{"X-User": "ada"}, http.StatusOK, "<main>hello 1</main>", ""},
+		{"forbidden", "/app/admin", map[string]string{"X-User": "bob"}, http.StatusForbidden, "", ""},
+		{"anonymous redirects", "/app/admin?tab=1", nil, http.StatusSeeOther, "", "/app/login?next=%2Fapp%2Fadmin%3Ftab%3D1"},
+		{"anonymous htmx", "/app/admin", map[string]string{"HX-Request": "true"}, http.StatusUnauthorized, "", ""},
+		{"anonymous json", "/app/admin", map[string]string{"Accept": "application/json"}, http.StatusUnauthorized, `{"data":null,"meta":{"route":"/app/admin"},"error":{"status":401,"message":"Unauthorized"}}` + "\n", ""},
+		{"forbidden json", "/app/admin", map[string]string{"X-User": "bob", "Accept": "application/json"}, http.StatusForbidden, "", ""},
+		{"owner", "/app/users/2", map[string]string{"X-User": "bob"}, http.StatusOK, "<main>hello 2</main>", ""},
+		{"not owner", "/app/users/1", map[string]string{"X-User": "bob"}, http.StatusForbidden, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(MethodGet, tt.target, nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+
+			if tt.wantBody != "" && w.Body.String() != tt.wantBody {
+				t.Errorf("Expected %q, got %q", tt.wantBody, w.Body.String())
+			}
+
+			if loc := w.Header().Get("Location"); loc != tt.wantLocation {
+				t.Errorf("Expected Location %q, got %q", tt.wantLocation, loc)
+			}
+		})
+	}
+}
+
+func TestAuthorizeWithoutLoginPath(t *testing.T) {
+	r := New()
+	r.Get("/admin", func(ctx *PageContext) (templ.Component, error) {
+		return templ.Raw("admin"), nil
+	}).Authorize(auth.Authenticated())
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(MethodGet, "/admin", nil))
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401, got %d", w.Code)
+	}
+}
+
+func TestGroupAuthorize(t *testing.T) {
+	r := New()
+	r.Use(Authenticate(userAuthenticator))
+	r.RegisterLayout("admin", wrapLayout("admin"))
+
+	admin := r.Group("/admin", GroupLayout("admin")).
+		Authorize(auth.Roles("admin")).
+		ErrorPage(http.StatusForbidden, func(ctx *PageContext) (templ.Component, error) {
+			return templ.Raw("admins only"), nil
+		})
+
+	admin.Get("/stats", func(ctx *PageContext) (templ.Component, error) {
+		return templ.Raw("stats"), nil
+	})
+
+	admin.Group("/billing").Page("/invoices").
+		Handler(func(ctx *PageContext) (templ.Component, error) {
+			return templ.Raw("invoices"), nil
+		}).
+		RequireRoles("billing").
+		Register()
+
+	tests := []struct {
+		path       string
+		user       string
+		wantStatus int
+		want       string
+	}{
+		{"/admin/stats", "ada", http.StatusOK, "<admin>stats</admin>"},
+		{"/admin/stats", "bob", http.StatusForbidden, "<admin>admins only</admin>"},
+		{"/admin/billing/invoices", "bob", http.StatusForbidden, "<admin>admins only</admin>"},
+		{"/admin/billing/invoices", "ada", http.StatusForbidden, "<admin>admins only</admin>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path+" as "+tt.user, func(t *testing.T) {
+			req := httptest.NewRequest(MethodGet, tt.path, nil)
+			req.Header.Set("X-User", tt.user)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+
+			if w.Body.String() != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestAuthorizeJSONEnvelope(t *testing.T) {
+	r := New()
+	r.Get("/admin", func(ctx *PageContext) (templ.Component, error) {
+		return templ.Raw("admin"), nil
+	}).Authorize(auth.Authenticated())
+
+	req := httptest.NewRequest(MethodGet, "/admin", nil)
+	req.Header.Set("Accept", "application/json")
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	var env Envelope
+	if err := json.Unmarshal(w.Body.Bytes(), &env); err != nil {
+		t.Fatalf("Expected a JSON envelope, got %q: %v", w.Body.String(), err)
+	}
+
+	if env.Error == nil || env.Error.Status != http.StatusUnauthorized {
+		t.Errorf("Expected a 401 error, got %+v", env.Error)
+	}
+}
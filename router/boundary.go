@@ -0,0 +1,96 @@
+package router
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+
+	"github.com/a-h/templ"
+
+	"github.com/xraph/forgeui/logging"
+)
+
+// ErrorFallback renders in place of a failed error boundary.
+type ErrorFallback func(ctx *PageContext, err error) templ.Component
+
+// WithErrorBoundary makes the layout an error boundary. When its loader
+// fails, or it or anything inside it fails to render, fallback renders in
+// its place within the parent layouts instead of the whole response
+// failing. The boundary buffers its output, so nothing of the failed
+// render reaches the client. The response keeps its status.
+func WithErrorBoundary(fallback ErrorFallback) LayoutOption {
+	return func(c *LayoutConfig) {
+		c.Fallback = fallback
+	}
+}
+
+// Boundary renders content as an error boundary: if it fails to render,
+// or panics, fallback(err) renders in its place and the rest of the page
+// is unaffected.
+//
+//	router.Boundary(RecentOrders(orders), func(err error) templ.Component {
+//	    return templ.Raw(`<p class="error">Orders are unavailable</p>`)
+//	})
+func Boundary(content templ.Component, fallback func(err error) templ.Component) templ.Component {
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		var buf bytes.Buffer
+
+		if err := renderRecovered(ctx, content, &buf); err != nil {
+			attrs := []any{logging.KeyError, err}
+
+			var p *renderPanic
+			if errors.As(err, &p) {
+				attrs = append(attrs, "stack", p.stack)
+			}
+
+			logging.FromContext(ctx).Error("error boundary caught a render error", attrs...)
+
+			return fallback(err).Render(ctx, w)
+		}
+
+		_, err := buf.WriteTo(w)
+
+		return err
+	})
+}
+
+// layoutBoundary applies a boundary layout to content.
+func layoutBoundary(ctx *PageContext, name string, fn LayoutFunc, fallback ErrorFallback, content templ.Component) templ.Component {
+	if err := ctx.layoutError(name); err != nil {
+		logging.FromContext(ctx.Context()).Error("layout loader failed, rendering its error boundary",
+			"layout", name,
+			logging.KeyError, err,
+		)
+
+		return fallback(ctx, err)
+	}
+
+	return Boundary(fn(ctx, content), func(err error) templ.Component {
+		return fallback(ctx, err)
+	})
+}
+
+// renderPanic is a panic recovered by an error boundary.
+type renderPanic struct {
+	value any
+	stack string
+}
+
+func (p *renderPanic) Error() string { return fmt.Sprintf("panic: %v", p.value) }
+
+// renderRecovered renders c, turning a panic into a renderPanic.
+func renderRecovered(ctx context.Context, c templ.Component, w io.Writer) (err error) {
+	defer func() {
+		if rv := recover(); rv != nil {
+			buf := make([]byte, 4096)
+			n := runtime.Stack(buf, false)
+
+			err = &renderPanic{value: rv, stack: string(buf[:n])}
+		}
+	}()
+
+	return c.Render(ctx, w)
+}
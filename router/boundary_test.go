@@ -0,0 +1,152 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/a-h/templ"
+)
+
+// boundaryRouter returns a router with a "root" layout and a "panel"
+// layout nested in it that is an error boundary.
+func boundaryRouter(panel LayoutFunc, opts ...LayoutOption) *Router {
+	r := New()
+	r.RegisterLayout("root", wrapLayout("root"))
+	r.RegisterLayout("panel", panel, append([]LayoutOption{
+		WithParentLayout("root"),
+		WithErrorBoundary(func(ctx *PageContext, err error) templ.Component {
+			return templ.Raw("fallback: " + err.Error())
+		}),
+	}, opts...)...)
+
+	return r
+}
+
+func TestLayoutBoundaryRenderError(t *testing.T) {
+	r := boundaryRouter(wrapLayout("panel"))
+
+	r.Get("/", func(ctx *PageContext) (templ.Component, error) {
+		return failingComponent, nil
+	}).SetLayout("panel")
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(MethodGet, "/", nil))
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", w.Code)
+	}
+
+	if w.Body.String() != "<root>fallback: template exploded</root>" {
+		t.Errorf("Expected the fallback within the parent layout, got %q", w.Body.String())
+	}
+}
+
+func TestLayoutBoundaryPanic(t *testing.T) {
+	r := boundaryRouter(func(ctx *PageContext, content templ.Component) templ.Component {
+		return templ.ComponentFunc(func(context.Context, io.Writer) error {
+			panic("layout exploded")
+		})
+	})
+
+	r.Get("/", func(ctx *PageContext) (templ.Component, error) {
+		return templ.Raw("page"), nil
+	}).SetLayout("panel")
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(MethodGet, "/", nil))
+
+	if w.Body.String() != "<root>fallback: panic: layout exploded</root>" {
+		t.Errorf("Expected the fallback within the parent layout, got %q", w.Body.String())
+	}
+}
+
+func TestLayoutBoundaryLoaderError(t *testing.T) {
+	pageLoaded := false
+
+	r := boundaryRouter(wrapLayout("panel"), WithLayoutLoader(func(ctx context.Context, params Params) (any, error) {
+		return nil, errors.New("panel data unavailable")
+	}))
+
+	r.Get("/", func(ctx *PageContext) (templ.Component, error) {
+		return templ.Raw("page"), nil
+	}).SetLayout("panel").Loader(func(ctx context.Context, params Params) (any, error) {
+		pageLoaded = true
+		return nil, ctx.Err()
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(MethodGet, "/", nil))
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", w.Code)
+	}
+
+	if w.Body.String() != "<root>fallback: panel data unavailable</root>" {
+		t.Errorf("Expected the fallback within the parent layout, got %q", w.Body.String())
+	}
+
+	if !pageLoaded {
+		t.Error("Expected the page loader to run")
+	}
+}
+
+func TestLayoutBoundaryPassesThrough(t *testing.T) {
+	r := boundaryRouter(wrapLayout("panel"))
+
+	r.Get("/", func(ctx *PageContext) (templ.Component, error) {
+		return templ.Raw("page"), nil
+	}).SetLayout("panel")
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(MethodGet, "/", nil))
+
+	if w.Body.String() != "<root><panel>page</panel></root>" {
+		t.Errorf("Expected the page in both layouts, got %q", w.Body.String())
+	}
+}
+
+func TestBoundary(t *testing.T) {
+	fallback := func(err error) templ.Component {
+		return templ.Raw("[" + err.Error() + "]")
+	}
+
+	tests := []struct {
+		name    string
+		content templ.Component
+		want    string
+	}{
+		{"success", templ.Raw("ok"), "before ok after"},
+		{"error", failingComponent, "before [template exploded] after"},
+		{"panic", templ.ComponentFunc(func(context.Context, io.Writer) error { panic("boom") }), "before [panic: boom] after"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page := templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+				_, _ = io.WriteString(w, "before ")
+				if err := Boundary(tt.content, fallback).Render(ctx, w); err != nil {
+					return err
+				}
+				_, err := io.WriteString(w, " after")
+
+				return err
+			})
+
+			r := New()
+			r.Get("/", func(ctx *PageContext) (templ.Component, error) {
+				return page, nil
+			})
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(MethodGet, "/", nil))
+
+			if w.Body.String() != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package router
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// WithBuffering renders every page into a buffer before sending it.
+//
+// Buffered responses are only committed once rendering succeeded: a template
+// that fails halfway is replaced by the SetErrorPage(500) page (or the error
+// handler). They also carry Content-Length and a strong ETag, and answer a
+// matching If-None-Match with 304 Not Modified. Deferred data (see Await) is
+// rendered inline instead of streamed.
+func WithBuffering() RouterOption {
+	return func(r *Router) {
+		r.buffered = true
+	}
+}
+
+// WithBuffering enables buffered rendering for this route only.
+// See the WithBuffering router option.
+func (r *Route) WithBuffering() *Route {
+	r.buffered = true
+	return r
+}
+
+// bufferedWriter holds the status and body of a response until commit.
+// Headers are written to the underlying ResponseWriter directly, since
+// they are not sent before WriteHeader.
+type bufferedWriter struct {
+	http.ResponseWriter
+
+	buf       bytes.Buffer
+	status    int
+	committed bool
+}
+
+// WriteHeader records the status code. Like net/http, the first call wins.
+func (b *bufferedWriter) WriteHeader(status int) {
+	if b.status == 0 {
+		b.status = status
+	}
+}
+
+// Write appends to the buffer.
+func (b *bufferedWriter) Write(p []byte) (int, error) {
+	if b.status == 0 {
+		b.status = http.StatusOK
+	}
+
+	return b.buf.Write(p)
+}
+
+// reset discards the buffered status and body.
+func (b *bufferedWriter) reset() {
+	b.buf.Reset()
+	b.status = 0
+}
+
+// commit sends the buffered response, answering conditional GET/HEAD
+// requests with 304 Not Modified when the ETag matches.
+func (b *bufferedWriter) commit(req *http.Request) error {
+	b.committed = true
+
+	status := b.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	header := b.ResponseWriter.Header()
+
+	if status == http.StatusOK && (req.Method == http.MethodGet || req.Method == http.MethodHead) {
+		etag := header.Get("ETag")
+		if etag == "" {
+			sum := sha256.Sum256(b.buf.Bytes())
+			etag = `"` + hex.EncodeToString(sum[:16]) + `"`
+			header.Set("ETag", etag)
+		}
+
+		if etagMatches(req.Header.Get("If-None-Match"), etag) {
+			header.Del("Content-Type")
+			header.Del("Content-Length")
+			b.ResponseWriter.WriteHeader(http.StatusNotModified)
+
+			return nil
+		}
+	}
+
+	header.Set("Content-Length", strconv.Itoa(b.buf.Len()))
+	b.ResponseWriter.WriteHeader(status)
+
+	_, err := b.ResponseWriter.Write(b.buf.Bytes())
+
+	return err
+}
+
+// etagMatches reports whether an If-None-Match header matches etag,
+// using the weak comparison RFC 9110 requires for If-None-Match.
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+
+	etag = strings.TrimPrefix(etag, "W/")
+
+	for candidate := range strings.SplitSeq(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,200 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/a-h/templ"
+)
+
+// failingComponent writes part of a page and then fails.
+var failingComponent = templ.ComponentFunc(func(_ context.Context, w io.Writer) error {
+	if _, err := io.WriteString(w, "<h1>half a page"); err != nil {
+		return err
+	}
+
+	return errors.New("template exploded")
+})
+
+func TestBufferedRenderErrorUsesErrorPage(t *testing.T) {
+	r := New(WithBuffering())
+
+	r.SetErrorPage(http.StatusInternalServerError, func(ctx *PageContext) (templ.Component, error) {
+		return templ.Raw("Custom 500"), nil
+	})
+
+	r.Get("/broken", func(ctx *PageContext) (templ.Component, error) {
+		return failingComponent, nil
+	})
+
+	req := httptest.NewRequest(MethodGet, "/broken", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status 500, got %d", w.Code)
+	}
+
+	if w.Body.String() != "Custom 500" {
+		t.Errorf("Expected 'Custom 500', got '%s'", w.Body.String())
+	}
+}
+
+func TestBufferedRenderErrorUsesErrorHandler(t *testing.T) {
+	var handled error
+
+	r := New(WithErrorHandler(func(ctx *PageContext, err error) templ.Component {
+		handled = err
+		ctx.ResponseWriter.WriteHeader(http.StatusServiceUnavailable)
+
+		return templ.Raw("unavailable")
+	}))
+
+	r.Get("/broken", func(ctx *PageContext) (templ.Component, error) {
+		return failingComponent, nil
+	}).WithBuffering()
+
+	req := httptest.NewRequest(MethodGet, "/broken", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if handled == nil || handled.Error() != "template exploded" {
+		t.Errorf("Expected error handler to receive render error, got %v", handled)
+	}
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status 503, got %d", w.Code)
+	}
+
+	if w.Body.String() != "unavailable" {
+		t.Errorf("Expected 'unavailable', got '%s'", w.Body.String())
+	}
+}
+
+func TestBufferedPanicSendsErrorPage(t *testing.T) {
+	r := New()
+
+	r.Get("/panics", func(ctx *PageContext) (templ.Component, error) {
+		return templ.ComponentFunc(func(_ context.Context, w io.Writer) error {
+			_, _ = io.WriteString(w, "<h1>half a page")
+			panic("render exploded")
+		}), nil
+	}).WithBuffering()
+
+	req := httptest.NewRequest(MethodGet, "/panics", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status 500, got %d", w.Code)
+	}
+
+	if body := w.Body.String(); body != "<h1>Internal Server Error</h1><p>An unexpected error occurred.</p>" {
+		t.Errorf("Expected the error page alone, got '%s'", body)
+	}
+}
+
+func TestBufferedETagAndContentLength(t *testing.T) {
+	r := New()
+
+	r.Get("/page", func(ctx *PageContext) (templ.Component, error) {
+		return templ.Raw("<p>hello</p>"), nil
+	}).WithBuffering()
+
+	req := httptest.NewRequest(MethodGet, "/page", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	if got := w.Header().Get("Content-Length"); got != strconv.Itoa(len("<p>hello</p>")) {
+		t.Errorf("Expected Content-Length %d, got %s", len("<p>hello</p>"), got)
+	}
+
+	etag := w.Header().Get("ETag")
+	if len(etag) < 3 || etag[0] != '"' {
+		t.Fatalf("Expected strong ETag, got %q", etag)
+	}
+
+	// Same content, matching If-None-Match
+	req = httptest.NewRequest(MethodGet, "/page", nil)
+	req.Header.Set("If-None-Match", `"other", `+etag)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotModified {
+		t.Errorf("Expected status 304, got %d", w.Code)
+	}
+
+	if w.Body.Len() != 0 {
+		t.Errorf("Expected empty body for 304, got '%s'", w.Body.String())
+	}
+
+	if w.Header().Get("ETag") != etag {
+		t.Errorf("Expected ETag %s on 304, got %s", etag, w.Header().Get("ETag"))
+	}
+}
+
+func TestBufferedNoETagForErrors(t *testing.T) {
+	r := New(WithBuffering())
+
+	req := httptest.NewRequest(MethodGet, "/missing", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404, got %d", w.Code)
+	}
+
+	if w.Header().Get("ETag") != "" {
+		t.Error("Expected no ETag for 404 response")
+	}
+
+	if w.Header().Get("Content-Length") != strconv.Itoa(w.Body.Len()) {
+		t.Error("Expected Content-Length on buffered 404 response")
+	}
+}
+
+func TestUnbufferedRouteHasNoETag(t *testing.T) {
+	r := New()
+
+	r.Get("/page", func(ctx *PageContext) (templ.Component, error) {
+		return templ.Raw("<p>hello</p>"), nil
+	})
+
+	req := httptest.NewRequest(MethodGet, "/page", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Header().Get("ETag") != "" {
+		t.Error("Expected no ETag without buffering")
+	}
+}
+
+func TestETagMatches(t *testing.T) {
+	tests := []struct {
+		header string
+		etag   string
+		want   bool
+	}{
+		{"", `"abc"`, false},
+		{`"abc"`, `"abc"`, true},
+		{`W/"abc"`, `"abc"`, true},
+		{`"x", "abc"`, `"abc"`, true},
+		{`*`, `"abc"`, true},
+		{`"x"`, `"abc"`, false},
+	}
+
+	for _, tt := range tests {
+		if got := etagMatches(tt.header, tt.etag); got != tt.want {
+			t.Errorf("etagMatches(%q, %q) = %v, want %v", tt.header, tt.etag, got, tt.want)
+		}
+	}
+}
@@ -4,8 +4,33 @@ import (
 	"context"
 	"net/http"
 	"strconv"
+
+	"github.com/xraph/forgeui/logging"
 )
 
+// contextKey is the type for router values stored in a context.Context
+type contextKey string
+
+const pageContextKey contextKey = "forgeui_page_context"
+
+// withPageContext stores the PageContext in a context.Context
+func withPageContext(ctx context.Context, pc *PageContext) context.Context {
+	return context.WithValue(ctx, pageContextKey, pc)
+}
+
+// PageContextFromContext retrieves the PageContext from a context.Context.
+// Loaders use this to read values set by middleware:
+//
+//	func loader(ctx context.Context, params router.Params) (any, error) {
+//	    pc, _ := router.PageContextFromContext(ctx)
+//	    user, _ := pc.Get("user")
+//	    ...
+//	}
+func PageContextFromContext(ctx context.Context) (*PageContext, bool) {
+	pc, ok := ctx.Value(pageContextKey).(*PageContext)
+	return pc, ok
+}
+
 // PageContext wraps the HTTP request and response with additional utilities
 type PageContext struct {
 	ResponseWriter http.ResponseWriter
@@ -13,7 +38,11 @@ type PageContext struct {
 	Params         Params
 	values         map[string]any
 	LoadedData     any
+	layoutData     map[string]any
+	state          *requestState
 	Meta           *RouteMeta
+	route          *Route
+	router         *Router
 	app            any // Reference to App (interface to avoid circular dependency)
 }
 
@@ -201,6 +230,79 @@ func (c *PageContext) LoaderData() any {
 	return c.LoadedData
 }
 
+// LayoutData returns the data loaded by the named layout's loader
+// (see WithLayoutLoader). Returns nil if the layout has no loader.
+func (c *PageContext) LayoutData(layout string) any {
+	if c.layoutData == nil {
+		return nil
+	}
+
+	return c.layoutData[layout]
+}
+
+// setLayoutData stores the data loaded for a layout
+func (c *PageContext) setLayoutData(layout string, data any) {
+	if c.layoutData == nil {
+		c.layoutData = make(map[string]any)
+	}
+
+	c.layoutData[layout] = data
+}
+
+// setLayoutError records the failed loader of a layout with an error
+// boundary, so the layout renders its fallback (see WithErrorBoundary)
+func (c *PageContext) setLayoutError(layout string, err error) {
+	if c.state == nil {
+		c.state = &requestState{}
+	}
+
+	if c.state.layoutErrors == nil {
+		c.state.layoutErrors = make(map[string]error)
+	}
+
+	c.state.layoutErrors[layout] = err
+}
+
+// layoutError returns the loader error recorded for a layout
+func (c *PageContext) layoutError(layout string) error {
+	if c.state == nil {
+		return nil
+	}
+
+	return c.state.layoutErrors[layout]
+}
+
+// requestState holds per-request rendering flags. Like layoutData, it is
+// shared by the shallow copies middleware makes with WithContext.
+type requestState struct {
+	skipLayout   bool
+	layoutErrors map[string]error // failed loaders of boundary layouts
+
+	recorder *logging.StatusRecorder // the response as sent, for access logs
+	finish   []func(status int)      // see onFinish
+}
+
+// SkipLayout renders the handler's component without the layout chain,
+// for example to answer an HTMX request with a fragment.
+func (c *PageContext) SkipLayout() {
+	if c.state == nil {
+		c.state = &requestState{}
+	}
+
+	c.state.skipLayout = true
+}
+
+// layoutSkipped reports whether SkipLayout was called.
+func (c *PageContext) layoutSkipped() bool {
+	return c.state != nil && c.state.skipLayout
+}
+
+// Route returns the matched route, or nil for not-found and
+// method-not-allowed pages
+func (c *PageContext) Route() *Route {
+	return c.route
+}
+
 // GetMeta returns the route's metadata
 func (c *PageContext) GetMeta() *RouteMeta {
 	return c.Meta
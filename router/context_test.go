@@ -4,6 +4,8 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+
+	"github.com/a-h/templ"
 )
 
 func TestPageContext_Param(t *testing.T) {
@@ -311,3 +313,52 @@ func TestPageContext_ClientIP(t *testing.T) {
 		})
 	}
 }
+
+func TestPageContext_FieldErrors(t *testing.T) {
+	ctx := &PageContext{}
+
+	if ctx.FieldErrors() != nil {
+		t.Error("Expected no field errors")
+	}
+
+	ctx.SetFieldErrors(FieldErrors{"email": "is required", "name": "too short"})
+
+	if ctx.FieldError("email") != "is required" {
+		t.Errorf("Expected email error, got %q", ctx.FieldError("email"))
+	}
+
+	if ctx.FieldError("age") != "" {
+		t.Errorf("Expected no age error, got %q", ctx.FieldError("age"))
+	}
+
+	if got := ctx.FieldErrors().Error(); got != "invalid form: email: is required, name: too short" {
+		t.Errorf("Unexpected Error(): %q", got)
+	}
+}
+
+func TestPageContext_SkipLayout(t *testing.T) {
+	r := New()
+	r.RegisterLayout("main", func(ctx *PageContext, content templ.Component) templ.Component {
+		return templ.Raw("layout")
+	})
+	r.SetDefaultLayout("main")
+
+	// Middleware copies the context; the flag must survive the copy
+	r.Use(func(next PageHandler) PageHandler {
+		return func(ctx *PageContext) (templ.Component, error) {
+			return next(ctx.WithContext(ctx.Context()))
+		}
+	})
+
+	r.Get("/fragment", func(ctx *PageContext) (templ.Component, error) {
+		ctx.SkipLayout()
+		return templ.Raw("fragment"), nil
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/fragment", nil))
+
+	if w.Body.String() != "fragment" {
+		t.Errorf("Expected fragment without layout, got %q", w.Body.String())
+	}
+}
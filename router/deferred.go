@@ -0,0 +1,305 @@
+package router
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/a-h/templ"
+
+	"github.com/xraph/forgeui/components/skeleton"
+)
+
+// Deferred is loader data that resolves after the page starts rendering.
+//
+// A loader returns resolved values directly and slow values as Deferred.
+// The page renders them with Await: when the response can be streamed, the
+// router flushes the layout shell immediately with a fallback in place of
+// each deferred part, then streams every part as it resolves (in completion
+// order) and swaps it into its placeholder.
+//
+// Example:
+//
+//	type DashboardData struct {
+//	    User  User
+//	    Stats *router.Deferred[Stats]
+//	}
+//
+//	func loader(ctx context.Context, params router.Params) (any, error) {
+//	    user, err := loadUser(ctx)
+//	    if err != nil {
+//	        return nil, err
+//	    }
+//
+//	    return DashboardData{
+//	        User:  user,
+//	        Stats: router.Defer(ctx, loadStats),
+//	    }, nil
+//	}
+type Deferred[T any] struct {
+	done chan struct{}
+	data T
+	err  error
+}
+
+// Defer starts fn in the background and returns its future result.
+//
+// The work is bound to the request rather than the loader, so it keeps running
+// after the loader returns and is cancelled when the client disconnects.
+// It is also bounded by LoaderTimeout.
+func Defer[T any](ctx context.Context, fn func(ctx context.Context) (T, error)) *Deferred[T] {
+	parent := ctx
+	if pc, ok := PageContextFromContext(ctx); ok {
+		parent = pc.Context()
+	}
+
+	d := &Deferred[T]{done: make(chan struct{})}
+
+	go func() {
+		defer close(d.done)
+
+		workCtx, cancel := context.WithTimeout(parent, LoaderTimeout)
+		defer cancel()
+
+		d.data, d.err = fn(workCtx)
+	}()
+
+	return d
+}
+
+// Resolved returns a Deferred that is already resolved with data.
+// Useful in tests and for values that are sometimes cached.
+func Resolved[T any](data T) *Deferred[T] {
+	d := &Deferred[T]{done: make(chan struct{}), data: data}
+	close(d.done)
+
+	return d
+}
+
+// Done returns a channel that is closed once the value is resolved.
+func (d *Deferred[T]) Done() <-chan struct{} {
+	return d.done
+}
+
+// Wait blocks until the value is resolved or ctx is done.
+func (d *Deferred[T]) Wait(ctx context.Context) (T, error) {
+	select {
+	case <-d.done:
+		return d.data, d.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// MarshalJSON waits for the value and encodes it, so loader data with
+// deferred fields can be answered as JSON (see WithJSON). A failed value
+// encodes as null.
+func (d *Deferred[T]) MarshalJSON() ([]byte, error) {
+	<-d.done
+
+	if d.err != nil {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(d.data)
+}
+
+// Await renders a deferred value.
+//
+// While streaming, it writes fallback (a skeleton when nil) in a placeholder
+// and streams content(data, err) once d resolves. Otherwise, for example when
+// the response is buffered, it waits for d and renders content inline.
+func Await[T any](d *Deferred[T], fallback templ.Component, content func(data T, err error) templ.Component) templ.Component {
+	if fallback == nil {
+		fallback = skeleton.Skeleton(skeleton.Props{Class: "h-24 w-full"})
+	}
+
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		render := func(ctx context.Context) templ.Component {
+			data, err := d.Wait(ctx)
+			return content(data, err)
+		}
+
+		s, ok := ctx.Value(streamKey).(*stream)
+		if !ok {
+			return render(ctx).Render(ctx, w)
+		}
+
+		id := s.add(d.done, render)
+
+		if _, err := fmt.Fprintf(w, `<div id="%s" data-forgeui-deferred style="display:contents">`, id); err != nil {
+			return err
+		}
+
+		if err := fallback.Render(ctx, w); err != nil {
+			return err
+		}
+
+		_, err := io.WriteString(w, `</div>`)
+
+		return err
+	})
+}
+
+const streamKey contextKey = "forgeui_stream"
+
+// deferredRuntime swaps a streamed <template> into its placeholder.
+// It is written once, before the first streamed chunk.
+const deferredRuntime = `<script>window.__forgeuiDeferred=function(id){` +
+	`var t=document.getElementById(id+"-content"),p=document.getElementById(id);` +
+	`if(t&&p){var parent=p.parentNode;p.replaceWith(t.content);if(window.htmx){htmx.process(parent)}}` +
+	`if(t){t.remove()}};</script>`
+
+// stream collects the deferred parts of a page while it renders and writes
+// them as out-of-order chunks once the shell has been flushed.
+type stream struct {
+	ctx     context.Context
+	w       io.Writer
+	flusher http.Flusher
+	htmx    bool
+
+	mu          sync.Mutex
+	next        int
+	outstanding int
+	ready       chan *streamChunk
+	wroteScript bool
+}
+
+// streamChunk is a deferred part waiting to be streamed.
+type streamChunk struct {
+	id     string
+	render func(ctx context.Context) templ.Component
+}
+
+// newStream returns a stream for w, or nil if w cannot be flushed.
+func newStream(ctx context.Context, w http.ResponseWriter, htmx bool) *stream {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return nil
+	}
+
+	return &stream{
+		ctx:     ctx,
+		w:       w,
+		flusher: flusher,
+		htmx:    htmx,
+		ready:   make(chan *streamChunk),
+	}
+}
+
+// add registers a deferred part and returns its placeholder ID.
+func (s *stream) add(done <-chan struct{}, render func(ctx context.Context) templ.Component) string {
+	s.mu.Lock()
+	s.next++
+	s.outstanding++
+	chunk := &streamChunk{id: fmt.Sprintf("forgeui-deferred-%d", s.next), render: render}
+	s.mu.Unlock()
+
+	go func() {
+		select {
+		case <-done:
+			select {
+			case s.ready <- chunk:
+			case <-s.ctx.Done():
+			}
+		case <-s.ctx.Done():
+		}
+	}()
+
+	return chunk.id
+}
+
+// pending returns the number of deferred parts not yet streamed.
+func (s *stream) pending() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.outstanding
+}
+
+// finish flushes the shell and streams every deferred part as it resolves.
+// It returns early with the context error when the client disconnects.
+func (s *stream) finish() error {
+	if s.pending() == 0 {
+		return nil
+	}
+
+	s.flusher.Flush()
+
+	for s.pending() > 0 {
+		select {
+		case chunk := <-s.ready:
+			// Render before writing so a failing part can't corrupt the page.
+			// Nested Await calls register further chunks on this stream.
+			var buf bytes.Buffer
+
+			err := chunk.render(s.ctx).Render(s.renderContext(), &buf)
+
+			s.mu.Lock()
+			s.outstanding--
+			s.mu.Unlock()
+
+			if err != nil {
+				return err
+			}
+
+			if err := s.writeChunk(chunk.id, buf.Bytes()); err != nil {
+				return err
+			}
+
+			s.flusher.Flush()
+		case <-s.ctx.Done():
+			return s.ctx.Err()
+		}
+	}
+
+	return nil
+}
+
+// renderContext returns the context deferred parts render with.
+func (s *stream) renderContext() context.Context {
+	return context.WithValue(s.ctx, streamKey, s)
+}
+
+// writeChunk writes a resolved part. HTMX requests get an out-of-band swap,
+// which htmx applies natively; other requests get a template and a script.
+func (s *stream) writeChunk(id string, content []byte) error {
+	if s.htmx {
+		if _, err := fmt.Fprintf(s.w, `<div id="%s" hx-swap-oob="true" style="display:contents">`, id); err != nil {
+			return err
+		}
+
+		if _, err := s.w.Write(content); err != nil {
+			return err
+		}
+
+		_, err := io.WriteString(s.w, `</div>`)
+
+		return err
+	}
+
+	if !s.wroteScript {
+		if _, err := io.WriteString(s.w, deferredRuntime); err != nil {
+			return err
+		}
+
+		s.wroteScript = true
+	}
+
+	if _, err := fmt.Fprintf(s.w, `<template id="%s-content">`, id); err != nil {
+		return err
+	}
+
+	if _, err := s.w.Write(content); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintf(s.w, `</template><script>window.__forgeuiDeferred(%q)</script>`, id)
+
+	return err
+}
@@ -0,0 +1,190 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/a-h/templ"
+)
+
+// flushRecorder signals on the first flush so deferred data can resolve
+// only after the shell was sent.
+type flushRecorder struct {
+	*httptest.ResponseRecorder
+
+	flushed chan struct{}
+	shell   string
+}
+
+func (f *flushRecorder) Flush() {
+	if f.shell == "" {
+		f.shell = f.Body.String()
+		close(f.flushed)
+	}
+
+	f.ResponseRecorder.Flush()
+}
+
+// plainWriter is a ResponseWriter that cannot be flushed.
+type plainWriter struct {
+	http.ResponseWriter
+}
+
+func deferredRoute(r *Router, release <-chan struct{}) {
+	r.Get("/dashboard", func(ctx *PageContext) (templ.Component, error) {
+		stats := ctx.LoaderData().(*Deferred[string])
+
+		return templ.Join(
+			templ.Raw("<h1>Dashboard</h1>"),
+			Await(stats, templ.Raw("loading"), func(data string, err error) templ.Component {
+				return templ.Raw("<p>" + data + "</p>")
+			}),
+		), nil
+	}).Loader(func(ctx context.Context, params Params) (any, error) {
+		return Defer(ctx, func(ctx context.Context) (string, error) {
+			select {
+			case <-release:
+				return "42 users", nil
+			case <-ctx.Done():
+				return "", ctx.Err()
+			}
+		}), nil
+	})
+}
+
+func TestDeferredStreaming(t *testing.T) {
+	r := New()
+
+	w := &flushRecorder{ResponseRecorder: httptest.NewRecorder(), flushed: make(chan struct{})}
+	deferredRoute(r, w.flushed)
+
+	r.ServeHTTP(w, httptest.NewRequest(MethodGet, "/dashboard", nil))
+
+	if !strings.Contains(w.shell, `<div id="forgeui-deferred-1" data-forgeui-deferred style="display:contents">loading</div>`) {
+		t.Errorf("Expected shell with fallback placeholder, got %s", w.shell)
+	}
+
+	if strings.Contains(w.shell, "42 users") {
+		t.Error("Expected deferred data to stream after the shell")
+	}
+
+	body := w.Body.String()
+	if !strings.Contains(body, `<template id="forgeui-deferred-1-content"><p>42 users</p></template>`) {
+		t.Errorf("Expected streamed chunk, got %s", body)
+	}
+
+	if !strings.Contains(body, `window.__forgeuiDeferred("forgeui-deferred-1")`) {
+		t.Errorf("Expected swap script, got %s", body)
+	}
+}
+
+func TestDeferredStreamingHTMX(t *testing.T) {
+	r := New()
+
+	w := &flushRecorder{ResponseRecorder: httptest.NewRecorder(), flushed: make(chan struct{})}
+	deferredRoute(r, w.flushed)
+
+	req := httptest.NewRequest(MethodGet, "/dashboard", nil)
+	req.Header.Set("HX-Request", "true")
+	r.ServeHTTP(w, req)
+
+	body := w.Body.String()
+	if !strings.Contains(body, `<div id="forgeui-deferred-1" hx-swap-oob="true" style="display:contents"><p>42 users</p></div>`) {
+		t.Errorf("Expected out-of-band swap chunk, got %s", body)
+	}
+
+	if strings.Contains(body, "<template") {
+		t.Error("Expected no template chunk for HTMX requests")
+	}
+}
+
+func TestDeferredInlineWithoutFlusher(t *testing.T) {
+	r := New()
+
+	release := make(chan struct{})
+	close(release)
+	deferredRoute(r, release)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(plainWriter{rec}, httptest.NewRequest(MethodGet, "/dashboard", nil))
+
+	if rec.Body.String() != "<h1>Dashboard</h1><p>42 users</p>" {
+		t.Errorf("Expected deferred data rendered inline, got %s", rec.Body.String())
+	}
+}
+
+func TestDeferredCancelledOnDisconnect(t *testing.T) {
+	r := New()
+
+	cancelled := make(chan struct{})
+
+	r.Get("/slow", func(ctx *PageContext) (templ.Component, error) {
+		return Await(ctx.LoaderData().(*Deferred[string]), nil, func(data string, err error) templ.Component {
+			return templ.Raw(data)
+		}), nil
+	}).Loader(func(ctx context.Context, params Params) (any, error) {
+		return Defer(ctx, func(ctx context.Context) (string, error) {
+			<-ctx.Done()
+			close(cancelled)
+
+			return "", ctx.Err()
+		}), nil
+	})
+
+	reqCtx, disconnect := context.WithCancel(context.Background())
+	w := &flushRecorder{ResponseRecorder: httptest.NewRecorder(), flushed: make(chan struct{})}
+
+	go func() {
+		<-w.flushed
+		disconnect()
+	}()
+
+	done := make(chan struct{})
+
+	go func() {
+		r.ServeHTTP(w, httptest.NewRequest(MethodGet, "/slow", nil).WithContext(reqCtx))
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected ServeHTTP to return after client disconnect")
+	}
+
+	select {
+	case <-cancelled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected deferred work to be cancelled")
+	}
+
+	if !strings.Contains(w.shell, "animate-pulse") {
+		t.Errorf("Expected skeleton fallback, got %s", w.shell)
+	}
+}
+
+func TestDeferredWait(t *testing.T) {
+	d := Resolved(7)
+
+	v, err := d.Wait(context.Background())
+	if err != nil || v != 7 {
+		t.Errorf("Expected 7, got %v (%v)", v, err)
+	}
+
+	pending := Defer(context.Background(), func(ctx context.Context) (int, error) {
+		<-ctx.Done()
+		return 0, ctx.Err()
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := pending.Wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
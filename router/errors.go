@@ -3,6 +3,7 @@ package router
 import (
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/a-h/templ"
 )
@@ -55,6 +56,78 @@ func (r *Router) getErrorPage(status int) PageHandler {
 	return DefaultErrorPage(status)
 }
 
+// errorPage renders the error page for status of the group nearest to the
+// request (see Group.ErrorPage) within the group's layouts, or fallback
+// when no group has one or it fails.
+func (r *Router) errorPage(ctx *PageContext, status int, fallback PageHandler) (templ.Component, error) {
+	handler, g := r.groupErrorPage(ctx, status)
+	if handler == nil {
+		return fallback(ctx)
+	}
+
+	ctx.ResponseWriter.WriteHeader(status)
+
+	layouts := r.layoutChain(r.resolveLayout(g.layout))
+
+	comp, err := r.withLoaders(nil, layouts, handler)(ctx)
+	if err != nil {
+		return fallback(ctx)
+	}
+
+	if comp != nil && !ctx.layoutSkipped() {
+		comp = r.applyLayouts(ctx, comp, layouts)
+	}
+
+	return comp, nil
+}
+
+// groupErrorPage returns the error page for status of the group nearest to
+// the request, and that group: the route's group or, for unmatched paths,
+// the group whose prefix is the longest prefix of the path, then their
+// parents.
+func (r *Router) groupErrorPage(ctx *PageContext, status int) (PageHandler, *Group) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if len(r.errorGroups) == 0 {
+		return nil, nil
+	}
+
+	var g *Group
+	if ctx.route != nil && ctx.route.group != nil {
+		g = ctx.route.group
+	} else if ctx.Request != nil {
+		g = r.prefixGroup(ctx.Request.URL.Path)
+	}
+
+	for ; g != nil; g = g.parent {
+		if handler, ok := g.errorPages[status]; ok {
+			return handler, g
+		}
+	}
+
+	return nil, nil
+}
+
+// prefixGroup returns the group with error pages whose prefix is the
+// longest prefix of path. The caller must hold r.mu.
+func (r *Router) prefixGroup(path string) *Group {
+	var nearest *Group
+
+	for _, g := range r.errorGroups {
+		prefix := r.basePath + g.prefix
+		if path != prefix && !strings.HasPrefix(path, strings.TrimSuffix(prefix, "/")+"/") {
+			continue
+		}
+
+		if nearest == nil || len(g.prefix) > len(nearest.prefix) {
+			nearest = g
+		}
+	}
+
+	return nearest
+}
+
 // DefaultErrorPage returns a default error page for the given status code
 func DefaultErrorPage(status int) PageHandler {
 	return func(ctx *PageContext) (templ.Component, error) {
@@ -75,6 +148,9 @@ func DefaultErrorPage(status int) PageHandler {
 		case http.StatusInternalServerError:
 			title = "500 - Internal Server Error"
 			message = "Something went wrong on our end."
+		case http.StatusMethodNotAllowed:
+			title = "405 - Method Not Allowed"
+			message = "This page doesn't support the request method."
 		case http.StatusRequestTimeout:
 			title = "408 - Request Timeout"
 			message = "The request took too long to complete."
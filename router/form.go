@@ -0,0 +1,52 @@
+package router
+
+import (
+	"sort"
+	"strings"
+)
+
+// FieldErrors maps form field names to validation messages.
+// It implements error, so form actions can return it to report invalid input.
+type FieldErrors map[string]string
+
+// Error implements the error interface
+func (e FieldErrors) Error() string {
+	fields := make([]string, 0, len(e))
+	for field := range e {
+		fields = append(fields, field)
+	}
+
+	sort.Strings(fields)
+
+	parts := make([]string, 0, len(fields))
+	for _, field := range fields {
+		parts = append(parts, field+": "+e[field])
+	}
+
+	return "invalid form: " + strings.Join(parts, ", ")
+}
+
+const fieldErrorsKey = "forgeui.fieldErrors"
+
+// SetFieldErrors stores the field errors of a failed form submission,
+// for the page to render next to its inputs.
+func (c *PageContext) SetFieldErrors(errs FieldErrors) {
+	c.Set(fieldErrorsKey, errs)
+}
+
+// FieldErrors returns the field errors of a failed form submission,
+// or nil when the request isn't a failed submission.
+func (c *PageContext) FieldErrors() FieldErrors {
+	if val, ok := c.Get(fieldErrorsKey); ok {
+		if errs, ok := val.(FieldErrors); ok {
+			return errs
+		}
+	}
+
+	return nil
+}
+
+// FieldError returns the error for a single form field, or "" if it is valid.
+func (c *PageContext) FieldError(name string) string {
+	return c.FieldErrors()[name]
+}
@@ -4,9 +4,12 @@ package router
 // Renamed from RouteGroup for cleaner API
 type Group struct {
 	router     *Router
+	parent     *Group
 	prefix     string
 	layout     string
 	middleware []Middleware
+	locales    []string
+	errorPages map[int]PageHandler
 }
 
 // RouteGroup is an alias for Group (backward compatibility)
@@ -56,6 +59,24 @@ func (g *Group) Middleware(middleware ...Middleware) *Group {
 	return g
 }
 
+// ErrorPage registers an error page for a status code within this group.
+// It is used instead of the router's (see Router.SetErrorPage) for the
+// group's routes, nested groups without their own, and unmatched paths
+// under the group's prefix, and renders within the group's layout.
+func (g *Group) ErrorPage(status int, handler PageHandler) *Group {
+	g.router.mu.Lock()
+	defer g.router.mu.Unlock()
+
+	if g.errorPages == nil {
+		g.errorPages = make(map[int]PageHandler)
+		g.router.errorGroups = append(g.router.errorGroups, g)
+	}
+
+	g.errorPages[status] = handler
+
+	return g
+}
+
 // Get registers a GET route in the group
 func (g *Group) Get(pattern string, handler PageHandler) *Route {
 	route := g.router.Get(g.prefix+pattern, handler)
@@ -125,9 +146,11 @@ func (g *Group) Page(pattern string) *GroupPageBuilder {
 func (g *Group) Group(prefix string, opts ...GroupOption) *Group {
 	nested := &Group{
 		router:     g.router,
+		parent:     g,
 		prefix:     g.prefix + prefix,
 		layout:     g.layout, // Inherit parent layout
 		middleware: make([]Middleware, len(g.middleware)),
+		locales:    g.locales,
 	}
 
 	// Copy parent middleware
@@ -143,6 +166,8 @@ func (g *Group) Group(prefix string, opts ...GroupOption) *Group {
 
 // applyGroupConfig applies group configuration to a route
 func (g *Group) applyGroupConfig(route *Route) {
+	route.group = g
+
 	// Apply group middleware (prepend to route middleware)
 	if len(g.middleware) > 0 {
 		route.Middleware = append(g.middleware, route.Middleware...)
@@ -152,4 +177,9 @@ func (g *Group) applyGroupConfig(route *Route) {
 	if g.layout != "" && route.Layout == "" {
 		route.Layout = g.layout
 	}
+
+	// Record the locales of a LocalePrefix group for alternate URLs
+	if len(g.locales) > 0 {
+		route.locales = g.locales
+	}
 }
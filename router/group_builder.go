@@ -1,5 +1,7 @@
 package router
 
+import "github.com/xraph/forgeui/auth"
+
 // GroupPageBuilder provides a fluent API for building and registering pages within a group
 type GroupPageBuilder struct {
 	group      *Group
@@ -12,6 +14,12 @@ type GroupPageBuilder struct {
 	meta       *RouteMeta
 	name       string
 	noLayout   bool
+	buffered   bool
+	params     ParamEnumerator
+	json       bool
+	partials   bool
+	fragments  []pageFragment
+	policies   []auth.Policy
 }
 
 // Handler sets the page handler function
@@ -72,6 +80,55 @@ func (gpb *GroupPageBuilder) Name(name string) *GroupPageBuilder {
 	return gpb
 }
 
+// Buffered renders this page into a buffer before sending it, so render
+// errors produce an error page and responses get an ETag and Content-Length
+func (gpb *GroupPageBuilder) Buffered() *GroupPageBuilder {
+	gpb.buffered = true
+	return gpb
+}
+
+// StaticParams sets the parameter sets this page is rendered with during
+// static export
+func (gpb *GroupPageBuilder) StaticParams(fn ParamEnumerator) *GroupPageBuilder {
+	gpb.params = fn
+	return gpb
+}
+
+// Authorize applies an auth.Policy to this page;
+// several policies must all allow a request (see Authorize)
+func (gpb *GroupPageBuilder) Authorize(policy auth.Policy) *GroupPageBuilder {
+	gpb.policies = append(gpb.policies, policy)
+	return gpb
+}
+
+// RequireRoles allows only users with at least one of roles
+// (see auth.Roles)
+func (gpb *GroupPageBuilder) RequireRoles(roles ...string) *GroupPageBuilder {
+	return gpb.Authorize(auth.Roles(roles...))
+}
+
+// JSON answers requests preferring application/json with the loader data
+// instead of the page (see router.Route.WithJSON)
+func (gpb *GroupPageBuilder) JSON() *GroupPageBuilder {
+	gpb.json = true
+	return gpb
+}
+
+// HTMXPartials renders this page without its layouts for HTMX requests
+// (see router.Route.WithHTMXPartials)
+func (gpb *GroupPageBuilder) HTMXPartials() *GroupPageBuilder {
+	gpb.partials = true
+	return gpb
+}
+
+// Fragment registers a named sub-component of this page, served for
+// ?_fragment=name or an HTMX request targeting one of targets
+// (see router.Route.WithFragment)
+func (gpb *GroupPageBuilder) Fragment(name string, handler PageHandler, targets ...string) *GroupPageBuilder {
+	gpb.fragments = append(gpb.fragments, pageFragment{name: name, handler: handler, targets: targets})
+	return gpb
+}
+
 // Method sets the HTTP method for this page
 func (gpb *GroupPageBuilder) Method(method string) *GroupPageBuilder {
 	gpb.method = method
@@ -119,11 +176,39 @@ func (gpb *GroupPageBuilder) Register() *Route {
 		route.WithMeta(gpb.meta)
 	}
 
+	// Apply authorization before the page's own middleware
+	if len(gpb.policies) > 0 {
+		route.Authorize(auth.All(gpb.policies...))
+	}
+
 	// Apply middleware if set
 	if len(gpb.middleware) > 0 {
 		route.WithMiddleware(gpb.middleware...)
 	}
 
+	// Apply buffered rendering if set
+	if gpb.buffered {
+		route.WithBuffering()
+	}
+
+	// Apply static export params if set
+	if gpb.params != nil {
+		route.StaticParams(gpb.params)
+	}
+
+	// Apply content negotiation if set
+	if gpb.json {
+		route.WithJSON()
+	}
+
+	if gpb.partials {
+		route.WithHTMXPartials()
+	}
+
+	for _, f := range gpb.fragments {
+		route.WithFragment(f.name, f.handler, f.targets...)
+	}
+
 	// Apply name if set
 	if gpb.name != "" {
 		gpb.group.router.Name(gpb.name, route)
@@ -161,3 +246,10 @@ func (gpb *GroupPageBuilder) DELETE() *GroupPageBuilder {
 	gpb.method = MethodDelete
 	return gpb
 }
+
+// pageFragment is a fragment registered with Fragment
+type pageFragment struct {
+	name    string
+	handler PageHandler
+	targets []string
+}
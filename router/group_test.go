@@ -2,6 +2,7 @@ package router
 
 import (
 	"context"
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -157,3 +158,87 @@ func TestRouteGroupInheritance(t *testing.T) {
 		t.Error("Expected X-Child header from child group")
 	}
 }
+
+// wrapLayout returns a layout wrapping content in <tag>...</tag>.
+func wrapLayout(tag string) LayoutFunc {
+	return func(ctx *PageContext, content templ.Component) templ.Component {
+		return templ.ComponentFunc(func(tCtx context.Context, w io.Writer) error {
+			if _, err := io.WriteString(w, "<"+tag+">"); err != nil {
+				return err
+			}
+
+			if err := content.Render(tCtx, w); err != nil {
+				return err
+			}
+
+			_, err := io.WriteString(w, "</"+tag+">")
+
+			return err
+		})
+	}
+}
+
+func TestGroupErrorPage(t *testing.T) {
+	r := New()
+	r.RegisterLayout("admin", wrapLayout("admin"))
+	admin := r.Group("/admin", GroupLayout("admin")).
+		ErrorPage(http.StatusNotFound, func(ctx *PageContext) (templ.Component, error) {
+			return templ.Raw("admin 404"), nil
+		}).
+		ErrorPage(http.StatusInternalServerError, func(ctx *PageContext) (templ.Component, error) {
+			return templ.Raw("admin 500"), nil
+		})
+
+	admin.Get("/missing", func(ctx *PageContext) (templ.Component, error) {
+		return templ.Raw("never"), nil
+	}).Loader(func(ctx context.Context, params Params) (any, error) {
+		return nil, &LoaderError{Status: http.StatusNotFound, Message: "gone"}
+	})
+
+	admin.Get("/broken", func(ctx *PageContext) (templ.Component, error) {
+		return nil, errors.New("boom")
+	})
+
+	billing := admin.Group("/billing").
+		ErrorPage(http.StatusNotFound, func(ctx *PageContext) (templ.Component, error) {
+			return templ.Raw("billing 404"), nil
+		})
+
+	billing.Get("/broken", func(ctx *PageContext) (templ.Component, error) {
+		return nil, errors.New("boom")
+	})
+
+	r.Get("/administrator", func(ctx *PageContext) (templ.Component, error) {
+		return nil, errors.New("boom")
+	})
+
+	tests := []struct {
+		path       string
+		wantStatus int
+		want       string
+	}{
+		{"/admin/nope", http.StatusNotFound, "<admin>admin 404</admin>"},
+		{"/admin", http.StatusNotFound, "<admin>admin 404</admin>"},
+		{"/admin/missing", http.StatusNotFound, "<admin>admin 404</admin>"},
+		{"/admin/broken", http.StatusInternalServerError, "<admin>admin 500</admin>"},
+		{"/admin/billing/nope", http.StatusNotFound, "<admin>billing 404</admin>"},
+		{"/admin/billing/broken", http.StatusInternalServerError, "<admin>admin 500</admin>"},
+		{"/nope", http.StatusNotFound, "404 Not Found"},
+		{"/administrator", http.StatusInternalServerError, "500 Internal Server Error: boom"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(MethodGet, tt.path, nil))
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+
+			if w.Body.String() != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, w.Body.String())
+			}
+		})
+	}
+}
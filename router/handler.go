@@ -20,3 +20,12 @@ func HandlerFunc(fn http.HandlerFunc) PageHandler {
 		return nil, nil
 	}
 }
+
+// ComponentHandler converts a component constructor that never fails, such as
+// a templ page declared as `templ AboutPage(ctx *router.PageContext)`, to a
+// PageHandler.
+func ComponentHandler(fn func(*PageContext) templ.Component) PageHandler {
+	return func(ctx *PageContext) (templ.Component, error) {
+		return fn(ctx), nil
+	}
+}
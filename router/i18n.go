@@ -0,0 +1,133 @@
+package router
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/a-h/templ"
+
+	"github.com/xraph/forgeui/i18n"
+)
+
+// LocaleParam is the route parameter LocalePrefix adds to a group's routes.
+const LocaleParam = "locale"
+
+// LocalePrefix prefixes every route in the group with a locale segment
+// restricted to the given locales, such as /de/about. The first locale is
+// the default, used for the x-default alternate.
+//
+//	pages := r.Group("", router.LocalePrefix("en", "de", "fr"))
+//	pages.Get("/about", about) // matches /en/about, /de/about, /fr/about
+//
+// With i18n middleware (router.I18n or forgeui.WithI18n) the locale segment
+// also selects the request's locale.
+func LocalePrefix(locales ...string) GroupOption {
+	return func(g *Group) {
+		if len(locales) == 0 {
+			return
+		}
+
+		quoted := make([]string, len(locales))
+		for i, l := range locales {
+			quoted[i] = regexp.QuoteMeta(l)
+		}
+
+		g.prefix = "/{" + LocaleParam + ":" + strings.Join(quoted, "|") + "}" + g.prefix
+		g.locales = locales
+	}
+}
+
+// Locale returns the route's locale parameter, for routes in a LocalePrefix
+// group, or else the request's locale detected by i18n middleware.
+func (c *PageContext) Locale() string {
+	if locale := c.Param(LocaleParam); locale != "" {
+		return locale
+	}
+
+	return i18n.Locale(c.Context())
+}
+
+// T translates a message in the request's locale (see i18n.T).
+func (c *PageContext) T(key string, args ...i18n.Args) string {
+	return i18n.T(c.Context(), key, args...)
+}
+
+// Alternates returns the page's versions in other languages: the route's
+// Meta alternates, plus, for routes in a LocalePrefix group, the same URL in
+// each of the group's locales and an x-default for the first one.
+func (c *PageContext) Alternates() []Alternate {
+	var alternates []Alternate
+
+	if c.Meta != nil {
+		alternates = append(alternates, c.Meta.Alternates...)
+	}
+
+	if c.route == nil || len(c.route.locales) == 0 {
+		return alternates
+	}
+
+	params := make(map[string]any, len(c.Params))
+	for k, v := range c.Params {
+		params[k] = v
+	}
+
+	for i, locale := range c.route.locales {
+		params[LocaleParam] = locale
+
+		u, err := c.route.BuildURLMap(params)
+		if err != nil {
+			continue
+		}
+
+		alternates = append(alternates, Alternate{Locale: locale, URL: u})
+
+		if i == 0 {
+			alternates = append(alternates, Alternate{Locale: "x-default", URL: u})
+		}
+	}
+
+	return alternates
+}
+
+// I18n returns middleware that detects the request's locale with bundle
+// (see i18n.Bundle.Detect) and makes it available to handlers, loaders and
+// templates through PageContext.Locale, PageContext.T and i18n.T.
+// A locale already detected by i18n.Middleware is kept.
+func I18n(bundle *i18n.Bundle, opts ...i18n.Option) Middleware {
+	return func(next PageHandler) PageHandler {
+		return func(ctx *PageContext) (templ.Component, error) {
+			if i18n.FromContext(ctx.Context()) == nil {
+				detectOpts := opts
+				if ctx.router != nil {
+					detectOpts = append([]i18n.Option{i18n.WithBasePath(ctx.router.BasePath())}, opts...)
+				}
+
+				// Update the context in place so rendering sees the locale too
+				ctx.Request = i18n.Localize(ctx.ResponseWriter, ctx.Request, bundle, detectOpts...)
+				localizeMeta(ctx)
+			}
+
+			return next(ctx)
+		}
+	}
+}
+
+// localizeMeta translates the page title and description when they are
+// message keys in the request's locale.
+func localizeMeta(ctx *PageContext) {
+	if ctx.Meta == nil || i18n.FromContext(ctx.Context()) == nil {
+		return
+	}
+
+	title := i18n.Translate(ctx.Context(), ctx.Meta.Title)
+	description := i18n.Translate(ctx.Context(), ctx.Meta.Description)
+
+	if title == ctx.Meta.Title && description == ctx.Meta.Description {
+		return
+	}
+
+	meta := *ctx.Meta
+	meta.Title = title
+	meta.Description = description
+	ctx.Meta = &meta
+}
@@ -0,0 +1,206 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+
+	"github.com/xraph/forgeui/i18n"
+)
+
+func newTestBundle(t *testing.T) *i18n.Bundle {
+	t.Helper()
+
+	b := i18n.NewBundle("en")
+
+	for locale, messages := range map[string]map[string]any{
+		"en": {"about": map[string]any{"title": "About us", "heading": "Hello {name}"}},
+		"de": {"about": map[string]any{"title": "Über uns", "heading": "Hallo {name}"}},
+	} {
+		if err := b.AddMessages(locale, messages); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return b
+}
+
+func TestLocalePrefix(t *testing.T) {
+	r := New()
+	r.Use(I18n(newTestBundle(t)))
+
+	g := r.Group("/docs", LocalePrefix("en", "de"))
+	g.Get("/{page}", func(ctx *PageContext) (templ.Component, error) {
+		heading := ctx.T("about.heading", i18n.Args{"name": ctx.Param("page")})
+		return templ.Raw(ctx.Locale() + ":" + heading + ":" + ctx.Meta.Title), nil
+	}).Title("about.title")
+
+	tests := []struct {
+		path   string
+		status int
+		body   string
+	}{
+		{"/en/docs/intro", 200, "en:Hello intro:About us"},
+		{"/de/docs/intro", 200, "de:Hallo intro:Über uns"},
+		{"/fr/docs/intro", 404, ""},
+		{"/docs/intro", 404, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(MethodGet, tt.path, nil))
+
+			if w.Code != tt.status {
+				t.Fatalf("Expected status %d, got %d", tt.status, w.Code)
+			}
+
+			if tt.body != "" && w.Body.String() != tt.body {
+				t.Errorf("Expected %q, got %q", tt.body, w.Body.String())
+			}
+		})
+	}
+
+	// Route metadata itself is left untranslated
+	if title := r.Routes()[0].Metadata.Title; title != "about.title" {
+		t.Errorf("Expected the route's title to be unchanged, got %q", title)
+	}
+}
+
+func TestPageContext_LocaleWithoutMiddleware(t *testing.T) {
+	r := New()
+	r.Group("", LocalePrefix("en", "de")).Get("/", func(ctx *PageContext) (templ.Component, error) {
+		return templ.Raw(ctx.Locale() + ":" + ctx.T("about.title")), nil
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(MethodGet, "/de/", nil))
+
+	if w.Body.String() != "de:about.title" {
+		t.Errorf("Expected the locale parameter and untranslated key, got %q", w.Body.String())
+	}
+}
+
+func TestI18n_BasePath(t *testing.T) {
+	r := New(WithBasePath("/app"))
+	r.Use(I18n(newTestBundle(t)))
+	r.Group("", LocalePrefix("en", "de")).Get("/about", func(ctx *PageContext) (templ.Component, error) {
+		return templ.Raw(ctx.Locale() + ":" + ctx.T("about.title")), nil
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(MethodGet, "/app/de/about", nil))
+
+	if w.Body.String() != "de:Über uns" {
+		t.Errorf("Expected the locale of the URL prefix after the base path, got %q", w.Body.String())
+	}
+}
+
+func TestPageContext_LocalePrefersRouteParam(t *testing.T) {
+	b := newTestBundle(t)
+
+	r := New()
+	r.Group("", LocalePrefix("en", "de")).Get("/", func(ctx *PageContext) (templ.Component, error) {
+		return templ.Raw(ctx.Locale()), nil
+	})
+
+	req := httptest.NewRequest(MethodGet, "/de/", nil)
+	req = req.WithContext(i18n.NewContext(req.Context(), b.Localizer("en")))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Body.String() != "de" {
+		t.Errorf("Expected the route's locale parameter, got %q", w.Body.String())
+	}
+}
+
+func TestI18n_KeepsDetectedLocale(t *testing.T) {
+	b := newTestBundle(t)
+
+	r := New()
+	r.Use(I18n(b))
+	r.Get("/", func(ctx *PageContext) (templ.Component, error) {
+		return templ.Raw(ctx.Locale()), nil
+	})
+
+	req := httptest.NewRequest(MethodGet, "/", nil)
+	req = req.WithContext(i18n.NewContext(req.Context(), b.Localizer("de")))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Body.String() != "de" {
+		t.Errorf("Expected the locale from i18n.Middleware, got %q", w.Body.String())
+	}
+}
+
+func TestPageContext_Alternates(t *testing.T) {
+	r := New()
+
+	var got []Alternate
+
+	r.Group("/blog", LocalePrefix("en", "de")).Get("/{slug}", func(ctx *PageContext) (templ.Component, error) {
+		got = ctx.Alternates()
+		return templ.Raw("ok"), nil
+	}).Meta(RouteMeta{Alternates: []Alternate{{Locale: "fr", URL: "https://fr.example.com/blog/hello"}}})
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(MethodGet, "/de/blog/hello", nil))
+
+	want := []Alternate{
+		{Locale: "fr", URL: "https://fr.example.com/blog/hello"},
+		{Locale: "en", URL: "/en/blog/hello"},
+		{Locale: "x-default", URL: "/en/blog/hello"},
+		{Locale: "de", URL: "/de/blog/hello"},
+	}
+
+	if !slices.Equal(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestRoute_StaticURLsLocales(t *testing.T) {
+	r := New()
+	g := r.Group("", LocalePrefix("en", "de"))
+
+	about := g.Get("/about", nil)
+	posts := g.Get("/posts/{id}", nil).StaticParams(func(context.Context) ([]Params, error) {
+		return []Params{{"id": "1"}, {"id": "2", LocaleParam: "de"}}, nil
+	})
+	unlisted := g.Get("/users/{id}", nil)
+
+	tests := []struct {
+		route *Route
+		want  []string
+	}{
+		{about, []string{"/en/about", "/de/about"}},
+		{posts, []string{"/en/posts/1", "/de/posts/1", "/de/posts/2"}},
+	}
+
+	for _, tt := range tests {
+		got, err := tt.route.StaticURLs(context.Background())
+		if err != nil || !slices.Equal(got, tt.want) {
+			t.Errorf("%s: expected %v, got %v (%v)", tt.route.Pattern, tt.want, got, err)
+		}
+	}
+
+	if _, err := unlisted.StaticURLs(context.Background()); err != ErrNoStaticParams {
+		t.Errorf("Expected ErrNoStaticParams, got %v", err)
+	}
+}
+
+func TestLocalePrefix_NestedGroups(t *testing.T) {
+	r := New()
+
+	admin := r.Group("", LocalePrefix("en", "de")).Group("/admin")
+	route := admin.Get("/users", nil)
+
+	if !strings.HasPrefix(route.Pattern, fmt.Sprintf("/{%s:", LocaleParam)) || !strings.HasSuffix(route.Pattern, "/admin/users") {
+		t.Errorf("Expected nested groups to keep the locale prefix, got %s", route.Pattern)
+	}
+}
@@ -13,8 +13,10 @@ type LayoutFunc func(ctx *PageContext, content templ.Component) templ.Component
 
 // LayoutConfig holds layout configuration including parent relationship.
 type LayoutConfig struct {
-	Fn     LayoutFunc
-	Parent string
+	Fn       LayoutFunc
+	Parent   string
+	Loader   LoaderFunc
+	Fallback ErrorFallback // set by WithErrorBoundary
 }
 
 // LayoutOption configures a layout.
@@ -27,6 +29,16 @@ func WithParentLayout(parent string) LayoutOption {
 	}
 }
 
+// WithLayoutLoader sets a data loader for the layout.
+// Layout loaders run concurrently with the page loader and the loaders of the
+// other layouts in the chain. The result is available to the layout through
+// PageContext.LayoutData(name).
+func WithLayoutLoader(loader LoaderFunc) LayoutOption {
+	return func(c *LayoutConfig) {
+		c.Loader = loader
+	}
+}
+
 // RegisterLayout registers a named layout with optional parent.
 func (r *Router) RegisterLayout(name string, fn LayoutFunc, opts ...LayoutOption) {
 	r.mu.Lock()
@@ -2,7 +2,13 @@ package router
 
 import (
 	"context"
+	"errors"
+	"sync"
 	"time"
+
+	"github.com/a-h/templ"
+
+	"github.com/xraph/forgeui/tracing"
 )
 
 // LoaderFunc loads data before rendering a page
@@ -20,6 +26,11 @@ func (r *Route) Loader(fn LoaderFunc) *Route {
 // executeLoader runs the loader function with timeout support
 // This function assumes LoaderFn is not nil and should only be called when LoaderFn is set
 func (r *Route) executeLoader(ctx context.Context, params Params) (any, error) {
+	return runLoader(ctx, r.LoaderFn, params)
+}
+
+// runLoader runs a loader function with timeout support
+func runLoader(ctx context.Context, fn LoaderFunc, params Params) (any, error) {
 	// Create timeout context
 	loaderCtx, cancel := context.WithTimeout(ctx, LoaderTimeout)
 	defer cancel()
@@ -34,7 +45,7 @@ func (r *Route) executeLoader(ctx context.Context, params Params) (any, error) {
 
 	// Execute loader in goroutine
 	go func() {
-		data, err := r.LoaderFn(loaderCtx, params)
+		data, err := fn(loaderCtx, params)
 		done <- result{data, err}
 	}()
 
@@ -50,3 +61,141 @@ func (r *Route) executeLoader(ctx context.Context, params Params) (any, error) {
 		}
 	}
 }
+
+// loaderFailure marks a LoaderError returned by a page or layout loader so
+// ServeHTTP renders the registered error page instead of calling the error handler.
+type loaderFailure struct {
+	err *LoaderError
+}
+
+func (f *loaderFailure) Error() string { return f.err.Error() }
+
+func (f *loaderFailure) Unwrap() error { return f.err }
+
+// asLoaderFailure wraps LoaderErrors in a loaderFailure.
+// Other errors are returned unchanged and reach the error handler.
+func asLoaderFailure(err error) error {
+	var le *LoaderError
+	if errors.As(err, &le) {
+		return &loaderFailure{err: le}
+	}
+
+	return err
+}
+
+// withLoaders returns a PageHandler that runs the route loader and the
+// loaders of every layout in the chain concurrently before calling next.
+// route may be nil to run the layout loaders only.
+// It runs innermost in the middleware chain, so middleware can reject a
+// request before any loader executes and loaders can read PageContext values
+// set by middleware (see PageContextFromContext).
+func (r *Router) withLoaders(route *Route, layouts []string, next PageHandler) PageHandler {
+	type job struct {
+		layout   string // empty for the page loader
+		fn       LoaderFunc
+		boundary bool // the layout renders a fallback when fn fails
+	}
+
+	var jobs []job
+
+	if route != nil && route.LoaderFn != nil {
+		jobs = append(jobs, job{fn: route.LoaderFn})
+	}
+
+	r.mu.RLock()
+	for _, name := range layouts {
+		if config, ok := r.layoutConfigs[name]; ok && config.Loader != nil {
+			jobs = append(jobs, job{layout: name, fn: config.Loader, boundary: config.Fallback != nil})
+		}
+	}
+	r.mu.RUnlock()
+
+	if len(jobs) == 0 {
+		return next
+	}
+
+	return func(ctx *PageContext) (templ.Component, error) {
+		loadCtx, cancel := context.WithCancel(withPageContext(ctx.Context(), ctx))
+		defer cancel()
+
+		data := make([]any, len(jobs))
+		errs := make([]error, len(jobs))
+
+		var wg sync.WaitGroup
+
+		for i, j := range jobs {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				jobCtx, span := tracing.Start(loadCtx, "router.loader", loaderAttrs(j.layout)...)
+				defer span.End()
+
+				start := time.Now()
+				data[i], errs[i] = runLoader(jobCtx, j.fn, ctx.Params)
+				r.metrics.observeLoader(route, j.layout, time.Since(start))
+
+				if errs[i] != nil {
+					span.RecordError(errs[i])
+
+					// Stop the remaining loaders, the page can't render anyway.
+					// A boundary layout renders its fallback instead.
+					if !j.boundary {
+						cancel()
+					}
+				}
+			}()
+		}
+
+		wg.Wait()
+
+		// Report the first failure in chain order (page loader first),
+		// preferring real failures over loaders cancelled because of them
+		var cancelled error
+
+		for i, err := range errs {
+			if err == nil || jobs[i].boundary {
+				continue
+			}
+
+			if errors.Is(err, context.Canceled) && ctx.Context().Err() == nil {
+				if cancelled == nil {
+					cancelled = err
+				}
+
+				continue
+			}
+
+			return nil, asLoaderFailure(err)
+		}
+
+		if cancelled != nil {
+			return nil, asLoaderFailure(cancelled)
+		}
+
+		for i, j := range jobs {
+			if j.layout == "" {
+				ctx.LoadedData = data[i]
+			} else if errs[i] != nil {
+				ctx.setLayoutError(j.layout, errs[i])
+			} else {
+				ctx.setLayoutData(j.layout, data[i])
+			}
+		}
+
+		return next(ctx)
+	}
+}
+
+// loaderAttrs returns the span attributes of a page or layout loader.
+func loaderAttrs(layout string) []tracing.Attr {
+	if layout == "" {
+		return []tracing.Attr{tracing.String(tracing.KeyLoader, "page")}
+	}
+
+	return []tracing.Attr{
+		tracing.String(tracing.KeyLoader, "layout"),
+		tracing.String(tracing.KeyLayout, layout),
+	}
+}
@@ -3,8 +3,10 @@ package router
 import (
 	"context"
 	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
 	"time"
 
@@ -194,3 +196,134 @@ func TestNoLoader(t *testing.T) {
 		t.Errorf("Expected status 200, got %d", w.Code)
 	}
 }
+
+func TestLoaderRunsAfterMiddleware(t *testing.T) {
+	r := New()
+
+	loaderCalled := false
+
+	r.Get("/secret", func(ctx *PageContext) (templ.Component, error) {
+		return templ.Raw("secret"), nil
+	}).Loader(func(ctx context.Context, params Params) (any, error) {
+		loaderCalled = true
+		return nil, nil
+	}).WithMiddleware(BasicAuth("admin", "pass"))
+
+	req := httptest.NewRequest(MethodGet, "/secret", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401, got %d", w.Code)
+	}
+
+	if loaderCalled {
+		t.Error("Expected loader not to run for unauthenticated request")
+	}
+}
+
+func TestLoaderReadsMiddlewareValues(t *testing.T) {
+	r := New()
+
+	r.Use(func(next PageHandler) PageHandler {
+		return func(ctx *PageContext) (templ.Component, error) {
+			ctx.Set("user", "alice")
+			return next(ctx)
+		}
+	})
+
+	r.Get("/me", func(ctx *PageContext) (templ.Component, error) {
+		return templ.Raw(ctx.LoaderData().(string)), nil
+	}).Loader(func(ctx context.Context, params Params) (any, error) {
+		pc, ok := PageContextFromContext(ctx)
+		if !ok {
+			return nil, errors.New("no page context")
+		}
+
+		return "hello " + pc.GetString("user"), nil
+	})
+
+	req := httptest.NewRequest(MethodGet, "/me", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Body.String() != "hello alice" {
+		t.Errorf("Expected 'hello alice', got '%s'", w.Body.String())
+	}
+}
+
+func TestLayoutLoaders(t *testing.T) {
+	r := New()
+
+	layout := func(name string) LayoutFunc {
+		return func(ctx *PageContext, content templ.Component) templ.Component {
+			return templ.ComponentFunc(func(tCtx context.Context, w io.Writer) error {
+				if _, err := io.WriteString(w, "<"+ctx.LayoutData(name).(string)+">"); err != nil {
+					return err
+				}
+
+				return content.Render(tCtx, w)
+			})
+		}
+	}
+
+	// Each loader waits for the others, so the test only completes if they
+	// run concurrently
+	var ready sync.WaitGroup
+	ready.Add(3)
+
+	wait := func(value string) LoaderFunc {
+		return func(ctx context.Context, params Params) (any, error) {
+			ready.Done()
+			ready.Wait()
+
+			return value, nil
+		}
+	}
+
+	r.RegisterLayout("root", layout("root"), WithLayoutLoader(wait("nav")))
+	r.RegisterLayout("dashboard", layout("dashboard"), WithParentLayout("root"), WithLayoutLoader(wait("user")))
+
+	r.Get("/dashboard", func(ctx *PageContext) (templ.Component, error) {
+		return templ.Raw(ctx.LoaderData().(string)), nil
+	}).Loader(wait("page")).SetLayout("dashboard")
+
+	req := httptest.NewRequest(MethodGet, "/dashboard", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Body.String() != "<nav><user>page" {
+		t.Errorf("Expected '<nav><user>page', got '%s'", w.Body.String())
+	}
+}
+
+func TestLayoutLoaderError(t *testing.T) {
+	r := New()
+
+	r.SetErrorPage(http.StatusForbidden, func(ctx *PageContext) (templ.Component, error) {
+		ctx.ResponseWriter.WriteHeader(http.StatusForbidden)
+		return templ.Raw("Custom 403"), nil
+	})
+
+	r.RegisterLayout("admin", func(ctx *PageContext, content templ.Component) templ.Component {
+		return content
+	}, WithLayoutLoader(func(ctx context.Context, params Params) (any, error) {
+		return nil, Error403("not an admin")
+	}))
+
+	r.Get("/admin", func(ctx *PageContext) (templ.Component, error) {
+		return templ.Raw("should not reach here"), nil
+	}).SetLayout("admin")
+
+	req := httptest.NewRequest(MethodGet, "/admin", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403, got %d", w.Code)
+	}
+
+	if w.Body.String() != "Custom 403" {
+		t.Errorf("Expected 'Custom 403', got '%s'", w.Body.String())
+	}
+}
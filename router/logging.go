@@ -0,0 +1,68 @@
+package router
+
+import (
+	"log/slog"
+
+	"github.com/xraph/forgeui/logging"
+)
+
+// WithLogger sets the logger for requests that don't carry one yet
+// (see logging.Middleware). Access logs (Logger), panics and
+// PageContext.Logger use it. Default: slog.Default()
+func WithLogger(l *slog.Logger) RouterOption {
+	return func(r *Router) {
+		r.logger = l
+	}
+}
+
+// Logger returns the request's logger with the matched route added as the
+// route attribute.
+func (c *PageContext) Logger() *slog.Logger {
+	l := logging.FromContext(c.Context())
+
+	if c.route != nil {
+		l = l.With(logging.KeyRoute, c.route.label())
+	}
+
+	return l
+}
+
+// label identifies the route in logs: its name, or its pattern.
+func (r *Route) label() string {
+	if r.Name != "" {
+		return r.Name
+	}
+
+	return r.Pattern
+}
+
+// onFinish registers fn to run with the response status once the response
+// has been written. It reports false when the context isn't served by a
+// Router, in which case fn is never called.
+func (c *PageContext) onFinish(fn func(status int)) bool {
+	if c.state == nil || c.state.recorder == nil {
+		return false
+	}
+
+	c.state.finish = append(c.state.finish, fn)
+
+	return true
+}
+
+// status returns the status of the response as sent.
+func (s *requestState) status() int {
+	if status := s.recorder.Status(); status != 0 {
+		return status
+	}
+
+	return 200
+}
+
+// runFinish runs the onFinish callbacks.
+func (s *requestState) runFinish() {
+	status := s.status()
+
+	for _, fn := range s.finish {
+		fn(status)
+	}
+}
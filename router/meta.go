@@ -18,6 +18,18 @@ type RouteMeta struct {
 	OGType       string
 	CanonicalURL string
 	NoIndex      bool
+
+	// Alternates are the page's versions in other languages, rendered as
+	// hreflang links by the seo plugin. Routes in a LocalePrefix group get
+	// theirs from PageContext.Alternates.
+	Alternates []Alternate
+}
+
+// Alternate is a version of a page in another language.
+type Alternate struct {
+	// Locale is a BCP 47 language tag, or "x-default"
+	Locale string
+	URL    string
 }
 
 // Meta sets the metadata for a route
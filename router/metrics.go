@@ -0,0 +1,131 @@
+package router
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/a-h/templ"
+
+	"github.com/xraph/forgeui/metrics"
+)
+
+// WithMetrics records request, loader and render metrics in reg:
+//
+//	forgeui_http_requests_total{method,route,status}
+//	forgeui_router_loader_duration_seconds{route,loader}
+//	forgeui_router_render_duration_seconds{route}
+//	forgeui_http_rate_limited_total{route}
+//
+// The route label is the route's name, or its pattern when unnamed;
+// requests that match no route are labelled "unmatched". Methods other
+// than the standard ones are labelled "other". Loaders are labelled "page"
+// or with their layout's name.
+func WithMetrics(reg *metrics.Registry) RouterOption {
+	return func(r *Router) {
+		r.metrics = newRouterMetrics(reg)
+	}
+}
+
+// unmatchedRoute labels requests that match no route.
+const unmatchedRoute = "unmatched"
+
+type routerMetrics struct {
+	requests *metrics.Counter
+	loaders  *metrics.Histogram
+	renders  *metrics.Histogram
+	limited  *metrics.Counter
+}
+
+func newRouterMetrics(reg *metrics.Registry) *routerMetrics {
+	if reg == nil {
+		return nil
+	}
+
+	return &routerMetrics{
+		requests: reg.Counter("forgeui_http_requests_total",
+			"Page requests served, by route and response status.", "method", "route", "status"),
+		loaders: reg.Histogram("forgeui_router_loader_duration_seconds",
+			"Time spent in page and layout loaders.", nil, "route", "loader"),
+		renders: reg.Histogram("forgeui_router_render_duration_seconds",
+			"Time spent rendering pages with their layouts.", nil, "route"),
+		limited: reg.Counter("forgeui_http_rate_limited_total",
+			"Page requests rejected by RateLimit, by route.", "route"),
+	}
+}
+
+// otherMethod labels requests with non-standard methods, which clients
+// can make up to create series at will.
+const otherMethod = "other"
+
+// methodLabel returns the method label of a request.
+func methodLabel(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+		http.MethodDelete, http.MethodConnect, http.MethodOptions, http.MethodTrace:
+		return method
+	default:
+		return otherMethod
+	}
+}
+
+// routeLabel returns the route label of a request.
+func routeLabel(route *Route) string {
+	if route == nil {
+		return unmatchedRoute
+	}
+
+	return route.label()
+}
+
+// observeRequest counts the request once its response is finished.
+func (m *routerMetrics) observeRequest(ctx *PageContext) {
+	if m == nil {
+		return
+	}
+
+	method, route := methodLabel(ctx.Request.Method), routeLabel(ctx.route)
+
+	ctx.onFinish(func(status int) {
+		m.requests.Inc(method, route, strconv.Itoa(status))
+	})
+}
+
+// rateLimit counts a request rejected by RateLimit.
+func (m *routerMetrics) rateLimit(route *Route) {
+	if m == nil {
+		return
+	}
+
+	m.limited.Inc(routeLabel(route))
+}
+
+// observeLoader records how long a loader took.
+func (m *routerMetrics) observeLoader(route *Route, layout string, d time.Duration) {
+	if m == nil {
+		return
+	}
+
+	loader := layout
+	if loader == "" {
+		loader = "page"
+	}
+
+	m.loaders.Observe(d.Seconds(), routeLabel(route), loader)
+}
+
+// timed returns c recording its render time.
+func (m *routerMetrics) timed(c templ.Component, route *Route) templ.Component {
+	if m == nil {
+		return c
+	}
+
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		start := time.Now()
+		defer func() { m.renders.Observe(time.Since(start).Seconds(), routeLabel(route)) }()
+
+		return c.Render(ctx, w)
+	})
+}
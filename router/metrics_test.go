@@ -0,0 +1,89 @@
+package router
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/a-h/templ"
+
+	"github.com/xraph/forgeui/metrics"
+)
+
+func TestRouter_Metrics(t *testing.T) {
+	reg := metrics.NewRegistry()
+	r := New(WithMetrics(reg))
+
+	r.RegisterLayout("root", func(ctx *PageContext, content templ.Component) templ.Component {
+		return content
+	}, WithLayoutLoader(func(ctx context.Context, params Params) (any, error) {
+		return "nav", nil
+	}))
+
+	r.Get("/users/:id", func(ctx *PageContext) (templ.Component, error) {
+		return templ.Raw("user"), nil
+	}).Loader(func(ctx context.Context, params Params) (any, error) {
+		return params["id"], nil
+	}).SetLayout("root").WithName("user")
+
+	for _, path := range []string{"/users/1", "/users/2", "/missing"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(MethodGet, path, nil))
+	}
+
+	requests := reg.Counter("forgeui_http_requests_total", "", "method", "route", "status")
+
+	tests := []struct {
+		route  string
+		status int
+		want   float64
+	}{
+		{"user", http.StatusOK, 2},
+		{unmatchedRoute, http.StatusNotFound, 1},
+	}
+
+	for _, tt := range tests {
+		if got := requests.Value(MethodGet, tt.route, strconv.Itoa(tt.status)); got != tt.want {
+			t.Errorf("Expected %v requests for %s with status %d, got %v", tt.want, tt.route, tt.status, got)
+		}
+	}
+
+	loaders := reg.Histogram("forgeui_router_loader_duration_seconds", "", nil, "route", "loader")
+	for _, loader := range []string{"page", "root"} {
+		if loaders.Count("user", loader) != 2 {
+			t.Errorf("Expected 2 %s loader observations, got %d", loader, loaders.Count("user", loader))
+		}
+	}
+
+	renders := reg.Histogram("forgeui_router_render_duration_seconds", "", nil, "route")
+	if renders.Count("user") != 2 {
+		t.Errorf("Expected 2 render observations, got %d", renders.Count("user"))
+	}
+}
+
+func TestRouter_MetricsMethodLabel(t *testing.T) {
+	reg := metrics.NewRegistry()
+	r := New(WithMetrics(reg))
+
+	for _, method := range []string{"PURGE", "X-RANDOM-1", MethodGet} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(method, "/missing", nil))
+	}
+
+	requests := reg.Counter("forgeui_http_requests_total", "", "method", "route", "status")
+
+	tests := []struct {
+		method string
+		want   float64
+	}{
+		{otherMethod, 2},
+		{MethodGet, 1},
+		{"PURGE", 0},
+	}
+
+	for _, tt := range tests {
+		if got := requests.Value(tt.method, unmatchedRoute, strconv.Itoa(http.StatusNotFound)); got != tt.want {
+			t.Errorf("Expected %v requests with method label %s, got %v", tt.want, tt.method, got)
+		}
+	}
+}
@@ -3,20 +3,30 @@ package router
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
 	"runtime/debug"
 	"strconv"
 	"time"
 
 	"github.com/a-h/templ"
+
+	"github.com/xraph/forgeui/logging"
+	"github.com/xraph/forgeui/ratelimit"
 )
 
 // Middleware is a function that wraps a PageHandler.
 type Middleware func(PageHandler) PageHandler
 
-// Logger returns middleware that logs requests.
-func Logger() Middleware {
+// Logger returns middleware that writes an access log record for each
+// request once its response is sent, with the method, path, status,
+// duration, client IP and route. By default records go to the request's
+// logger at Info, 4xx responses at Warn and 5xx responses at Error:
+//
+//	r.Use(router.Logger(logging.WithLevel(slog.LevelDebug)))
+func Logger(opts ...logging.AccessOption) Middleware {
+	o := logging.NewAccessOptions(opts...)
+
 	return func(next PageHandler) PageHandler {
 		return func(ctx *PageContext) (templ.Component, error) {
 			start := time.Now()
@@ -24,21 +34,35 @@ func Logger() Middleware {
 			// Process request
 			comp, err := next(ctx)
 
-			// Log request
-			duration := time.Since(start)
+			logRequest := func(status int) {
+				attrs := []slog.Attr{
+					slog.String(logging.KeyMethod, ctx.Method()),
+					slog.String(logging.KeyPath, ctx.Path()),
+					slog.Int(logging.KeyStatus, status),
+					slog.Duration(logging.KeyDuration, time.Since(start)),
+					slog.String(logging.KeyClientIP, ctx.ClientIP()),
+				}
+
+				if ctx.route != nil {
+					attrs = append(attrs, slog.String(logging.KeyRoute, ctx.route.label()))
+				}
+
+				if err != nil {
+					attrs = append(attrs, slog.String(logging.KeyError, err.Error()))
+				}
 
-			status := 200
-			if err != nil {
-				status = 500
+				o.LoggerFor(ctx.Context()).LogAttrs(ctx.Context(), o.LevelFor(status), "request", attrs...)
 			}
 
-			log.Printf("[%s] %s %s - %d (%v)",
-				ctx.Method(),
-				ctx.Path(),
-				ctx.ClientIP(),
-				status,
-				duration,
-			)
+			// Log once the response is sent, with its final status
+			if !ctx.onFinish(logRequest) {
+				status := http.StatusOK
+				if err != nil {
+					status = http.StatusInternalServerError
+				}
+
+				logRequest(status)
+			}
 
 			return comp, err
 		}
@@ -52,7 +76,10 @@ func Recovery() Middleware {
 			defer func() {
 				if r := recover(); r != nil {
 					// Log panic with stack trace
-					log.Printf("PANIC: %v\n%s", r, debug.Stack())
+					ctx.Logger().Error("panic recovered",
+						"panic", fmt.Sprint(r),
+						"stack", string(debug.Stack()),
+					)
 
 					// Set error response
 					ctx.ResponseWriter.WriteHeader(http.StatusInternalServerError)
@@ -87,18 +114,27 @@ func CORS(allowOrigin string) Middleware {
 	}
 }
 
-// RequestID returns middleware that adds a unique request ID.
+// RequestID returns middleware that adds a unique request ID, reusing the
+// X-Request-ID request header when present. The ID is stored under
+// "request_id", echoed in the X-Request-ID response header and added to the
+// request's logger (see logging.RequestID).
 func RequestID() Middleware {
 	return func(next PageHandler) PageHandler {
 		return func(ctx *PageContext) (templ.Component, error) {
-			// Generate simple request ID (in production, use UUID or similar)
-			requestID := strconv.FormatInt(time.Now().UnixNano(), 10)
+			requestID := ctx.Header(logging.RequestIDHeader)
+			if requestID == "" {
+				// Generate simple request ID (in production, use UUID or similar)
+				requestID = strconv.FormatInt(time.Now().UnixNano(), 10)
+			}
 
 			// Store in context
 			ctx.Set("request_id", requestID)
 
+			// Update the request in place so rendering logs carry the ID too
+			ctx.Request = ctx.Request.WithContext(logging.WithRequestID(ctx.Context(), requestID))
+
 			// Add to response header
-			ctx.SetHeader("X-Request-ID", requestID)
+			ctx.SetHeader(logging.RequestIDHeader, requestID)
 
 			return next(ctx)
 		}
@@ -125,6 +161,40 @@ func BasicAuth(username, password string) Middleware {
 	}
 }
 
+// RateLimit returns middleware that limits requests with l, keyed by key
+// (ratelimit.ByUser when nil). Responses get RateLimit-* headers, and
+// rejected requests 429 Too Many Requests with Retry-After, without
+// layouts. Rejections are counted when the router has WithMetrics:
+//
+//	r.Use(router.RateLimit(ratelimit.NewGCRA(100, time.Minute, 20), nil))
+func RateLimit(l ratelimit.Limiter, key ratelimit.KeyFunc) Middleware {
+	if key == nil {
+		key = ratelimit.ByUser
+	}
+
+	return func(next PageHandler) PageHandler {
+		return func(ctx *PageContext) (templ.Component, error) {
+			res := ratelimit.Check(ctx.Request, l, key)
+			if res.Limit > 0 {
+				ratelimit.SetHeaders(ctx.ResponseWriter.Header(), res)
+			}
+
+			if !res.Allowed {
+				if ctx.router != nil {
+					ctx.router.metrics.rateLimit(ctx.route)
+				}
+
+				ctx.SkipLayout()
+				ctx.ResponseWriter.WriteHeader(http.StatusTooManyRequests)
+
+				return templ.Raw("429 - Too Many Requests"), nil
+			}
+
+			return next(ctx)
+		}
+	}
+}
+
 // RequireMethod returns middleware that only allows specific HTTP methods.
 func RequireMethod(methods ...string) Middleware {
 	methodMap := make(map[string]bool)
@@ -1,11 +1,22 @@
 package router
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/a-h/templ"
+
+	"github.com/xraph/forgeui/metrics"
+	"github.com/xraph/forgeui/ratelimit"
+	"github.com/xraph/forgeui/session"
 )
 
 func TestLogger(t *testing.T) {
@@ -196,6 +207,73 @@ func TestBasicAuth_Failure(t *testing.T) {
 	}
 }
 
+func TestRateLimit(t *testing.T) {
+	handler := func(ctx *PageContext) (templ.Component, error) {
+		return templ.Raw("OK"), nil
+	}
+
+	wrapped := RateLimit(ratelimit.NewSlidingWindow(2, time.Minute), ratelimit.ByIP)(handler)
+
+	tests := []struct {
+		wantStatus    int
+		wantRemaining string
+	}{
+		{http.StatusOK, "1"},
+		{http.StatusOK, "0"},
+		{http.StatusTooManyRequests, "0"},
+	}
+
+	for i, tt := range tests {
+		w := httptest.NewRecorder()
+		ctx := &PageContext{
+			ResponseWriter: w,
+			Request:        httptest.NewRequest(MethodGet, "/test", nil),
+		}
+
+		if _, err := wrapped(ctx); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+
+		if w.Code != tt.wantStatus {
+			t.Errorf("Request %d: expected status %d, got %d", i+1, tt.wantStatus, w.Code)
+		}
+
+		if got := w.Header().Get(ratelimit.HeaderRemaining); got != tt.wantRemaining {
+			t.Errorf("Request %d: expected %s remaining, got %q", i+1, tt.wantRemaining, got)
+		}
+	}
+}
+
+func TestRateLimit_Router(t *testing.T) {
+	reg := metrics.NewRegistry()
+	r := New(WithMetrics(reg))
+	r.Use(RateLimit(ratelimit.NewSlidingWindow(1, time.Minute), ratelimit.ByIP))
+
+	r.RegisterLayout("root", func(ctx *PageContext, content templ.Component) templ.Component {
+		return templ.Join(templ.Raw("<nav>"), content)
+	})
+
+	r.Get("/", func(ctx *PageContext) (templ.Component, error) {
+		return templ.Raw("home"), nil
+	}).SetLayout("root").WithName("home")
+
+	for range 2 {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(MethodGet, "/", nil))
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(MethodGet, "/", nil))
+
+	if w.Code != http.StatusTooManyRequests || strings.Contains(w.Body.String(), "<nav>") {
+		t.Errorf("Expected a 429 without the layout, got %d %q", w.Code, w.Body.String())
+	}
+
+	limited := reg.Counter("forgeui_http_rate_limited_total", "", "route")
+	if got := limited.Value("home"); got != 2 {
+		t.Errorf("Expected 2 rate limited requests, got %v", got)
+	}
+}
+
 func TestRequireMethod(t *testing.T) {
 	handler := func(ctx *PageContext) (templ.Component, error) {
 		return templ.Raw("OK"), nil
@@ -306,3 +384,167 @@ func TestChain(t *testing.T) {
 		t.Error("Expected X-M2 header from second middleware")
 	}
 }
+
+func TestSessions(t *testing.T) {
+	store := session.NewMemoryStore(time.Minute)
+
+	r := New()
+	r.Use(Sessions(store))
+
+	r.Post("/save", func(ctx *PageContext) (templ.Component, error) {
+		ctx.Session().Set("user", "jane")
+		ctx.Session().AddFlash(session.FlashSuccess, "Saved")
+
+		http.Redirect(ctx.ResponseWriter, ctx.Request, "/", http.StatusSeeOther)
+
+		return nil, nil
+	})
+
+	r.Get("/", func(ctx *PageContext) (templ.Component, error) {
+		// Flashes are read while rendering, after the session was saved
+		return templ.ComponentFunc(func(c context.Context, w io.Writer) error {
+			s := session.FromContext(c)
+
+			var msg string
+			for _, f := range s.Flashes() {
+				msg += f.Message
+			}
+
+			_, err := io.WriteString(w, s.GetString("user")+":"+msg)
+
+			return err
+		}), nil
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(MethodPost, "/save", nil))
+
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 {
+		t.Fatalf("Expected session cookie on the redirect, got %d cookies", len(cookies))
+	}
+
+	get := func() string {
+		req := httptest.NewRequest(MethodGet, "/", nil)
+		req.AddCookie(cookies[0])
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		return w.Body.String()
+	}
+
+	if body := get(); body != "jane:Saved" {
+		t.Errorf("Expected jane:Saved, got %q", body)
+	}
+
+	if body := get(); body != "jane:" {
+		t.Errorf("Expected the flash to be shown once, got %q", body)
+	}
+}
+
+func TestSessions_HTMXKeepsFlashes(t *testing.T) {
+	store := session.NewMemoryStore(time.Minute)
+
+	r := New()
+	r.Use(Sessions(store))
+	r.Get("/", func(ctx *PageContext) (templ.Component, error) {
+		if ctx.Query("seed") != "" {
+			ctx.Session().AddFlash(session.FlashInfo, "hello")
+		}
+
+		return templ.Raw("ok"), nil
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(MethodGet, "/?seed=1", nil))
+	cookie := w.Result().Cookies()[0]
+
+	req := httptest.NewRequest(MethodGet, "/", nil)
+	req.Header.Set("HX-Request", "true")
+	req.AddCookie(cookie)
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	loaded, _ := store.Load(req)
+	if len(loaded.Flashes()) != 1 {
+		t.Error("Expected HTMX requests to leave flashes for the next page")
+	}
+}
+
+func TestPageContext_SessionWithoutMiddleware(t *testing.T) {
+	ctx := &PageContext{Request: httptest.NewRequest(MethodGet, "/", nil)}
+
+	if ctx.Session() != nil {
+		t.Error("Expected nil session without session middleware")
+	}
+}
+
+func TestLogger_AccessLog(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	r := New(WithLogger(logger))
+	r.Use(RequestID(), Logger())
+
+	r.Get("/users/:id", func(ctx *PageContext) (templ.Component, error) {
+		ctx.Logger().Info("loading user")
+		return templ.Raw("OK"), nil
+	}).Name = "users.show"
+
+	r.Get("/gone", func(ctx *PageContext) (templ.Component, error) {
+		ctx.ResponseWriter.WriteHeader(http.StatusGone)
+		return templ.Raw("Gone"), nil
+	})
+
+	tests := []struct {
+		path      string
+		wantLevel string
+		status    float64
+	}{
+		{"/users/1", "INFO", http.StatusOK},
+		{"/gone", "WARN", http.StatusGone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			buf.Reset()
+
+			req := httptest.NewRequest(MethodGet, tt.path, nil)
+			req.Header.Set("X-Request-ID", "req-1")
+			r.ServeHTTP(httptest.NewRecorder(), req)
+
+			lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+
+			var access map[string]any
+			if err := json.Unmarshal(lines[len(lines)-1], &access); err != nil {
+				t.Fatalf("Expected a JSON access log record, got %q", buf.String())
+			}
+
+			if access["msg"] != "request" || access["level"] != tt.wantLevel {
+				t.Errorf("Expected a %s request record, got %v", tt.wantLevel, access)
+			}
+
+			if access["status"] != tt.status {
+				t.Errorf("Expected status %v, got %v", tt.status, access["status"])
+			}
+
+			if access["request_id"] != "req-1" {
+				t.Errorf("Expected the incoming request ID, got %v", access["request_id"])
+			}
+		})
+	}
+
+	// Records from handlers carry the request ID and route name
+	buf.Reset()
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(MethodGet, "/users/1", nil))
+
+	var handlerRecord map[string]any
+	line, _, _ := bytes.Cut(buf.Bytes(), []byte("\n"))
+	if err := json.Unmarshal(line, &handlerRecord); err != nil {
+		t.Fatalf("Expected a JSON record, got %q", buf.String())
+	}
+
+	if handlerRecord["route"] != "users.show" || handlerRecord["request_id"] == nil {
+		t.Errorf("Expected route and request_id attributes, got %v", handlerRecord)
+	}
+}
@@ -0,0 +1,261 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/a-h/templ"
+
+	"github.com/xraph/forgeui/htmx"
+)
+
+// FragmentParam is the query parameter that selects a fragment of a route
+// (see WithFragment).
+const FragmentParam = "_fragment"
+
+// Envelope is the JSON body of a route negotiated to JSON (see WithJSON).
+// Data holds the loader data; Error is set instead when the loader failed.
+type Envelope struct {
+	Data  any            `json:"data"`
+	Meta  *EnvelopeMeta  `json:"meta,omitempty"`
+	Error *EnvelopeError `json:"error,omitempty"`
+}
+
+// EnvelopeMeta describes the route that produced an Envelope.
+type EnvelopeMeta struct {
+	Route       string `json:"route"`
+	Params      Params `json:"params,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// EnvelopeError is the error of a failed Envelope.
+type EnvelopeError struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+}
+
+// negotiation holds the representations a route opted in to.
+type negotiation struct {
+	json      bool                   // answer Accept: application/json with loader data
+	partials  bool                   // skip layouts for HTMX requests
+	fragments map[string]PageHandler // named sub-components
+	targets   map[string]string      // HX-Target element ID -> fragment name
+}
+
+// representation is the form a negotiated request is answered in.
+type representation int
+
+const (
+	representFull     representation = iota // the page in its layouts
+	representPartial                        // the page without layouts
+	representFragment                       // a named fragment
+	representJSON                           // the loader data as an Envelope
+	representMissing                        // an unknown fragment
+)
+
+// WithJSON answers requests that prefer application/json over text/html
+// with the route's loader data in an Envelope instead of the page. The
+// layout chain and layout loaders don't run. Loader errors are answered
+// with their status and an Envelope carrying the error.
+func (r *Route) WithJSON() *Route {
+	r.negotiated().json = true
+	return r
+}
+
+// WithHTMXPartials renders the page without its layouts (and skips their
+// loaders) for HTMX requests. Boosted requests and history restores still
+// get the full page, since they replace the whole body.
+func (r *Route) WithHTMXPartials() *Route {
+	r.negotiated().partials = true
+	return r
+}
+
+// WithFragment registers a named sub-component of the page. A request
+// with ?_fragment=name, or an HTMX request whose HX-Target is one of
+// targets, gets only that fragment, rendered without layouts after the
+// page loader ran. An unknown fragment name gets the 404 error page.
+//
+//	router.Get("/dashboard", dashboard).
+//	    Loader(loadDashboard).
+//	    WithFragment("stats", statsPanel, "stats")
+func (r *Route) WithFragment(name string, handler PageHandler, targets ...string) *Route {
+	n := r.negotiated()
+
+	if n.fragments == nil {
+		n.fragments = make(map[string]PageHandler)
+	}
+
+	n.fragments[name] = handler
+
+	for _, target := range targets {
+		if n.targets == nil {
+			n.targets = make(map[string]string)
+		}
+
+		n.targets[strings.TrimPrefix(target, "#")] = name
+	}
+
+	return r
+}
+
+// negotiated returns the route's negotiation, creating it.
+func (r *Route) negotiated() *negotiation {
+	if r.negotiation == nil {
+		r.negotiation = &negotiation{}
+	}
+
+	return r.negotiation
+}
+
+// negotiate picks the representation of a request to the route and, for
+// fragments, the handler rendering it. Fragments take precedence over
+// JSON, and JSON over HTMX partials.
+func (r *Route) negotiate(req *http.Request) (representation, PageHandler) {
+	n := r.negotiation
+	if n == nil {
+		return representFull, nil
+	}
+
+	if len(n.fragments) > 0 {
+		name := req.URL.Query().Get(FragmentParam)
+		if name == "" && htmx.IsHTMX(req) {
+			name = n.targets[htmx.HTMXTarget(req)]
+		}
+
+		if name != "" {
+			if handler, ok := n.fragments[name]; ok {
+				return representFragment, handler
+			}
+
+			return representMissing, nil
+		}
+	}
+
+	if n.json && prefersJSON(req.Header.Get("Accept")) {
+		return representJSON, nil
+	}
+
+	if n.partials && htmx.IsHTMX(req) && !htmx.IsHTMXBoosted(req) && !htmx.HTMXHistoryRestoreRequest(req) {
+		return representPartial, nil
+	}
+
+	return representFull, nil
+}
+
+// vary returns the request headers the route's representation depends on.
+func (n *negotiation) vary() []string {
+	var headers []string
+
+	if n.json {
+		headers = append(headers, "Accept")
+	}
+
+	if n.partials || len(n.targets) > 0 {
+		headers = append(headers, "HX-Request")
+	}
+
+	if len(n.targets) > 0 {
+		headers = append(headers, "HX-Target")
+	}
+
+	return headers
+}
+
+// prefersJSON reports whether an Accept header ranks application/json
+// above text/html. Ties go to HTML, so browsers sending */* get pages.
+func prefersJSON(accept string) bool {
+	if accept == "" {
+		return false
+	}
+
+	// The quality of each type, and the specificity it was matched with:
+	// 2 for an exact match, 1 for type/*, 0 for */*
+	jsonQ, jsonSpec := 0.0, -1
+	htmlQ, htmlSpec := 0.0, -1
+
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+				q = parsed
+			}
+		}
+
+		if spec := mediaSpecificity(mediaType, "application/json"); spec > jsonSpec {
+			jsonQ, jsonSpec = q, spec
+		}
+
+		if spec := mediaSpecificity(mediaType, "text/html"); spec > htmlSpec {
+			htmlQ, htmlSpec = q, spec
+		}
+	}
+
+	return jsonQ > 0 && jsonQ > htmlQ
+}
+
+// mediaSpecificity returns how specifically a media range matches
+// mediaType: 2 exactly, 1 by type/*, 0 by */* and -1 not at all.
+func mediaSpecificity(mediaRange, mediaType string) int {
+	switch {
+	case mediaRange == mediaType:
+		return 2
+	case mediaRange == "*/*":
+		return 0
+	case strings.HasSuffix(mediaRange, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(mediaRange, "*")):
+		return 1
+	default:
+		return -1
+	}
+}
+
+// jsonPage is the PageHandler of a request negotiated to JSON: it answers
+// with the loader data the page would have rendered.
+func jsonPage(ctx *PageContext) (templ.Component, error) {
+	return jsonEnvelope(ctx, http.StatusOK, Envelope{Data: ctx.LoadedData, Meta: envelopeMeta(ctx)}), nil
+}
+
+// jsonError answers a request negotiated to JSON with an error envelope.
+func jsonError(ctx *PageContext, status int, message string) templ.Component {
+	return jsonEnvelope(ctx, status, Envelope{
+		Meta:  envelopeMeta(ctx),
+		Error: &EnvelopeError{Status: status, Message: message},
+	})
+}
+
+// jsonEnvelope sets the JSON content type and status and returns a
+// component encoding env.
+func jsonEnvelope(ctx *PageContext, status int, env Envelope) templ.Component {
+	ctx.ResponseWriter.Header().Set("Content-Type", "application/json")
+	ctx.ResponseWriter.WriteHeader(status)
+
+	return templ.ComponentFunc(func(_ context.Context, w io.Writer) error {
+		return json.NewEncoder(w).Encode(env)
+	})
+}
+
+// envelopeMeta describes the matched route of ctx.
+func envelopeMeta(ctx *PageContext) *EnvelopeMeta {
+	meta := &EnvelopeMeta{Params: ctx.Params}
+
+	if ctx.route != nil {
+		meta.Route = ctx.route.Pattern
+	}
+
+	if ctx.Meta != nil {
+		meta.Title = ctx.Meta.Title
+		meta.Description = ctx.Meta.Description
+	}
+
+	return meta
+}
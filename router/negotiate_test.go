@@ -0,0 +1,316 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/a-h/templ"
+)
+
+// negotiatedRouter returns a router with a "main" layout (with a loader
+// counting its calls) and a /users/:id page with a loader.
+func negotiatedRouter(layoutLoads *atomic.Int32) (*Router, *Route) {
+	r := New()
+
+	r.RegisterLayout("main", func(ctx *PageContext, content templ.Component) templ.Component {
+		return templ.ComponentFunc(func(tCtx context.Context, w io.Writer) error {
+			if _, err := io.WriteString(w, "<main>"); err != nil {
+				return err
+			}
+
+			if err := content.Render(tCtx, w); err != nil {
+				return err
+			}
+
+			_, err := io.WriteString(w, "</main>")
+
+			return err
+		})
+	}, WithLayoutLoader(func(ctx context.Context, params Params) (any, error) {
+		layoutLoads.Add(1)
+		return nil, nil
+	}))
+	r.SetDefaultLayout("main")
+
+	route := r.Get("/users/:id", func(ctx *PageContext) (templ.Component, error) {
+		return templ.Raw("<h1>" + ctx.LoadedData.(map[string]string)["name"] + "</h1>"), nil
+	}).Loader(func(ctx context.Context, params Params) (any, error) {
+		if params["id"] == "0" {
+			return nil, &LoaderError{Status: http.StatusNotFound, Message: "User not found"}
+		}
+
+		return map[string]string{"name": "Ada"}, nil
+	}).Title("User")
+
+	return r, route
+}
+
+func TestNegotiateJSON(t *testing.T) {
+	var layoutLoads atomic.Int32
+
+	r, route := negotiatedRouter(&layoutLoads)
+	route.WithJSON()
+
+	req := httptest.NewRequest(MethodGet, "/users/1", nil)
+	req.Header.Set("Accept", "application/json")
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Expected JSON content type, got %q", ct)
+	}
+
+	if vary := w.Header().Values("Vary"); len(vary) != 1 || vary[0] != "Accept" {
+		t.Errorf("Expected Vary: Accept, got %v", vary)
+	}
+
+	var env struct {
+		Data  map[string]string `json:"data"`
+		Meta  EnvelopeMeta      `json:"meta"`
+		Error *EnvelopeError    `json:"error"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &env); err != nil {
+		t.Fatalf("Expected a JSON envelope, got %q: %v", w.Body.String(), err)
+	}
+
+	if env.Data["name"] != "Ada" {
+		t.Errorf("Expected the loader data, got %v", env.Data)
+	}
+
+	if env.Meta.Route != "/users/:id" || env.Meta.Params["id"] != "1" || env.Meta.Title != "User" {
+		t.Errorf("Expected the route metadata, got %+v", env.Meta)
+	}
+
+	if env.Error != nil {
+		t.Errorf("Expected no error, got %+v", env.Error)
+	}
+
+	if layoutLoads.Load() != 0 {
+		t.Errorf("Expected the layout loader not to run, ran %d times", layoutLoads.Load())
+	}
+}
+
+func TestNegotiateJSONLoaderError(t *testing.T) {
+	var layoutLoads atomic.Int32
+
+	r, route := negotiatedRouter(&layoutLoads)
+	route.WithJSON()
+
+	req := httptest.NewRequest(MethodGet, "/users/0", nil)
+	req.Header.Set("Accept", "application/json")
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404, got %d", w.Code)
+	}
+
+	var env Envelope
+	if err := json.Unmarshal(w.Body.Bytes(), &env); err != nil {
+		t.Fatalf("Expected a JSON envelope, got %q: %v", w.Body.String(), err)
+	}
+
+	if env.Error == nil || env.Error.Status != http.StatusNotFound || env.Error.Message != "User not found" {
+		t.Errorf("Expected the loader error, got %+v", env.Error)
+	}
+
+	if env.Data != nil {
+		t.Errorf("Expected no data, got %v", env.Data)
+	}
+}
+
+func TestNegotiateJSONHandlerError(t *testing.T) {
+	r := New()
+
+	// The page handler doesn't run for JSON, but middleware does
+	r.Get("/broken", func(ctx *PageContext) (templ.Component, error) {
+		return templ.Raw("page"), nil
+	}).WithJSON().WithMiddleware(func(next PageHandler) PageHandler {
+		return func(ctx *PageContext) (templ.Component, error) {
+			return nil, errors.New("secret details")
+		}
+	})
+
+	req := httptest.NewRequest(MethodGet, "/broken", nil)
+	req.Header.Set("Accept", "application/json")
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status 500, got %d", w.Code)
+	}
+
+	if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Expected JSON content type, got %q", ct)
+	}
+
+	if strings.Contains(w.Body.String(), "secret details") {
+		t.Errorf("Expected the error not to leak, got %q", w.Body.String())
+	}
+}
+
+func TestNegotiateJSONOptIn(t *testing.T) {
+	var layoutLoads atomic.Int32
+
+	r, _ := negotiatedRouter(&layoutLoads)
+
+	req := httptest.NewRequest(MethodGet, "/users/1", nil)
+	req.Header.Set("Accept", "application/json")
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Body.String() != "<main><h1>Ada</h1></main>" {
+		t.Errorf("Expected the page without WithJSON, got %q", w.Body.String())
+	}
+
+	if vary := w.Header().Get("Vary"); vary != "" {
+		t.Errorf("Expected no Vary header, got %q", vary)
+	}
+}
+
+func TestNegotiateHTMXPartials(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    string
+	}{
+		{"full page", nil, "<main><h1>Ada</h1></main>"},
+		{"htmx", map[string]string{"HX-Request": "true"}, "<h1>Ada</h1>"},
+		{"boosted", map[string]string{"HX-Request": "true", "HX-Boosted": "true"}, "<main><h1>Ada</h1></main>"},
+		{"history restore", map[string]string{"HX-Request": "true", "HX-History-Restore-Request": "true"}, "<main><h1>Ada</h1></main>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var layoutLoads atomic.Int32
+
+			r, route := negotiatedRouter(&layoutLoads)
+			route.WithHTMXPartials()
+
+			req := httptest.NewRequest(MethodGet, "/users/1", nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Body.String() != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, w.Body.String())
+			}
+
+			wantLoads := int32(1)
+			if tt.want == "<h1>Ada</h1>" {
+				wantLoads = 0
+			}
+
+			if layoutLoads.Load() != wantLoads {
+				t.Errorf("Expected the layout loader to run %d times, ran %d", wantLoads, layoutLoads.Load())
+			}
+		})
+	}
+}
+
+func TestNegotiateFragment(t *testing.T) {
+	stats := func(ctx *PageContext) (templ.Component, error) {
+		return templ.Raw("<p>stats for " + ctx.LoadedData.(map[string]string)["name"] + "</p>"), nil
+	}
+
+	tests := []struct {
+		name       string
+		target     string
+		headers    map[string]string
+		wantStatus int
+		want       string
+	}{
+		{"query", "/users/1?_fragment=stats", nil, http.StatusOK, "<p>stats for Ada</p>"},
+		{"hx-target", "/users/1", map[string]string{"HX-Request": "true", "HX-Target": "user-stats"}, http.StatusOK, "<p>stats for Ada</p>"},
+		{"unmapped hx-target", "/users/1", map[string]string{"HX-Request": "true", "HX-Target": "other"}, http.StatusOK, "<main><h1>Ada</h1></main>"},
+		{"hx-target without htmx", "/users/1", map[string]string{"HX-Target": "user-stats"}, http.StatusOK, "<main><h1>Ada</h1></main>"},
+		{"unknown", "/users/1?_fragment=nope", nil, http.StatusNotFound, ""},
+		{"over json", "/users/1?_fragment=stats", map[string]string{"Accept": "application/json"}, http.StatusOK, "<p>stats for Ada</p>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var layoutLoads atomic.Int32
+
+			r, route := negotiatedRouter(&layoutLoads)
+			route.WithJSON().WithFragment("stats", stats, "#user-stats")
+
+			req := httptest.NewRequest(MethodGet, tt.target, nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+
+			if tt.want != "" && w.Body.String() != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, w.Body.String())
+			}
+
+			if vary := strings.Join(w.Header().Values("Vary"), ", "); vary != "Accept, HX-Request, HX-Target" {
+				t.Errorf("Expected Vary: Accept, HX-Request, HX-Target, got %q", vary)
+			}
+		})
+	}
+}
+
+func TestPrefersJSON(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   bool
+	}{
+		{"", false},
+		{"application/json", true},
+		{"application/json, text/plain", true},
+		{"application/*", true},
+		{"*/*", false},
+		{"text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8", false},
+		{"application/json, text/html", false},
+		{"text/html;q=0.5, application/json", true},
+		{"application/json;q=0.9, */*;q=0.1", true},
+		{"application/json;q=0", false},
+		{"not a media type", false},
+	}
+
+	for _, tt := range tests {
+		if got := prefersJSON(tt.accept); got != tt.want {
+			t.Errorf("Expected prefersJSON(%q) = %v, got %v", tt.accept, tt.want, got)
+		}
+	}
+}
+
+func TestDeferredMarshalJSON(t *testing.T) {
+	data, err := json.Marshal(map[string]any{
+		"stats":  Resolved([]int{1, 2}),
+		"failed": Defer(context.Background(), func(context.Context) (int, error) { return 0, errors.New("boom") }),
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if string(data) != `{"failed":null,"stats":[1,2]}` {
+		t.Errorf("Expected deferred values to be awaited, got %s", data)
+	}
+}
@@ -0,0 +1,177 @@
+package router
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// segmentKind identifies the kind of a route pattern segment.
+type segmentKind int
+
+const (
+	segmentStatic   segmentKind = iota // literal text: /users
+	segmentParam                       // one path segment: /:id, /{id}, /{id:int}
+	segmentCatchAll                    // the rest of the path: /*path
+)
+
+// ParamTypes maps the names usable as typed segment constraints, as in
+// /users/{id:int}, to the regular expression a value must match.
+// Any other constraint is used as a regular expression itself:
+// /posts/{slug:[a-z-]+}.
+var ParamTypes = map[string]string{
+	"int":   `-?[0-9]+`,
+	"uint":  `[0-9]+`,
+	"alpha": `[A-Za-z]+`,
+	"alnum": `[A-Za-z0-9]+`,
+	"slug":  `[a-z0-9]+(?:-[a-z0-9]+)*`,
+	"uuid":  `[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`,
+}
+
+// segment is one parsed segment of a route pattern.
+type segment struct {
+	kind       segmentKind
+	value      string         // static text or parameter name
+	constraint string         // constraint as written ("int", "[a-z-]+"), empty if none
+	re         *regexp.Regexp // compiled constraint, nil if none
+	optional   bool
+}
+
+// matches reports whether v satisfies the segment's constraint.
+func (s segment) matches(v string) bool {
+	return v != "" && (s.re == nil || s.re.MatchString(v))
+}
+
+// describe returns the segment in {name:constraint} form for messages.
+func (s segment) describe() string {
+	switch s.kind {
+	case segmentParam:
+		if s.constraint != "" {
+			return "{" + s.value + ":" + s.constraint + "}"
+		}
+
+		return "{" + s.value + "}"
+	case segmentCatchAll:
+		return "*" + s.value
+	default:
+		return s.value
+	}
+}
+
+// parsePattern splits a route pattern into segments.
+//
+// Supported segments:
+//   - static text: /users
+//   - parameters: /:id or /{id}
+//   - typed parameters: /{id:int} or /{slug:[a-z-]+} (see ParamTypes)
+//   - optional parameters: /{page?} or /{page:int?}, only at the end
+//   - catch-alls: /*path or /*, only as the last segment
+func parsePattern(pattern string) ([]segment, error) {
+	trimmed := strings.Trim(pattern, "/")
+	if trimmed == "" {
+		return nil, nil
+	}
+
+	parts := strings.Split(trimmed, "/")
+	segments := make([]segment, 0, len(parts))
+	names := make(map[string]bool)
+
+	for i, part := range parts {
+		seg, err := parseSegment(part)
+		if err != nil {
+			return nil, fmt.Errorf("router: invalid pattern %q: %w", pattern, err)
+		}
+
+		if seg.kind != segmentStatic {
+			if names[seg.value] {
+				return nil, fmt.Errorf("router: invalid pattern %q: duplicate parameter %q", pattern, seg.value)
+			}
+
+			names[seg.value] = true
+		}
+
+		if seg.kind == segmentCatchAll && i != len(parts)-1 {
+			return nil, fmt.Errorf("router: invalid pattern %q: catch-all %q must be the last segment", pattern, part)
+		}
+
+		if !seg.optional && len(segments) > 0 && segments[len(segments)-1].optional {
+			return nil, fmt.Errorf("router: invalid pattern %q: only trailing segments can be optional", pattern)
+		}
+
+		segments = append(segments, seg)
+	}
+
+	return segments, nil
+}
+
+// parseSegment parses a single pattern segment.
+func parseSegment(part string) (segment, error) {
+	switch {
+	case part == "":
+		return segment{}, fmt.Errorf("empty segment")
+	case strings.HasPrefix(part, "*"):
+		name := part[1:]
+		if name == "" {
+			name = "wildcard"
+		}
+
+		return segment{kind: segmentCatchAll, value: name}, nil
+	case strings.HasPrefix(part, ":"):
+		return newParamSegment(part[1:], "")
+	case strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}"):
+		name, constraint, _ := strings.Cut(part[1:len(part)-1], ":")
+		return newParamSegment(name, constraint)
+	case strings.ContainsAny(part, "{}"):
+		return segment{}, fmt.Errorf("malformed parameter %q", part)
+	default:
+		return segment{kind: segmentStatic, value: part}, nil
+	}
+}
+
+// newParamSegment builds a parameter segment. A trailing "?" on the name or
+// constraint marks the parameter optional.
+func newParamSegment(name, constraint string) (segment, error) {
+	seg := segment{kind: segmentParam}
+
+	if constraint == "" {
+		name, seg.optional = strings.CutSuffix(name, "?")
+	} else {
+		constraint, seg.optional = strings.CutSuffix(constraint, "?")
+	}
+
+	if name == "" {
+		return segment{}, fmt.Errorf("parameter without a name")
+	}
+
+	seg.value = name
+
+	if constraint == "" {
+		return seg, nil
+	}
+
+	expr, ok := ParamTypes[constraint]
+	if !ok {
+		expr = constraint
+	}
+
+	re, err := regexp.Compile("^(?:" + expr + ")$")
+	if err != nil {
+		return segment{}, fmt.Errorf("parameter %q: invalid constraint %q: %w", name, constraint, err)
+	}
+
+	seg.constraint = constraint
+	seg.re = re
+
+	return seg, nil
+}
+
+// splitPath splits a request path into its segments. Trailing slashes are
+// ignored and the root path has no segments.
+func splitPath(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+
+	return strings.Split(path, "/")
+}
@@ -1,7 +1,6 @@
 package router
 
 import (
-	"regexp"
 	"strings"
 )
 
@@ -16,13 +15,20 @@ type Route struct {
 	LoaderFn   LoaderFunc
 	Metadata   *RouteMeta
 
+	buffered     bool            // render into a buffer before sending (see WithBuffering)
+	staticParams ParamEnumerator // parameter sets for static export (see StaticParams)
+	locales      []string        // values of the locale parameter (see LocalePrefix)
+	negotiation  *negotiation    // alternative representations (see WithJSON)
+	group        *Group          // the group the route was registered in
+
 	// Internal fields for matching
-	regex      *regexp.Regexp
+	segments   []segment
 	paramNames []string
 	priority   int // Lower is higher priority (static > param > wildcard)
 }
 
-// newRoute creates a new route with the given pattern and method
+// newRoute creates a new route with the given pattern and method.
+// It panics if the pattern is invalid.
 func newRoute(pattern, method string, handler PageHandler) *Route {
 	r := &Route{
 		Pattern:    pattern,
@@ -35,79 +41,80 @@ func newRoute(pattern, method string, handler PageHandler) *Route {
 	return r
 }
 
-// compile parses the pattern and builds the regex for matching
+// compile parses the pattern into segments
 func (r *Route) compile() {
-	// Handle root path
-	if r.Pattern == "/" {
-		r.regex = regexp.MustCompile("^/$")
-		r.priority = 0 // Highest priority for exact root
-
-		return
+	segments, err := parsePattern(r.Pattern)
+	if err != nil {
+		panic(err)
 	}
 
-	// Remove trailing slash for consistency
-	pattern := strings.TrimSuffix(r.Pattern, "/")
-	segments := strings.Split(strings.Trim(pattern, "/"), "/")
-
-	regexParts := make([]string, 0, len(segments))
 	paramNames := make([]string, 0)
 	priority := 0
 
-	for _, segment := range segments {
-		if after, ok := strings.CutPrefix(segment, ":"); ok {
-			// Named parameter: :id -> capture group
-			paramName := after
-			paramNames = append(paramNames, paramName)
-			regexParts = append(regexParts, "([^/]+)")
+	for _, seg := range segments {
+		switch seg.kind {
+		case segmentParam:
+			paramNames = append(paramNames, seg.value)
 			priority += 10 // Parameters have lower priority than static
-		} else if after, ok := strings.CutPrefix(segment, "*"); ok {
-			// Wildcard: *path -> capture everything
-			paramName := after
-			if paramName == "" {
-				paramName = "wildcard"
-			}
-
-			paramNames = append(paramNames, paramName)
-			regexParts = append(regexParts, "(.+)")
+		case segmentCatchAll:
+			paramNames = append(paramNames, seg.value)
 			priority += 20 // Wildcards have lowest priority
-		} else {
-			// Static segment
-			regexParts = append(regexParts, regexp.QuoteMeta(segment))
+		case segmentStatic:
 			// Static segments don't increase priority (priority = 0 is highest)
 		}
 	}
 
-	// Build the final regex
-	regexStr := "^/" + strings.Join(regexParts, "/") + "$"
-	r.regex = regexp.MustCompile(regexStr)
+	r.segments = segments
 	r.paramNames = paramNames
 	r.priority = priority
 }
 
 // Match checks if the given path matches this route and extracts parameters
 func (r *Route) Match(path string) (params Params, ok bool) {
-	// Normalize path: empty string (e.g. from http.StripPrefix) is root
-	if path == "" {
-		path = "/"
-	} else if path != "/" {
-		path = strings.TrimSuffix(path, "/")
+	parts := splitPath(path)
+	values := make([]string, 0, len(r.paramNames))
+
+	for i, seg := range r.segments {
+		if i >= len(parts) {
+			if !seg.optional {
+				return nil, false
+			}
+
+			break
+		}
+
+		switch seg.kind {
+		case segmentStatic:
+			if parts[i] != seg.value {
+				return nil, false
+			}
+		case segmentParam:
+			if !seg.matches(parts[i]) {
+				return nil, false
+			}
+
+			values = append(values, parts[i])
+		case segmentCatchAll:
+			values = append(values, strings.Join(parts[i:], "/"))
+			return r.bind(values), true
+		}
 	}
 
-	matches := r.regex.FindStringSubmatch(path)
-	if matches == nil {
+	if len(parts) > len(r.segments) {
 		return nil, false
 	}
 
-	// Extract parameters
-	params = make(Params)
+	return r.bind(values), true
+}
 
-	for i, name := range r.paramNames {
-		if i+1 < len(matches) {
-			params[name] = matches[i+1]
-		}
+// bind maps positional parameter values to the route's parameter names.
+func (r *Route) bind(values []string) Params {
+	params := make(Params, len(values))
+	for i, v := range values {
+		params[r.paramNames[i]] = v
 	}
 
-	return params, true
+	return params
 }
 
 // WithMiddleware adds middleware to this route
@@ -1,21 +1,29 @@
 package router
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"log"
+	"io"
+	"log/slog"
 	"net/http"
 	"runtime"
 	"sort"
+	"strings"
 	"sync"
 
 	"github.com/a-h/templ"
+
+	"github.com/xraph/forgeui/htmx"
+	"github.com/xraph/forgeui/logging"
+	"github.com/xraph/forgeui/tracing"
 )
 
 // Router handles HTTP routing for ForgeUI applications.
 type Router struct {
 	mu            sync.RWMutex
 	routes        []*Route
+	tree          *node
 	namedRoutes   map[string]*Route
 	notFound      PageHandler
 	errorHandler  ErrorHandler
@@ -24,7 +32,13 @@ type Router struct {
 	layouts       map[string]LayoutFunc
 	layoutConfigs map[string]*LayoutConfig
 	errorPages    map[int]PageHandler
+	errorGroups   []*Group // groups with error pages (see Group.ErrorPage)
+	loginPath     string   // see WithLoginPath
 	defaultLayout string
+	buffered      bool
+	logger        *slog.Logger
+	tracer        tracing.Tracer
+	metrics       *routerMetrics
 	app           any // Reference to App (interface to avoid circular dependency)
 }
 
@@ -35,6 +49,7 @@ type RouterOption func(*Router)
 func New(opts ...RouterOption) *Router {
 	r := &Router{
 		routes:       make([]*Route, 0),
+		tree:         &node{},
 		namedRoutes:  make(map[string]*Route),
 		middleware:   make([]Middleware, 0),
 		notFound:     defaultNotFound,
@@ -82,6 +97,8 @@ func (r *Router) Use(middleware ...Middleware) *Router {
 }
 
 // Handle registers a route with the given method, pattern, and handler.
+// It panics if the pattern is invalid, or if it conflicts with or is
+// ambiguous against a route registered before.
 func (r *Router) Handle(method, pattern string, handler PageHandler) *Route {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -92,6 +109,10 @@ func (r *Router) Handle(method, pattern string, handler PageHandler) *Route {
 	}
 
 	route := newRoute(pattern, method, handler)
+	if err := r.tree.insert(route); err != nil {
+		panic(err)
+	}
+
 	r.routes = append(r.routes, route)
 
 	// Sort routes by priority (lower priority number = higher precedence)
@@ -102,6 +123,36 @@ func (r *Router) Handle(method, pattern string, handler PageHandler) *Route {
 	return route
 }
 
+// BasePath returns the base path routes are registered under.
+func (r *Router) BasePath() string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.basePath
+}
+
+// SetBasePath moves every route, including those already registered, from
+// the current base path to path, so URLs built from them follow. Call it
+// before serving requests. It panics if the moved routes conflict.
+func (r *Router) SetBasePath(path string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tree := &node{}
+
+	for _, route := range r.routes {
+		route.Pattern = path + strings.TrimPrefix(route.Pattern, r.basePath)
+		route.compile()
+
+		if err := tree.insert(route); err != nil {
+			panic(err)
+		}
+	}
+
+	r.basePath = path
+	r.tree = tree
+}
+
 // Get registers a GET route.
 func (r *Router) Get(pattern string, handler PageHandler) *Route {
 	return r.Handle(MethodGet, pattern, handler)
@@ -162,20 +213,30 @@ func (r *Router) findRoute(method, path string) (*Route, Params) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	// Try to match routes in priority order
-	for _, route := range r.routes {
-		// Check method match
-		if route.Method != method {
-			continue
-		}
+	route, values := r.tree.lookup(method, splitPath(path), nil)
+	if route == nil {
+		return nil, nil
+	}
 
-		// Check path match
-		if params, ok := route.Match(path); ok {
-			return route, params
-		}
+	return route, route.bind(values)
+}
+
+// match returns the route serving req: the one another router matched (see
+// RouteHandler), or the one registered for path.
+func (r *Router) match(req *http.Request, path string) (*Route, Params) {
+	if m, ok := req.Context().Value(routeMatchKey{}).(*routeMatch); ok {
+		return m.route, m.params
 	}
 
-	return nil, nil
+	return r.findRoute(req.Method, path)
+}
+
+// allowedMethods returns the methods registered for path, sorted.
+func (r *Router) allowedMethods(path string) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.tree.allowedMethods(path)
 }
 
 // SetApp sets the app reference for PageContext.
@@ -188,12 +249,52 @@ func (r *Router) SetApp(app any) {
 
 // ServeHTTP implements http.Handler interface.
 func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if r.logger != nil && !logging.HasLogger(req.Context()) {
+		req = req.WithContext(logging.NewContext(req.Context(), r.logger))
+	}
+
+	req = tracing.Extract(req, r.tracer)
+
+	var span tracing.Span
+	if tracing.Enabled(req.Context()) {
+		var spanCtx context.Context
+
+		spanCtx, span = tracing.Start(req.Context(), "router.request",
+			tracing.String(tracing.KeyMethod, req.Method),
+			tracing.String(tracing.KeyPath, req.URL.Path),
+		)
+		req = req.WithContext(spanCtx)
+	}
+
+	// Record the status of the response as sent, for access logs
+	state := &requestState{}
+	state.recorder, w = logging.NewStatusRecorder(w)
+
+	var bw *bufferedWriter
+
 	// Recover from panics in page handlers/layouts to prevent crashing the server.
 	defer func() {
 		if rv := recover(); rv != nil {
 			buf := make([]byte, 4096)
 			n := runtime.Stack(buf, false)
-			log.Printf("forgeui: panic serving %s %s: %v\n%s", req.Method, req.URL.Path, rv, buf[:n])
+			logging.FromContext(req.Context()).Error("panic serving request",
+				logging.KeyMethod, req.Method,
+				logging.KeyPath, req.URL.Path,
+				"panic", fmt.Sprint(rv),
+				"stack", string(buf[:n]),
+			)
+
+			// A buffered response that was never committed can still be
+			// replaced. The error page goes to the underlying writer, since
+			// the buffer is never committed.
+			if bw != nil {
+				if !bw.committed {
+					bw.reset()
+					w.Header().Del("Content-Type")
+				}
+
+				w = bw.ResponseWriter
+			}
 
 			// Return a 500 error page if the response hasn't been written yet.
 			if w.Header().Get("Content-Type") == "" {
@@ -201,6 +302,17 @@ func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 				w.WriteHeader(http.StatusInternalServerError)
 				_, _ = fmt.Fprintf(w, "<h1>Internal Server Error</h1><p>An unexpected error occurred.</p>")
 			}
+
+			if span != nil {
+				span.RecordError(fmt.Errorf("panic: %v", rv))
+			}
+		}
+
+		state.runFinish()
+
+		if span != nil {
+			span.SetAttributes(tracing.Int(tracing.KeyStatus, state.status()))
+			span.End()
 		}
 	}()
 
@@ -211,7 +323,24 @@ func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	}
 
 	// Find matching route
-	route, params := r.findRoute(req.Method, path)
+	_, matchSpan := tracing.Start(req.Context(), "router.match")
+	route, params := r.match(req, path)
+
+	if route != nil {
+		matchSpan.SetAttributes(tracing.String(tracing.KeyRoute, route.Pattern))
+
+		if span != nil {
+			span.SetAttributes(tracing.String(tracing.KeyRoute, route.Pattern))
+		}
+	}
+
+	matchSpan.End()
+
+	// Buffer the response so a failed render can still become an error page
+	if r.buffered || (route != nil && route.buffered) {
+		bw = &bufferedWriter{ResponseWriter: w}
+		w = bw
+	}
 
 	// Create page context
 	ctx := &PageContext{
@@ -219,50 +348,71 @@ func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 		Request:        req,
 		Params:         params,
 		values:         make(map[string]any),
+		layoutData:     make(map[string]any),
+		state:          state,
+		route:          route,
 		app:            r.app,
+		router:         r,
 	}
 
+	r.metrics.observeRequest(ctx)
+
 	var (
 		comp templ.Component
 		err  error
+		rep  representation
 	)
 
 	if route == nil {
-		// No route found - call 404 handler
-		comp, err = r.notFound(ctx)
+		if allowed := r.allowedMethods(path); len(allowed) > 0 {
+			// The path exists for other methods
+			w.Header().Set("Allow", strings.Join(allowed, ", "))
+			comp, err = r.errorPage(ctx, http.StatusMethodNotAllowed, r.getErrorPage(http.StatusMethodNotAllowed))
+		} else {
+			// No route found - call the nearest group's 404 page or the 404 handler
+			comp, err = r.errorPage(ctx, http.StatusNotFound, r.notFound)
+		}
 	} else {
-		// Set metadata in context
+		// Set metadata in context, translated when the request has a locale
 		ctx.Meta = route.Metadata
+		localizeMeta(ctx)
+
+		// Resolve the layout chain up front so layout loaders can run
+		// alongside the page loader
+		layouts := r.layoutChain(r.routeLayout(route))
+
+		// Pick the representation the route opted in to (see WithJSON,
+		// WithHTMXPartials and WithFragment)
+		page := route.Handler
+		if route.negotiation != nil {
+			for _, header := range route.negotiation.vary() {
+				w.Header().Add("Vary", header)
+			}
 
-		// Execute loader if present
-		if route.LoaderFn != nil {
-			data, loaderErr := route.executeLoader(req.Context(), params)
-			if loaderErr != nil {
-				// Handle loader error
-				var le *LoaderError
-				if errors.As(loaderErr, &le) {
-					errorHandler := r.getErrorPage(le.Status)
-
-					comp, _ = errorHandler(ctx)
-					if comp != nil {
-						if ctx.ResponseWriter.Header().Get("Content-Type") == "" {
-							ctx.ResponseWriter.Header().Set("Content-Type", "text/html; charset=utf-8")
-						}
-
-						_ = comp.Render(ctx.Context(), ctx.ResponseWriter)
-					}
-
-					return
-				}
+			var fragment PageHandler
 
-				err = loaderErr
-			} else {
-				ctx.LoadedData = data
+			rep, fragment = route.negotiate(req)
+
+			switch rep {
+			case representFragment:
+				page = fragment
+			case representJSON:
+				page = jsonPage
+			}
+
+			if rep != representFull {
+				layouts = nil
+				ctx.SkipLayout()
 			}
 		}
 
-		// Build middleware chain
-		handler := route.Handler
+		// Build handler chain: loaders run innermost, after all middleware
+		handler := r.withLoaders(route, layouts, page)
+		if rep == representMissing {
+			handler = func(ctx *PageContext) (templ.Component, error) {
+				return r.errorPage(ctx, http.StatusNotFound, r.getErrorPage(http.StatusNotFound))
+			}
+		}
 
 		// Apply route-specific middleware (in reverse order)
 		for i := len(route.Middleware) - 1; i >= 0; i-- {
@@ -275,45 +425,150 @@ func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 		}
 
 		// Execute handler
-		if err == nil {
-			comp, err = handler(ctx)
-		}
+		comp, err = handler(ctx)
 
 		// Apply layout if configured (with composition support)
-		if comp != nil && err == nil {
-			layoutName := route.Layout
-			if layoutName == "" && r.defaultLayout != "" {
-				layoutName = r.defaultLayout
-			}
+		if comp != nil && err == nil && !ctx.layoutSkipped() {
+			comp = r.applyLayouts(ctx, comp, layouts)
+		}
+	}
 
-			if layoutName != "" && layoutName != "none" {
-				comp = r.applyLayoutChain(ctx, comp, layoutName)
-			}
+	// Loader errors render the registered error page for their status
+	var lf *loaderFailure
+	if errors.As(err, &lf) {
+		if rep == representJSON {
+			comp = jsonError(ctx, lf.err.Status, lf.err.Message)
+		} else {
+			comp, _ = r.errorPage(ctx, lf.err.Status, r.getErrorPage(lf.err.Status))
 		}
+
+		err = nil
+	}
+
+	// Other errors in JSON stay JSON, without leaking their details
+	if err != nil && rep == representJSON {
+		logging.FromContext(req.Context()).Error("page handler failed",
+			logging.KeyMethod, req.Method,
+			logging.KeyPath, req.URL.Path,
+			"error", err,
+		)
+
+		comp = jsonError(ctx, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		err = nil
 	}
 
-	// Handle errors
+	// Handle errors with the nearest group's 500 page or the error handler
 	if err != nil {
-		comp = r.errorHandler(ctx, err)
+		handlerErr := err
+
+		comp, _ = r.errorPage(ctx, http.StatusInternalServerError, func(ctx *PageContext) (templ.Component, error) {
+			return r.errorHandler(ctx, handlerErr), nil
+		})
 	}
 
 	// Render component if present
+	if comp != nil && span != nil {
+		comp = traced(comp, "router.render")
+	}
+
+	if comp != nil {
+		comp = r.metrics.timed(comp, route)
+	}
+
 	if comp != nil {
 		if ctx.ResponseWriter.Header().Get("Content-Type") == "" {
 			ctx.ResponseWriter.Header().Set("Content-Type", "text/html; charset=utf-8")
 		}
 
-		_ = comp.Render(ctx.Context(), ctx.ResponseWriter)
+		if bw != nil {
+			if err := comp.Render(ctx.Context(), bw); err != nil {
+				r.renderError(ctx, bw, err)
+			}
+		} else {
+			// Deferred loader data (see Await) streams after the shell is flushed
+			renderCtx := ctx.Context()
+
+			s := newStream(renderCtx, ctx.ResponseWriter, htmx.IsHTMX(req))
+			if s != nil {
+				renderCtx = s.renderContext()
+			}
+
+			_ = comp.Render(renderCtx, ctx.ResponseWriter)
+
+			if s != nil {
+				_ = s.finish()
+			}
+		}
+	}
+
+	if bw != nil {
+		_ = bw.commit(req)
+	}
+}
+
+// renderError replaces a buffered response whose render failed with the
+// SetErrorPage(500) page, or the error handler when none is registered.
+func (r *Router) renderError(ctx *PageContext, bw *bufferedWriter, renderErr error) {
+	bw.reset()
+
+	r.mu.RLock()
+	errorPage, ok := r.errorPages[http.StatusInternalServerError]
+	r.mu.RUnlock()
+
+	comp, _ := r.errorPage(ctx, http.StatusInternalServerError, func(ctx *PageContext) (templ.Component, error) {
+		if ok {
+			return errorPage(ctx)
+		}
+
+		return r.errorHandler(ctx, renderErr), nil
+	})
+
+	if bw.status == 0 {
+		bw.WriteHeader(http.StatusInternalServerError)
+	}
+
+	if comp == nil {
+		return
+	}
+
+	if err := comp.Render(ctx.Context(), bw); err != nil {
+		// The error page failed too: fall back to plain text
+		bw.reset()
+		bw.WriteHeader(http.StatusInternalServerError)
+		_, _ = io.WriteString(bw, "500 Internal Server Error")
+	}
+}
+
+// routeLayout returns the layout name for a route, falling back to the
+// default layout. Returns an empty string if the route has no layout.
+func (r *Router) routeLayout(route *Route) string {
+	return r.resolveLayout(route.Layout)
+}
+
+// resolveLayout returns the layout name to use for a route or group layout
+// setting: the default layout when empty, none for "none".
+func (r *Router) resolveLayout(layoutName string) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if layoutName == "" {
+		layoutName = r.defaultLayout
 	}
+
+	if layoutName == "none" {
+		return ""
+	}
+
+	return layoutName
 }
 
-// applyLayoutChain applies layouts in composition order (child -> parent -> root).
-func (r *Router) applyLayoutChain(ctx *PageContext, content templ.Component, layoutName string) templ.Component {
+// layoutChain resolves the names of the registered layouts in composition
+// order (child -> parent -> root).
+func (r *Router) layoutChain(layoutName string) []string {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	// Build layout chain from child to root
-	var chain []LayoutFunc
+	var chain []string
 
 	currentLayout := layoutName
 	visited := make(map[string]bool)
@@ -326,14 +581,11 @@ func (r *Router) applyLayoutChain(ctx *PageContext, content templ.Component, lay
 
 		visited[currentLayout] = true
 
-		// Get layout function
-		layoutFn, ok := r.layouts[currentLayout]
-		if !ok {
+		if _, ok := r.layouts[currentLayout]; !ok {
 			break
 		}
 
-		// Add to chain
-		chain = append(chain, layoutFn)
+		chain = append(chain, currentLayout)
 
 		// Get parent layout
 		if config, ok := r.layoutConfigs[currentLayout]; ok && config.Parent != "" {
@@ -343,11 +595,34 @@ func (r *Router) applyLayoutChain(ctx *PageContext, content templ.Component, lay
 		}
 	}
 
-	// Apply layouts in order (innermost first, outermost last)
-	// Chain is built from child to root, so apply forward to wrap correctly
+	return chain
+}
+
+// applyLayouts applies layouts in composition order (child -> parent -> root).
+// The chain is built from child to root, so apply forward to wrap correctly.
+func (r *Router) applyLayouts(ctx *PageContext, content templ.Component, chain []string) templ.Component {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
 	result := content
-	for i := range chain {
-		result = chain[i](ctx, result)
+
+	traceLayouts := tracing.Enabled(ctx.Context())
+	if traceLayouts && len(chain) > 0 {
+		result = traced(result, "router.page")
+	}
+
+	for _, name := range chain {
+		if layoutFn, ok := r.layouts[name]; ok {
+			if config, ok := r.layoutConfigs[name]; ok && config.Fallback != nil {
+				result = layoutBoundary(ctx, name, layoutFn, config.Fallback, result)
+			} else {
+				result = layoutFn(ctx, result)
+			}
+
+			if traceLayouts {
+				result = traced(result, "router.layout", tracing.String(tracing.KeyLayout, name))
+			}
+		}
 	}
 
 	return result
@@ -96,8 +96,12 @@ func TestRouter_ServeHTTP_MethodMismatch(t *testing.T) {
 
 	r.ServeHTTP(w, req)
 
-	if w.Code != http.StatusNotFound {
-		t.Errorf("Expected status 404, got %d", w.Code)
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected status 405, got %d", w.Code)
+	}
+
+	if allow := w.Header().Get("Allow"); allow != MethodGet {
+		t.Errorf("Expected Allow header %q, got %q", MethodGet, allow)
 	}
 }
 
@@ -219,9 +223,13 @@ func TestRouter_Match(t *testing.T) {
 	w = httptest.NewRecorder()
 	r.ServeHTTP(w, req)
 
-	if w.Code != http.StatusNotFound {
+	if w.Code != http.StatusMethodNotAllowed {
 		t.Errorf("PUT /api should not match, got status %d", w.Code)
 	}
+
+	if allow := w.Header().Get("Allow"); allow != "GET, POST" {
+		t.Errorf("Expected Allow header %q, got %q", "GET, POST", allow)
+	}
 }
 
 func TestRouter_BasePath(t *testing.T) {
@@ -241,6 +249,53 @@ func TestRouter_BasePath(t *testing.T) {
 	}
 }
 
+func TestRouter_SetBasePath(t *testing.T) {
+	r := New(WithBasePath("/v1"))
+
+	r.Name("user", r.Get("/users/:id", func(ctx *PageContext) (templ.Component, error) {
+		return templ.Raw("User " + ctx.Param("id")), nil
+	}))
+
+	r.SetBasePath("/admin/v1")
+
+	if r.BasePath() != "/admin/v1" {
+		t.Errorf("Expected base path /admin/v1, got %q", r.BasePath())
+	}
+
+	tests := []struct {
+		path string
+		want int
+	}{
+		{"/admin/v1/users/1", http.StatusOK},
+		{"/v1/users/1", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(MethodGet, tt.path, nil))
+
+		if w.Code != tt.want {
+			t.Errorf("GET %s: expected status %d, got %d", tt.path, tt.want, w.Code)
+		}
+	}
+
+	if got := r.URL("user", 7); got != "/admin/v1/users/7" {
+		t.Errorf("Expected URL /admin/v1/users/7, got %q", got)
+	}
+
+	// Routes registered afterwards use the new base path
+	r.Get("/health", func(ctx *PageContext) (templ.Component, error) {
+		return templ.Raw("OK"), nil
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(MethodGet, "/admin/v1/health", nil))
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", w.Code)
+	}
+}
+
 func TestRouter_CustomNotFound(t *testing.T) {
 	customHandler := func(ctx *PageContext) (templ.Component, error) {
 		ctx.ResponseWriter.WriteHeader(http.StatusNotFound)
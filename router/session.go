@@ -0,0 +1,61 @@
+package router
+
+import (
+	"github.com/a-h/templ"
+
+	"github.com/xraph/forgeui/htmx"
+	"github.com/xraph/forgeui/session"
+)
+
+// Session returns the request's session, or nil when no session middleware
+// (Sessions, session.Middleware or forgeui.WithSessions) is installed.
+// It is the same object bridge functions get from bridge.Context.Session().
+func (c *PageContext) Session() *session.Session {
+	return session.FromContext(c.Context())
+}
+
+// Sessions returns middleware that loads the session from store once per
+// request and saves it before the response is written. When a session was
+// already loaded by session.Middleware, that session is used.
+//
+// For full page loads it also takes the pending flash messages out of the
+// session before the page renders, so they are shown exactly once even when
+// the session is saved before rendering starts. Plain HTMX requests leave
+// them for the next full page.
+func Sessions(store session.Store) Middleware {
+	return func(next PageHandler) PageHandler {
+		return func(ctx *PageContext) (templ.Component, error) {
+			if s := ctx.Session(); s != nil {
+				fetchFlashes(ctx, s)
+				return next(ctx)
+			}
+
+			sw, req := session.Load(ctx.ResponseWriter, ctx.Request, store)
+
+			// Update the context in place so rendering sees the session too
+			ctx.ResponseWriter = sw
+			ctx.Request = req
+
+			fetchFlashes(ctx, sw.Session())
+
+			comp, err := next(ctx)
+
+			sw.Save()
+
+			return comp, err
+		}
+	}
+}
+
+// fetchFlashes takes the flash messages for a full page load.
+func fetchFlashes(ctx *PageContext, s *session.Session) {
+	if ctx.Method() != MethodGet {
+		return
+	}
+
+	if htmx.IsHTMX(ctx.Request) && !htmx.IsHTMXBoosted(ctx.Request) {
+		return
+	}
+
+	s.Flashes()
+}
@@ -0,0 +1,130 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrNoStaticParams is returned by Route.StaticURLs for parameterized routes
+// that have no ParamEnumerator.
+var ErrNoStaticParams = errors.New("router: route has parameters but no static params enumerator")
+
+// ParamEnumerator lists every parameter set a parameterized route should be
+// rendered with when the site is exported to static files.
+//
+// Example:
+//
+//	router.Get("/blog/:slug", blogPost).StaticParams(func(ctx context.Context) ([]router.Params, error) {
+//	    posts, err := db.ListPosts(ctx)
+//	    if err != nil {
+//	        return nil, err
+//	    }
+//	    params := make([]router.Params, len(posts))
+//	    for i, p := range posts {
+//	        params[i] = router.Params{"slug": p.Slug}
+//	    }
+//	    return params, nil
+//	})
+type ParamEnumerator func(ctx context.Context) ([]Params, error)
+
+// StaticParams sets the enumerator used to render this route during static export.
+func (r *Route) StaticParams(fn ParamEnumerator) *Route {
+	r.staticParams = fn
+	return r
+}
+
+// HasParams reports whether the route pattern contains parameters.
+func (r *Route) HasParams() bool {
+	return len(r.paramNames) > 0
+}
+
+// StaticURLs returns the URLs to render for this route during static export:
+// the pattern itself for static routes, or one URL per parameter set from
+// the route's ParamEnumerator. Routes in a LocalePrefix group are rendered
+// in each locale. It returns ErrNoStaticParams for a parameterized route
+// without an enumerator.
+func (r *Route) StaticURLs(ctx context.Context) ([]string, error) {
+	if !r.HasParams() {
+		u, err := r.BuildURLMap(nil)
+		if err != nil {
+			return nil, err
+		}
+
+		return []string{u}, nil
+	}
+
+	sets := []Params{{}}
+
+	switch {
+	case r.staticParams != nil:
+		var err error
+
+		sets, err = r.staticParams(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("router: %s: enumerating params: %w", r.Pattern, err)
+		}
+	case len(r.locales) == 0 || len(r.paramNames) > 1:
+		return nil, ErrNoStaticParams
+	}
+
+	sets = r.expandLocales(sets)
+
+	urls := make([]string, 0, len(sets))
+
+	for _, params := range sets {
+		values := make(map[string]any, len(params))
+		for k, v := range params {
+			values[k] = v
+		}
+
+		u, err := r.BuildURLMap(values)
+		if err != nil {
+			return nil, err
+		}
+
+		urls = append(urls, u)
+	}
+
+	return urls, nil
+}
+
+// expandLocales renders routes in a LocalePrefix group in each of the
+// group's locales, unless a parameter set names its locale.
+func (r *Route) expandLocales(sets []Params) []Params {
+	if len(r.locales) == 0 {
+		return sets
+	}
+
+	expanded := make([]Params, 0, len(sets)*len(r.locales))
+
+	for _, params := range sets {
+		if _, ok := params[LocaleParam]; ok {
+			expanded = append(expanded, params)
+			continue
+		}
+
+		for _, locale := range r.locales {
+			p := make(Params, len(params)+1)
+			for k, v := range params {
+				p[k] = v
+			}
+
+			p[LocaleParam] = locale
+			expanded = append(expanded, p)
+		}
+	}
+
+	return expanded
+}
+
+// Routes returns the registered routes in match priority order.
+func (r *Router) Routes() []*Route {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	routes := make([]*Route, len(r.routes))
+	copy(routes, r.routes)
+
+	return routes
+}
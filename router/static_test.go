@@ -0,0 +1,78 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestRoute_StaticURLs(t *testing.T) {
+	r := New()
+
+	slugs := func(context.Context) ([]Params, error) {
+		return []Params{{"slug": "hello world"}, {"slug": "second"}}, nil
+	}
+
+	tests := []struct {
+		name    string
+		route   *Route
+		want    []string
+		wantErr error
+	}{
+		{
+			name:  "static route",
+			route: r.Get("/about", nil),
+			want:  []string{"/about"},
+		},
+		{
+			name:  "enumerated params",
+			route: r.Get("/blog/:slug", nil).StaticParams(slugs),
+			want:  []string{"/blog/hello%20world", "/blog/second"},
+		},
+		{
+			name:    "params without enumerator",
+			route:   r.Get("/users/:id", nil),
+			wantErr: ErrNoStaticParams,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.route.StaticURLs(context.Background())
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRoute_StaticURLsInvalidParams(t *testing.T) {
+	route := New().Get("/users/{id:int}", nil).StaticParams(func(context.Context) ([]Params, error) {
+		return []Params{{"id": "abc"}}, nil
+	})
+
+	if _, err := route.StaticURLs(context.Background()); err == nil {
+		t.Error("Expected an error for params violating the constraint")
+	}
+}
+
+func TestRouter_Routes(t *testing.T) {
+	r := New()
+	r.Get("/users/:id", nil)
+	r.Get("/users/new", nil)
+
+	routes := r.Routes()
+	if len(routes) != 2 || routes[0].Pattern != "/users/new" {
+		t.Errorf("Expected routes in priority order, got %v", routes)
+	}
+
+	routes[0] = nil
+	if r.Routes()[0] == nil {
+		t.Error("Expected Routes to return a copy")
+	}
+}
@@ -0,0 +1,35 @@
+package router
+
+import (
+	"context"
+	"io"
+
+	"github.com/a-h/templ"
+
+	"github.com/xraph/forgeui/tracing"
+)
+
+// WithTracer traces requests that don't carry a tracer yet (see
+// tracing.Middleware). Each request gets a router.request span with
+// router.match, router.loader, router.render, router.layout and router.page
+// spans inside it.
+func WithTracer(t tracing.Tracer) RouterOption {
+	return func(r *Router) {
+		r.tracer = t
+	}
+}
+
+// traced returns c rendering inside a span.
+func traced(c templ.Component, name string, attrs ...tracing.Attr) templ.Component {
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		ctx, span := tracing.Start(ctx, name, attrs...)
+		defer span.End()
+
+		err := c.Render(ctx, w)
+		if err != nil {
+			span.RecordError(err)
+		}
+
+		return err
+	})
+}
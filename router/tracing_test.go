@@ -0,0 +1,138 @@
+package router
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/a-h/templ"
+
+	"github.com/xraph/forgeui/tracing"
+)
+
+func TestRouter_Tracing(t *testing.T) {
+	exporter := tracing.NewInMemoryExporter()
+	r := New(WithTracer(tracing.NewTracer(exporter)))
+
+	layout := func(ctx *PageContext, content templ.Component) templ.Component {
+		return templ.ComponentFunc(func(tCtx context.Context, w io.Writer) error {
+			return content.Render(tCtx, w)
+		})
+	}
+
+	loader := func(ctx context.Context, params Params) (any, error) {
+		return "data", nil
+	}
+
+	r.RegisterLayout("root", layout, WithLayoutLoader(loader))
+	r.RegisterLayout("dashboard", layout, WithParentLayout("root"))
+
+	r.Get("/users/:id", func(ctx *PageContext) (templ.Component, error) {
+		return templ.Raw("user"), nil
+	}).Loader(loader).SetLayout("dashboard")
+
+	remote := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+	req := httptest.NewRequest(MethodGet, "/users/42", nil)
+	req.Header.Set(tracing.TraceParentHeader, remote)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	requests := exporter.Named("router.request")
+	if len(requests) != 1 {
+		t.Fatalf("Expected 1 router.request span, got %d", len(requests))
+	}
+
+	root := requests[0]
+	if root.TraceID.String() != "4bf92f3577b34da6a3ce929d0e0e4736" || root.ParentID.String() != "00f067aa0ba902b7" {
+		t.Errorf("Expected the request span to continue the remote trace, got trace %s parent %s", root.TraceID, root.ParentID)
+	}
+
+	if route, _ := root.Attr(tracing.KeyRoute); route != "/users/:id" {
+		t.Errorf("Expected route attribute /users/:id, got %v", route)
+	}
+
+	if status, _ := root.Attr(tracing.KeyStatus); status != http.StatusOK {
+		t.Errorf("Expected status attribute 200, got %v", status)
+	}
+
+	spans := exporter.Spans()
+	byID := make(map[tracing.SpanID]tracing.SpanData, len(spans))
+
+	for _, s := range spans {
+		byID[s.SpanID] = s
+
+		if s.TraceID != root.TraceID {
+			t.Errorf("Expected span %s in trace %s, got %s", s.Name, root.TraceID, s.TraceID)
+		}
+	}
+
+	parentName := func(s tracing.SpanData) string {
+		return byID[s.ParentID].Name
+	}
+
+	tests := []struct {
+		name   string
+		count  int
+		parent string
+	}{
+		{"router.match", 1, "router.request"},
+		{"router.loader", 2, "router.request"},
+		{"router.render", 1, "router.request"},
+		{"router.page", 1, "router.layout"},
+	}
+
+	for _, tt := range tests {
+		got := exporter.Named(tt.name)
+		if len(got) != tt.count {
+			t.Errorf("Expected %d %s spans, got %d", tt.count, tt.name, len(got))
+			continue
+		}
+
+		for _, s := range got {
+			if parentName(s) != tt.parent {
+				t.Errorf("Expected %s to be a child of %s, got %q", tt.name, tt.parent, parentName(s))
+			}
+		}
+	}
+
+	// Layouts nest from the outermost one down to the page
+	layouts := map[any]tracing.SpanData{}
+	for _, s := range exporter.Named("router.layout") {
+		name, _ := s.Attr(tracing.KeyLayout)
+		layouts[name] = s
+	}
+
+	if parentName(layouts["root"]) != "router.render" {
+		t.Errorf("Expected the root layout to be a child of router.render, got %q", parentName(layouts["root"]))
+	}
+
+	if layouts["dashboard"].ParentID != layouts["root"].SpanID {
+		t.Error("Expected the dashboard layout to be a child of the root layout")
+	}
+}
+
+func TestRouter_TracingDisabled(t *testing.T) {
+	r := New()
+
+	r.Get("/", func(ctx *PageContext) (templ.Component, error) {
+		if tracing.TraceParent(ctx.Context()) != "" {
+			t.Error("Expected no trace context without a tracer")
+		}
+
+		return templ.Raw("home"), nil
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(MethodGet, "/", nil))
+
+	if w.Body.String() != "home" {
+		t.Errorf("Expected 'home', got '%s'", w.Body.String())
+	}
+}
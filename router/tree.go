@@ -0,0 +1,183 @@
+package router
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// node is a node in the route tree. The tree is a radix tree keyed by
+// path segments: every edge consumes one segment, except catch-all edges,
+// which consume the rest of the path.
+//
+// Lookups prefer static children, then typed parameters, then untyped
+// parameters, then catch-alls, and backtrack when a branch doesn't lead to
+// a route. Registrations that the lookup couldn't tell apart fail instead.
+type node struct {
+	seg      segment          // the edge leading to this node
+	static   map[string]*node // static children by segment text
+	typed    *node            // parameter child with a constraint
+	param    *node            // parameter child without a constraint
+	catchAll *node            // catch-all child
+	routes   map[string]*Route
+}
+
+// insert adds route to the tree, once per optional-segment variant.
+func (n *node) insert(route *Route) error {
+	required := len(route.segments)
+	for required > 0 && route.segments[required-1].optional {
+		required--
+	}
+
+	for end := required; end <= len(route.segments); end++ {
+		if err := n.insertSegments(route, route.segments[:end]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// insertSegments adds route at the node reached through segments.
+func (n *node) insertSegments(route *Route, segments []segment) error {
+	current := n
+
+	for _, seg := range segments {
+		child, err := current.child(seg, route)
+		if err != nil {
+			return err
+		}
+
+		current = child
+	}
+
+	if current.routes == nil {
+		current.routes = make(map[string]*Route)
+	}
+
+	if existing, ok := current.routes[route.Method]; ok {
+		return fmt.Errorf("router: %s %s conflicts with %s %s", route.Method, route.Pattern, existing.Method, existing.Pattern)
+	}
+
+	current.routes[route.Method] = route
+
+	return nil
+}
+
+// child returns the child for seg, creating it if needed.
+func (n *node) child(seg segment, route *Route) (*node, error) {
+	switch seg.kind {
+	case segmentStatic:
+		if n.static == nil {
+			n.static = make(map[string]*node)
+		}
+
+		child, ok := n.static[seg.value]
+		if !ok {
+			child = &node{seg: seg}
+			n.static[seg.value] = child
+		}
+
+		return child, nil
+	case segmentParam:
+		slot := &n.param
+		if seg.re != nil {
+			slot = &n.typed
+		}
+
+		if *slot == nil {
+			*slot = &node{seg: seg}
+		} else if (*slot).seg.constraint != seg.constraint {
+			// Two constraints at the same position could both accept a
+			// value, and neither takes precedence.
+			return nil, fmt.Errorf("router: %s %s is ambiguous: %s and %s match the same segment",
+				route.Method, route.Pattern, seg.describe(), (*slot).seg.describe())
+		}
+
+		return *slot, nil
+	default:
+		if n.catchAll == nil {
+			n.catchAll = &node{seg: seg}
+		}
+
+		return n.catchAll, nil
+	}
+}
+
+// lookup finds the route for method and path segments, appending the
+// parameter values it captures to values.
+func (n *node) lookup(method string, parts []string, values []string) (*Route, []string) {
+	if len(parts) == 0 {
+		if route, ok := n.routes[method]; ok {
+			return route, values
+		}
+
+		return nil, nil
+	}
+
+	if child, ok := n.static[parts[0]]; ok {
+		if route, v := child.lookup(method, parts[1:], values); route != nil {
+			return route, v
+		}
+	}
+
+	for _, child := range []*node{n.typed, n.param} {
+		if child == nil || !child.seg.matches(parts[0]) {
+			continue
+		}
+
+		if route, v := child.lookup(method, parts[1:], append(values, parts[0])); route != nil {
+			return route, v
+		}
+	}
+
+	if n.catchAll != nil {
+		if route, ok := n.catchAll.routes[method]; ok {
+			return route, append(values, strings.Join(parts, "/"))
+		}
+	}
+
+	return nil, nil
+}
+
+// allowed adds the methods of every route matching path segments to methods.
+func (n *node) allowed(parts []string, methods map[string]bool) {
+	if len(parts) == 0 {
+		for method := range n.routes {
+			methods[method] = true
+		}
+
+		return
+	}
+
+	if child, ok := n.static[parts[0]]; ok {
+		child.allowed(parts[1:], methods)
+	}
+
+	for _, child := range []*node{n.typed, n.param} {
+		if child != nil && child.seg.matches(parts[0]) {
+			child.allowed(parts[1:], methods)
+		}
+	}
+
+	if n.catchAll != nil {
+		for method := range n.catchAll.routes {
+			methods[method] = true
+		}
+	}
+}
+
+// allowedMethods returns the sorted methods registered for path.
+func (n *node) allowedMethods(path string) []string {
+	set := make(map[string]bool)
+	n.allowed(splitPath(path), set)
+
+	methods := make([]string, 0, len(set))
+	for method := range set {
+		methods = append(methods, method)
+	}
+
+	sort.Strings(methods)
+
+	return methods
+}
@@ -0,0 +1,243 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+)
+
+// routeName returns a handler that renders name, to tell routes apart.
+func routeName(name string) PageHandler {
+	return func(ctx *PageContext) (templ.Component, error) {
+		return templ.Raw(name), nil
+	}
+}
+
+func TestTreeMatching(t *testing.T) {
+	r := New()
+	r.Get("/", routeName("home"))
+	r.Get("/users/new", routeName("new"))
+	r.Get("/users/{id:int}", routeName("user-id"))
+	r.Get("/users/:name", routeName("user-name"))
+	r.Get("/users/:name/posts", routeName("user-posts"))
+	r.Get("/posts/{slug:[a-z-]+}", routeName("post"))
+	r.Get("/blog/{page:int?}", routeName("blog"))
+	r.Get("/files/*path", routeName("files"))
+	r.Get("/files/readme", routeName("readme"))
+
+	tests := []struct {
+		path     string
+		expected string
+		params   Params
+	}{
+		{"/", "home", Params{}},
+		{"/users/new", "new", Params{}},
+		{"/users/42", "user-id", Params{"id": "42"}},
+		{"/users/alice", "user-name", Params{"name": "alice"}},
+		{"/users/42/posts", "user-posts", Params{"name": "42"}},
+		{"/posts/hello-world", "post", Params{"slug": "hello-world"}},
+		{"/blog", "blog", Params{}},
+		{"/blog/2", "blog", Params{"page": "2"}},
+		{"/files/readme", "readme", Params{}},
+		{"/files/docs/guide.md", "files", Params{"path": "docs/guide.md"}},
+		{"/posts/Hello", "", nil},
+		{"/blog/two", "", nil},
+		{"/files", "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			route, params := r.findRoute(MethodGet, tt.path)
+
+			if tt.expected == "" {
+				if route != nil {
+					t.Fatalf("Expected no match, got %s", route.Pattern)
+				}
+
+				return
+			}
+
+			if route == nil {
+				t.Fatalf("Expected %s to match", tt.path)
+			}
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(MethodGet, tt.path, nil))
+
+			if w.Body.String() != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, w.Body.String())
+			}
+
+			if len(params) != len(tt.params) {
+				t.Errorf("Expected params %v, got %v", tt.params, params)
+			}
+
+			for k, v := range tt.params {
+				if params[k] != v {
+					t.Errorf("Expected %s=%s, got %s", k, v, params[k])
+				}
+			}
+		})
+	}
+}
+
+func TestTreeBacktracking(t *testing.T) {
+	r := New()
+	r.Get("/a/static/x", routeName("static"))
+	r.Get("/a/:p/y", routeName("param"))
+
+	route, params := r.findRoute(MethodGet, "/a/static/y")
+	if route == nil || route.Pattern != "/a/:p/y" {
+		t.Fatalf("Expected /a/:p/y to match, got %v", route)
+	}
+
+	if params["p"] != "static" {
+		t.Errorf("Expected p=static, got %s", params["p"])
+	}
+}
+
+func TestTreeRegistrationConflicts(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+	}{
+		{"duplicate", []string{"/users/:id", "/users/:id"}},
+		{"renamed param", []string{"/users/:id", "/users/{uid}"}},
+		{"same constraint", []string{"/users/{id:int}", "/users/{n:int}"}},
+		{"different constraints", []string{"/users/{id:int}", "/users/{slug:[a-z-]+}"}},
+		{"optional", []string{"/blog", "/blog/{page?}"}},
+		{"catch-all", []string{"/files/*path", "/files/*rest"}},
+		{"invalid constraint", []string{"/users/{id:[0-9}"}},
+		{"catch-all not last", []string{"/files/*path/edit"}},
+		{"optional not last", []string{"/blog/{page?}/edit"}},
+		{"duplicate name", []string{"/users/:id/posts/:id"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := New()
+
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Expected registration of %v to panic", tt.patterns)
+				}
+			}()
+
+			for _, pattern := range tt.patterns {
+				r.Get(pattern, routeName(pattern))
+			}
+		})
+	}
+}
+
+func TestTreeSamePatternDifferentMethods(t *testing.T) {
+	r := New()
+	r.Get("/users/{id:int}", routeName("get"))
+	r.Post("/users/{id:int}", routeName("post"))
+
+	route, _ := r.findRoute(MethodPost, "/users/1")
+	if route == nil || route.Method != MethodPost {
+		t.Fatalf("Expected POST route, got %v", route)
+	}
+}
+
+func TestMethodNotAllowed(t *testing.T) {
+	r := New()
+	r.Get("/users/{id:int}", routeName("get"))
+	r.Delete("/users/{id:int}", routeName("delete"))
+	r.SetErrorPage(http.StatusMethodNotAllowed, func(ctx *PageContext) (templ.Component, error) {
+		ctx.ResponseWriter.WriteHeader(http.StatusMethodNotAllowed)
+		return templ.Raw("custom 405"), nil
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(MethodPost, "/users/1", nil))
+
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected status 405, got %d", w.Code)
+	}
+
+	if allow := w.Header().Get("Allow"); allow != "DELETE, GET" {
+		t.Errorf("Expected Allow %q, got %q", "DELETE, GET", allow)
+	}
+
+	if !strings.Contains(w.Body.String(), "custom 405") {
+		t.Errorf("Expected custom 405 page, got %q", w.Body.String())
+	}
+
+	// A path that fails the constraint is not found at all
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(MethodPost, "/users/abc", nil))
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404, got %d", w.Code)
+	}
+}
+
+func TestBuildURLValidatesConstraints(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		params   []any
+		expected string
+		wantErr  bool
+	}{
+		{"/users/{id:int}", []any{42}, "/users/42", false},
+		{"/users/{id:int}", []any{"abc"}, "", true},
+		{"/users/{id:int}", nil, "", true},
+		{"/posts/{slug:[a-z-]+}", []any{"hello-world"}, "/posts/hello-world", false},
+		{"/posts/{slug:[a-z-]+}", []any{"Hello"}, "", true},
+		{"/blog/{page:int?}", nil, "/blog", false},
+		{"/blog/{page:int?}", []any{3}, "/blog/3", false},
+		{"/search/:q", []any{"a b"}, "/search/a%20b", false},
+		{"/files/*path", []any{"docs/a b.md"}, "/files/docs/a%20b.md", false},
+		{"/files/*path", []any{""}, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			route := newRoute(tt.pattern, MethodGet, nil)
+
+			url, err := route.BuildURL(tt.params...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("BuildURL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if url != tt.expected {
+				t.Errorf("BuildURL() = %q, want %q", url, tt.expected)
+			}
+
+			if route.URL(tt.params...) != tt.expected {
+				t.Errorf("URL() = %q, want %q", route.URL(tt.params...), tt.expected)
+			}
+		})
+	}
+}
+
+func TestRouterBuildURL(t *testing.T) {
+	r := New()
+	r.Get("/users/{id:int}", routeName("user")).WithName("user")
+	r.Name("user", r.routes[0])
+
+	if _, err := r.BuildURL("missing"); err == nil {
+		t.Error("Expected error for unknown route")
+	}
+
+	if _, err := r.BuildURL("user", "abc"); err == nil {
+		t.Error("Expected error for invalid parameter")
+	}
+
+	if url := r.URL("user", "abc"); url != "" {
+		t.Errorf("Expected empty URL, got %q", url)
+	}
+
+	if url := r.URL("user", 7); url != "/users/7" {
+		t.Errorf("Expected /users/7, got %q", url)
+	}
+
+	if url, _ := r.routes[0].BuildURLMap(map[string]any{"id": "x"}); url != "" {
+		t.Errorf("Expected empty URL, got %q", url)
+	}
+}
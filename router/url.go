@@ -2,103 +2,118 @@ package router
 
 import (
 	"fmt"
+	"net/url"
 	"strings"
 )
 
 // URL generates a URL for a named route with the given parameters
 // Example: router.URL("user", 123) -> "/users/123"
+//
+// It returns an empty string if the route doesn't exist or the parameters
+// are invalid; use BuildURL to get the reason.
 func (r *Router) URL(name string, params ...any) string {
+	u, _ := r.BuildURL(name, params...)
+	return u
+}
+
+// BuildURL generates a URL for a named route, returning an error if the
+// route doesn't exist or a parameter is missing or violates its constraint.
+func (r *Router) BuildURL(name string, params ...any) (string, error) {
 	r.mu.RLock()
 	route, ok := r.namedRoutes[name]
 	r.mu.RUnlock()
 
 	if !ok {
-		return ""
+		return "", fmt.Errorf("router: no route named %q", name)
 	}
 
-	return route.URL(params...)
+	return route.BuildURL(params...)
 }
 
-// URL generates a URL from this route's pattern with the given parameters
+// URL generates a URL from this route's pattern with the given parameters.
+// It returns an empty string if the parameters are invalid.
 func (r *Route) URL(params ...any) string {
-	pattern := r.Pattern
-	paramIdx := 0
+	u, _ := r.BuildURL(params...)
+	return u
+}
 
-	// Replace parameters in pattern
-	segments := strings.Split(strings.Trim(pattern, "/"), "/")
-	result := make([]string, 0, len(segments))
-
-	for _, segment := range segments {
-		switch {
-		case strings.HasPrefix(segment, ":"):
-			// Named parameter - replace with provided value
-			if paramIdx < len(params) {
-				result = append(result, fmt.Sprint(params[paramIdx]))
-				paramIdx++
-			} else {
-				// Not enough parameters provided
-				result = append(result, segment)
-			}
-		case strings.HasPrefix(segment, "*"):
-			// Wildcard - replace with provided value
-			if paramIdx < len(params) {
-				result = append(result, fmt.Sprint(params[paramIdx]))
-				paramIdx++
-			} else {
-				result = append(result, segment)
-			}
-		default:
-			// Static segment
-			result = append(result, segment)
+// BuildURL generates a URL from this route's pattern with positional
+// parameters. Values are checked against the same constraints the router
+// matches with, so the URL always routes back to this route.
+// Optional parameters may be left out at the end.
+func (r *Route) BuildURL(params ...any) (string, error) {
+	return r.build(func(i int, _ string) (any, bool) {
+		if i < len(params) {
+			return params[i], true
 		}
-	}
 
-	if len(result) == 0 {
-		return "/"
-	}
-
-	return "/" + strings.Join(result, "/")
+		return nil, false
+	})
 }
 
-// URLMap generates a URL from this route's pattern with named parameters
+// URLMap generates a URL from this route's pattern with named parameters.
+// It returns an empty string if the parameters are invalid.
 // Example: route.URLMap(map[string]interface{}{"id": 123, "action": "edit"})
 func (r *Route) URLMap(params map[string]any) string {
-	pattern := r.Pattern
-
-	// Replace parameters in pattern
-	segments := strings.Split(strings.Trim(pattern, "/"), "/")
-	result := make([]string, 0, len(segments))
-
-	for _, segment := range segments {
-		if after, ok := strings.CutPrefix(segment, ":"); ok {
-			// Named parameter
-			paramName := after
-			if val, ok := params[paramName]; ok {
-				result = append(result, fmt.Sprint(val))
-			} else {
-				result = append(result, segment)
+	u, _ := r.BuildURLMap(params)
+	return u
+}
+
+// BuildURLMap is like BuildURL with named parameters.
+func (r *Route) BuildURLMap(params map[string]any) (string, error) {
+	return r.build(func(_ int, name string) (any, bool) {
+		v, ok := params[name]
+		return v, ok
+	})
+}
+
+// build generates a URL, looking parameter values up by position and name.
+func (r *Route) build(lookup func(i int, name string) (any, bool)) (string, error) {
+	result := make([]string, 0, len(r.segments))
+	paramIdx := 0
+
+	for _, seg := range r.segments {
+		if seg.kind == segmentStatic {
+			result = append(result, seg.value)
+			continue
+		}
+
+		val, ok := lookup(paramIdx, seg.value)
+		paramIdx++
+
+		if !ok {
+			if seg.optional {
+				break
 			}
-		} else if after, ok := strings.CutPrefix(segment, "*"); ok {
-			// Wildcard
-			paramName := after
-			if paramName == "" {
-				paramName = "wildcard"
+
+			return "", fmt.Errorf("router: %s: missing parameter %q", r.Pattern, seg.value)
+		}
+
+		value := fmt.Sprint(val)
+
+		if seg.kind == segmentCatchAll {
+			if strings.Trim(value, "/") == "" {
+				return "", fmt.Errorf("router: %s: empty parameter %q", r.Pattern, seg.value)
 			}
 
-			if val, ok := params[paramName]; ok {
-				result = append(result, fmt.Sprint(val))
-			} else {
-				result = append(result, segment)
+			for part := range strings.SplitSeq(strings.Trim(value, "/"), "/") {
+				result = append(result, url.PathEscape(part))
 			}
-		} else {
-			// Static segment
-			result = append(result, segment)
+
+			continue
 		}
+
+		if !seg.matches(value) {
+			return "", fmt.Errorf("router: %s: parameter %q value %q does not match %s",
+				r.Pattern, seg.value, value, seg.describe())
+		}
+
+		result = append(result, url.PathEscape(value))
 	}
 
 	if len(result) == 0 {
-		return "/"
+		return "/", nil
 	}
 
-	return "/" + strings.Join(result, "/")
+	return "/" + strings.Join(result, "/"), nil
 }
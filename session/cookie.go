@@ -0,0 +1,216 @@
+package session
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// maxCookieSize is the largest cookie browsers are required to accept.
+const maxCookieSize = 4096
+
+// CookieStore keeps the whole session in a cookie. The payload is encrypted
+// with AES-256-GCM and signed with HMAC-SHA256, so clients can neither read
+// nor forge it. Values are JSON-encoded: numbers come back as float64 and
+// structs as map[string]any.
+type CookieStore struct {
+	keys    []cookieKeys
+	options Options
+}
+
+// cookieKeys are the signing and encryption keys derived from one secret.
+type cookieKeys struct {
+	hash  []byte
+	block cipher.AEAD
+}
+
+// NewCookieStore creates a cookie store. The secret must be at least 32
+// bytes; separate signing and encryption keys are derived from it.
+//
+// To rotate secrets, use NewCookieStoreWithRotation.
+func NewCookieStore(secret []byte, opts ...Option) (*CookieStore, error) {
+	return NewCookieStoreWithRotation([][]byte{secret}, opts...)
+}
+
+// NewCookieStoreWithRotation creates a cookie store that signs with the
+// first secret and also accepts cookies signed with the others. Sessions
+// move to the new secret the next time they change.
+func NewCookieStoreWithRotation(secrets [][]byte, opts ...Option) (*CookieStore, error) {
+	if len(secrets) == 0 {
+		return nil, errors.New("session: cookie store needs a secret")
+	}
+
+	store := &CookieStore{options: newOptions(opts)}
+
+	for _, secret := range secrets {
+		keys, err := deriveKeys(secret)
+		if err != nil {
+			return nil, err
+		}
+
+		store.keys = append(store.keys, keys)
+	}
+
+	return store, nil
+}
+
+// deriveKeys derives the signing and encryption keys from secret.
+func deriveKeys(secret []byte) (cookieKeys, error) {
+	if len(secret) < 32 {
+		return cookieKeys{}, errors.New("session: secret must be at least 32 bytes")
+	}
+
+	derive := func(purpose string) []byte {
+		mac := hmac.New(sha256.New, secret)
+		mac.Write([]byte("forgeui/session/" + purpose))
+
+		return mac.Sum(nil)
+	}
+
+	block, err := aes.NewCipher(derive("encrypt"))
+	if err != nil {
+		return cookieKeys{}, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return cookieKeys{}, err
+	}
+
+	return cookieKeys{hash: derive("sign"), block: aead}, nil
+}
+
+// Load decodes the session cookie. Missing, tampered or expired cookies
+// yield a new session.
+func (cs *CookieStore) Load(r *http.Request) (*Session, error) {
+	cookie, err := r.Cookie(cs.options.Name)
+	if err != nil {
+		return New(), nil
+	}
+
+	rec, ok := cs.decode(cookie.Value)
+	if !ok {
+		return New(), nil
+	}
+
+	return fromRecord(rec), nil
+}
+
+// Save encodes the session into its cookie.
+func (cs *CookieStore) Save(w http.ResponseWriter, _ *http.Request, s *Session) error {
+	rec := s.record()
+
+	if s.saved().destroyed {
+		http.SetCookie(w, cs.options.expired())
+		return nil
+	}
+
+	value, err := cs.encode(rec)
+	if err != nil {
+		return err
+	}
+
+	cookie := cs.options.cookie(value)
+	if len(cookie.String()) > maxCookieSize {
+		return ErrCookieTooLarge
+	}
+
+	http.SetCookie(w, cookie)
+
+	return nil
+}
+
+// encode encrypts and signs rec as <payload>.<signature>, where payload is
+// the signing time followed by the GCM nonce and ciphertext.
+func (cs *CookieStore) encode(rec record) (string, error) {
+	plain, err := json.Marshal(rec)
+	if err != nil {
+		return "", err
+	}
+
+	keys := cs.keys[0]
+
+	payload := make([]byte, 8, 8+keys.block.NonceSize()+len(plain)+keys.block.Overhead())
+	binary.BigEndian.PutUint64(payload, uint64(time.Now().Unix()))
+
+	nonce := make([]byte, keys.block.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	payload = append(payload, nonce...)
+	payload = keys.block.Seal(payload, nonce, plain, []byte(cs.options.Name))
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(cs.sign(keys, encoded)), nil
+}
+
+// decode verifies and decrypts a cookie value with any of the store's keys.
+func (cs *CookieStore) decode(value string) (record, bool) {
+	encoded, sig, ok := strings.Cut(value, ".")
+	if !ok {
+		return record{}, false
+	}
+
+	mac, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil {
+		return record{}, false
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return record{}, false
+	}
+
+	for _, keys := range cs.keys {
+		if !hmac.Equal(mac, cs.sign(keys, encoded)) {
+			continue
+		}
+
+		nonceSize := keys.block.NonceSize()
+		if len(payload) < 8+nonceSize {
+			return record{}, false
+		}
+
+		signedAt := time.Unix(int64(binary.BigEndian.Uint64(payload[:8])), 0)
+		if cs.options.MaxAge > 0 && time.Since(signedAt) > cs.options.MaxAge {
+			return record{}, false
+		}
+
+		nonce := payload[8 : 8+nonceSize]
+
+		plain, err := keys.block.Open(nil, nonce, payload[8+nonceSize:], []byte(cs.options.Name))
+		if err != nil {
+			return record{}, false
+		}
+
+		var rec record
+		if err := json.Unmarshal(plain, &rec); err != nil || rec.ID == "" {
+			return record{}, false
+		}
+
+		return rec, true
+	}
+
+	return record{}, false
+}
+
+// sign returns the HMAC of the cookie name and encoded payload.
+func (cs *CookieStore) sign(keys cookieKeys, encoded string) []byte {
+	mac := hmac.New(sha256.New, keys.hash)
+	mac.Write([]byte(cs.options.Name))
+	mac.Write([]byte{'|'})
+	mac.Write([]byte(encoded))
+
+	return mac.Sum(nil)
+}
@@ -0,0 +1,126 @@
+package session
+
+import (
+	"maps"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// MemoryStore keeps sessions in process memory and puts only the random
+// session ID in the cookie. Sessions expire after the TTL passes without a
+// request; expired sessions are swept as new ones are saved.
+//
+// Sessions are lost on restart and not shared between instances, which
+// makes MemoryStore best suited to development and single-instance apps.
+type MemoryStore struct {
+	mu        sync.Mutex
+	ttl       time.Duration
+	sessions  map[string]*memoryEntry
+	lastSweep time.Time
+	options   Options
+	now       func() time.Time
+}
+
+// memoryEntry is a stored session and its expiry.
+type memoryEntry struct {
+	rec     record
+	expires time.Time
+}
+
+// NewMemoryStore creates an in-memory store whose sessions expire after ttl
+// of inactivity.
+func NewMemoryStore(ttl time.Duration, opts ...Option) *MemoryStore {
+	return &MemoryStore{
+		ttl:      ttl,
+		sessions: make(map[string]*memoryEntry),
+		options:  newOptions(opts),
+		now:      time.Now,
+	}
+}
+
+// Load returns the session named by the request's cookie, extending its
+// expiry. Unknown or expired IDs yield a new session.
+func (ms *MemoryStore) Load(r *http.Request) (*Session, error) {
+	cookie, err := r.Cookie(ms.options.Name)
+	if err != nil {
+		return New(), nil
+	}
+
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	entry, ok := ms.sessions[cookie.Value]
+	if !ok {
+		return New(), nil
+	}
+
+	now := ms.now()
+	if now.After(entry.expires) {
+		delete(ms.sessions, cookie.Value)
+		return New(), nil
+	}
+
+	entry.expires = now.Add(ms.ttl)
+
+	// Copy so concurrent requests don't share the values map
+	rec := entry.rec
+	rec.Values = maps.Clone(rec.Values)
+	rec.Flashes = append([]Flash(nil), rec.Flashes...)
+
+	return fromRecord(rec), nil
+}
+
+// Save stores the session and sets its ID cookie.
+func (ms *MemoryStore) Save(w http.ResponseWriter, _ *http.Request, s *Session) error {
+	rec := s.record()
+	changes := s.saved()
+
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if changes.previousID != "" {
+		delete(ms.sessions, changes.previousID)
+	}
+
+	if changes.destroyed {
+		delete(ms.sessions, rec.ID)
+		http.SetCookie(w, ms.options.expired())
+
+		return nil
+	}
+
+	now := ms.now()
+	ms.sweep(now)
+
+	ms.sessions[rec.ID] = &memoryEntry{rec: rec, expires: now.Add(ms.ttl)}
+
+	http.SetCookie(w, ms.options.cookie(rec.ID))
+
+	return nil
+}
+
+// Len returns the number of stored sessions, including expired ones that
+// haven't been swept yet.
+func (ms *MemoryStore) Len() int {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	return len(ms.sessions)
+}
+
+// sweep removes expired sessions, at most once per TTL.
+// The caller must hold ms.mu.
+func (ms *MemoryStore) sweep(now time.Time) {
+	if now.Sub(ms.lastSweep) < ms.ttl {
+		return
+	}
+
+	ms.lastSweep = now
+
+	for id, entry := range ms.sessions {
+		if now.After(entry.expires) {
+			delete(ms.sessions, id)
+		}
+	}
+}
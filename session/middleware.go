@@ -0,0 +1,124 @@
+package session
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+
+	"github.com/xraph/forgeui/logging"
+)
+
+// Middleware returns HTTP middleware that loads the session once per request
+// and stores it in the request context (see FromContext). Changes are saved
+// just before the response headers are written.
+//
+// When an outer middleware already loaded a session, the request passes
+// through untouched, so wrapping both the whole app and individual handlers
+// is harmless. If the store fails to load, the request gets a new session.
+func Middleware(store Store) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if FromContext(r.Context()) != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			sw, r := Load(w, r, store)
+
+			next.ServeHTTP(sw, r)
+
+			sw.Save()
+		})
+	}
+}
+
+// Load loads the request's session from store and returns a ResponseWriter
+// that saves it before the headers are written, along with the request
+// carrying the session in its context. Call Save on the writer once the
+// handler returns to persist changes made after the response started.
+//
+// Middleware for other handler types (such as router.Sessions) use Load;
+// applications normally use Middleware.
+func Load(w http.ResponseWriter, r *http.Request, store Store) (*ResponseWriter, *http.Request) {
+	s, err := store.Load(r)
+	if err != nil {
+		logging.FromContext(r.Context()).Warn("session load failed", logging.KeyError, err.Error())
+
+		s = New()
+	}
+
+	sw := &ResponseWriter{ResponseWriter: w, req: r, store: store, session: s}
+
+	return sw, r.WithContext(NewContext(r.Context(), s))
+}
+
+// ResponseWriter saves the session before the first write of the response.
+type ResponseWriter struct {
+	http.ResponseWriter
+
+	req         *http.Request
+	store       Store
+	session     *Session
+	wroteHeader bool
+}
+
+// Session returns the session the writer saves.
+func (w *ResponseWriter) Session() *Session {
+	return w.session
+}
+
+// Save saves the session if it has unsaved changes. After the headers are
+// written a store can no longer set its cookie, but server-side stores such
+// as MemoryStore still persist the changes.
+func (w *ResponseWriter) Save() {
+	if !w.session.modified() {
+		return
+	}
+
+	if err := w.store.Save(w.ResponseWriter, w.req, w.session); err != nil {
+		logging.FromContext(w.req.Context()).Error("session save failed", logging.KeyError, err.Error())
+	}
+}
+
+// WriteHeader saves the session and writes the status code.
+func (w *ResponseWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		w.Save()
+	}
+
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Write saves the session if the headers haven't been written yet.
+func (w *ResponseWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush implements http.Flusher for streamed responses.
+func (w *ResponseWriter) Flush() {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Hijack implements http.Hijacker for WebSocket upgrades.
+func (w *ResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		w.Save()
+	}
+
+	return http.NewResponseController(w.ResponseWriter).Hijack()
+}
+
+// Unwrap returns the underlying ResponseWriter for http.ResponseController.
+func (w *ResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,102 @@
+package session
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestMiddleware(t *testing.T) {
+	store := NewMemoryStore(time.Minute)
+
+	handler := Middleware(store)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s := FromContext(r.Context())
+		if s == nil {
+			t.Fatal("Expected a session in the request context")
+		}
+
+		s.Set("visits", s.GetInt("visits")+1)
+
+		http.Redirect(w, r, "/next", http.StatusSeeOther)
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 {
+		t.Fatalf("Expected the session cookie to be set before the redirect, got %d cookies", len(cookies))
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(cookies[0])
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	loaded, _ := store.Load(req)
+	if loaded.GetInt("visits") != 2 {
+		t.Errorf("Expected 2 visits, got %d", loaded.GetInt("visits"))
+	}
+}
+
+func TestMiddleware_LoadsOnce(t *testing.T) {
+	loads := 0
+	store := &countingStore{Store: NewMemoryStore(time.Minute), loads: &loads}
+
+	var inner, outer *Session
+
+	handler := Middleware(store)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		outer = FromContext(r.Context())
+
+		Middleware(store)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			inner = FromContext(r.Context())
+		})).ServeHTTP(w, r)
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if loads != 1 {
+		t.Errorf("Expected one load, got %d", loads)
+	}
+
+	if inner != outer {
+		t.Error("Expected nested middleware to share the session")
+	}
+}
+
+func TestMiddleware_UnmodifiedSessionSetsNoCookie(t *testing.T) {
+	handler := Middleware(NewMemoryStore(time.Minute))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if len(w.Result().Cookies()) != 0 {
+		t.Error("Expected no cookie for an untouched session")
+	}
+}
+
+func TestResponseWriter_SupportsFlushAndHijack(t *testing.T) {
+	var w http.ResponseWriter = &ResponseWriter{ResponseWriter: httptest.NewRecorder(), session: New()}
+
+	if _, ok := w.(http.Flusher); !ok {
+		t.Error("Expected ResponseWriter to implement http.Flusher")
+	}
+
+	if _, ok := w.(http.Hijacker); !ok {
+		t.Error("Expected ResponseWriter to implement http.Hijacker")
+	}
+}
+
+// countingStore counts Load calls.
+type countingStore struct {
+	Store
+
+	loads *int
+}
+
+func (s *countingStore) Load(r *http.Request) (*Session, error) {
+	*s.loads++
+	return s.Store.Load(r)
+}
@@ -0,0 +1,302 @@
+// Package session provides HTTP sessions shared by ForgeUI pages and bridge
+// functions.
+//
+// A Store loads the session from the request and saves it with the
+// response. Two stores are included:
+//   - CookieStore keeps the whole session in an encrypted, signed cookie
+//   - MemoryStore keeps sessions in memory with a TTL and puts only the
+//     session ID in the cookie
+//
+// Middleware loads the session once per request and stores it in the
+// request context, where router.PageContext.Session() and
+// bridge.Context.Session() find the same *Session.
+//
+// # Basic Usage
+//
+//	store, err := session.NewCookieStore([]byte(os.Getenv("SESSION_SECRET")))
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	app := forgeui.New(forgeui.WithSessions(store))
+//
+//	func handler(ctx *router.PageContext) (templ.Component, error) {
+//	    s := ctx.Session()
+//	    s.Set("theme", "dark")
+//	    s.AddFlash(session.FlashSuccess, "Settings saved")
+//	    ...
+//	}
+package session
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"maps"
+	"sync"
+)
+
+// Flash kinds, matching the toast plugin's variants.
+const (
+	FlashInfo    = "info"
+	FlashSuccess = "success"
+	FlashWarning = "warning"
+	FlashError   = "error"
+)
+
+// Flash is a one-time message shown on the next page the user sees.
+type Flash struct {
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+}
+
+// Session holds the values of one user's session.
+// It is safe for concurrent use.
+type Session struct {
+	mu        sync.Mutex
+	id        string
+	values    map[string]any
+	flashes   []Flash
+	added     []Flash
+	shown     []Flash
+	fetched   bool
+	isNew     bool
+	dirty     bool
+	previous  string
+	destroyed bool
+}
+
+// record is the persisted form of a session.
+type record struct {
+	ID      string         `json:"id"`
+	Values  map[string]any `json:"values,omitempty"`
+	Flashes []Flash        `json:"flashes,omitempty"`
+}
+
+// New returns an empty session with a fresh ID.
+func New() *Session {
+	return &Session{
+		id:     newID(),
+		values: make(map[string]any),
+		isNew:  true,
+	}
+}
+
+// fromRecord returns the session a store loaded.
+func fromRecord(rec record) *Session {
+	if rec.Values == nil {
+		rec.Values = make(map[string]any)
+	}
+
+	return &Session{
+		id:      rec.ID,
+		values:  rec.Values,
+		flashes: rec.Flashes,
+	}
+}
+
+// record returns a copy of the session's persisted state.
+func (s *Session) record() record {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return record{
+		ID:      s.id,
+		Values:  maps.Clone(s.values),
+		Flashes: append(append([]Flash(nil), s.flashes...), s.added...),
+	}
+}
+
+// ID returns the session ID.
+func (s *Session) ID() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.id
+}
+
+// IsNew reports whether the session was created for this request rather
+// than loaded from it.
+func (s *Session) IsNew() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.isNew
+}
+
+// Get retrieves a value from the session.
+func (s *Session) Get(key string) (any, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	val, ok := s.values[key]
+
+	return val, ok
+}
+
+// GetString retrieves a string value from the session.
+func (s *Session) GetString(key string) string {
+	val, _ := s.Get(key)
+	str, _ := val.(string)
+
+	return str
+}
+
+// GetInt retrieves an integer value from the session. Numbers that went
+// through a CookieStore come back as float64 and are converted.
+func (s *Session) GetInt(key string) int {
+	val, _ := s.Get(key)
+
+	switch n := val.(type) {
+	case int:
+		return n
+	case int64:
+		return int(n)
+	case float64:
+		return int(n)
+	case json.Number:
+		i, _ := n.Int64()
+		return int(i)
+	}
+
+	return 0
+}
+
+// Set stores a value in the session. Values must be JSON-encodable to be
+// saved in a CookieStore.
+func (s *Session) Set(key string, value any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.values[key] = value
+	s.dirty = true
+}
+
+// Delete removes a value from the session.
+func (s *Session) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.values[key]; ok {
+		delete(s.values, key)
+		s.dirty = true
+	}
+}
+
+// Clear removes all values and pending flash messages from the session.
+func (s *Session) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.values = make(map[string]any)
+	s.flashes = nil
+	s.added = nil
+	s.dirty = true
+}
+
+// Renew gives the session a new ID while keeping its values. Call it when
+// the user's privileges change, such as on login, to prevent session fixation.
+func (s *Session) Renew() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.previous == "" && !s.isNew {
+		s.previous = s.id
+	}
+
+	s.id = newID()
+	s.dirty = true
+}
+
+// Destroy removes the session: the store deletes it and expires its cookie
+// when the response is written.
+func (s *Session) Destroy() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.values = make(map[string]any)
+	s.flashes = nil
+	s.added = nil
+	s.destroyed = true
+	s.dirty = true
+}
+
+// AddFlash queues a message for the next page the user sees, typically the
+// one a form submission redirects to.
+func (s *Session) AddFlash(kind, message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.added = append(s.added, Flash{Kind: kind, Message: message})
+	s.dirty = true
+}
+
+// Flashes returns the messages queued by earlier requests and removes them
+// from the session. Repeated calls during the same request return the same
+// messages; flashes added during the request are kept for the next one.
+func (s *Session) Flashes() []Flash {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.fetched {
+		s.fetched = true
+		s.shown = s.flashes
+		s.flashes = nil
+
+		if len(s.shown) > 0 {
+			s.dirty = true
+		}
+	}
+
+	return append([]Flash(nil), s.shown...)
+}
+
+// changes describes what a store must do with a session on save.
+type changes struct {
+	// previousID is the ID the session was loaded with, set after Renew
+	previousID string
+	destroyed  bool
+}
+
+// saved marks the session's changes as saved and returns them.
+func (s *Session) saved() changes {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c := changes{previousID: s.previous, destroyed: s.destroyed}
+	s.dirty = false
+	s.previous = ""
+
+	return c
+}
+
+// modified reports whether the session has unsaved changes.
+func (s *Session) modified() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.dirty
+}
+
+// newID returns a random, URL-safe session ID.
+func newID() string {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying the session.
+func NewContext(ctx context.Context, s *Session) context.Context {
+	return context.WithValue(ctx, contextKey{}, s)
+}
+
+// FromContext returns the session stored in ctx by Middleware,
+// or nil if there is none.
+func FromContext(ctx context.Context) *Session {
+	s, _ := ctx.Value(contextKey{}).(*Session)
+	return s
+}
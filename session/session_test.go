@@ -0,0 +1,108 @@
+package session
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSession_Values(t *testing.T) {
+	s := New()
+
+	if !s.IsNew() {
+		t.Error("Expected new session")
+	}
+
+	if s.modified() {
+		t.Error("Expected new session to be unmodified")
+	}
+
+	s.Set("name", "jane")
+	s.Set("count", float64(3))
+
+	if s.GetString("name") != "jane" {
+		t.Errorf("Expected name jane, got %q", s.GetString("name"))
+	}
+
+	if s.GetInt("count") != 3 {
+		t.Errorf("Expected count 3, got %d", s.GetInt("count"))
+	}
+
+	if !s.modified() {
+		t.Error("Expected Set to mark the session modified")
+	}
+
+	s.saved()
+	s.Delete("missing")
+
+	if s.modified() {
+		t.Error("Deleting a missing key should not modify the session")
+	}
+
+	s.Delete("name")
+
+	if _, ok := s.Get("name"); ok {
+		t.Error("Expected name to be deleted")
+	}
+
+	s.Clear()
+
+	if _, ok := s.Get("count"); ok {
+		t.Error("Expected Clear to remove all values")
+	}
+}
+
+func TestSession_Flashes(t *testing.T) {
+	s := fromRecord(record{
+		ID:      "id",
+		Flashes: []Flash{{Kind: FlashSuccess, Message: "Saved"}},
+	})
+
+	s.AddFlash(FlashInfo, "For the next request")
+
+	flashes := s.Flashes()
+	if len(flashes) != 1 || flashes[0].Message != "Saved" {
+		t.Fatalf("Expected the flash from the previous request, got %v", flashes)
+	}
+
+	if again := s.Flashes(); len(again) != 1 {
+		t.Errorf("Expected repeated calls to return the same flashes, got %v", again)
+	}
+
+	rec := s.record()
+	if len(rec.Flashes) != 1 || rec.Flashes[0].Message != "For the next request" {
+		t.Errorf("Expected only the new flash to be persisted, got %v", rec.Flashes)
+	}
+
+	if !s.modified() {
+		t.Error("Expected taking flashes to modify the session")
+	}
+}
+
+func TestSession_Renew(t *testing.T) {
+	s := fromRecord(record{ID: "old"})
+	s.Set("user", "jane")
+	s.Renew()
+
+	if s.ID() == "old" {
+		t.Error("Expected a new ID")
+	}
+
+	if s.GetString("user") != "jane" {
+		t.Error("Expected Renew to keep values")
+	}
+
+	if c := s.saved(); c.previousID != "old" {
+		t.Errorf("Expected previous ID old, got %q", c.previousID)
+	}
+}
+
+func TestContext(t *testing.T) {
+	if FromContext(context.Background()) != nil {
+		t.Error("Expected no session in an empty context")
+	}
+
+	s := New()
+	if FromContext(NewContext(context.Background(), s)) != s {
+		t.Error("Expected FromContext to return the stored session")
+	}
+}
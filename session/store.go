@@ -0,0 +1,130 @@
+package session
+
+import (
+	"errors"
+	"net/http"
+	"time"
+)
+
+// ErrCookieTooLarge is returned by CookieStore.Save when the encoded session
+// exceeds the 4096 bytes browsers accept for a cookie.
+var ErrCookieTooLarge = errors.New("session: cookie exceeds 4096 bytes")
+
+// Store loads and saves sessions.
+type Store interface {
+	// Load returns the request's session. A request without a session, or
+	// with an invalid or expired one, gets a new session and no error.
+	Load(r *http.Request) (*Session, error)
+
+	// Save persists the session and sets its cookie on w.
+	// It must be called before the response headers are written.
+	Save(w http.ResponseWriter, r *http.Request, s *Session) error
+}
+
+// Options configures the session cookie.
+type Options struct {
+	// Name is the cookie name. Default: "forgeui_session"
+	Name string
+
+	// Path is the cookie path. Default: "/"
+	Path string
+
+	// Domain is the cookie domain. Default: the request host
+	Domain string
+
+	// MaxAge is how long the session lasts. For CookieStore it is also
+	// checked against the time the cookie was signed.
+	// Zero means a browser-session cookie.
+	MaxAge time.Duration
+
+	// Secure restricts the cookie to HTTPS
+	Secure bool
+
+	// HTTPOnly hides the cookie from JavaScript. Default: true
+	HTTPOnly bool
+
+	// SameSite sets the cookie's SameSite attribute. Default: Lax
+	SameSite http.SameSite
+}
+
+// Option configures a store's cookie options.
+type Option func(*Options)
+
+// DefaultOptions returns the default cookie options.
+func DefaultOptions() Options {
+	return Options{
+		Name:     "forgeui_session",
+		Path:     "/",
+		HTTPOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	}
+}
+
+// WithCookieName sets the cookie name.
+func WithCookieName(name string) Option {
+	return func(o *Options) { o.Name = name }
+}
+
+// WithPath sets the cookie path.
+func WithPath(path string) Option {
+	return func(o *Options) { o.Path = path }
+}
+
+// WithDomain sets the cookie domain.
+func WithDomain(domain string) Option {
+	return func(o *Options) { o.Domain = domain }
+}
+
+// WithMaxAge sets how long sessions last.
+func WithMaxAge(maxAge time.Duration) Option {
+	return func(o *Options) { o.MaxAge = maxAge }
+}
+
+// WithSecure restricts the cookie to HTTPS.
+func WithSecure(secure bool) Option {
+	return func(o *Options) { o.Secure = secure }
+}
+
+// WithSameSite sets the cookie's SameSite attribute.
+func WithSameSite(sameSite http.SameSite) Option {
+	return func(o *Options) { o.SameSite = sameSite }
+}
+
+// newOptions applies opts to the defaults.
+func newOptions(opts []Option) Options {
+	o := DefaultOptions()
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+// cookie returns the session cookie carrying value.
+func (o Options) cookie(value string) *http.Cookie {
+	c := &http.Cookie{
+		Name:     o.Name,
+		Value:    value,
+		Path:     o.Path,
+		Domain:   o.Domain,
+		Secure:   o.Secure,
+		HttpOnly: o.HTTPOnly,
+		SameSite: o.SameSite,
+	}
+
+	if o.MaxAge > 0 {
+		c.MaxAge = int(o.MaxAge / time.Second)
+		c.Expires = time.Now().Add(o.MaxAge)
+	}
+
+	return c
+}
+
+// expired returns a cookie that deletes the session cookie.
+func (o Options) expired() *http.Cookie {
+	c := o.cookie("")
+	c.MaxAge = -1
+	c.Expires = time.Unix(0, 0)
+
+	return c
+}
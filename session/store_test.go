@@ -0,0 +1,317 @@
+package session
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testSecret = []byte("0123456789abcdef0123456789abcdef")
+
+// roundTrip saves s with store and loads it back from the resulting cookie.
+func roundTrip(t *testing.T, store Store, s *Session) (*Session, *http.Cookie) {
+	t.Helper()
+
+	w := httptest.NewRecorder()
+	if err := store.Save(w, httptest.NewRequest(http.MethodGet, "/", nil), s); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 {
+		t.Fatalf("Expected one cookie, got %d", len(cookies))
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(cookies[0])
+
+	loaded, err := store.Load(req)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	return loaded, cookies[0]
+}
+
+func TestNewCookieStore(t *testing.T) {
+	if _, err := NewCookieStore([]byte("short")); err == nil {
+		t.Error("Expected error for a short secret")
+	}
+
+	if _, err := NewCookieStoreWithRotation(nil); err == nil {
+		t.Error("Expected error without secrets")
+	}
+}
+
+func TestCookieStore_RoundTrip(t *testing.T) {
+	store, err := NewCookieStore(testSecret, WithCookieName("sid"), WithSecure(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := New()
+	s.Set("user", "jane")
+	s.Set("visits", 2)
+	s.AddFlash(FlashSuccess, "Welcome")
+
+	loaded, cookie := roundTrip(t, store, s)
+
+	if cookie.Name != "sid" || !cookie.Secure || !cookie.HttpOnly {
+		t.Errorf("Unexpected cookie attributes: %+v", cookie)
+	}
+
+	if strings.Contains(cookie.Value, "jane") {
+		t.Error("Cookie value should be encrypted")
+	}
+
+	if loaded.IsNew() || loaded.ID() != s.ID() {
+		t.Errorf("Expected session %s to be loaded, got %s", s.ID(), loaded.ID())
+	}
+
+	if loaded.GetString("user") != "jane" || loaded.GetInt("visits") != 2 {
+		t.Errorf("Unexpected values: user=%q visits=%d", loaded.GetString("user"), loaded.GetInt("visits"))
+	}
+
+	if flashes := loaded.Flashes(); len(flashes) != 1 || flashes[0].Message != "Welcome" {
+		t.Errorf("Expected flash to survive the round trip, got %v", flashes)
+	}
+}
+
+func TestCookieStore_RejectsInvalidCookies(t *testing.T) {
+	store, _ := NewCookieStore(testSecret)
+	other, _ := NewCookieStore([]byte("another secret, also 32 bytes long"))
+
+	s := New()
+	s.Set("role", "user")
+
+	w := httptest.NewRecorder()
+	_ = store.Save(w, nil, s)
+	valid := w.Result().Cookies()[0].Value
+
+	payload, sig, _ := strings.Cut(valid, ".")
+
+	// Flip one character in the middle of the payload
+	mid := len(payload) / 2
+	flipped := "A"
+	if payload[mid] == 'A' {
+		flipped = "B"
+	}
+
+	tampered := payload[:mid] + flipped + payload[mid+1:]
+
+	tests := []struct {
+		name  string
+		store *CookieStore
+		value string
+	}{
+		{"tampered payload", store, tampered + "." + sig},
+		{"tampered signature", store, payload + ".AAAA"},
+		{"no signature", store, payload},
+		{"garbage", store, "not a cookie"},
+		{"other secret", other, valid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.AddCookie(&http.Cookie{Name: "forgeui_session", Value: tt.value})
+
+			loaded, err := tt.store.Load(req)
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+
+			if !loaded.IsNew() {
+				t.Error("Expected a new session for an invalid cookie")
+			}
+		})
+	}
+}
+
+func TestCookieStore_Rotation(t *testing.T) {
+	oldSecret := []byte("the old secret, at least 32 bytes")
+
+	old, _ := NewCookieStore(oldSecret)
+	rotated, err := NewCookieStoreWithRotation([][]byte{testSecret, oldSecret})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := New()
+	s.Set("user", "jane")
+
+	w := httptest.NewRecorder()
+	_ = old.Save(w, nil, s)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(w.Result().Cookies()[0])
+
+	loaded, _ := rotated.Load(req)
+	if loaded.GetString("user") != "jane" {
+		t.Error("Expected cookies signed with the previous secret to be accepted")
+	}
+}
+
+func TestCookieStore_MaxAge(t *testing.T) {
+	store, _ := NewCookieStore(testSecret, WithMaxAge(time.Hour))
+
+	rec := record{ID: "id"}
+
+	value, err := store.encode(rec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := store.decode(value); !ok {
+		t.Fatal("Expected fresh cookie to decode")
+	}
+
+	store.options.MaxAge = -time.Second
+	if _, ok := store.decode(value); !ok {
+		t.Error("Negative MaxAge should not expire cookies")
+	}
+
+	store.options.MaxAge = time.Nanosecond
+	time.Sleep(time.Millisecond)
+
+	if _, ok := store.decode(value); ok {
+		t.Error("Expected expired cookie to be rejected")
+	}
+}
+
+func TestCookieStore_TooLarge(t *testing.T) {
+	store, _ := NewCookieStore(testSecret)
+
+	s := New()
+	s.Set("blob", strings.Repeat("x", maxCookieSize))
+
+	if err := store.Save(httptest.NewRecorder(), nil, s); err != ErrCookieTooLarge {
+		t.Errorf("Expected ErrCookieTooLarge, got %v", err)
+	}
+}
+
+func TestCookieStore_Destroy(t *testing.T) {
+	store, _ := NewCookieStore(testSecret)
+
+	s := New()
+	s.Destroy()
+
+	w := httptest.NewRecorder()
+	_ = store.Save(w, nil, s)
+
+	if cookie := w.Result().Cookies()[0]; cookie.MaxAge >= 0 {
+		t.Errorf("Expected the cookie to be expired, got MaxAge %d", cookie.MaxAge)
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	store := NewMemoryStore(time.Minute)
+
+	s := New()
+	s.Set("user", "jane")
+
+	loaded, cookie := roundTrip(t, store, s)
+
+	if cookie.Value != s.ID() {
+		t.Errorf("Expected the cookie to hold the session ID, got %q", cookie.Value)
+	}
+
+	if loaded.GetString("user") != "jane" {
+		t.Error("Expected stored value")
+	}
+
+	// Loaded sessions are copies
+	loaded.Set("user", "john")
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(cookie)
+
+	if again, _ := store.Load(req); again.GetString("user") != "jane" {
+		t.Error("Unsaved changes should not leak into the store")
+	}
+}
+
+func TestMemoryStore_Expiry(t *testing.T) {
+	now := time.Now()
+
+	store := NewMemoryStore(time.Minute)
+	store.now = func() time.Time { return now }
+
+	s := New()
+	s.Set("user", "jane")
+
+	_, cookie := roundTrip(t, store, s)
+
+	now = now.Add(2 * time.Minute)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(cookie)
+
+	loaded, _ := store.Load(req)
+	if !loaded.IsNew() {
+		t.Error("Expected expired session to be replaced")
+	}
+
+	if store.Len() != 0 {
+		t.Errorf("Expected expired session to be removed, %d left", store.Len())
+	}
+}
+
+func TestMemoryStore_Sweep(t *testing.T) {
+	now := time.Now()
+
+	store := NewMemoryStore(time.Minute)
+	store.now = func() time.Time { return now }
+
+	for range 3 {
+		s := New()
+		s.Set("k", "v")
+		_ = store.Save(httptest.NewRecorder(), nil, s)
+	}
+
+	now = now.Add(2 * time.Minute)
+
+	s := New()
+	s.Set("k", "v")
+	_ = store.Save(httptest.NewRecorder(), nil, s)
+
+	if store.Len() != 1 {
+		t.Errorf("Expected expired sessions to be swept, %d left", store.Len())
+	}
+}
+
+func TestMemoryStore_RenewAndDestroy(t *testing.T) {
+	store := NewMemoryStore(time.Minute)
+
+	s := New()
+	s.Set("user", "jane")
+
+	loaded, _ := roundTrip(t, store, s)
+	oldID := loaded.ID()
+
+	loaded.Renew()
+	_ = store.Save(httptest.NewRecorder(), nil, loaded)
+
+	if store.Len() != 1 {
+		t.Fatalf("Expected the old session to be replaced, %d stored", store.Len())
+	}
+
+	if _, ok := store.sessions[oldID]; ok {
+		t.Error("Expected the old ID to be removed")
+	}
+
+	loaded.Destroy()
+
+	w := httptest.NewRecorder()
+	_ = store.Save(w, nil, loaded)
+
+	if store.Len() != 0 {
+		t.Error("Expected Destroy to delete the session")
+	}
+
+	if cookie := w.Result().Cookies()[0]; cookie.MaxAge >= 0 {
+		t.Error("Expected Destroy to expire the cookie")
+	}
+}
@@ -0,0 +1,179 @@
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"slices"
+	"sync"
+	"time"
+)
+
+// SpanData is a finished span, as handed to an Exporter.
+type SpanData struct {
+	Name     string
+	TraceID  TraceID
+	SpanID   SpanID
+	ParentID SpanID // zero for root spans
+	Start    time.Time
+	End      time.Time
+	Attrs    []Attr
+	Err      error
+}
+
+// Duration returns how long the span took.
+func (d SpanData) Duration() time.Duration {
+	return d.End.Sub(d.Start)
+}
+
+// Attr returns the value of the attribute key, and whether it is set.
+// Later values win.
+func (d SpanData) Attr(key string) (any, bool) {
+	for i := len(d.Attrs) - 1; i >= 0; i-- {
+		if d.Attrs[i].Key == key {
+			return d.Attrs[i].Value, true
+		}
+	}
+
+	return nil, false
+}
+
+// Exporter receives finished spans. Implementations must be safe for
+// concurrent use.
+type Exporter interface {
+	ExportSpan(SpanData)
+}
+
+// NewTracer returns a tracer that hands finished spans to the exporters.
+// Every span is sampled.
+func NewTracer(exporters ...Exporter) Tracer {
+	return &tracer{exporters: exporters}
+}
+
+type tracer struct {
+	exporters []Exporter
+}
+
+// Start implements Tracer.
+func (t *tracer) Start(ctx context.Context, name string, attrs ...Attr) (context.Context, Span) {
+	s := &span{
+		tracer: t,
+		data: SpanData{
+			Name:  name,
+			Start: time.Now(),
+			Attrs: slices.Clone(attrs),
+		},
+	}
+
+	parent := SpanFromContext(ctx).SpanContext()
+	if !parent.IsValid() {
+		parent, _ = RemoteParent(ctx)
+	}
+
+	if parent.IsValid() {
+		s.data.TraceID = parent.TraceID
+		s.data.ParentID = parent.SpanID
+	} else {
+		_, _ = rand.Read(s.data.TraceID[:])
+	}
+
+	_, _ = rand.Read(s.data.SpanID[:])
+
+	return ctx, s
+}
+
+type span struct {
+	tracer *tracer
+
+	mu    sync.Mutex
+	data  SpanData
+	ended bool
+}
+
+func (s *span) SpanContext() SpanContext {
+	return SpanContext{TraceID: s.data.TraceID, SpanID: s.data.SpanID, Flags: 1}
+}
+
+func (s *span) SetAttributes(attrs ...Attr) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.ended {
+		s.data.Attrs = append(s.data.Attrs, attrs...)
+	}
+}
+
+func (s *span) RecordError(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.ended && err != nil {
+		s.data.Err = err
+	}
+}
+
+func (s *span) End() {
+	s.mu.Lock()
+
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+
+	s.ended = true
+	s.data.End = time.Now()
+	data := s.data
+
+	s.mu.Unlock()
+
+	for _, e := range s.tracer.exporters {
+		e.ExportSpan(data)
+	}
+}
+
+// InMemoryExporter keeps finished spans in memory, for tests and debugging.
+type InMemoryExporter struct {
+	mu    sync.Mutex
+	spans []SpanData
+}
+
+// NewInMemoryExporter returns an empty InMemoryExporter.
+func NewInMemoryExporter() *InMemoryExporter {
+	return &InMemoryExporter{}
+}
+
+// ExportSpan implements Exporter.
+func (e *InMemoryExporter) ExportSpan(d SpanData) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.spans = append(e.spans, d)
+}
+
+// Spans returns the finished spans in the order they ended.
+func (e *InMemoryExporter) Spans() []SpanData {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return slices.Clone(e.spans)
+}
+
+// Named returns the finished spans called name.
+func (e *InMemoryExporter) Named(name string) []SpanData {
+	var spans []SpanData
+
+	for _, s := range e.Spans() {
+		if s.Name == name {
+			spans = append(spans, s)
+		}
+	}
+
+	return spans
+}
+
+// Reset discards the recorded spans.
+func (e *InMemoryExporter) Reset() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.spans = nil
+}
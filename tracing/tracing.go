@@ -0,0 +1,210 @@
+// Package tracing records where time goes while ForgeUI serves a request.
+//
+// The router opens spans for the request, route matching, loaders, each
+// layout and the page render; the bridge opens one for every function call,
+// including the calls of a batch. Spans go to a Tracer: the built-in one
+// (NewTracer) hands finished spans to an Exporter such as InMemoryExporter,
+// and adapters plug in other tracing systems.
+//
+//	exporter := tracing.NewInMemoryExporter()
+//	app := forgeui.New(forgeui.WithTracer(tracing.NewTracer(exporter)))
+//
+// Trace context travels in W3C traceparent headers. Pages pass theirs to the
+// forge-bridge.js client (see App.BridgeScripts), which sends it with every
+// call, so browser-initiated RPCs join the trace of the page that made them.
+//
+// # OpenTelemetry
+//
+// An adapter implements Tracer. It starts its span as a child of the span
+// in ctx, or, for the first span of a request, of RemoteParent(ctx):
+//
+//	type otelTracer struct{ t trace.Tracer }
+//
+//	func (o otelTracer) Start(ctx context.Context, name string, attrs ...tracing.Attr) (context.Context, tracing.Span) {
+//	    if parent, ok := tracing.RemoteParent(ctx); ok && !trace.SpanFromContext(ctx).SpanContext().IsValid() {
+//	        ctx = trace.ContextWithRemoteSpanContext(ctx, toOtel(parent))
+//	    }
+//	    ctx, span := o.t.Start(ctx, name, trace.WithAttributes(toOtelAttrs(attrs)...))
+//	    return ctx, otelSpan{span}
+//	}
+package tracing
+
+import (
+	"context"
+	"net/http"
+)
+
+// Attribute keys used by ForgeUI spans. HTTP keys follow the OpenTelemetry
+// semantic conventions.
+const (
+	KeyMethod    = "http.request.method"
+	KeyPath      = "url.path"
+	KeyRoute     = "http.route"
+	KeyStatus    = "http.response.status_code"
+	KeyLayout    = "forgeui.layout"
+	KeyLoader    = "forgeui.loader"
+	KeyFunction  = "forgeui.bridge.function"
+	KeyBatchSize = "forgeui.bridge.batch_size"
+)
+
+// Tracer starts spans. Implementations must be safe for concurrent use.
+type Tracer interface {
+	// Start starts a span named name as a child of the span in ctx, or of
+	// RemoteParent(ctx) when ctx has no span, and returns a context for
+	// work done inside it.
+	Start(ctx context.Context, name string, attrs ...Attr) (context.Context, Span)
+}
+
+// Span is a timed operation within a trace.
+type Span interface {
+	// SpanContext identifies the span for propagation.
+	SpanContext() SpanContext
+
+	// SetAttributes adds attributes to the span.
+	SetAttributes(attrs ...Attr)
+
+	// RecordError marks the span as failed with err.
+	RecordError(err error)
+
+	// End finishes the span. Calls after the first are ignored.
+	End()
+}
+
+// Attr is a span attribute.
+type Attr struct {
+	Key   string
+	Value any
+}
+
+// String returns a string attribute.
+func String(key, value string) Attr {
+	return Attr{Key: key, Value: value}
+}
+
+// Int returns an integer attribute.
+func Int(key string, value int) Attr {
+	return Attr{Key: key, Value: value}
+}
+
+// Bool returns a boolean attribute.
+func Bool(key string, value bool) Attr {
+	return Attr{Key: key, Value: value}
+}
+
+type (
+	tracerKey struct{}
+	spanKey   struct{}
+	remoteKey struct{}
+)
+
+// NewContext returns a copy of ctx carrying the tracer.
+func NewContext(ctx context.Context, t Tracer) context.Context {
+	return context.WithValue(ctx, tracerKey{}, t)
+}
+
+// FromContext returns the tracer stored in ctx, or nil.
+func FromContext(ctx context.Context) Tracer {
+	t, _ := ctx.Value(tracerKey{}).(Tracer)
+	return t
+}
+
+// Enabled reports whether ctx carries a tracer.
+func Enabled(ctx context.Context) bool {
+	return FromContext(ctx) != nil
+}
+
+// Start starts a span with the tracer in ctx. Without a tracer it returns
+// ctx unchanged and a span that does nothing, so instrumented code needn't
+// check whether tracing is enabled.
+func Start(ctx context.Context, name string, attrs ...Attr) (context.Context, Span) {
+	t := FromContext(ctx)
+	if t == nil {
+		return ctx, noopSpan{}
+	}
+
+	ctx, span := t.Start(ctx, name, attrs...)
+
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+// SpanFromContext returns the current span of ctx, or a span that does
+// nothing.
+func SpanFromContext(ctx context.Context) Span {
+	if span, ok := ctx.Value(spanKey{}).(Span); ok {
+		return span
+	}
+
+	return noopSpan{}
+}
+
+// WithRemoteParent returns a copy of ctx whose first span continues the
+// trace of sc, typically parsed from an incoming traceparent header.
+func WithRemoteParent(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, remoteKey{}, sc)
+}
+
+// RemoteParent returns the remote parent stored in ctx.
+func RemoteParent(ctx context.Context) (SpanContext, bool) {
+	sc, ok := ctx.Value(remoteKey{}).(SpanContext)
+	return sc, ok && sc.IsValid()
+}
+
+// TraceParent returns the traceparent header value for the current span of
+// ctx, or of its remote parent, or "" when there is neither.
+func TraceParent(ctx context.Context) string {
+	if sc := SpanFromContext(ctx).SpanContext(); sc.IsValid() {
+		return sc.TraceParent()
+	}
+
+	if sc, ok := RemoteParent(ctx); ok {
+		return sc.TraceParent()
+	}
+
+	return ""
+}
+
+// Extract returns r with t as its tracer, unless r already carries one, and
+// with the remote parent from its traceparent header. A nil t only extracts
+// the remote parent.
+func Extract(r *http.Request, t Tracer) *http.Request {
+	ctx := r.Context()
+
+	if t != nil && FromContext(ctx) == nil {
+		ctx = NewContext(ctx, t)
+	}
+
+	if _, ok := RemoteParent(ctx); !ok {
+		if sc, err := ParseTraceParent(r.Header.Get(TraceParentHeader)); err == nil {
+			ctx = WithRemoteParent(ctx, sc)
+		}
+	}
+
+	if ctx == r.Context() {
+		return r
+	}
+
+	return r.WithContext(ctx)
+}
+
+// Middleware returns HTTP middleware that makes t the tracer of every
+// request and continues the trace of incoming traceparent headers
+// (see Extract). A nil t is a no-op.
+func Middleware(t Tracer) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if t == nil {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, Extract(r, t))
+		})
+	}
+}
+
+// noopSpan is the span of untraced requests.
+type noopSpan struct{}
+
+func (noopSpan) SpanContext() SpanContext { return SpanContext{} }
+func (noopSpan) SetAttributes(...Attr)    {}
+func (noopSpan) RecordError(error)        {}
+func (noopSpan) End()                     {}
@@ -0,0 +1,126 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const testTraceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func TestParseTraceParent(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		wantErr bool
+	}{
+		{"valid", testTraceParent, false},
+		{"not sampled", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00", false},
+		{"future version", "01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra", false},
+		{"empty", "", true},
+		{"forbidden version", "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", true},
+		{"extra fields in version 00", testTraceParent + "-extra", true},
+		{"short trace ID", "00-4bf92f3577b34da6a3ce929d0e0e47-00f067aa0ba902b7-01", true},
+		{"uppercase", "00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01", true},
+		{"zero trace ID", "00-00000000000000000000000000000000-00f067aa0ba902b7-01", true},
+		{"zero span ID", "00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sc, err := ParseTraceParent(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTraceParent(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+
+			if err == nil && sc.TraceID.String() != "4bf92f3577b34da6a3ce929d0e0e4736" {
+				t.Errorf("Expected trace ID 4bf92f3577b34da6a3ce929d0e0e4736, got %s", sc.TraceID)
+			}
+		})
+	}
+
+	sc, _ := ParseTraceParent(testTraceParent)
+	if sc.TraceParent() != testTraceParent {
+		t.Errorf("Expected %s to round-trip, got %s", testTraceParent, sc.TraceParent())
+	}
+}
+
+func TestTracer_ParentChild(t *testing.T) {
+	exporter := NewInMemoryExporter()
+	ctx := NewContext(context.Background(), NewTracer(exporter))
+
+	ctx, parent := Start(ctx, "parent", String("key", "value"))
+	_, child := Start(ctx, "child")
+
+	child.RecordError(errors.New("boom"))
+	child.End()
+	child.End()
+	parent.End()
+
+	spans := exporter.Spans()
+	if len(spans) != 2 {
+		t.Fatalf("Expected 2 spans, got %d", len(spans))
+	}
+
+	c, p := spans[0], spans[1]
+
+	if c.TraceID != p.TraceID || c.ParentID != p.SpanID {
+		t.Error("Expected child to be in the parent's trace, under the parent span")
+	}
+
+	if p.ParentID.IsValid() {
+		t.Error("Expected the parent to be a root span")
+	}
+
+	if c.Err == nil {
+		t.Error("Expected the child span to record its error")
+	}
+
+	if v, ok := p.Attr("key"); !ok || v != "value" {
+		t.Errorf("Expected attribute key=value, got %v", v)
+	}
+}
+
+func TestStart_WithoutTracer(t *testing.T) {
+	ctx := context.Background()
+
+	got, span := Start(ctx, "noop")
+	if got != ctx {
+		t.Error("Expected the context unchanged without a tracer")
+	}
+
+	if span.SpanContext().IsValid() {
+		t.Error("Expected an invalid span context without a tracer")
+	}
+
+	span.End()
+}
+
+func TestExtract(t *testing.T) {
+	exporter := NewInMemoryExporter()
+	tracer := NewTracer(exporter)
+
+	handler := Middleware(tracer)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if TraceParent(r.Context()) != testTraceParent {
+			t.Errorf("Expected remote parent %s, got %q", testTraceParent, TraceParent(r.Context()))
+		}
+
+		_, span := Start(r.Context(), "handler")
+		span.End()
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(TraceParentHeader, testTraceParent)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := exporter.Named("handler")
+	if len(spans) != 1 {
+		t.Fatalf("Expected 1 handler span, got %d", len(spans))
+	}
+
+	if spans[0].TraceID.String() != "4bf92f3577b34da6a3ce929d0e0e4736" || spans[0].ParentID.String() != "00f067aa0ba902b7" {
+		t.Error("Expected the span to continue the remote trace")
+	}
+}
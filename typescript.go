@@ -0,0 +1,42 @@
+package forgeui
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// BridgeTypeScriptEnv is the environment variable that makes
+// BridgeTypeScriptFromEnv write the bridge's TypeScript declarations to the
+// file it names. `forgeui bridge gen-ts` sets it.
+const BridgeTypeScriptEnv = "FORGEUI_BRIDGE_TS"
+
+// BridgeTypeScriptFromEnv writes the TypeScript declarations of the
+// bridge functions (see bridge.Bridge.GenerateTypeScript) when the
+// FORGEUI_BRIDGE_TS environment variable names an output file, and reports
+// whether it did. App.Run calls it; apps that run their own server call it
+// from main after registering their bridge functions, like ExportFromEnv.
+func (a *App) BridgeTypeScriptFromEnv() (bool, error) {
+	out := os.Getenv(BridgeTypeScriptEnv)
+	if out == "" {
+		return false, nil
+	}
+
+	hookStarted()
+
+	if a.bridge == nil {
+		return true, errors.New("bridge is not enabled")
+	}
+
+	var buf bytes.Buffer
+	if err := a.bridge.GenerateTypeScript(&buf); err != nil {
+		return true, fmt.Errorf("failed to generate TypeScript: %w", err)
+	}
+
+	if err := os.WriteFile(out, buf.Bytes(), 0600); err != nil {
+		return true, fmt.Errorf("failed to write TypeScript: %w", err)
+	}
+
+	return true, nil
+}
@@ -0,0 +1,47 @@
+package forgeui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/xraph/forgeui/bridge"
+)
+
+func TestApp_BridgeTypeScriptFromEnv(t *testing.T) {
+	app := New(WithBridge())
+
+	err := app.Bridge().Register("ping", func(ctx bridge.Context) (string, error) {
+		return "pong", nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv(BridgeTypeScriptEnv, "")
+
+	if ok, _ := app.BridgeTypeScriptFromEnv(); ok {
+		t.Error("Expected no generation without the environment variable")
+	}
+
+	out := filepath.Join(t.TempDir(), "bridge.d.ts")
+	t.Setenv(BridgeTypeScriptEnv, out)
+
+	if ok, err := app.BridgeTypeScriptFromEnv(); !ok || err != nil {
+		t.Fatalf("Expected generation, got %v %v", ok, err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("Expected the declarations to be written: %v", err)
+	}
+
+	if !strings.Contains(string(data), `"ping": { params: void; result: string };`) {
+		t.Errorf("Expected the ping function, got:\n%s", data)
+	}
+
+	if ok, err := New().BridgeTypeScriptFromEnv(); !ok || err == nil {
+		t.Errorf("Expected an error without a bridge, got %v %v", ok, err)
+	}
+}